@@ -63,6 +63,8 @@ func main() {
 		cli.ReceiveMode(ctx, cliOpts, command, args)
 	case "configure":
 		cli.SetupMode(cliOpts, command, args)
+	case "verify-archive":
+		cli.VerifyArchiveMode(cliOpts, command, args)
 	case "version":
 		if len(args) > 0 && (args[0] == "--verbosity" || args[0] == "-v") {
 			fmt.Printf("SDSyslog %s\n", global.ProgVersion)
@@ -6,6 +6,7 @@ require (
 	github.com/cilium/ebpf v0.21.0
 	github.com/elastic/go-lumber v0.1.1
 	github.com/godbus/dbus/v5 v5.2.2
+	github.com/klauspost/compress v1.18.6
 	github.com/pbnjay/memory v0.0.0-20210728143218-7b4eea64cf58
 	golang.org/x/crypto v0.53.0
 	golang.org/x/sys v0.46.0
@@ -14,7 +15,6 @@ require (
 )
 
 require (
-	github.com/klauspost/compress v1.18.6 // indirect
 	golang.org/x/mod v0.37.0 // indirect
 	golang.org/x/sync v0.21.0 // indirect
 )
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sdsyslog/internal/crypto/certificate"
+	"sdsyslog/internal/setup"
+)
+
+// Verifies hash chain and checkpoint signatures of audit file output archives
+func VerifyArchiveMode(cliOpts *CommandSet, commandname string, args []string) {
+	var publicKeyLocation string
+	var allowUnsigned bool
+
+	commandFlags := flag.NewFlagSet(commandname, flag.ExitOnError)
+	commandFlags.StringVar(&publicKeyLocation, "k", "", "Audit signing public key (base64 key, or file with base64 or PEM key)")
+	commandFlags.StringVar(&publicKeyLocation, "public-key", "", "Audit signing public key (base64 key, or file with base64 or PEM key)")
+	commandFlags.BoolVar(&allowUnsigned, "allow-unsigned", false, "Accept chains with records not covered by a signed checkpoint")
+
+	commandFlags.Usage = func() {
+		PrintHelpMenu(commandFlags, commandname, cliOpts)
+	}
+	if len(args) < 1 {
+		PrintHelpMenu(commandFlags, commandname, cliOpts)
+		os.Exit(1)
+	}
+	err := commandFlags.Parse(args[0:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if publicKeyLocation == "" || commandFlags.NArg() == 0 {
+		PrintHelpMenu(commandFlags, commandname, cliOpts)
+		os.Exit(1)
+	}
+
+	publicKey, err := loadAuditPublicKey(publicKeyLocation)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := setup.VerifyArchive(commandFlags.Args(), publicKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if report.Broken != nil {
+		fmt.Printf("BROKEN: %s\n", report.Broken)
+		fmt.Printf("Verified %d record(s) and %d checkpoint(s) before the break\n", report.Records, report.Checkpoints)
+		os.Exit(2)
+	}
+
+	start := "first record"
+	if report.FirstSeq > 1 {
+		start = fmt.Sprintf("signed checkpoint at sequence %d (older files removed)", report.FirstSeq)
+	}
+
+	// Hashes alone can be recomputed by anyone, only signed records are proven
+	if report.Checkpoints == 0 || report.Unsigned > 0 {
+		status := "UNSIGNED"
+		if allowUnsigned {
+			status = "OK"
+		}
+		fmt.Printf("%s: %d record(s), %d signed checkpoint(s), chain starts at %s\n",
+			status, report.Records, report.Checkpoints, start)
+		fmt.Printf("Warning: last %d record(s) are not covered by a signed checkpoint\n", report.Unsigned)
+		if !allowUnsigned {
+			os.Exit(3)
+		}
+		return
+	}
+	fmt.Printf("OK: %d record(s), %d signed checkpoint(s), chain starts at %s\n",
+		report.Records, report.Checkpoints, start)
+}
+
+// Reads public key from a file (base64 or PEM) or, when no such file exists, given directly (base64)
+func loadAuditPublicKey(location string) (publicKey []byte, err error) {
+	_, err = os.Stat(location)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("failed to access public key file: %w", err)
+			return
+		}
+		publicKey, err = base64.StdEncoding.DecodeString(location)
+		if err != nil {
+			err = fmt.Errorf("public key is neither an existing file nor base64: %w", err)
+			return
+		}
+	} else {
+		publicKey, err = readAuditPublicKeyFile(location)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		err = fmt.Errorf("public key has length %d, expected %d byte ed25519 key", len(publicKey), ed25519.PublicKeySize)
+		return
+	}
+	return
+}
+
+// Reads public key file with base64 or PEM contents
+func readAuditPublicKeyFile(path string) (publicKey []byte, err error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("failed to read public key file: %w", err)
+		return
+	}
+	publicKey, lerr := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(contents)))
+	if lerr == nil {
+		return
+	}
+
+	var algo x509.PublicKeyAlgorithm
+	publicKey, algo, err = certificate.LoadPublicKeyFile(path)
+	if err != nil {
+		err = fmt.Errorf("failed retrieving public key: %w", err)
+		return
+	}
+	if algo != x509.Ed25519 {
+		err = fmt.Errorf("file at %q is not ed25519", path)
+		return
+	}
+	return
+}
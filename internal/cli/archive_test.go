@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadAuditPublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(publicKey)
+
+	// Relative file names that are also valid base64
+	t.Chdir(t.TempDir())
+	err = os.WriteFile("keyfile1", []byte(encoded+"\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		location      string
+		expectedError string
+	}{
+		{
+			name:     "base64 key",
+			location: encoded,
+		},
+		{
+			name:     "file name that is valid base64",
+			location: "keyfile1",
+		},
+		{
+			name:          "short base64 key",
+			location:      base64.StdEncoding.EncodeToString(publicKey[:16]),
+			expectedError: "expected 32 byte ed25519 key",
+		},
+		{
+			name:          "missing file that is not base64",
+			location:      "missing.key",
+			expectedError: "neither an existing file nor base64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := loadAuditPublicKey(tt.location)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, but got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(key, publicKey) {
+				t.Errorf("loaded key does not match")
+			}
+		})
+	}
+}
@@ -35,6 +35,14 @@ func DefineOptions() (cmdOpts *CommandSet) {
 		ChildCommands:   nil,
 	}
 
+	// Audit archive verification
+	root.ChildCommands["verify-archive"] = &CommandSet{
+		CommandName:     "verify-archive",
+		UsageOption:     "[file...]",
+		Description:     "Verify Audit Archives",
+		FullDescription: "Walks the hash chain of audit file output archives (oldest first) and reports the first broken link",
+	}
+
 	// Version Info
 	root.ChildCommands["version"] = &CommandSet{
 		CommandName:     "version",
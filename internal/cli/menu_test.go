@@ -46,10 +46,11 @@ func TestPrintHelpMenu_RootOutput(t *testing.T) {
 ` + root.FullDescription + `
 
   Subcommands:
-    configure   - Setup Actions
-    ` + global.RecvMode + `     - Receive Messages
-    ` + global.SendMode + `        - Send Messages
-    version     - Show Version Information
+    configure        - Setup Actions
+    ` + global.RecvMode + `          - Receive Messages
+    ` + global.SendMode + `             - Send Messages
+    verify-archive   - Verify Audit Archives
+    version          - Show Version Information
 
   Options:
   -v, --verbosity  Increase detailed progress messages (Higher is more verbose) <0...5> [default: 1]
@@ -6,6 +6,7 @@ import (
 	"os"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/setup"
+	"sdsyslog/pkg/crypto/registry"
 )
 
 // Setup/installation options
@@ -19,6 +20,8 @@ func SetupMode(cliOpts *CommandSet, commandname string, args []string) {
 	var uninstallSender bool
 	var uninstallReceiver bool
 	var confPath string
+	var parserProfile string
+	var suiteName string
 	var dryRun bool
 	var verbose bool
 
@@ -30,9 +33,11 @@ func SetupMode(cliOpts *CommandSet, commandname string, args []string) {
 	commandFlags.StringVar(&confPath, "c", "", "Path to config file")
 	commandFlags.StringVar(&confPath, "config", "", "Path to config file")
 	commandFlags.BoolVar(&newKeyPair, "create-keys", false, "Create new persistent key pair (prints to stdout)")
+	commandFlags.StringVar(&suiteName, "suite", registry.DefaultCryptoName, "Transport suite name for new persistent key pair (used with create-keys)")
 	commandFlags.BoolVar(&newSigningKeys, "create-signing-keys", false, "Create new persistent signing key pair (prints to stdout)")
 	commandFlags.BoolVar(&newSendConf, "send-config-template", false, "Create new template config for the sender daemon (using config-path argument)")
 	commandFlags.BoolVar(&newRecvConf, "recv-config-template", false, "Create new template config for the receiver daemon (using config-path argument)")
+	commandFlags.StringVar(&parserProfile, "test-parser", "", "Parse sample lines (from file arguments or stdin) with the named parser profile of the sender config, or \"auto\"")
 	commandFlags.BoolVar(&dryRun, "T", false, "No not mutate anything, but print what would have been done")
 	commandFlags.BoolVar(&dryRun, "dry-run", false, "No not mutate anything, but print what would have been done")
 	commandFlags.BoolVar(&verbose, "v", false, "Print detailed progress messages")
@@ -54,13 +59,20 @@ func SetupMode(cliOpts *CommandSet, commandname string, args []string) {
 	var defaultSuiteID uint8 = 1
 
 	if newKeyPair {
-		err = setup.GeneratePrivateKeys(defaultSuiteID)
+		transportSuiteID, validName := registry.SuiteNameToID(suiteName)
+		if !validName {
+			fmt.Fprintf(os.Stderr, "Error: invalid transport suite name %s\n", suiteName)
+			os.Exit(1)
+		}
+		err = setup.GeneratePrivateKeys(transportSuiteID)
 	} else if newSigningKeys {
 		err = setup.GenerateSigningKeys(defaultSuiteID)
 	} else if newSendConf {
 		err = setup.CreateSendTemplateConfig(confPath)
 	} else if newRecvConf {
 		err = setup.CreateRecvTemplateConfig(confPath)
+	} else if parserProfile != "" {
+		err = setup.TestParserProfile(confPath, parserProfile, commandFlags.Args(), os.Stdout)
 	} else if installSender {
 		var inst *setup.Installer
 		inst, err = setup.NewInstaller(global.SendMode, defaultSuiteID, dryRun, verbose)
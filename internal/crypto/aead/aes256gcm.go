@@ -0,0 +1,76 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"sdsyslog/internal/crypto"
+	"sdsyslog/internal/crypto/random"
+)
+
+const (
+	AES256KeySize  int = 32
+	GCMNonceSize   int = 12
+	GCMTagOverhead int = 16
+)
+
+// Encrypts provided plain text using provided parameters using AES-256-GCM AEAD cipher
+// Zeroes key memory after encryption
+func EncryptAES256GCM(plaintext, key, nonce, additional []byte) (ciphertext []byte, err error) {
+	err = random.PopulateEmptySlice(&nonce, GCMNonceSize)
+	if err != nil {
+		err = fmt.Errorf("encountered error fixing insecure provided nonce: %w", err)
+		return
+	}
+
+	aead, err := newAES256GCM(key)
+	if err != nil {
+		return
+	}
+
+	// Encrypt message
+	ciphertext = aead.Seal(nil, nonce, plaintext, additional)
+	// Do not zero nonce memory - must be included post-encryption
+	return
+}
+
+// Decrypts provided cipher text using provided parameters using AES-256-GCM AEAD cipher
+// Zeroes both key and nonce after decryption
+func DecryptAES256GCM(ciphertext, key, nonce, additional []byte) (plaintext []byte, err error) {
+	aead, err := newAES256GCM(key)
+	if err != nil {
+		return
+	}
+
+	// Decrypt message
+	plaintext, err = aead.Open(nil, nonce, ciphertext, additional)
+	crypto.Memzero(nonce) // Kill nonces' memory
+	if err != nil {
+		err = fmt.Errorf("failed decryption of cipher text: %w", err)
+		return
+	}
+
+	return
+}
+
+// Creates cipher from key and zeroes key memory
+func newAES256GCM(key []byte) (aead cipher.AEAD, err error) {
+	if len(key) != AES256KeySize {
+		crypto.Memzero(key)
+		err = fmt.Errorf("failed creation of AEAD: key must be %d bytes (got %d bytes)", AES256KeySize, len(key))
+		return
+	}
+
+	block, err := aes.NewCipher(key)
+	crypto.Memzero(key) // Kill keys' memory (cipher holds its own expanded copy)
+	if err != nil {
+		err = fmt.Errorf("failed creation of AEAD: %w", err)
+		return
+	}
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		err = fmt.Errorf("failed creation of AEAD: %w", err)
+		return
+	}
+	return
+}
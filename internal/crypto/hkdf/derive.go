@@ -4,6 +4,7 @@ package hkdf
 import (
 	"crypto/sha512"
 	"fmt"
+	"hash"
 	"sdsyslog/internal/crypto"
 
 	"golang.org/x/crypto/hkdf"
@@ -14,8 +15,20 @@ import (
 // Returned key length is of the keySize input
 // Secret and salt is zeroed after key creation
 func DeriveKey(secret, salt []byte, namespace string, keySize int) (secureKey []byte, err error) {
+	secureKey, err = deriveKeyWithHash(sha512.New, secret, salt, namespace, keySize)
+	return
+}
+
+// Same as DeriveKey using the SHA-384 hash function
+func DeriveKeySHA384(secret, salt []byte, namespace string, keySize int) (secureKey []byte, err error) {
+	secureKey, err = deriveKeyWithHash(sha512.New384, secret, salt, namespace, keySize)
+	return
+}
+
+// Same as DeriveKey using the supplied hash function
+func deriveKeyWithHash(hashFunc func() hash.Hash, secret, salt []byte, namespace string, keySize int) (secureKey []byte, err error) {
 	info := []byte(namespace)
-	deriver := hkdf.New(sha512.New, secret, salt, info)
+	deriver := hkdf.New(hashFunc, secret, salt, info)
 
 	secureKey = make([]byte, keySize)
 
@@ -0,0 +1,66 @@
+// Central crypto functions for Key Encapsulation Mechanisms (KEM)
+package kem
+
+import (
+	"crypto/mlkem"
+	"fmt"
+)
+
+const (
+	MLKEM768SeedSize       int = mlkem.SeedSize                // Persistent private key (decapsulation key seed)
+	MLKEM768PublicKeySize  int = mlkem.EncapsulationKeySize768 // Persistent public key (encapsulation key)
+	MLKEM768CiphertextSize int = mlkem.CiphertextSize768       // Encapsulated key sent over the wire
+	MLKEM768SharedKeySize  int = mlkem.SharedKeySize           // Shared secret recovered by both sides
+)
+
+// Creates persistent key pair using ML-KEM-768
+// Private key is the 64 byte seed form of the decapsulation key
+func CreatePersistentKey() (private, public []byte, err error) {
+	decapsulationKey, err := mlkem.GenerateKey768()
+	if err != nil {
+		err = fmt.Errorf("failed to generate decapsulation key: %w", err)
+		return
+	}
+	private = decapsulationKey.Bytes()
+	public = decapsulationKey.EncapsulationKey().Bytes()
+	return
+}
+
+// Using existing private key seed (ML-KEM-768) to derive the associated public key
+func DerivePersistentPublicKey(private []byte) (public []byte, err error) {
+	decapsulationKey, err := mlkem.NewDecapsulationKey768(private)
+	if err != nil {
+		err = fmt.Errorf("failed to load decapsulation key: %w", err)
+		return
+	}
+	public = decapsulationKey.EncapsulationKey().Bytes()
+	return
+}
+
+// Uses supplied public key to create a shared secret and its encapsulation (ciphertext)
+// Meant for use on sender side
+func Encapsulate(publicKey []byte) (sharedSecret, encapsulatedKey []byte, err error) {
+	encapsulationKey, err := mlkem.NewEncapsulationKey768(publicKey)
+	if err != nil {
+		err = fmt.Errorf("failed to load encapsulation key: %w", err)
+		return
+	}
+	sharedSecret, encapsulatedKey = encapsulationKey.Encapsulate()
+	return
+}
+
+// Uses supplied private key seed and encapsulated key to recover shared secret
+// Meant for use on receiver side
+func Decapsulate(privateKey, encapsulatedKey []byte) (sharedSecret []byte, err error) {
+	decapsulationKey, err := mlkem.NewDecapsulationKey768(privateKey)
+	if err != nil {
+		err = fmt.Errorf("failed to load decapsulation key: %w", err)
+		return
+	}
+	sharedSecret, err = decapsulationKey.Decapsulate(encapsulatedKey)
+	if err != nil {
+		err = fmt.Errorf("failed to decapsulate shared secret: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package kem
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncapsulate(t *testing.T) {
+	// Generate persistent key pair for receiver
+	private, public, err := CreatePersistentKey()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(private) != MLKEM768SeedSize {
+		t.Errorf("Expected private key length of %d bytes, but got %d bytes", MLKEM768SeedSize, len(private))
+	}
+	if len(public) != MLKEM768PublicKeySize {
+		t.Errorf("Expected public key length of %d bytes, but got %d bytes", MLKEM768PublicKeySize, len(public))
+	}
+
+	derivedPublic, err := DerivePersistentPublicKey(private)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !bytes.Equal(derivedPublic, public) {
+		t.Errorf("Derived public key does not match generated public key")
+	}
+
+	// Sender side
+	sharedSecret, encapsulatedKey, err := Encapsulate(public)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(sharedSecret) != MLKEM768SharedKeySize {
+		t.Errorf("Expected shared secret length of %d bytes, but got %d bytes", MLKEM768SharedKeySize, len(sharedSecret))
+	}
+	if len(encapsulatedKey) != MLKEM768CiphertextSize {
+		t.Errorf("Expected encapsulated key length of %d bytes, but got %d bytes", MLKEM768CiphertextSize, len(encapsulatedKey))
+	}
+
+	// Receiver side
+	receiverSharedSecret, err := Decapsulate(private, encapsulatedKey)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !bytes.Equal(sharedSecret, receiverSharedSecret) {
+		t.Errorf("Shared secret does not match between sender and receiver")
+	}
+
+	// Invalid inputs
+	_, _, err = Encapsulate(public[:len(public)-1])
+	if err == nil {
+		t.Errorf("Expected error for truncated public key")
+	}
+	_, err = Decapsulate(private, encapsulatedKey[:len(encapsulatedKey)-1])
+	if err == nil {
+		t.Errorf("Expected error for truncated encapsulated key")
+	}
+}
@@ -0,0 +1,112 @@
+package wrappers
+
+import (
+	"fmt"
+	"sdsyslog/internal/crypto"
+	"sdsyslog/pkg/crypto/registry"
+	"sync/atomic"
+	"time"
+)
+
+// Receiver private key with an optional validity window (zero times are unbounded)
+type DecryptionKey struct {
+	Name       string
+	PrivateKey []byte
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	Decrypted  atomic.Uint64 // Payloads decrypted with this key (cleared by metric collection)
+	Failed     atomic.Uint64 // Decryption attempts with this key that failed (cleared by metric collection)
+}
+
+// Reports whether the key may be used for decryption at the given time
+func (key *DecryptionKey) IsValidAt(now time.Time) (valid bool) {
+	if !key.ValidFrom.IsZero() && now.Before(key.ValidFrom) {
+		return
+	}
+	if !key.ValidUntil.IsZero() && now.After(key.ValidUntil) {
+		return
+	}
+	valid = true
+	return
+}
+
+// Sets up decryption wrapper function with multiple private keys (for key rotation).
+// Each payload is tried against every key that is inside its validity window and usable by the payloads' suite.
+// The key that last decrypted a payload is tried first, so only payloads encrypted for other keys pay for extra attempts.
+// Does not set the function if no keys are provided. Will throw error if function is not initialized and no keys are provided.
+func SetupDecryptInnerPayloadKeys(keys []*DecryptionKey) (err error) {
+	if len(keys) == 0 {
+		if DecryptInnerPayload == nil {
+			err = fmt.Errorf("provided no private keys and decryption function is not already initialized")
+		}
+		return
+	}
+	for _, key := range keys {
+		if len(key.PrivateKey) == 0 || crypto.IsZero(key.PrivateKey) {
+			err = fmt.Errorf("private key %q empty: all bytes are zero", key.Name)
+			return
+		}
+		if !key.ValidFrom.IsZero() && !key.ValidUntil.IsZero() && !key.ValidUntil.After(key.ValidFrom) {
+			err = fmt.Errorf("private key %q validity window ends before it starts", key.Name)
+			return
+		}
+	}
+
+	var lastUsed atomic.Int32 // Index of key hint
+
+	DecryptInnerPayload = func(ciphertext, ephemeralPub, nonce []byte, suiteID uint8) (innerPayload []byte, err error) {
+		suite, valid := registry.GetSuiteInfo(suiteID)
+		if !valid {
+			err = fmt.Errorf("invalid crypto suite ID %d", suiteID)
+			return
+		}
+
+		now := time.Now()
+		hint := int(lastUsed.Load())
+
+		var attempts int
+		var lastErr error
+		for offset := range keys {
+			index := (hint + offset) % len(keys)
+			key := keys[index]
+
+			if !key.IsValidAt(now) {
+				continue
+			}
+			if suite.ValidateKey(key.PrivateKey) != nil {
+				// Key belongs to a different suite family (e.g. during a suite migration)
+				continue
+			}
+
+			// Decryption zeroes the nonce, each attempt needs its own copy
+			nonceCopy := make([]byte, len(nonce))
+			copy(nonceCopy, nonce)
+
+			attempts++
+			innerPayload, lastErr = suite.Decrypt(key.PrivateKey, ciphertext, ephemeralPub, nonceCopy)
+			if lastErr != nil {
+				key.Failed.Add(1)
+				continue
+			}
+
+			key.Decrypted.Add(1)
+			if index != hint {
+				lastUsed.Store(int32(index))
+			}
+			crypto.Memzero(nonce)
+			return
+		}
+		crypto.Memzero(nonce)
+
+		switch attempts {
+		case 0:
+			err = fmt.Errorf("no current private key is usable with crypto suite ID %d", suiteID)
+		case 1:
+			err = lastErr
+		default:
+			err = fmt.Errorf("no current private key decrypted payload (tried %d keys): %w", attempts, lastErr)
+		}
+		return
+	}
+	return
+}
@@ -0,0 +1,174 @@
+package wrappers
+
+import (
+	"bytes"
+	"sdsyslog/internal/tests/utils"
+	"sdsyslog/pkg/crypto/registry"
+	"testing"
+	"time"
+)
+
+func TestDecryptInnerPayloadKeys(t *testing.T) {
+	now := time.Now()
+
+	newKeyPair := func(t *testing.T, suiteID uint8) (priv, pub []byte) {
+		info, validID := registry.GetSuiteInfo(suiteID)
+		if !validID {
+			t.Fatalf("invalid suite ID %d", suiteID)
+		}
+		priv, pub, err := info.NewKey()
+		if err != nil {
+			t.Fatalf("failed to generate keys: %v", err)
+		}
+		return
+	}
+
+	oldPriv, oldPub := newKeyPair(t, 1)
+	newPriv, newPub := newKeyPair(t, 1)
+	pqPriv, pqPub := newKeyPair(t, 3)
+	_, unknownPub := newKeyPair(t, 1)
+
+	tests := []struct {
+		name             string
+		keys             []*DecryptionKey
+		suiteID          uint8
+		senderPub        []byte
+		expectDecryptKey string // Name of key expected to decrypt
+		expectedSetupErr string
+		expectedDecrErr  string
+	}{
+		{
+			name: "old key still accepted during rotation",
+			keys: []*DecryptionKey{
+				{Name: "new", PrivateKey: newPriv},
+				{Name: "old", PrivateKey: oldPriv},
+			},
+			suiteID:          1,
+			senderPub:        oldPub,
+			expectDecryptKey: "old",
+		},
+		{
+			name: "new key",
+			keys: []*DecryptionKey{
+				{Name: "old", PrivateKey: oldPriv},
+				{Name: "new", PrivateKey: newPriv},
+			},
+			suiteID:          1,
+			senderPub:        newPub,
+			expectDecryptKey: "new",
+		},
+		{
+			name: "expired key is not tried",
+			keys: []*DecryptionKey{
+				{Name: "new", PrivateKey: newPriv},
+				{Name: "old", PrivateKey: oldPriv, ValidUntil: now.Add(-time.Hour)},
+			},
+			suiteID:         1,
+			senderPub:       oldPub,
+			expectedDecrErr: "failed decryption of cipher text",
+		},
+		{
+			name: "key not yet valid",
+			keys: []*DecryptionKey{
+				{Name: "new", PrivateKey: newPriv, ValidFrom: now.Add(time.Hour)},
+			},
+			suiteID:         1,
+			senderPub:       newPub,
+			expectedDecrErr: "no current private key is usable with crypto suite ID 1",
+		},
+		{
+			name: "key inside validity window",
+			keys: []*DecryptionKey{
+				{Name: "new", PrivateKey: newPriv, ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+			},
+			suiteID:          1,
+			senderPub:        newPub,
+			expectDecryptKey: "new",
+		},
+		{
+			name: "keys for other suites are skipped",
+			keys: []*DecryptionKey{
+				{Name: "classical", PrivateKey: oldPriv},
+				{Name: "post-quantum", PrivateKey: pqPriv},
+			},
+			suiteID:          3,
+			senderPub:        pqPub,
+			expectDecryptKey: "post-quantum",
+		},
+		{
+			name: "unknown sender key",
+			keys: []*DecryptionKey{
+				{Name: "old", PrivateKey: oldPriv},
+				{Name: "new", PrivateKey: newPriv},
+			},
+			suiteID:         1,
+			senderPub:       unknownPub,
+			expectedDecrErr: "no current private key decrypted payload (tried 2 keys)",
+		},
+		{
+			name: "inverted validity window",
+			keys: []*DecryptionKey{
+				{Name: "bad", PrivateKey: oldPriv, ValidFrom: now, ValidUntil: now.Add(-time.Hour)},
+			},
+			expectedSetupErr: "validity window ends before it starts",
+		},
+		{
+			name: "empty key",
+			keys: []*DecryptionKey{
+				{Name: "empty", PrivateKey: make([]byte, 32)},
+			},
+			expectedSetupErr: "private key \"empty\" empty",
+		},
+	}
+
+	payload := []byte("rotating keys")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DecryptInnerPayload = nil
+
+			err := SetupDecryptInnerPayloadKeys(tt.keys)
+			matches, err := utils.MatchErrorString(err, tt.expectedSetupErr)
+			if err != nil {
+				t.Fatalf("setup: %v", err)
+			} else if matches {
+				return
+			}
+
+			err = SetupEncryptInnerPayload(tt.senderPub)
+			if err != nil {
+				t.Fatalf("setup encryption: %v", err)
+			}
+
+			// Twice to exercise the last used key hint
+			for range 2 {
+				ciphertext, ephemeralPub, nonce, err := EncryptInnerPayload(payload, tt.suiteID)
+				if err != nil {
+					t.Fatalf("encrypt: %v", err)
+				}
+
+				decrypted, err := DecryptInnerPayload(ciphertext, ephemeralPub, nonce, tt.suiteID)
+				matches, err = utils.MatchErrorString(err, tt.expectedDecrErr)
+				if err != nil {
+					t.Fatalf("decrypt: %v", err)
+				} else if matches {
+					return
+				}
+
+				if !bytes.Equal(decrypted, payload) {
+					t.Fatalf("expected payload %q, got %q", payload, decrypted)
+				}
+			}
+
+			for _, key := range tt.keys {
+				var expected uint64
+				if key.Name == tt.expectDecryptKey {
+					expected = 2
+				}
+				if got := key.Decrypted.Load(); got != expected {
+					t.Errorf("key %q: expected %d decryptions, got %d", key.Name, expected, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package filtering
+
+// Syslog severity names and common application level aliases to codes, lower codes are more severe.
+// Single table for all severity names (syslog severity normalization uses it too).
+var severityCodes = map[string]int{
+	"emerg":       0,
+	"emergency":   0,
+	"panic":       0,
+	"alert":       1,
+	"crit":        2,
+	"critical":    2,
+	"fatal":       2,
+	"err":         3,
+	"error":       3,
+	"warning":     4,
+	"warn":        4,
+	"notice":      5,
+	"info":        6,
+	"information": 6,
+	"debug":       7,
+	"trace":       7,
+}
@@ -3,6 +3,9 @@ package filtering
 
 import (
 	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 // Checks if supplied string matches the filter
@@ -33,22 +36,109 @@ func (filter Filter) Match(input []byte) (matches bool) {
 		return !filter.Not.Match(input)
 	}
 
+	if filter.Regex != "" {
+		regex := filter.compiledRegex
+		if regex == nil {
+			// Not validated, compile on every use
+			var err error
+			regex, err = filter.compileRegex()
+			if err != nil {
+				return
+			}
+		}
+		return regex.Match(input)
+	}
+
+	if filter.SeverityAtLeast != "" {
+		threshold, valid := SeverityCode(filter.SeverityAtLeast)
+		if !valid {
+			return
+		}
+		code, valid := SeverityCode(string(input))
+		return valid && code <= threshold
+	}
+
+	if filter.Equal != nil || filter.GreaterThan != nil || filter.GreaterOrEqual != nil ||
+		filter.LessThan != nil || filter.LessOrEqual != nil {
+		return filter.matchNumber(input)
+	}
+
+	if filter.IgnoreCase {
+		input = bytes.ToLower(input)
+	}
+
 	if filter.Prefix != "" {
-		return bytes.HasPrefix(input, []byte(filter.Prefix))
+		return bytes.HasPrefix(input, filter.leafText(filter.Prefix))
 	}
 
 	if filter.Suffix != "" {
-		return bytes.HasSuffix(input, []byte(filter.Suffix))
+		return bytes.HasSuffix(input, filter.leafText(filter.Suffix))
 	}
 
 	if filter.Contains != "" {
-		return bytes.Contains(input, []byte(filter.Contains))
+		return bytes.Contains(input, filter.leafText(filter.Contains))
 	}
 
 	if filter.Exact != "" {
-		return bytes.Equal(input, []byte(filter.Exact))
+		return bytes.Equal(input, filter.leafText(filter.Exact))
 	}
 
 	matches = false
 	return
 }
+
+// Leaf text in the same case as the (possibly lowered) input
+func (filter Filter) leafText(text string) (leaf []byte) {
+	if filter.IgnoreCase {
+		text = strings.ToLower(text)
+	}
+	leaf = []byte(text)
+	return
+}
+
+// Compares numeric input against the configured number
+func (filter Filter) matchNumber(input []byte) (matches bool) {
+	number, err := strconv.ParseFloat(string(bytes.TrimSpace(input)), 64)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case filter.Equal != nil:
+		matches = number == *filter.Equal
+	case filter.GreaterThan != nil:
+		matches = number > *filter.GreaterThan
+	case filter.GreaterOrEqual != nil:
+		matches = number >= *filter.GreaterOrEqual
+	case filter.LessThan != nil:
+		matches = number < *filter.LessThan
+	case filter.LessOrEqual != nil:
+		matches = number <= *filter.LessOrEqual
+	}
+	return
+}
+
+// Compiles regex leaf (with case-insensitive flag when requested)
+func (filter Filter) compileRegex() (regex *regexp.Regexp, err error) {
+	expression := filter.Regex
+	if filter.IgnoreCase {
+		expression = "(?i)" + expression
+	}
+	regex, err = regexp.Compile(expression)
+	return
+}
+
+// Severity code from name or alias (case-insensitive) or numeric code
+func SeverityCode(severity string) (code int, valid bool) {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	code, valid = severityCodes[severity]
+	if valid {
+		return
+	}
+
+	code, err := strconv.Atoi(severity)
+	if err == nil && code >= 0 && code <= 7 {
+		valid = true
+	}
+	return
+}
@@ -159,7 +159,205 @@ func TestTrimDurationPrecision(t *testing.T) {
 			expectedMatch: true,
 		},
 
+		// Regex
+		{
+			name:  "regex match",
+			input: "Failed password for root from 10.0.0.1 port 22",
+			filter: Filter{
+				Regex: `^Failed password for \S+ from [0-9.]+`,
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "regex non-match",
+			input: "Accepted password for root",
+			filter: Filter{
+				Regex: `^Failed`,
+			},
+			expectedMatch: false,
+		},
+		{
+			name:  "regex ignore case",
+			input: "FAILED login",
+			filter: Filter{
+				Regex:      `^failed`,
+				IgnoreCase: true,
+			},
+			expectedMatch: true,
+		},
+
+		// Case insensitive
+		{
+			name:  "exact ignore case",
+			input: "SSHD",
+			filter: Filter{
+				Exact:      "sshd",
+				IgnoreCase: true,
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "contains ignore case",
+			input: "Disk Full on sda",
+			filter: Filter{
+				Contains:   "DISK FULL",
+				IgnoreCase: true,
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "exact case sensitive by default",
+			input: "SSHD",
+			filter: Filter{
+				Exact: "sshd",
+			},
+			expectedMatch: false,
+		},
+
+		// Numeric
+		{
+			name:  "greater than",
+			input: "312.5",
+			filter: Filter{
+				GreaterThan: floatPtr(250),
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "less or equal",
+			input: "250",
+			filter: Filter{
+				LessOrEqual: floatPtr(250),
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "equal numeric forms",
+			input: "4.0",
+			filter: Filter{
+				Equal: floatPtr(4),
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "numeric non-number input",
+			input: "many",
+			filter: Filter{
+				GreaterOrEqual: floatPtr(0),
+			},
+			expectedMatch: false,
+		},
+		{
+			name:  "numeric in range",
+			input: "-3",
+			filter: Filter{
+				And: []Filter{
+					{GreaterThan: floatPtr(-10)},
+					{LessThan: floatPtr(0)},
+				},
+			},
+			expectedMatch: true,
+		},
+
+		// Severity
+		{
+			name:  "severity more severe",
+			input: "err",
+			filter: Filter{
+				SeverityAtLeast: "warning",
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "severity equal",
+			input: "WARNING",
+			filter: Filter{
+				SeverityAtLeast: "warn",
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "severity less severe",
+			input: "info",
+			filter: Filter{
+				SeverityAtLeast: "warning",
+			},
+			expectedMatch: false,
+		},
+		{
+			name:  "severity numeric code",
+			input: "2",
+			filter: Filter{
+				SeverityAtLeast: "4",
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "severity application level aliases",
+			input: "Critical",
+			filter: Filter{
+				SeverityAtLeast: "fatal",
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "severity trace is debug",
+			input: "trace",
+			filter: Filter{
+				SeverityAtLeast: "debug",
+			},
+			expectedMatch: true,
+		},
+		{
+			name:  "severity unknown input",
+			input: "loud",
+			filter: Filter{
+				SeverityAtLeast: "debug",
+			},
+			expectedMatch: false,
+		},
+
 		// Validation errors
+		{
+			name: "invalid regex",
+			filter: Filter{
+				Regex: "(unclosed",
+			},
+			expectedValidationError: "invalid regex",
+		},
+		{
+			name: "nested invalid regex",
+			filter: Filter{
+				Or: []Filter{
+					{Exact: "a"},
+					{Regex: "[z-a]"},
+				},
+			},
+			expectedValidationError: "or[1]: invalid regex",
+		},
+		{
+			name: "unknown severity",
+			filter: Filter{
+				SeverityAtLeast: "loud",
+			},
+			expectedValidationError: "unknown severity",
+		},
+		{
+			name: "ignore case on numeric leaf",
+			filter: Filter{
+				GreaterThan: floatPtr(1),
+				IgnoreCase:  true,
+			},
+			expectedValidationError: "ignoreCase can only be used with",
+		},
+		{
+			name: "regex and exact",
+			filter: Filter{
+				Regex: "a",
+				Exact: "a",
+			},
+			expectedValidationError: "filter node must have exactly one operator/leaf",
+		},
 		{
 			name:                    "empty node",
 			filter:                  Filter{},
@@ -221,3 +419,35 @@ func TestTrimDurationPrecision(t *testing.T) {
 		})
 	}
 }
+
+func TestRegexCompiledOnce(t *testing.T) {
+	filter := &Filter{
+		And: []Filter{
+			{Regex: `^a+$`},
+		},
+	}
+	err := filter.Validate()
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if filter.And[0].compiledRegex == nil {
+		t.Fatalf("expected nested regex to be compiled by validation")
+	}
+
+	// Copies share the compiled expression
+	filterCopy := *filter
+	if !filterCopy.Match([]byte("aaa")) || filterCopy.Match([]byte("aab")) {
+		t.Errorf("unexpected match result from compiled regex")
+	}
+
+	// Unvalidated filters still match
+	unvalidated := Filter{Regex: `^a+$`}
+	if !unvalidated.Match([]byte("aaa")) {
+		t.Errorf("expected unvalidated regex filter to match")
+	}
+}
+
+func floatPtr(value float64) (ptr *float64) {
+	ptr = &value
+	return
+}
@@ -1,5 +1,7 @@
 package filtering
 
+import "regexp"
+
 type Filter struct {
 	And []Filter `json:"and,omitempty"`
 	Or  []Filter `json:"or,omitempty"`
@@ -9,4 +11,20 @@ type Filter struct {
 	Suffix   string `json:"suffix,omitempty"`
 	Contains string `json:"contains,omitempty"`
 	Exact    string `json:"exact,omitempty"`
+	Regex    string `json:"regex,omitempty"` // RE2 syntax, unanchored (use ^ and $ to match whole input)
+
+	// Numeric comparisons (input is parsed as a number, non-numeric input never matches)
+	Equal          *float64 `json:"eq,omitempty"`
+	GreaterThan    *float64 `json:"gt,omitempty"`
+	GreaterOrEqual *float64 `json:"gte,omitempty"`
+	LessThan       *float64 `json:"lt,omitempty"`
+	LessOrEqual    *float64 `json:"lte,omitempty"`
+
+	// Syslog severity (name or code), matches this severity and anything more severe
+	SeverityAtLeast string `json:"severityAtLeast,omitempty"`
+
+	// Modifier for prefix/suffix/contains/exact/regex leaves
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+
+	compiledRegex *regexp.Regexp // Set by Validate
 }
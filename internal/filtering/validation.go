@@ -2,8 +2,9 @@ package filtering
 
 import "fmt"
 
-// Ensures filter configuration is valid
-func (filter Filter) Validate() (err error) {
+// Ensures filter configuration is valid and compiles regex leaves.
+// Must be called on the filter in place (not a copy), so matching reuses the compiled expressions.
+func (filter *Filter) Validate() (err error) {
 	// Count how many fields are set
 	count := 0
 	if len(filter.And) > 0 {
@@ -27,6 +28,27 @@ func (filter Filter) Validate() (err error) {
 	if filter.Exact != "" {
 		count++
 	}
+	if filter.Regex != "" {
+		count++
+	}
+	if filter.Equal != nil {
+		count++
+	}
+	if filter.GreaterThan != nil {
+		count++
+	}
+	if filter.GreaterOrEqual != nil {
+		count++
+	}
+	if filter.LessThan != nil {
+		count++
+	}
+	if filter.LessOrEqual != nil {
+		count++
+	}
+	if filter.SeverityAtLeast != "" {
+		count++
+	}
 
 	// Exactly one field must be set
 	if count == 0 {
@@ -38,24 +60,26 @@ func (filter Filter) Validate() (err error) {
 		return
 	}
 
-	// Recursively validate children
-	if len(filter.And) > 0 {
-		for i, sub := range filter.And {
-			err = sub.Validate()
-			if err != nil {
-				err = fmt.Errorf("and[%d]: %w", i, err)
-				return
-			}
+	if filter.IgnoreCase && filter.Prefix == "" && filter.Suffix == "" && filter.Contains == "" &&
+		filter.Exact == "" && filter.Regex == "" {
+		err = fmt.Errorf("ignoreCase can only be used with prefix, suffix, contains, exact, or regex")
+		return
+	}
+
+	// Recursively validate children (in place)
+	for i := range filter.And {
+		err = filter.And[i].Validate()
+		if err != nil {
+			err = fmt.Errorf("and[%d]: %w", i, err)
+			return
 		}
 	}
 
-	if len(filter.Or) > 0 {
-		for i, sub := range filter.Or {
-			err = sub.Validate()
-			if err != nil {
-				err = fmt.Errorf("or[%d]: %w", i, err)
-				return
-			}
+	for i := range filter.Or {
+		err = filter.Or[i].Validate()
+		if err != nil {
+			err = fmt.Errorf("or[%d]: %w", i, err)
+			return
 		}
 	}
 
@@ -67,6 +91,22 @@ func (filter Filter) Validate() (err error) {
 		}
 	}
 
-	// Leaf nodes (Prefix, Suffix, Contains, Exact) require no further validation
+	if filter.Regex != "" {
+		filter.compiledRegex, err = filter.compileRegex()
+		if err != nil {
+			err = fmt.Errorf("invalid regex %q: %w", filter.Regex, err)
+			return
+		}
+	}
+
+	if filter.SeverityAtLeast != "" {
+		_, valid := SeverityCode(filter.SeverityAtLeast)
+		if !valid {
+			err = fmt.Errorf("unknown severity %q", filter.SeverityAtLeast)
+			return
+		}
+	}
+
+	// Remaining leaf nodes (Prefix, Suffix, Contains, Exact, numeric) require no further validation
 	return
 }
@@ -0,0 +1,279 @@
+package audit
+
+import "time"
+
+const (
+	// Custom fields
+	CFserial  string = "AuditSerial"  // Serial number shared by the records of an event
+	CFtype    string = "AuditType"    // Type of the first record of an event
+	CFrecords string = "AuditRecords" // Types of all records of an event (comma separated)
+
+	// Common field values of audit events
+	appName         string = "audit"
+	defaultFacility string = "authpriv"
+	defaultSeverity string = "info"
+	anomalySeverity string = "warning" // ANOM_* records
+
+	// Multicast group receiving a copy of all kernel audit records (needs CAP_AUDIT_READ)
+	netlinkGroupReadLog uint32 = 1
+
+	// Events missing their end-of-event record are sent after this long
+	defaultEventTimeout time.Duration = 2 * time.Second
+	// Events waiting for more records before the oldest one is sent early
+	maxPendingEvents int = 256
+	// Longest wait for queue space when sending pending events at shutdown
+	shutdownFlushTimeout time.Duration = 5 * time.Second
+
+	// Each netlink datagram holds one record (header and text up to 8970 bytes)
+	maxDatagramSize int = 1 << 16
+	// Requested socket receive buffer (records are lost when it fills up)
+	receiveBufferSize int = 1 << 22
+	// Records received but not yet grouped
+	recordBacklog int = 1024
+
+	// Lower type numbers are audit control messages
+	firstRecordType uint16 = 1100
+
+	// End of multi-record event marker
+	eoeType string = "EOE"
+
+	// Records of kernel events (syscall, SELinux, integrity) lie between user message ranges and arrive in multiple parts
+	firstMultipartType uint16 = 1300
+	firstUserMsg2Type  uint16 = 2100
+	lastUserMsg2Type   uint16 = 2999
+)
+
+// Record type names as written by auditd
+var recordTypeNames = map[uint16]string{
+	1100: "USER_AUTH",
+	1101: "USER_ACCT",
+	1102: "USER_MGMT",
+	1103: "CRED_ACQ",
+	1104: "CRED_DISP",
+	1105: "USER_START",
+	1106: "USER_END",
+	1107: "USER_AVC",
+	1108: "USER_CHAUTHTOK",
+	1109: "USER_ERR",
+	1110: "CRED_REFR",
+	1111: "USYS_CONFIG",
+	1112: "USER_LOGIN",
+	1113: "USER_LOGOUT",
+	1114: "ADD_USER",
+	1115: "DEL_USER",
+	1116: "ADD_GROUP",
+	1117: "DEL_GROUP",
+	1118: "DAC_CHECK",
+	1119: "CHGRP_ID",
+	1120: "TEST",
+	1121: "TRUSTED_APP",
+	1122: "USER_SELINUX_ERR",
+	1123: "USER_CMD",
+	1124: "USER_TTY",
+	1125: "CHUSER_ID",
+	1126: "GRP_AUTH",
+	1127: "SYSTEM_BOOT",
+	1128: "SYSTEM_SHUTDOWN",
+	1129: "SYSTEM_RUNLEVEL",
+	1130: "SERVICE_START",
+	1131: "SERVICE_STOP",
+	1132: "GRP_MGMT",
+	1133: "GRP_CHAUTHTOK",
+	1134: "MAC_CHECK",
+	1135: "ACCT_LOCK",
+	1136: "ACCT_UNLOCK",
+	1137: "USER_DEVICE",
+	1138: "SOFTWARE_UPDATE",
+	1200: "DAEMON_START",
+	1201: "DAEMON_END",
+	1202: "DAEMON_ABORT",
+	1203: "DAEMON_CONFIG",
+	1204: "DAEMON_RECONFIG",
+	1205: "DAEMON_ROTATE",
+	1206: "DAEMON_RESUME",
+	1207: "DAEMON_ACCEPT",
+	1208: "DAEMON_CLOSE",
+	1209: "DAEMON_ERR",
+	1300: "SYSCALL",
+	1302: "PATH",
+	1303: "IPC",
+	1304: "SOCKETCALL",
+	1305: "CONFIG_CHANGE",
+	1306: "SOCKADDR",
+	1307: "CWD",
+	1309: "EXECVE",
+	1311: "IPC_SET_PERM",
+	1312: "MQ_OPEN",
+	1313: "MQ_SENDRECV",
+	1314: "MQ_NOTIFY",
+	1315: "MQ_GETSETATTR",
+	1316: "KERNEL_OTHER",
+	1317: "FD_PAIR",
+	1318: "OBJ_PID",
+	1319: "TTY",
+	1320: "EOE",
+	1321: "BPRM_FCAPS",
+	1322: "CAPSET",
+	1323: "MMAP",
+	1324: "NETFILTER_PKT",
+	1325: "NETFILTER_CFG",
+	1326: "SECCOMP",
+	1327: "PROCTITLE",
+	1328: "FEATURE_CHANGE",
+	1329: "REPLACE",
+	1330: "KERN_MODULE",
+	1331: "FANOTIFY",
+	1332: "TIME_INJOFFSET",
+	1333: "TIME_ADJNTPVAL",
+	1334: "BPF",
+	1335: "EVENT_LISTENER",
+	1336: "URINGOP",
+	1337: "OPENAT2",
+	1338: "DM_CTRL",
+	1339: "DM_EVENT",
+	1400: "AVC",
+	1401: "SELINUX_ERR",
+	1402: "AVC_PATH",
+	1403: "MAC_POLICY_LOAD",
+	1404: "MAC_STATUS",
+	1405: "MAC_CONFIG_CHANGE",
+	1406: "MAC_UNLBL_ALLOW",
+	1407: "MAC_CIPSOV4_ADD",
+	1408: "MAC_CIPSOV4_DEL",
+	1409: "MAC_MAP_ADD",
+	1410: "MAC_MAP_DEL",
+	1411: "MAC_IPSEC_ADDSA",
+	1412: "MAC_IPSEC_DELSA",
+	1413: "MAC_IPSEC_ADDSPD",
+	1414: "MAC_IPSEC_DELSPD",
+	1415: "MAC_IPSEC_EVENT",
+	1416: "MAC_UNLBL_STCADD",
+	1417: "MAC_UNLBL_STCDEL",
+	1418: "MAC_CALIPSO_ADD",
+	1419: "MAC_CALIPSO_DEL",
+	1420: "IPE_ACCESS",
+	1421: "IPE_CONFIG_CHANGE",
+	1422: "IPE_POLICY_LOAD",
+	1423: "LANDLOCK_ACCESS",
+	1424: "LANDLOCK_DOMAIN",
+	1500: "AA",
+	1501: "APPARMOR_AUDIT",
+	1502: "APPARMOR_ALLOWED",
+	1503: "APPARMOR_DENIED",
+	1504: "APPARMOR_HINT",
+	1505: "APPARMOR_STATUS",
+	1506: "APPARMOR_ERROR",
+	1507: "APPARMOR_KILL",
+	1700: "ANOM_PROMISCUOUS",
+	1701: "ANOM_ABEND",
+	1702: "ANOM_LINK",
+	1703: "ANOM_CREAT",
+	1800: "INTEGRITY_DATA",
+	1801: "INTEGRITY_METADATA",
+	1802: "INTEGRITY_STATUS",
+	1803: "INTEGRITY_HASH",
+	1804: "INTEGRITY_PCR",
+	1805: "INTEGRITY_RULE",
+	1806: "INTEGRITY_EVM_XATTR",
+	1807: "INTEGRITY_POLICY_RULE",
+	2100: "ANOM_LOGIN_FAILURES",
+	2101: "ANOM_LOGIN_TIME",
+	2102: "ANOM_LOGIN_SESSIONS",
+	2103: "ANOM_LOGIN_ACCT",
+	2104: "ANOM_LOGIN_LOCATION",
+	2105: "ANOM_MAX_DAC",
+	2106: "ANOM_MAX_MAC",
+	2107: "ANOM_AMTU_FAIL",
+	2108: "ANOM_RBAC_FAIL",
+	2109: "ANOM_RBAC_INTEGRITY_FAIL",
+	2110: "ANOM_CRYPTO_FAIL",
+	2111: "ANOM_ACCESS_FS",
+	2112: "ANOM_EXEC",
+	2113: "ANOM_MK_EXEC",
+	2114: "ANOM_ADD_ACCT",
+	2115: "ANOM_DEL_ACCT",
+	2116: "ANOM_MOD_ACCT",
+	2117: "ANOM_ROOT_TRANS",
+	2118: "ANOM_LOGIN_SERVICE",
+	2119: "ANOM_LOGIN_ROOT",
+	2120: "ANOM_ORIGIN_FAILURES",
+	2121: "ANOM_SESSION",
+	2200: "RESP_ANOMALY",
+	2300: "USER_ROLE_CHANGE",
+	2309: "USER_LABELED_EXPORT",
+	2310: "USER_UNLABELED_EXPORT",
+	2311: "DEV_ALLOC",
+	2312: "DEV_DEALLOC",
+	2313: "FS_RELABEL",
+	2314: "USER_MAC_POLICY_LOAD",
+	2315: "ROLE_MODIFY",
+	2316: "USER_MAC_CONFIG_CHANGE",
+	2317: "USER_MAC_STATUS",
+	2400: "CRYPTO_TEST_USER",
+	2401: "CRYPTO_PARAM_CHANGE_USER",
+	2402: "CRYPTO_LOGIN",
+	2403: "CRYPTO_LOGOUT",
+	2404: "CRYPTO_KEY_USER",
+	2405: "CRYPTO_FAILURE_USER",
+	2406: "CRYPTO_REPLAY_USER",
+	2407: "CRYPTO_SESSION",
+	2408: "CRYPTO_IKE_SA",
+	2409: "CRYPTO_IPSEC_SA",
+	2500: "VIRT_CONTROL",
+	2501: "VIRT_RESOURCE",
+	2502: "VIRT_MACHINE_ID",
+	2503: "VIRT_INTEGRITY_CHECK",
+	2504: "VIRT_CREATE",
+	2505: "VIRT_DESTROY",
+	2506: "VIRT_MIGRATE_IN",
+	2507: "VIRT_MIGRATE_OUT",
+}
+
+// Values decoded from hex when not quoted (untrusted text that contained spaces, quotes, or control characters)
+var encodedKeys = map[string]struct{}{
+	"acct":      {},
+	"cmd":       {},
+	"comm":      {},
+	"cwd":       {},
+	"data":      {},
+	"dir":       {},
+	"exe":       {},
+	"file":      {},
+	"grp":       {},
+	"key":       {},
+	"name":      {},
+	"new-group": {},
+	"old-group": {},
+	"path":      {},
+	"proctitle": {},
+	"root_dir":  {},
+	"vm":        {},
+}
+
+// Values that are decimal numbers (others like arch, mode, or syscall arguments are hex or octal text)
+var numericKeys = map[string]struct{}{
+	"argc":     {},
+	"auid":     {},
+	"egid":     {},
+	"euid":     {},
+	"exit":     {},
+	"fsgid":    {},
+	"fsuid":    {},
+	"gid":      {},
+	"id":       {},
+	"inode":    {},
+	"item":     {},
+	"items":    {},
+	"ogid":     {},
+	"old-auid": {},
+	"old-ses":  {},
+	"ouid":     {},
+	"pid":      {},
+	"ppid":     {},
+	"ses":      {},
+	"sgid":     {},
+	"sig":      {},
+	"suid":     {},
+	"syscall":  {},
+	"uid":      {},
+}
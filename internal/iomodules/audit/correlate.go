@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+func newCorrelator(timeout time.Duration, maxPending int) (new *correlator) {
+	new = &correlator{
+		pending:    make(map[uint64]*pendingEvent),
+		timeout:    timeout,
+		maxPending: maxPending,
+	}
+	return
+}
+
+// Adds a record of the given type number. Returns events completed by it (oldest first).
+func (events *correlator) add(record Record, recordType uint16, now time.Time) (complete [][]Record) {
+	// Single record messages from user space never get an end of event record
+	if !isMultipart(recordType) {
+		if event, exists := events.pending[record.Serial]; exists {
+			delete(events.pending, record.Serial)
+			complete = append(complete, event.records)
+		}
+		complete = append(complete, []Record{record})
+		return
+	}
+
+	event, exists := events.pending[record.Serial]
+	if !exists {
+		// Make room by sending the oldest event early
+		if len(events.pending) >= events.maxPending {
+			complete = append(complete, events.takeOldest())
+		}
+		event = &pendingEvent{started: now}
+		events.pending[record.Serial] = event
+	}
+	event.records = append(event.records, record)
+
+	if record.Type == eoeType {
+		delete(events.pending, record.Serial)
+		complete = append(complete, event.records)
+	}
+	return
+}
+
+// Returns events that waited longer than the timeout for their end (oldest first)
+func (events *correlator) expire(now time.Time) (complete [][]Record) {
+	var expired []uint64
+	for serial, event := range events.pending {
+		if now.Sub(event.started) >= events.timeout {
+			expired = append(expired, serial)
+		}
+	}
+	slices.Sort(expired)
+
+	for _, serial := range expired {
+		complete = append(complete, events.pending[serial].records)
+		delete(events.pending, serial)
+	}
+	return
+}
+
+// Removes and returns all pending events (oldest first)
+func (events *correlator) flush() (complete [][]Record) {
+	serials := slices.Sorted(maps.Keys(events.pending))
+	for _, serial := range serials {
+		complete = append(complete, events.pending[serial].records)
+		delete(events.pending, serial)
+	}
+	return
+}
+
+// Removes and returns the pending event with the lowest serial number
+func (events *correlator) takeOldest() (records []Record) {
+	var oldest uint64
+	first := true
+	for serial := range events.pending {
+		if first || serial < oldest {
+			oldest = serial
+			first = false
+		}
+	}
+	records = events.pending[oldest].records
+	delete(events.pending, oldest)
+	return
+}
+
+// Reports whether records of this type are part of kernel events ending with an end of event record
+func isMultipart(recordType uint16) (multipart bool) {
+	multipart = recordType >= firstMultipartType && (recordType < firstUserMsg2Type || recordType > lastUserMsg2Type)
+	return
+}
@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCorrelator(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	type input struct {
+		recordType uint16
+		serial     uint64
+		after      time.Duration // Time since start the record arrives
+	}
+	tests := []struct {
+		name         string
+		records      []input
+		expireAfter  time.Duration // Zero skips expiry
+		flush        bool          // Send all pending events at the end (like at shutdown)
+		maxPending   int
+		expectedSent [][]uint16 // Record types of sent events in order
+	}{
+		{
+			name: "interleaved events complete at their end record",
+			records: []input{
+				{1300, 10, 0},
+				{1300, 11, 0},
+				{1307, 10, 0},
+				{1307, 11, 0},
+				{1320, 11, 0},
+				{1302, 10, 0},
+				{1320, 10, 0},
+			},
+			maxPending:   maxPendingEvents,
+			expectedSent: [][]uint16{{1300, 1307, 1320}, {1300, 1307, 1302, 1320}},
+		},
+		{
+			name: "user space messages are sent immediately",
+			records: []input{
+				{1300, 20, 0},
+				{1112, 21, 0},
+				{2100, 22, 0},
+			},
+			maxPending:   maxPendingEvents,
+			expectedSent: [][]uint16{{1112}, {2100}},
+		},
+		{
+			name: "events without end record are sent after the timeout",
+			records: []input{
+				{1400, 31, 0},
+				{1300, 30, 0},
+				{1300, 32, 3 * time.Second},
+			},
+			expireAfter:  3 * time.Second,
+			maxPending:   maxPendingEvents,
+			expectedSent: [][]uint16{{1300}, {1400}},
+		},
+		{
+			name: "oldest event is sent early when too many are pending",
+			records: []input{
+				{1300, 41, 0},
+				{1300, 40, 0},
+				{1300, 42, 0},
+			},
+			maxPending:   2,
+			expectedSent: [][]uint16{{1300}},
+		},
+		{
+			name: "pending events are flushed oldest first",
+			records: []input{
+				{1300, 31, 0},
+				{1300, 30, 0},
+				{1302, 30, 0},
+			},
+			flush:        true,
+			maxPending:   maxPendingEvents,
+			expectedSent: [][]uint16{{1300, 1302}, {1300}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := newCorrelator(defaultEventTimeout, tt.maxPending)
+			types := make(map[string]uint16)
+
+			var sent [][]Record
+			for _, in := range tt.records {
+				typeName := recordTypeNames[in.recordType]
+				types[typeName] = in.recordType
+				record := Record{Type: typeName, Serial: in.serial}
+				sent = append(sent, events.add(record, in.recordType, start.Add(in.after))...)
+			}
+			if tt.expireAfter != 0 {
+				sent = append(sent, events.expire(start.Add(tt.expireAfter))...)
+			}
+			if tt.flush {
+				sent = append(sent, events.flush()...)
+				if len(events.pending) != 0 {
+					t.Errorf("expected no pending events after flush, got %d", len(events.pending))
+				}
+			}
+
+			if len(sent) != len(tt.expectedSent) {
+				t.Fatalf("expected %d events, got %d: %+v", len(tt.expectedSent), len(sent), sent)
+			}
+			for index, event := range sent {
+				if len(event) != len(tt.expectedSent[index]) {
+					t.Fatalf("event %d: expected %d records, got %d", index, len(tt.expectedSent[index]), len(event))
+				}
+				for position, record := range event {
+					if types[record.Type] != tt.expectedSent[index][position] {
+						t.Errorf("event %d record %d: expected type %d, got %s", index, position, tt.expectedSent[index][position], record.Type)
+					}
+					if record.Serial != event[0].Serial {
+						t.Errorf("event %d mixes serial numbers %d and %d", index, event[0].Serial, record.Serial)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"strconv"
+	"strings"
+)
+
+// Parses the audit.log lines of one event into a message
+func ParseEvent(text string, localHostname string) (message *protocol.Message, err error) {
+	var records []Record
+	for line := range strings.SplitSeq(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		record, lerr := ParseLine(line)
+		if lerr != nil {
+			err = fmt.Errorf("invalid audit record %q: %w", line, lerr)
+			return
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		err = fmt.Errorf("event has no records")
+		return
+	}
+	message = NewMessage(records, localHostname)
+	return
+}
+
+// Creates one message from the records of an event.
+// Values of the first record are fields by name, values of other records are prefixed with the
+// lower case record type (and the position for repeated types like path.0.name).
+func NewMessage(records []Record, localHostname string) (message *protocol.Message) {
+	primaryPosition := primaryIndex(records)
+	primary := records[primaryPosition]
+
+	message = &protocol.Message{
+		Timestamp: primary.Timestamp,
+		Hostname:  localHostname,
+		Fields:    make(map[string]any),
+	}
+	if primary.Node != "" {
+		message.Hostname = primary.Node
+	}
+
+	typeCounts := make(map[string]int)
+	var types []string
+	var lines []string
+	severity := defaultSeverity
+	for _, record := range records {
+		lines = append(lines, record.Text)
+		if record.Type == eoeType {
+			continue
+		}
+		typeCounts[record.Type]++
+		types = append(types, record.Type)
+		if strings.HasPrefix(record.Type, "ANOM_") {
+			severity = anomalySeverity
+		}
+	}
+
+	message.Fields[iomodules.CFappname] = appName
+	message.Fields[iomodules.CFfacility] = defaultFacility
+	message.Fields[iomodules.CFseverity] = severity
+	message.Fields[iomodules.CFprocessid] = os.Getpid()
+	message.Fields[CFserial] = int64(primary.Serial)
+	message.Fields[CFtype] = primary.Type
+	if recordList := strings.Join(types, ","); len(recordList) <= protocol.MaxCtxValLen {
+		message.Fields[CFrecords] = recordList
+	}
+
+	typePositions := make(map[string]int)
+	for index, record := range records {
+		if record.Type == eoeType {
+			continue
+		}
+
+		var prefix, typeName string
+		if index != primaryPosition {
+			typeName = strings.ToLower(record.Type)
+			prefix = typeName + "."
+			if typeCounts[record.Type] > 1 {
+				prefix += strconv.Itoa(typePositions[record.Type]) + "."
+			}
+			typePositions[record.Type]++
+		}
+
+		for _, value := range record.values {
+			key := prefix + value.key
+			if value.key == typeName && typeCounts[record.Type] == 1 {
+				// Like cwd.cwd or proctitle.proctitle
+				key = typeName
+			}
+			if len(key) > protocol.MaxCtxKeyLen {
+				continue
+			}
+			if text, isText := value.value.(string); isText && len(text) > protocol.MaxCtxValLen {
+				// Kept in the message text
+				continue
+			}
+			if _, exists := message.Fields[key]; exists {
+				continue
+			}
+			message.Fields[key] = value.value
+		}
+	}
+
+	// Process the event is about
+	if pid, isNumber := message.Fields["pid"].(int64); isNumber && pid > 0 {
+		message.Fields[iomodules.CFprocessid] = int(pid)
+	}
+
+	message.Data = []byte(strings.Join(lines, "\n"))
+	return
+}
+
+// Position of the first record that is not an end of event marker
+func primaryIndex(records []Record) (index int) {
+	for position, record := range records {
+		if record.Type != eoeType {
+			index = position
+			return
+		}
+	}
+	return
+}
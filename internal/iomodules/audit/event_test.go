@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"os"
+	"sdsyslog/internal/iomodules"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name              string
+		text              string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedFields    map[string]any
+		absentFields      []string
+		expectErr         bool
+	}{
+		{
+			name: "syscall event",
+			text: `type=SYSCALL msg=audit(1700000000.123:4242): arch=c000003e syscall=59 success=yes exit=0 a0=55d0 a1=55d1 items=2 ppid=1000 pid=1234 auid=1000 uid=0 gid=0 euid=0 ses=3 comm="cat" exe="/usr/bin/cat" subj=unconfined key="exec-watch"
+type=EXECVE msg=audit(1700000000.123:4242): argc=2 a0="cat" a1=2F6574632F736861646F77
+type=CWD msg=audit(1700000000.123:4242): cwd="/root"
+type=PATH msg=audit(1700000000.123:4242): item=0 name="/usr/bin/cat" inode=1311 dev=fd:00 mode=0100755 ouid=0 ogid=0 nametype=NORMAL
+type=PATH msg=audit(1700000000.123:4242): item=1 name=2F6C6962363420646972 inode=2048 nametype=NORMAL
+type=PROCTITLE msg=audit(1700000000.123:4242): proctitle=636174002F6574632F736861646F77`,
+			expectedTimestamp: time.Unix(1700000000, 123000000),
+			expectedHostname:  "localhost",
+			expectedFields: map[string]any{
+				CFserial:              int64(4242),
+				CFtype:                "SYSCALL",
+				CFrecords:             "SYSCALL,EXECVE,CWD,PATH,PATH,PROCTITLE",
+				iomodules.CFappname:   appName,
+				iomodules.CFprocessid: 1234,
+				iomodules.CFfacility:  defaultFacility,
+				iomodules.CFseverity:  defaultSeverity,
+				"syscall":             int64(59),
+				"success":             true,
+				"exit":                int64(0),
+				"arch":                "c000003e",
+				"a0":                  "55d0",
+				"uid":                 int64(0),
+				"auid":                int64(1000),
+				"exe":                 "/usr/bin/cat",
+				"comm":                "cat",
+				"key":                 "exec-watch",
+				"execve.argc":         int64(2),
+				"execve.a1":           "/etc/shadow",
+				"cwd":                 "/root",
+				"path.0.name":         "/usr/bin/cat",
+				"path.0.mode":         "0100755",
+				"path.1.name":         "/lib64 dir",
+				"path.1.inode":        int64(2048),
+				"proctitle":           "cat /etc/shadow",
+			},
+		},
+		{
+			name:             "user space message with node name",
+			text:             `node=web01 type=USER_LOGIN msg=audit(1700000100.500:77): pid=812 uid=0 auid=4294967295 ses=4294967295 msg='op=login acct="alice" exe="/usr/sbin/sshd" hostname=? addr=192.0.2.7 terminal=sshd res=failed'`,
+			expectedHostname: "web01",
+			expectedFields: map[string]any{
+				CFtype:     "USER_LOGIN",
+				"pid":      int64(812),
+				"auid":     int64(4294967295),
+				"op":       "login",
+				"acct":     "alice",
+				"exe":      "/usr/sbin/sshd",
+				"addr":     "192.0.2.7",
+				"terminal": "sshd",
+				"res":      false,
+			},
+			absentFields: []string{"hostname", "msg"},
+		},
+		{
+			name: "enriched values and anomaly severity",
+			text: "type=ANOM_ABEND msg=audit(1700000200.000:9): auid=1000 uid=1000 pid=55 comm=\"crashy\" sig=11 res=1\x1dAUID=\"alice\" UID=\"alice\"",
+			expectedFields: map[string]any{
+				CFtype:               "ANOM_ABEND",
+				iomodules.CFseverity: anomalySeverity,
+				"uid":                int64(1000),
+				"UID":                "alice",
+				"AUID":               "alice",
+				"res":                true,
+				"sig":                int64(11),
+			},
+		},
+		{
+			name: "end of event record and oversized values",
+			text: `type=SYSCALL msg=audit(1700000300.000:10): syscall=2 key=(null) comm="` + strings.Repeat("c", 300) + `"
+type=EOE msg=audit(1700000300.000:10):`,
+			expectedFields: map[string]any{
+				CFtype:                "SYSCALL",
+				CFrecords:             "SYSCALL",
+				iomodules.CFprocessid: os.Getpid(),
+			},
+			absentFields: []string{"key", "comm", "eoe"},
+		},
+		{
+			name:      "not an audit record",
+			text:      "Jan  1 00:00:00 host sshd[1]: hello",
+			expectErr: true,
+		},
+		{
+			name:      "missing serial",
+			text:      "type=SYSCALL msg=audit(1700000000.123): syscall=1",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseEvent(tt.text, "localhost")
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got message %+v", msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.expectedTimestamp.IsZero() && !msg.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %v, got %v", tt.expectedTimestamp, msg.Timestamp)
+			}
+			if tt.expectedHostname != "" && msg.Hostname != tt.expectedHostname {
+				t.Errorf("expected hostname %q, got %q", tt.expectedHostname, msg.Hostname)
+			}
+			if string(msg.Data) != strings.TrimSpace(tt.text) {
+				t.Errorf("expected records as message text, got %q", msg.Data)
+			}
+			for key, expected := range tt.expectedFields {
+				if msg.Fields[key] != expected {
+					t.Errorf("expected field %q to be %v (%T), got %v (%T)", key, expected, expected, msg.Fields[key], msg.Fields[key])
+				}
+			}
+			for _, key := range tt.absentFields {
+				if _, exists := msg.Fields[key]; exists {
+					t.Errorf("expected field %q to be left out, got %v", key, msg.Fields[key])
+				}
+			}
+		})
+	}
+}
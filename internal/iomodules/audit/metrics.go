@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/metrics"
+	"sync/atomic"
+	"time"
+)
+
+type MetricStorage struct {
+	RecordsRead   atomic.Uint64 // number of records received from the audit socket
+	ParseFailures atomic.Uint64 // number of records that could not be parsed
+	Overruns      atomic.Uint64 // number of times records were lost because the socket buffer was full
+	Success       atomic.Uint64 // number of events processed successfully
+}
+
+const (
+	MTRecordsRead string = "records_read"
+	MTParseFail   string = "parse_failures"
+	MTOverruns    string = "receive_overruns"
+	MTSuc         string = "success_processed"
+)
+
+func (mod *InModule) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	// Read and clear
+	read := mod.metrics.RecordsRead.Swap(0)
+	parseFails := mod.metrics.ParseFailures.Swap(0)
+	overruns := mod.metrics.Overruns.Swap(0)
+	suc := mod.metrics.Success.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	namespace := logctx.GetTagList(mod.ctx)
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTRecordsRead,
+			Description: "Total records received from the audit netlink socket in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      read,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTParseFail,
+			Description: "Total audit records that failed parsing in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      parseFails,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTOverruns,
+			Description: "Total audit socket receive buffer overruns (records lost) in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      overruns,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSuc,
+			Description: "Total audit events sent as messages in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      suc,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
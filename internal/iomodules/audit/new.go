@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+
+	"golang.org/x/sys/unix"
+)
+
+// Creates new audit input module receiving a copy of all kernel audit records (auditd keeps running)
+func NewInput(ctx context.Context, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (new *InModule, err error) {
+	for index, filter := range filters {
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter at index %d: %w", index, err)
+			return
+		}
+	}
+
+	localHostname, err := os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve local hostname: %w", err)
+		return
+	}
+
+	source, err := openNetlink()
+	if err != nil {
+		return
+	}
+
+	// New context for audit
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoAudit)
+	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
+	modCtx, cancel := context.WithCancel(modCtx)
+
+	new = &InModule{
+		ctx:           modCtx,
+		localHostname: localHostname,
+		source:        source,
+		events:        newCorrelator(defaultEventTimeout, maxPendingEvents),
+		filters:       filters,
+		outbox:        queue,
+		metrics:       MetricStorage{},
+		cancel:        cancel,
+	}
+	return
+}
+
+// Joins the read-only audit multicast group
+func openNetlink() (socket *os.File, err error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC|unix.SOCK_NONBLOCK, unix.NETLINK_AUDIT)
+	if err != nil {
+		err = fmt.Errorf("failed to create audit netlink socket: %w", err)
+		return
+	}
+
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: netlinkGroupReadLog})
+	if err != nil {
+		_ = unix.Close(fd)
+		err = fmt.Errorf("failed to join audit multicast group (requires CAP_AUDIT_READ): %w", err)
+		return
+	}
+
+	// Best effort, bursts of records are lost once the default buffer is full
+	_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF, receiveBufferSize)
+
+	// Non-blocking descriptors use the runtime poller, so closing unblocks reads
+	socket = os.NewFile(uintptr(fd), "audit-netlink")
+	return
+}
@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"runtime/debug"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Receives netlink datagrams and passes their records on for grouping
+func (mod *InModule) receiver(records chan<- netlinkRecord) {
+	defer mod.wg.Done()
+	defer close(records)
+	ctx := mod.ctx
+
+	datagram := make([]byte, maxDatagramSize)
+	for {
+		n, err := mod.source.Read(datagram)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, os.ErrClosed) {
+				return
+			}
+			if errors.Is(err, syscall.ENOBUFS) {
+				// Records arrived faster than they were read, reading continues with the next one
+				mod.metrics.Overruns.Add(1)
+				continue
+			}
+			logctx.LogStdErr(ctx,
+				"failed to receive audit records: %w\n", err)
+			return
+		}
+		if n < unix.NLMSG_HDRLEN {
+			mod.metrics.ParseFailures.Add(1)
+			continue
+		}
+
+		// Length in the header is not reliable for audit records, the record is the rest of the datagram
+		recordType := binary.NativeEndian.Uint16(datagram[4:6])
+		if recordType < firstRecordType {
+			continue
+		}
+		mod.metrics.RecordsRead.Add(1)
+
+		record, err := parseNetlinkRecord(recordType, string(datagram[unix.NLMSG_HDRLEN:n]))
+		if err != nil {
+			mod.metrics.ParseFailures.Add(1)
+			logctx.LogEvent(ctx, logctx.VerbosityData, logctx.WarnLog,
+				"failed to parse audit record: %w\n", err)
+			continue
+		}
+
+		select {
+		case records <- netlinkRecord{record: record, recordType: recordType}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Groups records into events and sends them once complete
+func (mod *InModule) correlate(records <-chan netlinkRecord) {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	ticker := time.NewTicker(mod.events.timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		var complete [][]Record
+		select {
+		case <-ctx.Done():
+			mod.flush(records, nil)
+			return
+		case received, open := <-records:
+			if !open {
+				mod.flush(records, nil)
+				return
+			}
+			complete = mod.events.add(received.record, received.recordType, time.Now())
+		case now := <-ticker.C:
+			complete = mod.events.expire(now)
+		}
+
+		for i, event := range complete {
+			queued := mod.send(ctx, event)
+			if !queued && ctx.Err() != nil {
+				// Stopped while waiting for queue space
+				mod.flush(records, complete[i:])
+				return
+			}
+		}
+	}
+}
+
+// Sends events of records already received and all events still waiting for records, so stopping (like a hot swap) loses no events
+func (mod *InModule) flush(records <-chan netlinkRecord, unsent [][]Record) {
+	// Module context is cancelled, queue is still drained by the sender
+	pushCtx, cancel := context.WithTimeout(context.WithoutCancel(mod.ctx), shutdownFlushTimeout)
+	defer cancel()
+
+	complete := unsent
+	for draining := true; draining; {
+		select {
+		case received, open := <-records:
+			if !open {
+				draining = false
+				break
+			}
+			complete = append(complete, mod.events.add(received.record, received.recordType, time.Now())...)
+		default:
+			draining = false
+		}
+	}
+	complete = append(complete, mod.events.flush()...)
+
+	for _, event := range complete {
+		if !mod.send(pushCtx, event) && pushCtx.Err() != nil {
+			logctx.LogStdErr(mod.ctx, "failed to send audit event %d at shutdown: queue is full\n", event[0].Serial)
+		}
+	}
+}
+
+// Creates the message of an event and sends it unless filtered.
+// Push waits for queue space until pushCtx is done.
+func (mod *InModule) send(pushCtx context.Context, event []Record) (queued bool) {
+	ctx := mod.ctx
+
+	// Record panics and continue working
+	defer func() {
+		if fatalError := recover(); fatalError != nil {
+			stack := debug.Stack()
+			logctx.LogStdErr(ctx,
+				"panic in audit correlation thread: %v\n%s", fatalError, stack)
+		}
+	}()
+
+	msg := NewMessage(event, mod.localHostname)
+	msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(ctx), "/")
+
+	for _, filter := range mod.filters {
+		if filter.Match(msg) {
+			// First filter match wins - drop message
+			return
+		}
+	}
+
+	mod.outbox.PushBlocking(pushCtx, msg, msg.Size())
+	if pushCtx.Err() != nil {
+		return
+	}
+	mod.metrics.Success.Add(1)
+	queued = true
+	return
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"sdsyslog/internal/filtering"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Netlink socket stand-in returning one datagram per read, blocking once drained until closed
+type fakeSocket struct {
+	datagrams chan []byte
+	closed    chan struct{}
+}
+
+func (socket *fakeSocket) Read(buffer []byte) (n int, err error) {
+	select {
+	case datagram := <-socket.datagrams:
+		if datagram == nil {
+			err = syscall.ENOBUFS
+			return
+		}
+		n = copy(buffer, datagram)
+	case <-socket.closed:
+		err = os.ErrClosed
+	}
+	return
+}
+
+func (socket *fakeSocket) Close() (err error) {
+	close(socket.closed)
+	return
+}
+
+// Audit record as sent by the kernel (header length without the header, like older kernels)
+func netlinkDatagram(recordType uint16, text string) (datagram []byte) {
+	datagram = make([]byte, unix.NLMSG_HDRLEN, unix.NLMSG_HDRLEN+len(text))
+	binary.NativeEndian.PutUint32(datagram[0:4], uint32(len(text)))
+	binary.NativeEndian.PutUint16(datagram[4:6], recordType)
+	datagram = append(datagram, text...)
+	return
+}
+
+func TestInputGroupsNetlinkRecords(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 16, global.MinValue(16), global.MaxValue(16))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	filters := []protocol.MessageFilter{{Fields: map[string]*filtering.Filter{CFtype: {Exact: "DAEMON_ROTATE"}}}}
+	socket := &fakeSocket{datagrams: make(chan []byte, 16), closed: make(chan struct{})}
+	modCtx, modCancel := context.WithCancel(ctx)
+	mod := &InModule{
+		ctx:           modCtx,
+		cancel:        modCancel,
+		localHostname: "host1",
+		source:        socket,
+		events:        newCorrelator(defaultEventTimeout, maxPendingEvents),
+		filters:       filters,
+		outbox:        queue,
+	}
+
+	socket.datagrams <- netlinkDatagram(1300, "audit(1700000000.001:500): syscall=257 success=no exit=-13 pid=99 uid=1000 comm=\"vi\" key=\"shadow\"\x00")
+	socket.datagrams <- netlinkDatagram(1205, "audit(1700000000.002:501): op=rotate-logs auid=0 pid=1 res=success")
+	socket.datagrams <- nil
+	socket.datagrams <- netlinkDatagram(1302, "audit(1700000000.001:500): item=0 name=\"/etc/shadow\" nametype=NORMAL")
+	socket.datagrams <- netlinkDatagram(1320, "audit(1700000000.001:500): ")
+	socket.datagrams <- netlinkDatagram(1300, "audit(1700000000.003:503): syscall=59 success=yes exit=0 pid=100 uid=0 comm=\"sh\"")
+	socket.datagrams <- netlinkDatagram(1112, "audit(1700000000.003:502): pid=7 uid=0 msg='op=login acct=\"bob\" res=success'")
+
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+
+	popCtx, popCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer popCancel()
+
+	msg, ok := queue.Pop(popCtx)
+	if !ok {
+		t.Fatalf("timed out waiting for syscall event")
+	}
+	expectedText := "type=SYSCALL msg=audit(1700000000.001:500): syscall=257 success=no exit=-13 pid=99 uid=1000 comm=\"vi\" key=\"shadow\"\n" +
+		"type=PATH msg=audit(1700000000.001:500): item=0 name=\"/etc/shadow\" nametype=NORMAL\n" +
+		"type=EOE msg=audit(1700000000.001:500):"
+	if string(msg.Data) != expectedText {
+		t.Errorf("expected message text %q, got %q", expectedText, msg.Data)
+	}
+	expectedFields := map[string]any{
+		CFserial:    int64(500),
+		"exit":      int64(-13),
+		"success":   false,
+		"key":       "shadow",
+		"path.name": "/etc/shadow",
+		"path.item": int64(0),
+	}
+	for key, expected := range expectedFields {
+		if msg.Fields[key] != expected {
+			t.Errorf("expected field %q to be %v (%T), got %v (%T)", key, expected, expected, msg.Fields[key], msg.Fields[key])
+		}
+	}
+
+	msg, ok = queue.Pop(popCtx)
+	if !ok {
+		t.Fatalf("timed out waiting for login event")
+	}
+	if msg.Fields[CFtype] != "USER_LOGIN" || msg.Fields["acct"] != "bob" {
+		t.Errorf("expected filtered rotation to be skipped and login event sent, got %v", msg.Fields)
+	}
+
+	err = mod.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected error stopping input: %v", err)
+	}
+
+	// Event still waiting for its end record is sent at shutdown
+	msg, ok = queue.Pop(popCtx)
+	if !ok {
+		t.Fatalf("timed out waiting for pending event flushed at shutdown")
+	}
+	if msg.Fields[CFserial] != int64(503) || msg.Fields["comm"] != "sh" {
+		t.Errorf("expected pending syscall event 503, got %v", msg.Fields)
+	}
+
+	if overruns := mod.metrics.Overruns.Load(); overruns != 1 {
+		t.Errorf("expected 1 overrun, got %d", overruns)
+	}
+	if read := mod.metrics.RecordsRead.Load(); read != 6 {
+		t.Errorf("expected 6 records read, got %d", read)
+	}
+	if sent := mod.metrics.Success.Load(); sent != 3 {
+		t.Errorf("expected 3 events sent, got %d", sent)
+	}
+}
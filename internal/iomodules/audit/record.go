@@ -0,0 +1,250 @@
+package audit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses an audit.log line:
+// [node=<host> ]type=<TYPE> msg=audit(<seconds>.<milliseconds>:<serial>): key=value ...
+func ParseLine(line string) (record Record, err error) {
+	text := strings.TrimSpace(line)
+	rest := text
+
+	var node string
+	if strings.HasPrefix(rest, "node=") {
+		node, rest, _ = strings.Cut(rest[len("node="):], " ")
+	}
+
+	if !strings.HasPrefix(rest, "type=") {
+		err = fmt.Errorf("record has no type")
+		return
+	}
+	recordType, rest, _ := strings.Cut(rest[len("type="):], " ")
+	if !strings.HasPrefix(rest, "msg=") {
+		err = fmt.Errorf("record has no audit header")
+		return
+	}
+
+	record, err = parseBody(recordType, rest[len("msg="):])
+	if err != nil {
+		return
+	}
+	record.Node = node
+	record.Text = text
+	return
+}
+
+// Parses a record sent over netlink (type from the message header, body starting with the audit header)
+func parseNetlinkRecord(recordType uint16, body string) (record Record, err error) {
+	typeName, known := recordTypeNames[recordType]
+	if !known {
+		typeName = fmt.Sprintf("UNKNOWN[%d]", recordType)
+	}
+
+	body = strings.TrimRight(body, "\x00\n ")
+	record, err = parseBody(typeName, body)
+	if err != nil {
+		return
+	}
+	record.Text = "type=" + typeName + " msg=" + body
+	return
+}
+
+// Parses "audit(<seconds>.<milliseconds>:<serial>): key=value ..."
+func parseBody(recordType string, body string) (record Record, err error) {
+	record.Type = recordType
+
+	record.Timestamp, record.Serial, err = parseHeader(body)
+	if err != nil {
+		return
+	}
+	_, values, _ := strings.Cut(body, ")")
+	values = strings.TrimLeft(strings.TrimPrefix(values, ":"), " ")
+
+	// Enriched log format appends interpreted values (like UID="root") after a group separator
+	raw, interpreted, _ := strings.Cut(values, "\x1d")
+	record.values = parseValues(recordType, raw, false)
+	record.values = append(record.values, parseValues(recordType, interpreted, true)...)
+	return
+}
+
+// Timestamp and serial number of "audit(<seconds>.<milliseconds>:<serial>)"
+func parseHeader(body string) (timestamp time.Time, serial uint64, err error) {
+	if !strings.HasPrefix(body, "audit(") {
+		err = fmt.Errorf("record has no audit header")
+		return
+	}
+	stamp, _, found := strings.Cut(body[len("audit("):], ")")
+	if !found {
+		err = fmt.Errorf("unterminated audit header")
+		return
+	}
+
+	clock, serialText, found := strings.Cut(stamp, ":")
+	if !found {
+		err = fmt.Errorf("audit header %q has no serial number", stamp)
+		return
+	}
+	serial, err = strconv.ParseUint(serialText, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid serial number %q: %w", serialText, err)
+		return
+	}
+
+	secondsText, fraction, _ := strings.Cut(clock, ".")
+	seconds, err := strconv.ParseInt(secondsText, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid record time %q: %w", clock, err)
+		return
+	}
+	var nanoseconds int64
+	if fraction != "" && len(fraction) <= 9 {
+		nanoseconds, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil {
+			err = fmt.Errorf("invalid record time %q: %w", clock, err)
+			return
+		}
+	}
+	timestamp = time.Unix(seconds, nanoseconds)
+	return
+}
+
+// Serial number of an audit.log line (false if the line is not an audit record)
+func LineSerial(line string) (serial uint64, valid bool) {
+	start := strings.Index(line, "msg=audit(")
+	if start == -1 {
+		return
+	}
+	_, serial, err := parseHeader(line[start+len("msg="):])
+	valid = err == nil
+	return
+}
+
+// Splits key=value pairs. Values of user space messages (msg='...') are added as values of the record.
+func parseValues(recordType string, text string, interpreted bool) (values []recordValue) {
+	rest := strings.TrimSpace(text)
+	for rest != "" {
+		keyEnd := strings.IndexAny(rest, "= ")
+		if keyEnd == -1 {
+			break
+		}
+		key := rest[:keyEnd]
+		if rest[keyEnd] == ' ' || key == "" {
+			// Token without a value
+			rest = strings.TrimLeft(rest[keyEnd+1:], " ")
+			continue
+		}
+		rest = rest[keyEnd+1:]
+
+		var raw string
+		var quoted bool
+		if quote := firstByte(rest); quote == '"' || quote == '\'' {
+			end := strings.IndexByte(rest[1:], quote)
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			raw = rest[1 : end+1]
+			rest = rest[min(end+2, len(rest)):]
+			quoted = true
+
+			if quote == '\'' && key == "msg" {
+				values = append(values, parseValues(recordType, raw, interpreted)...)
+				rest = strings.TrimLeft(rest, " ")
+				continue
+			}
+		} else {
+			valueEnd := strings.IndexByte(rest, ' ')
+			if valueEnd == -1 {
+				valueEnd = len(rest)
+			}
+			raw = rest[:valueEnd]
+			rest = rest[valueEnd:]
+		}
+		rest = strings.TrimLeft(rest, " ")
+
+		value, valid := typedValue(recordType, key, raw, quoted || interpreted)
+		if valid {
+			values = append(values, recordValue{key: key, value: value})
+		}
+	}
+	return
+}
+
+// Converts a raw value to text, number, or boolean. Unset values ("?" or "(null)") are not valid.
+func typedValue(recordType string, key string, raw string, quoted bool) (value any, valid bool) {
+	if quoted {
+		value, valid = raw, true
+		return
+	}
+	if raw == "" || raw == "?" || raw == "(null)" {
+		return
+	}
+
+	_, encoded := encodedKeys[key]
+	if recordType == "EXECVE" && isArgumentKey(key) {
+		encoded = true
+	}
+	if encoded && strings.ToUpper(raw) == raw {
+		decoded, err := hex.DecodeString(raw)
+		if err == nil {
+			// Command lines separate arguments with NUL, multiple rule keys with 0x01
+			text := strings.ReplaceAll(string(decoded), "\x00", " ")
+			text = strings.ReplaceAll(text, "\x01", ",")
+			value, valid = strings.TrimRight(text, " "), true
+			return
+		}
+	}
+
+	if _, numeric := numericKeys[key]; numeric {
+		number, err := strconv.ParseInt(raw, 10, 64)
+		if err == nil {
+			value, valid = number, true
+			return
+		}
+	}
+
+	switch key {
+	case "success":
+		switch raw {
+		case "yes":
+			value, valid = true, true
+			return
+		case "no":
+			value, valid = false, true
+			return
+		}
+	case "res":
+		switch raw {
+		case "success", "1":
+			value, valid = true, true
+			return
+		case "failed", "0":
+			value, valid = false, true
+			return
+		}
+	}
+
+	value, valid = raw, true
+	return
+}
+
+// Reports whether a key is an EXECVE argument (a0, a1, ...)
+func isArgumentKey(key string) (argument bool) {
+	if len(key) < 2 || key[0] != 'a' {
+		return
+	}
+	_, err := strconv.ParseUint(key[1:], 10, 32)
+	argument = err == nil
+	return
+}
+
+func firstByte(text string) (char byte) {
+	if text != "" {
+		char = text[0]
+	}
+	return
+}
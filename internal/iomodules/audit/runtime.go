@@ -0,0 +1,30 @@
+package audit
+
+// Starts audit record receiver and event correlation in background
+func (mod *InModule) Start() (err error) {
+	records := make(chan netlinkRecord, recordBacklog)
+
+	mod.wg.Add(2)
+	go mod.receiver(records)
+	go mod.correlate(records)
+	return
+}
+
+// Gracefully stops module (events still waiting for records are sent as they are)
+func (mod *InModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+
+	// Unblock receiver
+	if mod.source != nil {
+		err = mod.source.Close()
+	}
+
+	mod.wg.Wait()
+	return
+}
@@ -0,0 +1,65 @@
+// IOModule for Linux audit records (netlink multicast group or audit.log lines)
+package audit
+
+import (
+	"context"
+	"io"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"time"
+)
+
+type InModule struct {
+	// Settings
+	filters       []protocol.MessageFilter
+	localHostname string
+
+	// Input
+	source io.ReadCloser // Netlink socket (one datagram per read)
+	events *correlator
+
+	// Output
+	outbox *mpmc.Queue[*protocol.Message]
+
+	metrics MetricStorage
+
+	wg     sync.WaitGroup     // Waiter for instance
+	cancel context.CancelFunc // cancel instance
+	ctx    context.Context
+}
+
+// Single audit record
+type Record struct {
+	Type      string
+	Timestamp time.Time
+	Serial    uint64
+	Node      string // Host name prefix added by auditd (name_format setting)
+	Text      string // Record as written to audit.log
+	values    []recordValue
+}
+
+// Typed value of a record in record order
+type recordValue struct {
+	key   string
+	value any
+}
+
+// Received record with its type number
+type netlinkRecord struct {
+	record     Record
+	recordType uint16
+}
+
+// Groups records of interleaved events by serial number
+type correlator struct {
+	pending    map[uint64]*pendingEvent
+	timeout    time.Duration
+	maxPending int
+}
+
+// Records of an event waiting for the end of the event
+type pendingEvent struct {
+	records []Record
+	started time.Time
+}
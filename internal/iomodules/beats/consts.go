@@ -1,5 +1,18 @@
 package beats
 
+import "time"
+
 const (
-	DefaultAddress string = "localhost:5044"
+	DefaultAddress         string = "localhost:5044"
+	DefaultMaxSendAttempts int    = 6
+
+	// Input server
+	networkTimeout    time.Duration = 30 * time.Second // Read/write timeout of client connections
+	keepaliveInterval time.Duration = 3 * time.Second  // Empty ACKs telling clients a batch is still being queued
+
+	// Beats event fields
+	eventMessage   string = "message"
+	eventTimestamp string = "@timestamp"
+	eventHost      string = "host"
+	eventLog       string = "log"
 )
@@ -0,0 +1,79 @@
+package beats
+
+import (
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/metrics"
+	"time"
+)
+
+const (
+	MTEventsRead string = "events_read"
+	MTParseFail  string = "parse_failures"
+	MTBatches    string = "batches_acknowledged"
+	MTSuc        string = "success_processed"
+)
+
+func (mod *InModule) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	// Read and clear
+	read := mod.metrics.EventsRead.Swap(0)
+	parseFails := mod.metrics.ParseFailures.Swap(0)
+	batches := mod.metrics.Batches.Swap(0)
+	suc := mod.metrics.Success.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	namespace := logctx.GetTagList(mod.ctx)
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTEventsRead,
+			Description: "Total events received by the beats server in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      read,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTParseFail,
+			Description: "Total received beats events that failed parsing in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      parseFails,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTBatches,
+			Description: "Total event batches queued and acknowledged to beats clients in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      batches,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSuc,
+			Description: "Total processed messages extracted from beats events in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      suc,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
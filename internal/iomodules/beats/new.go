@@ -1,10 +1,19 @@
 package beats
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net"
+	"os"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
 	"time"
 
 	lumberjack "github.com/elastic/go-lumber/client/v2"
+	lumberserver "github.com/elastic/go-lumber/server/v2"
 )
 
 // Creates new beats (lumberjack) output module. Returns nil nil if no path.
@@ -30,8 +39,104 @@ func NewOutput(endpoint string, maxSendAttempts int) (module *OutModule, err err
 	return
 }
 
-// Unsupported
-func NewInput() (err error) {
-	err = fmt.Errorf("beats input is currently not supported")
+// Creates new beats (lumberjack v2) input server module and binds the listen address. Returns nil nil if no address.
+func NewInput(ctx context.Context, config InputConfig, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (module *InModule, err error) {
+	if config.Address == "" {
+		return
+	}
+
+	for index, filter := range filters {
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter at index %d: %w", index, err)
+			return
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if config.CertFile != "" || config.KeyFile != "" || config.ClientCAFile != "" {
+		tlsConfig, err = newServerTLSConfig(config)
+		if err != nil {
+			return
+		}
+	}
+
+	// New context for listener
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoBeats)
+	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
+	modCtx, cancel := context.WithCancel(modCtx)
+
+	new := &InModule{
+		filters: filters,
+		outbox:  queue,
+		metrics: MetricStorage{},
+		ctx:     modCtx,
+		cancel:  cancel,
+	}
+
+	new.localHostname, err = os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve local hostname: %w", err)
+		return
+	}
+
+	listener, err := net.Listen("tcp", config.Address)
+	if err != nil {
+		err = fmt.Errorf("failed to listen on beats address %q: %w", config.Address, err)
+		return
+	}
+	new.address = listener.Addr().String()
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	lumberLogger.ctx.Store(&modCtx)
+
+	new.server, err = lumberserver.NewWithListener(listener,
+		lumberserver.Timeout(networkTimeout),
+		lumberserver.Keepalive(keepaliveInterval),
+		lumberserver.JSONDecoder(decodeEvent),
+	)
+	if err != nil {
+		err = fmt.Errorf("failed to create beats server: %w", err)
+		_ = listener.Close()
+		return
+	}
+
+	module = new
+	return
+}
+
+// Builds server TLS settings from the configured certificate and optional client CA files
+func newServerTLSConfig(config InputConfig) (tlsConfig *tls.Config, err error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		err = fmt.Errorf("beats input TLS requires both a certificate and key file")
+		return
+	}
+
+	serverCert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		err = fmt.Errorf("failed to load beats server certificate: %w", err)
+		return
+	}
+	tlsConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{serverCert},
+	}
+
+	if config.ClientCAFile != "" {
+		var caPEM []byte
+		caPEM, err = os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			err = fmt.Errorf("failed to read beats client CA file: %w", err)
+			return
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caPEM) {
+			err = fmt.Errorf("beats client CA file %q contains no PEM certificates", config.ClientCAFile)
+			return
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
 	return
 }
@@ -0,0 +1,185 @@
+package beats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/syslog"
+	"sdsyslog/pkg/protocol"
+	"strconv"
+	"time"
+)
+
+// Decodes event JSON keeping numbers exact
+func decodeEvent(data []byte, event any) (err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(event)
+	return
+}
+
+// Creates a message from the fields of a beats event (message, @timestamp, host.name, and log.*)
+func parseEvent(event map[string]any, localHostname string) (message *protocol.Message, err error) {
+	text, _ := event[eventMessage].(string)
+	if text == "" {
+		err = fmt.Errorf("event has no %q field", eventMessage)
+		return
+	}
+
+	message = &protocol.Message{
+		Data:   []byte(text),
+		Fields: make(map[string]any),
+	}
+
+	timestamp, _ := event[eventTimestamp].(string)
+	message.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		// Beats always send a timestamp, but other lumberjack clients may not
+		message.Timestamp = time.Now()
+		err = nil
+	}
+
+	host, _ := event[eventHost].(map[string]any)
+	message.Hostname, _ = host["name"].(string)
+	if message.Hostname == "" {
+		message.Hostname = localHostname
+	}
+
+	logFields, _ := event[eventLog].(map[string]any)
+	addLogFields(message.Fields, eventLog+".", logFields)
+
+	_, ok := message.Fields[iomodules.CFappname]
+	if !ok {
+		message.Fields[iomodules.CFappname] = protocol.EmptyFieldChar
+	}
+	_, ok = message.Fields[iomodules.CFprocessid]
+	if !ok {
+		message.Fields[iomodules.CFprocessid] = os.Getpid()
+	}
+	_, ok = message.Fields[iomodules.CFfacility]
+	if !ok {
+		message.Fields[iomodules.CFfacility] = iomodules.DefaultFacility
+	}
+	_, ok = message.Fields[iomodules.CFseverity]
+	if !ok {
+		message.Fields[iomodules.CFseverity] = iomodules.DefaultSeverity
+	}
+	return
+}
+
+// Adds log.* values as fields with dotted keys. ECS level and syslog values set the common fields.
+func addLogFields(fields map[string]any, prefix string, object map[string]any) {
+	for key, value := range object {
+		key = prefix + key
+
+		if nested, isObject := value.(map[string]any); isObject {
+			addLogFields(fields, key+".", nested)
+			continue
+		}
+
+		text := fieldText(value)
+		switch key {
+		case "log.level":
+			// Syslog severity of the event takes precedence
+			if _, exists := fields[iomodules.CFseverity]; exists {
+				continue
+			}
+			severity, valid := syslog.NormalizeSeverity(text)
+			if valid {
+				fields[iomodules.CFseverity] = severity
+				continue
+			}
+		case "log.syslog.severity.name", "log.syslog.severity.code":
+			severity, valid := syslog.NormalizeSeverity(text)
+			if valid {
+				fields[iomodules.CFseverity] = severity
+				continue
+			}
+		case "log.syslog.facility.name":
+			_, err := syslog.FacilityToCode(text)
+			if err == nil {
+				fields[iomodules.CFfacility] = text
+				continue
+			}
+		case "log.syslog.facility.code":
+			code, err := strconv.ParseUint(text, 10, 16)
+			if err == nil {
+				facility, err := syslog.CodeToFacility(uint16(code))
+				if err == nil {
+					fields[iomodules.CFfacility] = facility
+					continue
+				}
+			}
+		case "log.syslog.appname":
+			if text != "" && len(text) <= protocol.MaxCtxValLen {
+				fields[iomodules.CFappname] = text
+				continue
+			}
+		case "log.syslog.procid":
+			pid, err := strconv.Atoi(text)
+			if err == nil {
+				fields[iomodules.CFprocessid] = pid
+				continue
+			}
+		}
+
+		if len(key) > protocol.MaxCtxKeyLen {
+			continue
+		}
+		fieldValue, valid := typedValue(value)
+		if valid {
+			fields[key] = fieldValue
+		}
+	}
+}
+
+// Converts a decoded JSON value to a field value type. Values the protocol cannot carry are left out.
+func typedValue(value any) (typed any, valid bool) {
+	switch converted := value.(type) {
+	case nil:
+		return
+	case bool:
+		typed = converted
+	case json.Number:
+		integer, err := converted.Int64()
+		if err == nil {
+			typed = integer
+			break
+		}
+		float, err := converted.Float64()
+		if err != nil {
+			return
+		}
+		typed = float
+	default:
+		text := fieldText(value)
+		if text == "" || len(text) > protocol.MaxCtxValLen {
+			return
+		}
+		typed = text
+	}
+	valid = true
+	return
+}
+
+// Text of a decoded JSON value (arrays as JSON text)
+func fieldText(value any) (text string) {
+	switch converted := value.(type) {
+	case nil:
+		return
+	case string:
+		text = converted
+	case json.Number:
+		text = converted.String()
+	case bool:
+		text = strconv.FormatBool(converted)
+	default:
+		encoded, err := json.Marshal(converted)
+		if err == nil {
+			text = string(encoded)
+		}
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package beats
+
+import (
+	"encoding/json"
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name              string
+		event             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedData      string
+		expectedFields    map[string]any
+		absentFields      []string
+		expectErr         bool
+	}{
+		{
+			name:              "filebeat log file event",
+			event:             `{"@timestamp":"2026-03-04T10:11:12.345Z","message":"GET /index.html 200","host":{"name":"web01","os":{"family":"debian"}},"log":{"file":{"path":"/var/log/nginx/access.log"},"offset":1532},"agent":{"type":"filebeat"}}`,
+			expectedTimestamp: time.Date(2026, 3, 4, 10, 11, 12, 345000000, time.UTC),
+			expectedHostname:  "web01",
+			expectedData:      "GET /index.html 200",
+			expectedFields: map[string]any{
+				"log.file.path":       "/var/log/nginx/access.log",
+				"log.offset":          int64(1532),
+				iomodules.CFappname:   protocol.EmptyFieldChar,
+				iomodules.CFprocessid: os.Getpid(),
+				iomodules.CFfacility:  iomodules.DefaultFacility,
+				iomodules.CFseverity:  iomodules.DefaultSeverity,
+			},
+			absentFields: []string{"agent.type", "host.os.family"},
+		},
+		{
+			name:             "log level",
+			event:            `{"message":"disk almost full","host":{"name":"db01"},"log":{"level":"WARN","logger":"monitor"}}`,
+			expectedHostname: "db01",
+			expectedData:     "disk almost full",
+			expectedFields:   map[string]any{iomodules.CFseverity: "warning", "log.logger": "monitor"},
+			absentFields:     []string{"log.level"},
+		},
+		{
+			name:  "syslog values set common fields",
+			event: `{"message":"session opened","log":{"level":"info","syslog":{"appname":"sshd","procid":"812","facility":{"code":10,"name":"authpriv"},"severity":{"code":5,"name":"notice"},"hostname":"bastion"}}}`,
+			expectedFields: map[string]any{
+				iomodules.CFappname:   "sshd",
+				iomodules.CFprocessid: 812,
+				iomodules.CFfacility:  "authpriv",
+				iomodules.CFseverity:  "notice",
+				"log.syslog.hostname": "bastion",
+			},
+			absentFields: []string{"log.syslog.appname", "log.syslog.procid", "log.level"},
+		},
+		{
+			name:           "unknown level and oversized values",
+			event:          `{"message":"m","log":{"level":"verbose","origin":{"function":"` + strings.Repeat("f", 300) + `","file":{"name":"main.go","line":42}},"flags":["a","b"],"sampled":true,"ratio":0.5}}`,
+			expectedFields: map[string]any{iomodules.CFseverity: iomodules.DefaultSeverity, "log.level": "verbose", "log.origin.file.name": "main.go", "log.origin.file.line": int64(42), "log.flags": `["a","b"]`, "log.sampled": true, "log.ratio": 0.5},
+			absentFields:   []string{"log.origin.function"},
+		},
+		{
+			name:           "key over the key limit",
+			event:          `{"message":"m","log":{"kubernetes":{"annotations":{"checksum/config":"abc"}}}}`,
+			expectedFields: map[string]any{iomodules.CFseverity: iomodules.DefaultSeverity},
+			absentFields:   []string{"log.kubernetes.annotations.checksum/config"},
+		},
+		{
+			name:      "missing message",
+			event:     `{"@timestamp":"2026-03-04T10:11:12Z","metricset":{"name":"cpu"}}`,
+			expectErr: true,
+		},
+	}
+
+	localHostname := "local-host"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event map[string]any
+			err := decodeEvent([]byte(tt.event), &event)
+			if err != nil {
+				t.Fatalf("unexpected error decoding test event: %v", err)
+			}
+
+			msg, err := parseEvent(event, localHostname)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got message %+v", msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.expectedTimestamp.IsZero() && !msg.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %v, got %v", tt.expectedTimestamp, msg.Timestamp)
+			}
+			if tt.expectedTimestamp.IsZero() && time.Since(msg.Timestamp) > time.Minute {
+				t.Errorf("expected current time for event without timestamp, got %v", msg.Timestamp)
+			}
+			expectedHostname := tt.expectedHostname
+			if expectedHostname == "" {
+				expectedHostname = localHostname
+			}
+			if msg.Hostname != expectedHostname {
+				t.Errorf("expected hostname %q, got %q", expectedHostname, msg.Hostname)
+			}
+			if tt.expectedData != "" && string(msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, msg.Data)
+			}
+			for key, expected := range tt.expectedFields {
+				if msg.Fields[key] != expected {
+					t.Errorf("expected field %q to be %v (%T), got %v (%T)", key, expected, expected, msg.Fields[key], msg.Fields[key])
+				}
+			}
+			for _, key := range tt.absentFields {
+				if _, exists := msg.Fields[key]; exists {
+					t.Errorf("expected field %q to be left out, got %v", key, msg.Fields[key])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeEventNumbers(t *testing.T) {
+	var event map[string]any
+	err := decodeEvent([]byte(`{"offset":9007199254740993}`), &event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, valid := typedValue(event["offset"])
+	if !valid {
+		t.Fatalf("expected offset to be a valid field value")
+	}
+	if value != int64(9007199254740993) {
+		t.Errorf("expected exact integer 9007199254740993, got %v (%T)", value, value)
+	}
+	if _, isNumber := event["offset"].(json.Number); !isNumber {
+		t.Errorf("expected decoded number to stay a json.Number, got %T", event["offset"])
+	}
+}
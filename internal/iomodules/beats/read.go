@@ -0,0 +1,142 @@
+package beats
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"strings"
+
+	"github.com/elastic/go-lumber/lj"
+	lumberlog "github.com/elastic/go-lumber/log"
+)
+
+// Queues the events of received batches. Batches are only acknowledged once all of their events are queued.
+func (mod *InModule) batchReader() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	batches := mod.server.ReceiveChan()
+	for {
+		var batch *lj.Batch
+		var open bool
+		select {
+		case <-ctx.Done():
+			return
+		case batch, open = <-batches:
+			if !open {
+				return
+			}
+		}
+
+		queued := mod.handleBatch(batch)
+		if !queued {
+			// Shutdown before the whole batch was queued, client resends it after reconnecting
+			return
+		}
+	}
+}
+
+// Queues and acknowledges a single batch. Returns false when stopped before all events were queued.
+func (mod *InModule) handleBatch(batch *lj.Batch) (queued bool) {
+	// Record panics and continue working
+	defer func() {
+		if fatalError := recover(); fatalError != nil {
+			stack := debug.Stack()
+			logctx.LogStdErr(mod.ctx,
+				"panic in beats batch reader thread: %v\n%s", fatalError, stack)
+
+			// Remaining events of the batch are dropped, client would otherwise wait for the ACK forever
+			batch.ACK()
+			queued = true
+		}
+	}()
+
+	queued = mod.queueBatch(batch)
+	if !queued {
+		return
+	}
+	batch.ACK()
+	mod.metrics.Batches.Add(1)
+	return
+}
+
+// Parses, filters and queues every event of a batch. Returns false when stopped before all events were queued.
+func (mod *InModule) queueBatch(batch *lj.Batch) (queued bool) {
+	for _, event := range batch.Events {
+		msg := mod.handleEvent(event)
+		if msg == nil {
+			continue
+		}
+
+		// Clients get back-pressure instead of drops (they wait for the ACK)
+		mod.outbox.PushBlocking(mod.ctx, msg, msg.Size())
+		if mod.ctx.Err() != nil {
+			return
+		}
+		mod.metrics.Success.Add(1)
+	}
+	queued = true
+	return
+}
+
+// Parses and filters a beats event. Returns nil when the event should not be forwarded.
+func (mod *InModule) handleEvent(event any) (msg *protocol.Message) {
+	mod.metrics.EventsRead.Add(1)
+
+	fields, isObject := event.(map[string]any)
+	if !isObject {
+		mod.metrics.ParseFailures.Add(1)
+		logctx.LogEvent(mod.ctx, logctx.VerbosityData, logctx.WarnLog,
+			"failed to parse beats event: expected JSON object, got %T\n", event)
+		return
+	}
+
+	msg, err := parseEvent(fields, mod.localHostname)
+	if err != nil {
+		mod.metrics.ParseFailures.Add(1)
+		logctx.LogEvent(mod.ctx, logctx.VerbosityData, logctx.WarnLog,
+			"failed to parse beats event: %w\n", err)
+		msg = nil
+		return
+	}
+
+	msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(mod.ctx), "/")
+
+	for _, filter := range mod.filters {
+		if filter.Match(msg) {
+			// First filter match wins - drop message
+			msg = nil
+			return
+		}
+	}
+	return
+}
+
+// Connection level library messages are only of interest when debugging
+var lumberLogger = &libraryLogger{}
+
+func init() {
+	lumberlog.Logger = lumberLogger
+}
+
+func (logger *libraryLogger) Printf(format string, args ...any) {
+	logger.log(fmt.Sprintf(format, args...))
+}
+
+func (logger *libraryLogger) Println(args ...any) {
+	logger.log(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (logger *libraryLogger) Print(args ...any) {
+	logger.log(fmt.Sprint(args...))
+}
+
+func (logger *libraryLogger) log(text string) {
+	ctx := logger.ctx.Load()
+	if ctx == nil {
+		return
+	}
+	logctx.LogEvent(*ctx, logctx.VerbosityDebug, logctx.InfoLog, "beats server: %s\n", text)
+}
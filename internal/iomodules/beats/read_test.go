@@ -0,0 +1,221 @@
+package beats
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/internal/tests/utils"
+	"sdsyslog/pkg/protocol"
+	"testing"
+	"time"
+
+	lumberjack "github.com/elastic/go-lumber/client/v2"
+)
+
+func TestInputAcknowledgesQueuedBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	// Room for only two of the three events
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 2, global.MinValue(2), global.MaxValue(2))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	mod, err := NewInput(ctx, InputConfig{Address: "127.0.0.1:0"}, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error creating input: %v", err)
+	}
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+	defer mod.Shutdown()
+
+	conn, err := net.Dial("tcp", mod.address)
+	if err != nil {
+		t.Fatalf("unexpected error connecting to beats input: %v", err)
+	}
+	client, err := lumberjack.NewWithConn(conn, lumberjack.Timeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	defer client.Close()
+
+	events := []any{
+		map[string]any{"message": "first", "host": map[string]any{"name": "agent01"}},
+		map[string]any{"message": "second", "host": map[string]any{"name": "agent01"}},
+		map[string]any{"message": "third", "host": map[string]any{"name": "agent01"}, "log": map[string]any{"level": "error"}},
+	}
+	err = client.Send(events)
+	if err != nil {
+		t.Fatalf("unexpected error sending batch: %v", err)
+	}
+
+	acked := make(chan uint32, 1)
+	go func() {
+		count, _ := client.AwaitACK(uint32(len(events)))
+		acked <- count
+	}()
+
+	select {
+	case count := <-acked:
+		t.Fatalf("expected no ACK while the queue is full, got ACK for %d events", count)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	popCtx, popCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer popCancel()
+
+	var received []*protocol.Message
+	for range events {
+		msg, ok := queue.Pop(popCtx)
+		if !ok {
+			t.Fatalf("timed out waiting for message %d", len(received)+1)
+		}
+		received = append(received, msg)
+	}
+
+	select {
+	case count := <-acked:
+		if count != uint32(len(events)) {
+			t.Errorf("expected ACK for %d events, got %d", len(events), count)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for ACK after batch was queued")
+	}
+
+	for index, expectedData := range []string{"first", "second", "third"} {
+		msg := received[index]
+		if string(msg.Data) != expectedData {
+			t.Errorf("expected data %q, got %q", expectedData, msg.Data)
+		}
+		if msg.Hostname != "agent01" {
+			t.Errorf("expected hostname %q, got %q", "agent01", msg.Hostname)
+		}
+		if msg.Fields[iomodules.CtxKey] == nil {
+			t.Errorf("expected source field %q to be set", iomodules.CtxKey)
+		}
+	}
+	if received[2].Fields[iomodules.CFseverity] != "err" {
+		t.Errorf("expected severity %q, got %v", "err", received[2].Fields[iomodules.CFseverity])
+	}
+}
+
+func TestInputContinuesAfterPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	// Queueing into a missing queue panics for every event
+	mod, err := NewInput(ctx, InputConfig{Address: "127.0.0.1:0"}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating input: %v", err)
+	}
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+	defer mod.Shutdown()
+
+	conn, err := net.Dial("tcp", mod.address)
+	if err != nil {
+		t.Fatalf("unexpected error connecting to beats input: %v", err)
+	}
+	client, err := lumberjack.NewSyncClientWithConn(conn, lumberjack.Timeout(2*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	defer client.Close()
+
+	// Every batch is still acknowledged, the reader keeps running after a failed batch
+	for batch := range 2 {
+		sent, err := client.Send([]any{map[string]any{"message": "panics"}})
+		if err != nil {
+			t.Fatalf("batch %d: unexpected error sending batch: %v", batch, err)
+		}
+		if sent != 1 {
+			t.Errorf("batch %d: expected 1 acknowledged event, got %d", batch, sent)
+		}
+	}
+}
+
+func TestInputTLS(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	certFile, keyFile, certPool := utils.WriteTestCertificate(t)
+
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 16, global.MinValue(16), global.MaxValue(16))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	mod, err := NewInput(ctx, InputConfig{Address: "127.0.0.1:0", CertFile: certFile, KeyFile: keyFile}, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error creating input: %v", err)
+	}
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+	defer mod.Shutdown()
+
+	conn, err := tls.Dial("tcp", mod.address, &tls.Config{RootCAs: certPool, ServerName: "localhost"})
+	if err != nil {
+		t.Fatalf("unexpected error connecting to beats TLS input: %v", err)
+	}
+	client, err := lumberjack.NewSyncClientWithConn(conn, lumberjack.Timeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	defer client.Close()
+
+	sent, err := client.Send([]any{map[string]any{"message": "over tls"}})
+	if err != nil {
+		t.Fatalf("unexpected error sending batch: %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 acknowledged event, got %d", sent)
+	}
+
+	popCtx, popCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer popCancel()
+	msg, ok := queue.Pop(popCtx)
+	if !ok {
+		t.Fatalf("timed out waiting for message")
+	}
+	if string(msg.Data) != "over tls" {
+		t.Errorf("expected data %q, got %q", "over tls", msg.Data)
+	}
+}
+
+func TestNewInputValidation(t *testing.T) {
+	ctx := logctx.New(context.Background(), logctx.NSTest, 1, nil)
+
+	tests := []struct {
+		name   string
+		config InputConfig
+	}{
+		{name: "key without certificate", config: InputConfig{Address: "127.0.0.1:0", KeyFile: "/nonexistent/key.pem"}},
+		{name: "client CA without certificate", config: InputConfig{Address: "127.0.0.1:0", ClientCAFile: "/nonexistent/ca.pem"}},
+		{name: "missing certificate files", config: InputConfig{Address: "127.0.0.1:0", CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"}},
+		{name: "invalid address", config: InputConfig{Address: "127.0.0.1:notaport"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mod, err := NewInput(ctx, tt.config, nil, nil)
+			if err == nil {
+				_ = mod.Shutdown()
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
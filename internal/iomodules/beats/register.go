@@ -0,0 +1,35 @@
+package beats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/iomodules"
+)
+
+const OutputType string = "beats"
+
+func init() {
+	iomodules.RegisterOutput(OutputType, newOutputFromConfig)
+}
+
+// Creates beats output from its JSON configuration block
+func newOutputFromConfig(config json.RawMessage) (module iomodules.Output, err error) {
+	options := OutputConfig{
+		MaxSendAttempts: DefaultMaxSendAttempts,
+	}
+	err = iomodules.DecodeOutputConfig(config, &options)
+	if err != nil {
+		return
+	}
+	if options.Address == "" {
+		err = fmt.Errorf("beats output requires an address")
+		return
+	}
+	if options.MaxSendAttempts < 1 {
+		err = fmt.Errorf("beats output max send attempts must be at least 1")
+		return
+	}
+
+	module, err = NewOutput(options.Address, options.MaxSendAttempts)
+	return
+}
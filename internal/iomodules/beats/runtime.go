@@ -1,5 +1,29 @@
 package beats
 
+// Starts batch reader in background
+func (mod *InModule) Start() (err error) {
+	mod.wg.Add(1)
+	go mod.batchReader()
+	return
+}
+
+// Gracefully stops module. Batches not queued yet are not acknowledged and will be resent by the clients.
+func (mod *InModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+	mod.wg.Wait()
+
+	if mod.server != nil {
+		err = mod.server.Close()
+	}
+	return
+}
+
 // Gracefully stops module
 func (mod *OutModule) Shutdown() (err error) {
 	if mod == nil {
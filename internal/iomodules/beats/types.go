@@ -2,9 +2,43 @@
 package beats
 
 import (
+	"context"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"sync/atomic"
+
 	lumberjack "github.com/elastic/go-lumber/client/v2"
+	lumberserver "github.com/elastic/go-lumber/server/v2"
 )
 
+type InModule struct {
+	// Settings
+	filters       []protocol.MessageFilter
+	localHostname string
+
+	// Input
+	server  *lumberserver.Server
+	address string // Bound listen address
+
+	// Output
+	outbox *mpmc.Queue[*protocol.Message]
+
+	metrics MetricStorage
+
+	wg     sync.WaitGroup     // Waiter for instance
+	cancel context.CancelFunc // cancel instance
+	ctx    context.Context
+}
+
+// Input server configuration block
+type InputConfig struct {
+	Address      string `json:"address"`                // Listen host:port
+	CertFile     string `json:"certFile,omitempty"`     // PEM server certificate (enables TLS)
+	KeyFile      string `json:"keyFile,omitempty"`      // PEM server private key (required with CertFile)
+	ClientCAFile string `json:"clientCAFile,omitempty"` // PEM CA bundle clients must present a certificate from (TLS only, optional)
+}
+
 type OutModule struct {
 	sink *lumberjack.SyncClient
 
@@ -14,3 +48,21 @@ type OutModule struct {
 	timeout        lumberjack.Option
 	maxSendRetries int
 }
+
+// Output configuration block
+type OutputConfig struct {
+	Address         string `json:"address"`                   // Beats server host:port
+	MaxSendAttempts int    `json:"maxSendAttempts,omitempty"` // Attempts (with reconnects) before a message is dropped
+}
+
+// Adapter for go-lumber library logging (logs with the context of the newest input module)
+type libraryLogger struct {
+	ctx atomic.Pointer[context.Context]
+}
+
+type MetricStorage struct {
+	EventsRead    atomic.Uint64 // number of events received from clients
+	ParseFailures atomic.Uint64 // number of events that could not be parsed
+	Batches       atomic.Uint64 // number of batches acknowledged to clients
+	Success       atomic.Uint64 // number of messages processed successfully
+}
@@ -0,0 +1,24 @@
+package dbusnotify
+
+import (
+	"encoding/json"
+	"sdsyslog/internal/iomodules"
+)
+
+const OutputType string = "dbusnotify"
+
+func init() {
+	iomodules.RegisterOutput(OutputType, newOutputFromConfig)
+}
+
+// Creates desktop notification output from its JSON configuration block
+func newOutputFromConfig(config json.RawMessage) (module iomodules.Output, err error) {
+	var options OutputConfig
+	err = iomodules.DecodeOutputConfig(config, &options)
+	if err != nil {
+		return
+	}
+
+	module, err = NewOutput(true)
+	return
+}
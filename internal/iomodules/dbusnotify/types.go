@@ -19,3 +19,6 @@ type notification struct {
 }
 
 type InModule struct{}
+
+// Output configuration block (no settings, presence enables notifications)
+type OutputConfig struct{}
@@ -0,0 +1,21 @@
+package devlog
+
+import "os"
+
+const (
+	DefaultPath string = "/dev/log"
+
+	// Custom fields (peer credentials)
+	CFuserID  string = "UserID"
+	CFgroupID string = "GroupID"
+
+	// Any local user must be able to log
+	socketPermissions os.FileMode = 0666
+
+	maxDatagramSize int = 65535
+	maxStreamFrame  int = 1024 * 1024
+
+	// Preserved socket name prefixes (lifecycle hot-swap)
+	preservedDatagramPrefix string = "unixgram:"
+	preservedStreamPrefix   string = "unix:"
+)
@@ -0,0 +1,116 @@
+package devlog
+
+import (
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/metrics"
+	"sync/atomic"
+	"time"
+)
+
+type MetricStorage struct {
+	MessagesRead  atomic.Uint64 // number of messages read from the sockets
+	ParseFailures atomic.Uint64 // number of messages that could not be parsed
+	MissingCreds  atomic.Uint64 // number of messages without peer credentials
+	Connections   atomic.Uint64 // number of accepted stream connections
+	Success       atomic.Uint64 // number of messages processed successfully
+	Dropped       atomic.Uint64 // number of messages dropped due to full queue
+}
+
+const (
+	MTMsgsRead     string = "messages_read"
+	MTParseFail    string = "parse_failures"
+	MTMissingCreds string = "missing_credentials"
+	MTConnections  string = "stream_connections"
+	MTSuc          string = "success_processed"
+)
+
+func (mod *InModule) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	// Read and clear
+	read := mod.metrics.MessagesRead.Swap(0)
+	parseFails := mod.metrics.ParseFailures.Swap(0)
+	missingCreds := mod.metrics.MissingCreds.Swap(0)
+	conns := mod.metrics.Connections.Swap(0)
+	suc := mod.metrics.Success.Swap(0)
+	dropped := mod.metrics.Dropped.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	namespace := logctx.GetTagList(mod.ctx)
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTMsgsRead,
+			Description: "Total messages read from local log sockets in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      read,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTParseFail,
+			Description: "Total local log socket messages that failed parsing in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      parseFails,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTMissingCreds,
+			Description: "Total local log socket messages received without peer credentials in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      missingCreds,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTConnections,
+			Description: "Total accepted local log stream connections in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      conns,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSuc,
+			Description: "Total processed messages extracted from local log sockets in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      suc,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        metrics.MTDropped,
+			Description: metrics.DescDropped,
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      dropped,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package devlog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+)
+
+// Creates new local log socket input module. Binds the given datagram and/or stream socket paths. Returns nil nil if no paths.
+// Sockets are preserved across self updates so local writers never see the socket disappear.
+func NewInput(ctx context.Context, datagramPath string, streamPath string, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (module *InModule, err error) {
+	if datagramPath == "" && streamPath == "" {
+		return
+	}
+	if datagramPath == streamPath {
+		err = fmt.Errorf("datagram and stream sockets cannot share the same path %q", datagramPath)
+		return
+	}
+
+	for index, filter := range filters {
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter at index %d: %w", index, err)
+			return
+		}
+	}
+
+	localHostname, err := os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve local hostname: %w", err)
+		return
+	}
+
+	// New context for sockets
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoDevLog)
+	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
+	modCtx, cancel := context.WithCancel(modCtx)
+
+	new := &InModule{
+		localHostname: localHostname,
+		filters:       filters,
+		datagramPath:  datagramPath,
+		streamPath:    streamPath,
+		streamConns:   make(map[net.Conn]struct{}),
+		outbox:        queue,
+		metrics:       MetricStorage{},
+		ctx:           modCtx,
+		cancel:        cancel,
+	}
+
+	if datagramPath != "" {
+		new.datagramConn, err = listenDatagram(datagramPath)
+		if err != nil {
+			err = fmt.Errorf("failed to listen on %q: %w", datagramPath, err)
+			cancel()
+			return
+		}
+	}
+
+	if streamPath != "" {
+		new.streamListener, err = listenStream(streamPath)
+		if err != nil {
+			err = fmt.Errorf("failed to listen on %q: %w", streamPath, err)
+			if new.datagramConn != nil {
+				_ = new.datagramConn.Close()
+			}
+			cancel()
+			return
+		}
+	}
+
+	module = new
+	return
+}
@@ -0,0 +1,199 @@
+package devlog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"runtime/debug"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/syslog"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Reads datagrams from the local socket. Each datagram is exactly one message.
+func (mod *InModule) datagramReader() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	buf := make([]byte, maxDatagramSize)
+	oob := make([]byte, unix.CmsgSpace(unix.SizeofUcred))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		n, oobn, _, _, err := mod.datagramConn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logctx.LogStdErr(ctx, "failed to read from local datagram socket: %w\n", err)
+			continue
+		}
+
+		creds, found := parseCredentials(oob[:oobn])
+		if !found {
+			mod.metrics.MissingCreds.Add(1)
+		}
+
+		msg := mod.handleMessage(buf[:n], creds, found)
+		if msg == nil {
+			continue
+		}
+
+		// Local writers are not slowed down by a full queue (matches journald/rsyslog behavior)
+		err = mod.outbox.Push(msg, uint64(msg.Size()))
+		if err != nil {
+			mod.metrics.Dropped.Add(1)
+			continue
+		}
+		mod.metrics.Success.Add(1)
+	}
+}
+
+// Accepts new stream connections until shutdown
+func (mod *InModule) streamAcceptor() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	for {
+		conn, err := mod.streamListener.AcceptUnix()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logctx.LogStdErr(ctx, "failed to accept local stream connection: %w\n", err)
+			continue
+		}
+
+		mod.streamConnMu.Lock()
+		if ctx.Err() != nil {
+			// Shutdown raced with accept
+			mod.streamConnMu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		mod.streamConns[conn] = struct{}{}
+		mod.streamConnMu.Unlock()
+
+		mod.metrics.Connections.Add(1)
+		mod.wg.Add(1)
+		go mod.streamReader(conn)
+	}
+}
+
+// Reads messages from a single stream connection.
+// Messages are terminated by NUL (libc syslog(3)) or newline (logger and most other writers).
+func (mod *InModule) streamReader(conn *net.UnixConn) {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	defer func() {
+		mod.streamConnMu.Lock()
+		delete(mod.streamConns, conn)
+		mod.streamConnMu.Unlock()
+		_ = conn.Close()
+	}()
+
+	// Record panics and drop connection
+	defer func() {
+		if fatalError := recover(); fatalError != nil {
+			stack := debug.Stack()
+			logctx.LogStdErr(ctx,
+				"panic in local stream reader thread: %v\n%s", fatalError, stack)
+		}
+	}()
+
+	creds, err := getPeerCred(conn)
+	found := err == nil
+	if !found {
+		logctx.LogEvent(ctx, logctx.VerbosityData, logctx.WarnLog,
+			"failed to retrieve peer credentials of local stream connection: %w\n", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamFrame)
+	scanner.Split(splitMessages)
+	for scanner.Scan() {
+		frame := scanner.Bytes()
+		if len(frame) == 0 {
+			continue
+		}
+
+		if !found {
+			mod.metrics.MissingCreds.Add(1)
+		}
+
+		msg := mod.handleMessage(frame, creds, found)
+		if msg == nil {
+			continue
+		}
+
+		// Stream writers get back-pressure instead of drops
+		mod.outbox.PushBlocking(ctx, msg, msg.Size())
+		mod.metrics.Success.Add(1)
+	}
+
+	err = scanner.Err()
+	if err != nil && ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+		logctx.LogStdErr(ctx, "closing local stream connection: %w\n", err)
+	}
+}
+
+// Splits stream data on NUL or newline terminators
+func splitMessages(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return
+	}
+
+	end := bytes.IndexAny(data, "\x00\n")
+	if end >= 0 {
+		advance = end + 1
+		token = data[:end]
+		return
+	}
+
+	if atEOF {
+		advance = len(data)
+		token = data
+	}
+	return
+}
+
+// Parses, annotates, and filters a raw message. Returns nil when the message should not be forwarded.
+func (mod *InModule) handleMessage(raw []byte, creds peerCredentials, hasCreds bool) (msg *protocol.Message) {
+	mod.metrics.MessagesRead.Add(1)
+
+	msg, err := syslog.ParseLocalMessage(raw, mod.localHostname)
+	if err != nil {
+		mod.metrics.ParseFailures.Add(1)
+		logctx.LogEvent(mod.ctx, logctx.VerbosityData, logctx.WarnLog,
+			"failed to parse local syslog message: %w\n", err)
+		msg = nil
+		return
+	}
+
+	msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(mod.ctx), "/")
+	if hasCreds {
+		// Kernel provided values always win over what the writer claims
+		msg.Fields[iomodules.CFprocessid] = int(creds.pid)
+		msg.Fields[CFuserID] = int(creds.uid)
+		msg.Fields[CFgroupID] = int(creds.gid)
+	}
+
+	for _, filter := range mod.filters {
+		if filter.Match(msg) {
+			// First filter match wins - drop message
+			msg = nil
+			return
+		}
+	}
+	return
+}
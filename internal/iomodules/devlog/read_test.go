@@ -0,0 +1,222 @@
+package devlog
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitMessages(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedFrames []string
+	}{
+		{
+			name:           "nul terminated",
+			input:          "<13>one\x00<13>two\x00",
+			expectedFrames: []string{"<13>one", "<13>two"},
+		},
+		{
+			name:           "newline terminated",
+			input:          "<13>one\n<13>two\n",
+			expectedFrames: []string{"<13>one", "<13>two"},
+		},
+		{
+			name:           "mixed with trailing unterminated",
+			input:          "<13>one\x00<13>two\n<13>three",
+			expectedFrames: []string{"<13>one", "<13>two", "<13>three"},
+		},
+		{
+			name:           "empty",
+			input:          "",
+			expectedFrames: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tt.input))
+			scanner.Split(splitMessages)
+
+			var frames []string
+			for scanner.Scan() {
+				frames = append(frames, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(frames, tt.expectedFrames) {
+				t.Errorf("expected frames %q, got %q", tt.expectedFrames, frames)
+			}
+		})
+	}
+}
+
+func TestSocketInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	localHostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("unexpected error retrieving system hostname: %v", err)
+	}
+
+	dir := t.TempDir()
+	datagramPath := filepath.Join(dir, "log")
+	streamPath := filepath.Join(dir, "log-stream")
+
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 1024, global.MinValue(1024), global.MaxValue(1024))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	mod, err := NewInput(ctx, datagramPath, streamPath, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error creating input: %v", err)
+	}
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+
+	info, err := os.Stat(datagramPath)
+	if err != nil {
+		t.Fatalf("expected datagram socket to exist: %v", err)
+	}
+	if info.Mode().Perm() != socketPermissions {
+		t.Errorf("expected socket permissions %v, got %v", socketPermissions, info.Mode().Perm())
+	}
+
+	expectMessage := func(expectedData string, expectedApp string) {
+		t.Helper()
+
+		popCtx, popCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer popCancel()
+		msg, ok := queue.Pop(popCtx)
+		if !ok {
+			t.Fatalf("timed out waiting for message %q", expectedData)
+		}
+
+		if string(msg.Data) != expectedData {
+			t.Errorf("expected data %q, got %q", expectedData, string(msg.Data))
+		}
+		if msg.Hostname != localHostname {
+			t.Errorf("expected hostname %q, got %q", localHostname, msg.Hostname)
+		}
+		if msg.Fields[iomodules.CFappname] != expectedApp {
+			t.Errorf("expected application name %q, got %v", expectedApp, msg.Fields[iomodules.CFappname])
+		}
+		if msg.Fields[iomodules.CFprocessid] != os.Getpid() {
+			t.Errorf("expected credential PID %d, got %v", os.Getpid(), msg.Fields[iomodules.CFprocessid])
+		}
+		if msg.Fields[CFuserID] != os.Getuid() {
+			t.Errorf("expected credential UID %d, got %v", os.Getuid(), msg.Fields[CFuserID])
+		}
+		if msg.Fields[CFgroupID] != os.Getgid() {
+			t.Errorf("expected credential GID %d, got %v", os.Getgid(), msg.Fields[CFgroupID])
+		}
+	}
+
+	// Datagram (libc style, claimed PID must be replaced by kernel credentials)
+	datagramClient, err := net.Dial("unixgram", datagramPath)
+	if err != nil {
+		t.Fatalf("unexpected error connecting to datagram socket: %v", err)
+	}
+	defer datagramClient.Close()
+	_, err = datagramClient.Write([]byte("<14>Oct 16 12:00:00 myapp[1]: datagram message"))
+	if err != nil {
+		t.Fatalf("unexpected error writing datagram: %v", err)
+	}
+	expectMessage("datagram message", "myapp")
+
+	// Stream
+	streamClient, err := net.Dial("unix", streamPath)
+	if err != nil {
+		t.Fatalf("unexpected error connecting to stream socket: %v", err)
+	}
+	_, err = streamClient.Write([]byte("<14>Oct 16 12:00:00 streamapp: first\x00<14>Oct 16 12:00:01 streamapp: second\n"))
+	if err != nil {
+		t.Fatalf("unexpected error writing stream: %v", err)
+	}
+	expectMessage("first", "streamapp")
+	expectMessage("second", "streamapp")
+	_ = streamClient.Close()
+
+	// Simulate hot-swap: messages written while no instance runs must not be lost
+	err = mod.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected error shutting down input: %v", err)
+	}
+	_, err = datagramClient.Write([]byte("<14>Oct 16 12:00:02 myapp: during swap"))
+	if err != nil {
+		t.Fatalf("expected datagram socket to stay bound during swap: %v", err)
+	}
+
+	mod, err = NewInput(ctx, datagramPath, streamPath, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error re-creating input: %v", err)
+	}
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error restarting input: %v", err)
+	}
+	defer mod.Shutdown()
+
+	expectMessage("during swap", "myapp")
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	// Regular files are never removed
+	regularPath := filepath.Join(dir, "regular")
+	err := os.WriteFile(regularPath, []byte("data"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	err = removeStaleSocket(regularPath, "unixgram")
+	if err == nil {
+		t.Errorf("expected error for regular file, got nil")
+	}
+
+	// Sockets in use are never removed
+	activePath := filepath.Join(dir, "active")
+	active, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: activePath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("unexpected error binding socket: %v", err)
+	}
+	err = removeStaleSocket(activePath, "unixgram")
+	if err == nil {
+		t.Errorf("expected error for socket in use, got nil")
+	}
+
+	// Left over sockets are removed
+	_ = active.Close()
+	err = removeStaleSocket(activePath, "unixgram")
+	if err != nil {
+		t.Errorf("unexpected error removing stale socket: %v", err)
+	}
+	_, err = os.Stat(activePath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected stale socket to be removed, got %v", err)
+	}
+
+	// Missing path is a no-op
+	err = removeStaleSocket(filepath.Join(dir, "missing"), "unixgram")
+	if err != nil {
+		t.Errorf("unexpected error for missing path: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package devlog
+
+// Starts socket readers in background
+func (mod *InModule) Start() (err error) {
+	if mod.datagramConn != nil {
+		mod.wg.Add(1)
+		go mod.datagramReader()
+	}
+	if mod.streamListener != nil {
+		mod.wg.Add(1)
+		go mod.streamAcceptor()
+	}
+	return
+}
+
+// Gracefully stops module.
+// Only this process's handles are closed, the bound sockets stay preserved for the next process after a self update.
+// Unread datagrams and pending stream connections remain queued in the kernel until then.
+func (mod *InModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+
+	// Unblock readers
+	if mod.datagramConn != nil {
+		lerr := mod.datagramConn.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	if mod.streamListener != nil {
+		lerr := mod.streamListener.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	mod.streamConnMu.Lock()
+	for conn := range mod.streamConns {
+		_ = conn.Close()
+	}
+	mod.streamConnMu.Unlock()
+
+	mod.wg.Wait()
+	return
+}
@@ -0,0 +1,204 @@
+package devlog
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sdsyslog/internal/lifecycle"
+
+	"golang.org/x/sys/unix"
+)
+
+// Binds (or reuses the socket preserved across a self update) a Unix datagram socket at path with credential passing enabled
+func listenDatagram(path string) (conn *net.UnixConn, err error) {
+	preservedName := preservedDatagramPrefix + path
+
+	file := lifecycle.PreservedFile(preservedName)
+	if file != nil {
+		var packetConn net.PacketConn
+		packetConn, err = net.FilePacketConn(file) // Duplicate - preserved original stays open
+		if err != nil {
+			err = fmt.Errorf("failed to reuse preserved datagram socket: %w", err)
+			return
+		}
+		var ok bool
+		conn, ok = packetConn.(*net.UnixConn)
+		if !ok {
+			_ = packetConn.Close()
+			err = fmt.Errorf("preserved socket for %q is not a Unix datagram socket (type %T)", path, packetConn)
+			return
+		}
+	} else {
+		err = removeStaleSocket(path, "unixgram")
+		if err != nil {
+			return
+		}
+
+		conn, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		if err != nil {
+			err = fmt.Errorf("failed to bind datagram socket: %w", err)
+			return
+		}
+		err = os.Chmod(path, socketPermissions)
+		if err != nil {
+			_ = conn.Close()
+			err = fmt.Errorf("failed to set socket permissions: %w", err)
+			return
+		}
+
+		file, err = conn.File()
+		if err != nil {
+			_ = conn.Close()
+			err = fmt.Errorf("failed to duplicate datagram socket for preservation: %w", err)
+			return
+		}
+		lifecycle.PreserveFile(preservedName, file)
+	}
+
+	err = setPassCred(conn)
+	if err != nil {
+		_ = conn.Close()
+		err = fmt.Errorf("failed to enable credential passing: %w", err)
+		return
+	}
+	return
+}
+
+// Binds (or reuses the socket preserved across a self update) a Unix stream socket at path
+func listenStream(path string) (listener *net.UnixListener, err error) {
+	preservedName := preservedStreamPrefix + path
+
+	file := lifecycle.PreservedFile(preservedName)
+	if file != nil {
+		var genericListener net.Listener
+		genericListener, err = net.FileListener(file) // Duplicate - preserved original stays open
+		if err != nil {
+			err = fmt.Errorf("failed to reuse preserved stream socket: %w", err)
+			return
+		}
+		var ok bool
+		listener, ok = genericListener.(*net.UnixListener)
+		if !ok {
+			_ = genericListener.Close()
+			err = fmt.Errorf("preserved socket for %q is not a Unix stream socket (type %T)", path, genericListener)
+			return
+		}
+	} else {
+		err = removeStaleSocket(path, "unix")
+		if err != nil {
+			return
+		}
+
+		listener, err = net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			err = fmt.Errorf("failed to bind stream socket: %w", err)
+			return
+		}
+		err = os.Chmod(path, socketPermissions)
+		if err != nil {
+			_ = listener.Close()
+			err = fmt.Errorf("failed to set socket permissions: %w", err)
+			return
+		}
+
+		file, err = listener.File()
+		if err != nil {
+			_ = listener.Close()
+			err = fmt.Errorf("failed to duplicate stream socket for preservation: %w", err)
+			return
+		}
+		lifecycle.PreserveFile(preservedName, file)
+	}
+
+	// Path must survive shutdown so clients can keep connecting during a hot-swap
+	listener.SetUnlinkOnClose(false)
+	return
+}
+
+// Removes a leftover socket file from a previous run.
+// Refuses to touch non-socket files or sockets that still have a listener (like another syslog daemon).
+func removeStaleSocket(path string, network string) (err error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		err = fmt.Errorf("path %q exists and is not a socket", path)
+		return
+	}
+
+	probe, err := net.Dial(network, path)
+	if err == nil {
+		_ = probe.Close()
+		err = fmt.Errorf("socket %q is already in use by another process", path)
+		return
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		err = fmt.Errorf("failed to remove stale socket %q: %w", path, err)
+		return
+	}
+	return
+}
+
+// Enables SO_PASSCRED so the kernel attaches sender credentials to every datagram
+func setPassCred(conn *net.UnixConn) (err error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+	ctrlErr := rawConn.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_PASSCRED, 1)
+	})
+	if ctrlErr != nil {
+		err = ctrlErr
+	}
+	return
+}
+
+// Retrieves credentials of the process on the other end of a stream connection (as of connect time)
+func getPeerCred(conn *net.UnixConn) (creds peerCredentials, err error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	var ucred *unix.Ucred
+	ctrlErr := rawConn.Control(func(fd uintptr) {
+		ucred, err = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if ctrlErr != nil {
+		err = ctrlErr
+	}
+	if err != nil {
+		return
+	}
+
+	creds = peerCredentials{pid: ucred.Pid, uid: ucred.Uid, gid: ucred.Gid}
+	return
+}
+
+// Extracts SCM_CREDENTIALS from datagram ancillary data
+func parseCredentials(oob []byte) (creds peerCredentials, found bool) {
+	controlMsgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return
+	}
+
+	for _, controlMsg := range controlMsgs {
+		ucred, err := unix.ParseUnixCredentials(&controlMsg)
+		if err != nil {
+			continue
+		}
+		creds = peerCredentials{pid: ucred.Pid, uid: ucred.Uid, gid: ucred.Gid}
+		found = true
+		return
+	}
+	return
+}
@@ -0,0 +1,37 @@
+// IOModule for reading local syslog messages from Unix sockets (/dev/log)
+package devlog
+
+import (
+	"context"
+	"net"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"sync"
+)
+
+type InModule struct {
+	localHostname string
+	filters       []protocol.MessageFilter
+
+	// Read Sources
+	datagramPath   string
+	streamPath     string
+	datagramConn   *net.UnixConn
+	streamListener *net.UnixListener
+	streamConnMu   sync.Mutex
+	streamConns    map[net.Conn]struct{}
+
+	outbox  *mpmc.Queue[*protocol.Message]
+	metrics MetricStorage
+
+	wg     sync.WaitGroup     // Waiter for instance
+	cancel context.CancelFunc // cancel instance
+	ctx    context.Context
+}
+
+// Kernel verified identity of the process that wrote a message
+type peerCredentials struct {
+	pid int32
+	uid uint32
+	gid uint32
+}
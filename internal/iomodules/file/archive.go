@@ -0,0 +1,224 @@
+package file
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Reports whether the file must be rotated before the next line is appended
+func (mod *OutModule) needsRotation(handle *outputFile, lineLen int, now time.Time) (rotate bool) {
+	if handle.size == 0 {
+		return
+	}
+	if mod.maxSize > 0 && handle.size+uint64(lineLen) > mod.maxSize {
+		rotate = true
+		return
+	}
+	if mod.rotateInterval > 0 && now.Sub(handle.rotationStart) >= mod.rotateInterval {
+		rotate = true
+		return
+	}
+	return
+}
+
+// Closes and renames the current file, then compresses and prunes old rotated files in the background
+func (mod *OutModule) rotate(path string, handle *outputFile, now time.Time) (err error) {
+	delete(mod.files, path)
+	err = handle.file.Close()
+	if err != nil {
+		err = fmt.Errorf("failed to close file for rotation: %w", err)
+		return
+	}
+
+	rotatedPath := unusedRotatedPath(path, now)
+	err = os.Rename(path, rotatedPath)
+	if err != nil {
+		err = fmt.Errorf("failed to rotate file: %w", err)
+		return
+	}
+
+	mod.archivers.Go(func() {
+		mod.archiveMu.Lock()
+		defer mod.archiveMu.Unlock()
+
+		lerr := mod.archive(path, rotatedPath)
+		if lerr != nil {
+			mod.archiveErrMu.Lock()
+			mod.archiveErr = lerr
+			mod.archiveErrMu.Unlock()
+		}
+	})
+	return
+}
+
+// Returns (and clears) the last error of background archiving
+func (mod *OutModule) takeArchiveError() (err error) {
+	mod.archiveErrMu.Lock()
+	defer mod.archiveErrMu.Unlock()
+
+	err = mod.archiveErr
+	mod.archiveErr = nil
+	return
+}
+
+// Compresses a rotated file and removes rotated files of path beyond the retention count
+func (mod *OutModule) archive(path string, rotatedPath string) (err error) {
+	if mod.compression != "" {
+		err = compressFile(rotatedPath, mod.compression)
+		if os.IsNotExist(err) {
+			// Already pruned by a later rotation
+			err = nil
+		} else if err != nil {
+			err = fmt.Errorf("failed to compress rotated file %q: %w", rotatedPath, err)
+			return
+		}
+	}
+
+	if mod.retention == 0 {
+		return
+	}
+	rotated, err := rotatedFiles(path)
+	if err != nil {
+		err = fmt.Errorf("failed to list rotated files: %w", err)
+		return
+	}
+	for len(rotated) > mod.retention {
+		err = os.Remove(rotated[0])
+		if err != nil && !os.IsNotExist(err) {
+			err = fmt.Errorf("failed to remove old rotated file: %w", err)
+			return
+		}
+		err = nil
+		rotated = rotated[1:]
+	}
+	return
+}
+
+// Replaces file with a compressed copy
+func compressFile(path string, compression string) (err error) {
+	extension := gzipExtension
+	if compression == CompressionZstd {
+		extension = zstdExtension
+	}
+	compressedPath := path + extension
+	partialPath := compressedPath + partialExtension
+
+	source, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = source.Close()
+	}()
+
+	destination, err := os.OpenFile(partialPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, outputFileMode)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = destination.Close()
+			_ = os.Remove(partialPath)
+		}
+	}()
+
+	var encoder io.WriteCloser
+	if compression == CompressionZstd {
+		encoder, err = zstd.NewWriter(destination)
+		if err != nil {
+			return
+		}
+	} else {
+		encoder = gzip.NewWriter(destination)
+	}
+
+	_, err = io.Copy(encoder, source)
+	if err != nil {
+		_ = encoder.Close()
+		return
+	}
+	err = encoder.Close()
+	if err != nil {
+		return
+	}
+	err = destination.Sync()
+	if err != nil {
+		return
+	}
+	err = destination.Close()
+	if err != nil {
+		return
+	}
+
+	err = os.Rename(partialPath, compressedPath)
+	if err != nil {
+		return
+	}
+	err = os.Remove(path)
+	return
+}
+
+// Rotated copies of path (compressed or not), oldest first
+func rotatedFiles(path string) (rotated []string, err error) {
+	directory := filepath.Dir(path)
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		candidate := filepath.Join(directory, entry.Name())
+		_, valid := rotationStamp(path, candidate)
+		if valid {
+			rotated = append(rotated, candidate)
+		}
+	}
+	slices.Sort(rotated)
+	return
+}
+
+// Name for a rotated copy of path, moved forward in time if a copy with that stamp already exists
+func unusedRotatedPath(path string, now time.Time) (rotatedPath string) {
+	stamp := now.UTC()
+	for {
+		rotatedPath = path + "." + stamp.Format(rotatedTimeLayout)
+
+		var taken bool
+		for _, extension := range []string{"", gzipExtension, zstdExtension} {
+			_, err := os.Lstat(rotatedPath + extension)
+			if err == nil {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return
+		}
+		stamp = stamp.Add(time.Nanosecond)
+	}
+}
+
+// Parses rotation time from the name of a rotated copy of path
+func rotationStamp(path string, rotatedPath string) (stamp time.Time, valid bool) {
+	suffix, found := strings.CutPrefix(filepath.Base(rotatedPath), filepath.Base(path)+".")
+	if !found {
+		return
+	}
+	suffix = strings.TrimSuffix(suffix, gzipExtension)
+	suffix = strings.TrimSuffix(suffix, zstdExtension)
+
+	stamp, err := time.Parse(rotatedTimeLayout, suffix)
+	if err != nil {
+		return
+	}
+	valid = true
+	return
+}
@@ -0,0 +1,350 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/parsing"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestRotation(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            OutputConfig
+		messages          int
+		expectedRotated   int
+		expectedExtension string
+	}{
+		{
+			name:            "size rotation keeps all",
+			config:          OutputConfig{MaxSizeBytes: 300},
+			messages:        10,
+			expectedRotated: 4,
+		},
+		{
+			name:              "size rotation gzip with retention",
+			config:            OutputConfig{MaxSizeBytes: 300, Compression: CompressionGzip, Retention: 2},
+			messages:          10,
+			expectedRotated:   2,
+			expectedExtension: gzipExtension,
+		},
+		{
+			name:              "size rotation zstd with retention",
+			config:            OutputConfig{MaxSizeBytes: 300, Compression: CompressionZstd, Retention: 3},
+			messages:          10,
+			expectedRotated:   3,
+			expectedExtension: zstdExtension,
+		},
+		{
+			name:            "no rotation below limit",
+			config:          OutputConfig{MaxSizeBytes: 1 << 20},
+			messages:        10,
+			expectedRotated: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+			tempDir := t.TempDir()
+			tt.config.Path = filepath.Join(tempDir, "{hostname}", "messages.log")
+
+			outMod, err := NewOutput(tt.config)
+			if err != nil {
+				t.Fatalf("failed to create output module: %v", err)
+			}
+
+			// Single batch, rotation happens within one flush
+			startTime := time.Now()
+			for index := range tt.messages {
+				_, err = outMod.Write(ctx, &protocol.Payload{
+					Hostname:  "web01",
+					Timestamp: startTime.Add(time.Duration(index) * time.Second),
+					Data:      fmt.Appendf(nil, "message %02d %s", index, strings.Repeat("x", 80)),
+				})
+				if err != nil {
+					t.Fatalf("failed to write message: %v", err)
+				}
+			}
+			_, err = outMod.FlushBuffer()
+			if err != nil {
+				t.Fatalf("failed to flush write buffer: %v", err)
+			}
+			err = outMod.Shutdown()
+			if err != nil {
+				t.Fatalf("failed to shutdown output module: %v", err)
+			}
+
+			activePath := filepath.Join(tempDir, "web01", "messages.log")
+			rotated, err := rotatedFiles(activePath)
+			if err != nil {
+				t.Fatalf("failed to list rotated files: %v", err)
+			}
+			if len(rotated) != tt.expectedRotated {
+				t.Fatalf("expected %d rotated files, but found %d: %q", tt.expectedRotated, len(rotated), rotated)
+			}
+
+			// Oldest retained first, then the active file - messages must remain in order
+			var contents []byte
+			for _, rotatedPath := range rotated {
+				if !strings.HasSuffix(rotatedPath, tt.expectedExtension) || filepath.Ext(rotatedPath) == partialExtension {
+					t.Errorf("expected rotated file %q to have extension %q", rotatedPath, tt.expectedExtension)
+				}
+				contents = append(contents, readRotated(t, rotatedPath)...)
+			}
+			active, err := os.ReadFile(activePath)
+			if err != nil {
+				t.Fatalf("failed to read active file: %v", err)
+			}
+			contents = append(contents, active...)
+
+			lines := bytes.Split(bytes.TrimSuffix(contents, []byte("\n")), []byte("\n"))
+			firstKept := tt.messages - len(lines)
+			if tt.config.Retention == 0 && firstKept != 0 {
+				t.Errorf("expected all %d messages to be kept, but found %d", tt.messages, len(lines))
+			}
+			for index, line := range lines {
+				expected := fmt.Sprintf("message %02d ", firstKept+index)
+				if !bytes.Contains(line, []byte(expected)) {
+					t.Errorf("expected line %d to contain %q, but got %q", index, expected, line)
+				}
+			}
+			if len(active) > int(tt.config.MaxSizeBytes) {
+				t.Errorf("expected active file to stay below %d bytes, but got %d bytes", tt.config.MaxSizeBytes, len(active))
+			}
+		})
+	}
+}
+
+func TestRotationInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	config := OutputConfig{
+		Path:           filepath.Join(tempDir, "messages.log"),
+		BatchSize:      1,
+		RotateInterval: parsing.Duration(50 * time.Millisecond),
+	}
+
+	outMod, err := NewOutput(config)
+	if err != nil {
+		t.Fatalf("failed to create output module: %v", err)
+	}
+	write := func(text string) {
+		_, err := outMod.Write(ctx, &protocol.Payload{Hostname: "web01", Timestamp: time.Now(), Data: []byte(text)})
+		if err == nil {
+			_, err = outMod.FlushBuffer()
+		}
+		if err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+	}
+
+	write("first")
+	write("second")
+	time.Sleep(100 * time.Millisecond)
+	write("third")
+
+	err = outMod.Shutdown()
+	if err != nil {
+		t.Fatalf("failed to shutdown output module: %v", err)
+	}
+
+	rotated, err := rotatedFiles(config.Path)
+	if err != nil {
+		t.Fatalf("failed to list rotated files: %v", err)
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("expected 1 rotated file, but found %d: %q", len(rotated), rotated)
+	}
+	old := readRotated(t, rotated[0])
+	if !bytes.Contains(old, []byte("first")) || !bytes.Contains(old, []byte("second")) {
+		t.Errorf("expected rotated file to contain first and second message, but got %q", old)
+	}
+	active, err := os.ReadFile(config.Path)
+	if err != nil {
+		t.Fatalf("failed to read active file: %v", err)
+	}
+	if !bytes.Contains(active, []byte("third")) || bytes.Contains(active, []byte("second")) {
+		t.Errorf("expected active file to contain only the third message, but got %q", active)
+	}
+
+	// Rotation start survives restarts (taken from newest rotated file)
+	started := lastRotation(config.Path)
+	stamp, _ := rotationStamp(config.Path, rotated[0])
+	if !started.Equal(stamp) {
+		t.Errorf("expected rotation start %v after restart, but got %v", stamp, started)
+	}
+}
+
+func TestRotationIntervalAfterEviction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	outMod, err := NewOutput(OutputConfig{
+		Path:           filepath.Join(tempDir, "{hostname}.log"),
+		BatchSize:      1,
+		MaxOpenFiles:   1,
+		RotateInterval: parsing.Duration(100 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("failed to create output module: %v", err)
+	}
+	write := func(host string) {
+		_, err := outMod.Write(ctx, &protocol.Payload{Hostname: host, Timestamp: time.Now(), Data: []byte("from " + host)})
+		if err == nil {
+			_, err = outMod.FlushBuffer()
+		}
+		if err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+	}
+
+	activePath := filepath.Join(tempDir, "a.log")
+	write("a")
+	if _, ok := getBirthTime(activePath); !ok {
+		t.Skip("filesystem does not record file creation times")
+	}
+
+	// Every write to a reopens its file, the handle of the other host evicts it in between
+	for range 5 {
+		time.Sleep(40 * time.Millisecond)
+		write("b")
+		write("a")
+	}
+
+	err = outMod.Shutdown()
+	if err != nil {
+		t.Fatalf("failed to shutdown output module: %v", err)
+	}
+
+	rotated, err := rotatedFiles(activePath)
+	if err != nil {
+		t.Fatalf("failed to list rotated files: %v", err)
+	}
+	if len(rotated) == 0 {
+		t.Errorf("expected file reopened after eviction to be rotated by age")
+	}
+}
+
+func TestMaxOpenFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	outMod, err := NewOutput(OutputConfig{
+		Path:         filepath.Join(tempDir, "{hostname}.log"),
+		MaxOpenFiles: 2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create output module: %v", err)
+	}
+
+	hosts := []string{"a", "b", "c", "a", "d", "a"}
+	for _, host := range hosts {
+		_, err = outMod.Write(ctx, &protocol.Payload{Hostname: host, Timestamp: time.Now(), Data: []byte("from " + host)})
+		if err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+		_, err = outMod.FlushBuffer()
+		if err != nil {
+			t.Fatalf("failed to flush write buffer: %v", err)
+		}
+		if len(outMod.files) > 2 {
+			t.Fatalf("expected at most 2 open files, but found %d", len(outMod.files))
+		}
+	}
+
+	// Most recently used host stays open
+	_, open := outMod.files[filepath.Join(tempDir, "a.log")]
+	if !open {
+		t.Errorf("expected most recently used file to remain open")
+	}
+
+	err = outMod.Shutdown()
+	if err != nil {
+		t.Fatalf("failed to shutdown output module: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(tempDir, "a.log"))
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if bytes.Count(contents, []byte("from a")) != 3 {
+		t.Errorf("expected 3 lines from host a after reopening, but got %q", contents)
+	}
+}
+
+func TestNewOutputValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	tests := []struct {
+		name   string
+		config OutputConfig
+	}{
+		{name: "unknown format", config: OutputConfig{Path: filepath.Join(tempDir, "a.log"), Format: "xml"}},
+		{name: "unknown compression", config: OutputConfig{Path: filepath.Join(tempDir, "a.log"), Compression: "lz4"}},
+		{name: "negative retention", config: OutputConfig{Path: filepath.Join(tempDir, "a.log"), Retention: -1}},
+		{name: "unknown placeholder", config: OutputConfig{Path: filepath.Join(tempDir, "{user}.log")}},
+		{name: "missing directory", config: OutputConfig{Path: filepath.Join(tempDir, "missing", "a.log")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOutput(tt.config)
+			if err == nil {
+				t.Errorf("expected error, but got none")
+			}
+		})
+	}
+}
+
+// Reads (and decompresses) a rotated file
+func readRotated(t *testing.T, path string) (contents []byte) {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open rotated file: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	switch filepath.Ext(path) {
+	case gzipExtension:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("failed to open gzip file: %v", err)
+		}
+		reader = gzipReader
+	case zstdExtension:
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			t.Fatalf("failed to open zstd file: %v", err)
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+	}
+
+	contents, err = io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read rotated file: %v", err)
+	}
+	return
+}
@@ -0,0 +1,239 @@
+package file
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sdsyslog/pkg/crypto/registry"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Reads the receivers' audit signing key (base64 encoded private key)
+func loadAuditKey(keyPath string) (key []byte, err error) {
+	encodedKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		err = fmt.Errorf("failed to read audit signing key: %w", err)
+		return
+	}
+	key, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encodedKey)))
+	if err != nil {
+		err = fmt.Errorf("failed to decode audit signing key: %w", err)
+		return
+	}
+
+	suite, _ := registry.GetSignatureInfo(auditSignatureSuite)
+	err = suite.ValidateKey(key)
+	if err != nil {
+		err = fmt.Errorf("invalid audit signing key: %w", err)
+		return
+	}
+	return
+}
+
+// Hash of an audit record, chained to the previous record
+func auditHash(prev [sha256.Size]byte, seq uint64, checkpoint bool, entry string) (hash [sha256.Size]byte) {
+	hasher := sha256.New()
+	hasher.Write(prev[:])
+	hasher.Write(binary.BigEndian.AppendUint64(nil, seq))
+	if checkpoint {
+		hasher.Write([]byte{auditKindCheckpoint})
+	} else {
+		hasher.Write([]byte{auditKindEntry})
+	}
+	hasher.Write([]byte(entry))
+	hasher.Sum(hash[:0])
+	return
+}
+
+// Wraps a formatted line into the next record of the chain. Chain is only advanced by commit.
+func (chain *auditChain) next(line string) (record auditRecord, hash [sha256.Size]byte) {
+	// Hashed text must survive the JSON round trip unchanged
+	entry := strings.ToValidUTF8(strings.TrimSuffix(line, "\n"), string(utf8.RuneError))
+
+	seq := chain.seq + 1
+	hash = auditHash(chain.hash, seq, false, entry)
+	record = auditRecord{
+		Seq:   seq,
+		Prev:  hex.EncodeToString(chain.hash[:]),
+		Entry: entry,
+		Hash:  hex.EncodeToString(hash[:]),
+	}
+	return
+}
+
+// Creates the signed checkpoint record covering every record of the chain so far
+func (chain *auditChain) checkpoint(signingKey []byte, now time.Time) (record auditRecord, hash [sha256.Size]byte, err error) {
+	entry := "checkpoint time=" + now.UTC().Format(time.RFC3339Nano) +
+		" records=" + strconv.Itoa(chain.sinceCheckpoint)
+
+	seq := chain.seq + 1
+	hash = auditHash(chain.hash, seq, true, entry)
+
+	suite, _ := registry.GetSignatureInfo(auditSignatureSuite)
+	signature, err := suite.Sign(signingKey, hash[:])
+	if err != nil {
+		err = fmt.Errorf("failed to sign audit checkpoint: %w", err)
+		return
+	}
+
+	record = auditRecord{
+		Seq:        seq,
+		Prev:       hex.EncodeToString(chain.hash[:]),
+		Entry:      entry,
+		Checkpoint: true,
+		Suite:      auditSignatureSuite,
+		Signature:  signature,
+		Hash:       hex.EncodeToString(hash[:]),
+	}
+	return
+}
+
+// Advances chain past a written record
+func (chain *auditChain) commit(record auditRecord, hash [sha256.Size]byte) {
+	chain.seq = record.Seq
+	chain.hash = hash
+	if record.Checkpoint {
+		chain.sinceCheckpoint = 0
+	} else {
+		chain.sinceCheckpoint++
+	}
+}
+
+// Serializes audit record as a single line
+func (record auditRecord) line() (line []byte, err error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(record) // Adds trailing newline
+	if err != nil {
+		return
+	}
+	line = buf.Bytes()
+	return
+}
+
+// Writes a signed checkpoint to the file if it has records not covered by one
+func (mod *OutModule) writeCheckpoint(handle *outputFile) (err error) {
+	if handle.chain == nil || handle.chain.sinceCheckpoint == 0 {
+		return
+	}
+	err = mod.appendCheckpoint(handle)
+	return
+}
+
+// Writes a signed checkpoint to the file, even when all records are already covered
+// (rotated files start with one, so the chain stays verifiable after older files are removed)
+func (mod *OutModule) appendCheckpoint(handle *outputFile) (err error) {
+	record, hash, err := handle.chain.checkpoint(mod.auditKey, time.Now())
+	if err != nil {
+		return
+	}
+	line, err := record.line()
+	if err != nil {
+		return
+	}
+	err = handle.write(line)
+	if err != nil {
+		err = fmt.Errorf("failed to write audit checkpoint: %w", err)
+		return
+	}
+	handle.chain.commit(record, hash)
+	return
+}
+
+// Recovers chain state from the last record of an existing audit file.
+// Incomplete record left by a crash is truncated (it was never part of the chain), discarded is its length.
+func resumeAuditChain(file *os.File, size int64) (chain *auditChain, discarded int64, err error) {
+	chain = &auditChain{}
+	if size == 0 {
+		return
+	}
+
+	lastLine, end, err := readLastLine(file, size)
+	if err != nil {
+		err = fmt.Errorf("failed to read last audit record: %w", err)
+		return
+	}
+	if end < size {
+		err = file.Truncate(end)
+		if err != nil {
+			err = fmt.Errorf("failed to truncate incomplete audit record: %w", err)
+			return
+		}
+		discarded = size - end
+	}
+	if end == 0 {
+		return
+	}
+
+	var record auditRecord
+	err = json.Unmarshal(lastLine, &record)
+	if err != nil {
+		err = fmt.Errorf("cannot continue audit chain: last line of file is not an audit record: %w", err)
+		return
+	}
+	hash, err := hex.DecodeString(record.Hash)
+	if err != nil || len(hash) != sha256.Size {
+		err = fmt.Errorf("cannot continue audit chain: last record has invalid hash %q", record.Hash)
+		return
+	}
+
+	chain.seq = record.Seq
+	copy(chain.hash[:], hash)
+	if !record.Checkpoint {
+		chain.sinceCheckpoint = 1 // Exact count unknown, ensures a checkpoint is written
+	}
+	return
+}
+
+// Reads the final newline terminated line of a file by reading backwards from the end.
+// End is the offset just past that line (bytes after it are an incomplete line, 0 when there is no complete line).
+func readLastLine(file *os.File, size int64) (line []byte, end int64, err error) {
+	const chunkSize int64 = 64 * 1024
+
+	end = -1
+	start := size
+	var tail []byte // File contents from start
+	for start > 0 {
+		chunkStart := max(start-chunkSize, 0)
+		chunk := make([]byte, start-chunkStart)
+		_, err = file.ReadAt(chunk, chunkStart)
+		if err != nil && err != io.EOF {
+			return
+		}
+		err = nil
+		tail = append(chunk, tail...)
+		start = chunkStart
+
+		if end == -1 {
+			index := bytes.LastIndexByte(tail, '\n')
+			if index == -1 {
+				continue
+			}
+			end = start + int64(index) + 1
+		}
+
+		// Newline before the final complete line
+		lineEnd := int(end-start) - 1
+		index := bytes.LastIndexByte(tail[:lineEnd], '\n')
+		if index != -1 {
+			line = tail[index+1 : lineEnd]
+			return
+		}
+	}
+	if end == -1 {
+		end = 0
+		return
+	}
+	line = tail[:end-1]
+	return
+}
@@ -0,0 +1,357 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/crypto/registry"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditChain(t *testing.T) {
+	tests := []struct {
+		name           string
+		tamper         func(lines [][]byte) [][]byte
+		expectedLine   int
+		expectedReason string
+	}{
+		{
+			name:   "intact",
+			tamper: func(lines [][]byte) [][]byte { return lines },
+		},
+		{
+			name: "modified entry",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[2] = bytes.Replace(lines[2], []byte("message 02"), []byte("message 99"), 1)
+				return lines
+			},
+			expectedLine:   3,
+			expectedReason: "record hash mismatch",
+		},
+		{
+			name: "removed record",
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:4], lines[5:]...)
+			},
+			expectedLine:   5,
+			expectedReason: "sequence jumps",
+		},
+		{
+			name: "swapped records",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			expectedLine:   2,
+			expectedReason: "sequence jumps",
+		},
+		{
+			name: "rewritten chain",
+			tamper: func(lines [][]byte) [][]byte {
+				// Recompute all hashes after modifying a record, only the checkpoint signature can catch it
+				var chain auditChain
+				var rewritten [][]byte
+				for _, line := range lines {
+					if bytes.Contains(line, []byte(`"checkpoint":true`)) {
+						checkpoint, hash, _ := chain.checkpoint(otherAuditKey(t), time.Now())
+						line, _ = checkpoint.line()
+						chain.commit(checkpoint, hash)
+						rewritten = append(rewritten, line)
+						continue
+					}
+					entry := strings.Replace(string(line), "message 00", "message 99", 1)
+					record, hash := chain.next(auditEntry(t, []byte(entry)))
+					line, _ = record.line()
+					chain.commit(record, hash)
+					rewritten = append(rewritten, line)
+				}
+				return rewritten
+			},
+			expectedLine:   4,
+			expectedReason: "checkpoint signature is invalid",
+		},
+		{
+			name: "removed first records",
+			tamper: func(lines [][]byte) [][]byte {
+				return lines[2:]
+			},
+			expectedLine:   1,
+			expectedReason: "without a signed checkpoint",
+		},
+		{
+			name: "truncated record",
+			tamper: func(lines [][]byte) [][]byte {
+				last := len(lines) - 1
+				lines[last] = lines[last][:len(lines[last])/2]
+				return lines
+			},
+			expectedLine:   10,
+			expectedReason: "incomplete record",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+			tempDir := t.TempDir()
+			keyFile, publicKey := newAuditKeyFile(t, tempDir)
+			config := OutputConfig{
+				Path:                   filepath.Join(tempDir, "audit.log"),
+				AuditKeyFile:           keyFile,
+				AuditCheckpointRecords: 3,
+			}
+
+			// Restart half way (chain must continue from the existing file)
+			for _, batch := range [][]int{{0, 1, 2, 3}, {4, 5, 6}} {
+				outMod, err := NewOutput(config)
+				if err != nil {
+					t.Fatalf("failed to create output module: %v", err)
+				}
+				for _, index := range batch {
+					_, err = outMod.Write(ctx, &protocol.Payload{
+						Hostname:  "web01",
+						Timestamp: time.Now(),
+						Data:      fmt.Appendf(nil, "message %02d", index),
+					})
+					if err != nil {
+						t.Fatalf("failed to write message: %v", err)
+					}
+				}
+				_, err = outMod.FlushBuffer()
+				if err != nil {
+					t.Fatalf("failed to flush write buffer: %v", err)
+				}
+				err = outMod.Shutdown()
+				if err != nil {
+					t.Fatalf("failed to shutdown output module: %v", err)
+				}
+			}
+
+			// 7 records, checkpoints every 3 records and on the first shutdown (last shutdown has nothing left to cover)
+			contents, err := os.ReadFile(config.Path)
+			if err != nil {
+				t.Fatalf("failed to read audit file: %v", err)
+			}
+			lines := bytes.SplitAfter(contents, []byte("\n"))
+			lines = lines[:len(lines)-1]
+			if len(lines) != 10 {
+				t.Fatalf("expected 10 audit lines, but got %d:\n%s", len(lines), contents)
+			}
+
+			lines = tt.tamper(lines)
+			err = os.WriteFile(config.Path, bytes.Join(lines, nil), 0600)
+			if err != nil {
+				t.Fatalf("failed to write tampered file: %v", err)
+			}
+
+			report, err := VerifyArchive([]string{config.Path}, publicKey)
+			if err != nil {
+				t.Fatalf("unexpected verification error: %v", err)
+			}
+			if tt.expectedReason == "" {
+				if report.Broken != nil {
+					t.Fatalf("expected intact chain, but got break: %s", report.Broken)
+				}
+				if report.Records != 7 || report.Checkpoints != 3 || report.FirstSeq != 1 || report.Unsigned != 0 {
+					t.Errorf("unexpected report: %+v", report)
+				}
+				return
+			}
+			if report.Broken == nil {
+				t.Fatalf("expected break at line %d, but chain verified: %+v", tt.expectedLine, report)
+			}
+			if report.Broken.Line != tt.expectedLine || !strings.Contains(report.Broken.Reason, tt.expectedReason) {
+				t.Errorf("expected break at line %d containing %q, but got %s", tt.expectedLine, tt.expectedReason, report.Broken)
+			}
+		})
+	}
+}
+
+func TestAuditChainRotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	keyFile, publicKey := newAuditKeyFile(t, tempDir)
+	config := OutputConfig{
+		Path:         filepath.Join(tempDir, "audit.log"),
+		AuditKeyFile: keyFile,
+		MaxSizeBytes: 1000,
+		Compression:  CompressionGzip,
+	}
+
+	outMod, err := NewOutput(config)
+	if err != nil {
+		t.Fatalf("failed to create output module: %v", err)
+	}
+	for index := range 20 {
+		_, err = outMod.Write(ctx, &protocol.Payload{
+			Hostname:  "web01",
+			Timestamp: time.Now(),
+			Data:      fmt.Appendf(nil, "message %02d", index),
+		})
+		if err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+	}
+	_, err = outMod.FlushBuffer()
+	if err != nil {
+		t.Fatalf("failed to flush write buffer: %v", err)
+	}
+	err = outMod.Shutdown()
+	if err != nil {
+		t.Fatalf("failed to shutdown output module: %v", err)
+	}
+
+	rotated, err := rotatedFiles(config.Path)
+	if err != nil {
+		t.Fatalf("failed to list rotated files: %v", err)
+	}
+	if len(rotated) < 2 {
+		t.Fatalf("expected multiple rotated files, but found %q", rotated)
+	}
+
+	// Chain continues across rotated (compressed) files
+	report, err := VerifyArchive(append(rotated, config.Path), publicKey)
+	if err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	if report.Broken != nil {
+		t.Fatalf("expected intact chain, but got break: %s", report.Broken)
+	}
+	if report.Records != 20 || report.Unsigned != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	// Oldest file missing is allowed (pruned, next file starts at a checkpoint), a missing file in the middle is not
+	report, err = VerifyArchive(append(rotated[1:], config.Path), publicKey)
+	if err != nil || report.Broken != nil || report.FirstSeq == 1 {
+		t.Errorf("expected chain starting mid-way to verify, but got %+v (err: %v)", report, err)
+	}
+	report, err = VerifyArchive([]string{config.Path}, publicKey)
+	if err != nil || report.Broken != nil {
+		t.Errorf("expected current file to start at a checkpoint, but got %+v (err: %v)", report, err)
+	}
+	report, err = VerifyArchive(append([]string{rotated[0]}, config.Path), publicKey)
+	if err != nil || report.Broken == nil {
+		t.Errorf("expected chain with missing file to be broken, but got %+v (err: %v)", report, err)
+	}
+}
+
+func TestAuditChainResumeAfterCrash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	keyFile, publicKey := newAuditKeyFile(t, tempDir)
+	config := OutputConfig{
+		Path:         filepath.Join(tempDir, "audit.log"),
+		AuditKeyFile: keyFile,
+	}
+
+	writeMessages := func(indexes ...int) {
+		outMod, err := NewOutput(config)
+		if err != nil {
+			t.Fatalf("failed to create output module: %v", err)
+		}
+		for _, index := range indexes {
+			_, err = outMod.Write(ctx, &protocol.Payload{
+				Hostname:  "web01",
+				Timestamp: time.Now(),
+				Data:      fmt.Appendf(nil, "message %02d", index),
+			})
+			if err != nil {
+				t.Fatalf("failed to write message: %v", err)
+			}
+		}
+		_, err = outMod.FlushBuffer()
+		if err != nil {
+			t.Fatalf("failed to flush write buffer: %v", err)
+		}
+		err = outMod.Shutdown()
+		if err != nil {
+			t.Fatalf("failed to shutdown output module: %v", err)
+		}
+	}
+
+	writeMessages(0, 1)
+
+	// Crash in the middle of writing a record
+	file, err := os.OpenFile(config.Path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open audit file: %v", err)
+	}
+	_, err = file.WriteString(`{"seq":4,"prev":"00`)
+	if err != nil {
+		t.Fatalf("failed to append incomplete record: %v", err)
+	}
+	_ = file.Close()
+
+	writeMessages(2, 3)
+
+	contents, err := os.ReadFile(config.Path)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+	if bytes.Contains(contents, []byte(`"prev":"00{`)) {
+		t.Errorf("incomplete record was not removed:\n%s", contents)
+	}
+	report, err := VerifyArchive([]string{config.Path}, publicKey)
+	if err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	if report.Broken != nil {
+		t.Fatalf("expected intact chain, but got break: %s", report.Broken)
+	}
+	if report.Records != 4 || report.Unsigned != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+// Writes new audit signing key to a file
+func newAuditKeyFile(t *testing.T, dir string) (keyFile string, publicKey []byte) {
+	suite, _ := registry.GetSignatureInfo(auditSignatureSuite)
+	privateKey, publicKey, err := suite.NewKey()
+	if err != nil {
+		t.Fatalf("failed to create signing key: %v", err)
+	}
+	keyFile = filepath.Join(dir, "audit.key")
+	err = os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(privateKey)+"\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed to write signing key: %v", err)
+	}
+	return
+}
+
+// Signing key the verifier does not trust
+func otherAuditKey(t *testing.T) (privateKey []byte) {
+	suite, _ := registry.GetSignatureInfo(auditSignatureSuite)
+	privateKey, _, err := suite.NewKey()
+	if err != nil {
+		t.Fatalf("failed to create signing key: %v", err)
+	}
+	return
+}
+
+// Extracts the entry of a serialized audit record
+func auditEntry(t *testing.T, line []byte) (entry string) {
+	var record auditRecord
+	err := json.Unmarshal(line, &record)
+	if err != nil {
+		t.Fatalf("failed to parse audit record: %v", err)
+	}
+	entry = record.Entry
+	return
+}
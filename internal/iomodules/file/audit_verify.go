@@ -0,0 +1,172 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sdsyslog/pkg/crypto/registry"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Walks the hash chain of audit files (oldest first, rotated files may be compressed) and reports the first broken link.
+// Chain must start at its first record or at a signed checkpoint (older files pruned), and be continuous across all given files.
+func VerifyArchive(paths []string, publicKey []byte) (report ArchiveReport, err error) {
+	if len(paths) == 0 {
+		err = fmt.Errorf("no archive files given")
+		return
+	}
+	if len(publicKey) == 0 {
+		err = fmt.Errorf("public key is required to verify checkpoint signatures")
+		return
+	}
+
+	state := archiveVerifier{publicKey: publicKey, report: &report}
+	for _, path := range paths {
+		err = state.verifyFile(path)
+		if err != nil || report.Broken != nil {
+			return
+		}
+	}
+	return
+}
+
+// Verifies every record of a single audit file
+func (state *archiveVerifier) verifyFile(path string) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	switch filepath.Ext(path) {
+	case gzipExtension:
+		var gzipReader *gzip.Reader
+		gzipReader, err = gzip.NewReader(file)
+		if err != nil {
+			err = fmt.Errorf("failed to open gzip file %q: %w", path, err)
+			return
+		}
+		reader = gzipReader
+	case zstdExtension:
+		var zstdReader *zstd.Decoder
+		zstdReader, err = zstd.NewReader(file)
+		if err != nil {
+			err = fmt.Errorf("failed to open zstd file %q: %w", path, err)
+			return
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+	}
+
+	lineReader := bufio.NewReader(reader)
+	for lineNumber := 1; ; lineNumber++ {
+		var line []byte
+		line, err = lineReader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			err = nil
+			return
+		}
+		if err != nil && err != io.EOF {
+			err = fmt.Errorf("failed to read %q: %w", path, err)
+			return
+		}
+		err = nil
+
+		reason := state.verifyRecord(line)
+		if reason != "" {
+			state.report.Broken = &ArchiveBreak{
+				Path:   path,
+				Line:   lineNumber,
+				Seq:    state.seq + 1,
+				Reason: reason,
+			}
+			return
+		}
+	}
+}
+
+// Checks a single record against the chain so far, returning why it is broken (empty if valid)
+func (state *archiveVerifier) verifyRecord(line []byte) (reason string) {
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		reason = "incomplete record (missing newline)"
+		return
+	}
+
+	var record auditRecord
+	err := json.Unmarshal(line, &record)
+	if err != nil {
+		reason = fmt.Sprintf("invalid record: %v", err)
+		return
+	}
+
+	prev, err := hex.DecodeString(record.Prev)
+	if err != nil || len(prev) != sha256.Size {
+		reason = fmt.Sprintf("invalid previous hash %q", record.Prev)
+		return
+	}
+	var prevHash [sha256.Size]byte
+	copy(prevHash[:], prev)
+
+	if state.started {
+		if record.Seq != state.seq+1 {
+			reason = fmt.Sprintf("sequence jumps from %d to %d (records removed or reordered)", state.seq, record.Seq)
+			return
+		}
+		if prevHash != state.hash {
+			reason = "previous hash does not match the preceding record (records removed or modified)"
+			return
+		}
+	} else {
+		genesis := record.Seq == 1 && prevHash == [sha256.Size]byte{}
+		if !genesis && !record.Checkpoint {
+			reason = fmt.Sprintf("chain starts at sequence %d without a signed checkpoint (earlier records removed)", record.Seq)
+			return
+		}
+		state.report.FirstSeq = record.Seq
+	}
+
+	hash := auditHash(prevHash, record.Seq, record.Checkpoint, record.Entry)
+	if hex.EncodeToString(hash[:]) != record.Hash {
+		reason = "record hash mismatch (record modified)"
+		return
+	}
+
+	if record.Checkpoint {
+		suite, validID := registry.GetSignatureInfo(record.Suite)
+		if !validID || record.Suite == 0 {
+			reason = fmt.Sprintf("checkpoint has invalid signature suite ID %d", record.Suite)
+			return
+		}
+		if !suite.Verify(state.publicKey, hash[:], record.Signature) {
+			reason = "checkpoint signature is invalid"
+			return
+		}
+		state.report.Checkpoints++
+		state.report.Unsigned = 0
+	} else {
+		state.report.Records++
+		state.report.Unsigned++
+	}
+
+	state.started = true
+	state.seq = record.Seq
+	state.hash = hash
+	return
+}
+
+// Describes where the chain is broken
+func (brk ArchiveBreak) String() (text string) {
+	text = fmt.Sprintf("%s:%d (seq %d): %s", brk.Path, brk.Line, brk.Seq, brk.Reason)
+	return
+}
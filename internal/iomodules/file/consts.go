@@ -0,0 +1,103 @@
+package file
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	// Multi-line input modes
+	MultilineContinuation string = "continuation" // Lines starting with whitespace continue the previous event
+	MultilinePattern      string = "pattern"      // Lines matching the start pattern begin a new event
+	MultilineAudit        string = "audit"        // Consecutive audit records with the same serial number form one event
+
+	// Multi-line input defaults
+	defaultMultilineMaxLines int           = 500
+	defaultMultilineMaxBytes int           = 1 << 20
+	defaultMultilineTimeout  time.Duration = 2 * time.Second
+
+	// Parser profile formats
+	ParserAuto   string = "auto"   // Built-in detection of common formats
+	ParserRegex  string = "regex"  // Named capture groups
+	ParserGrok   string = "grok"   // Named pattern references like %{IP:client}
+	ParserJSON   string = "json"   // Object keys (nested objects as dotted keys)
+	ParserLogfmt string = "logfmt" // key=value pairs
+	ParserCEF    string = "cef"    // ArcSight Common Event Format
+	ParserLEEF   string = "leef"   // IBM Log Event Extended Format
+	ParserAudit  string = "audit"  // Linux audit records (lines of an event grouped by serial number)
+
+	// Timestamp layouts for epoch values
+	LayoutUnix      string = "unix"
+	LayoutUnixMilli string = "unixMilli"
+
+	// Grok pattern references expanded within a pattern
+	maxGrokDepth int = 16
+
+	// Retired input files are checked for new data this often
+	retirePollInterval time.Duration = 100 * time.Millisecond
+
+	// Output defaults
+	defaultBatchSize    int = 20
+	defaultMaxOpenFiles int = 64
+
+	// Output line formats
+	FormatText string = "text"
+	FormatJSON string = "json" // JSON Lines
+
+	// Signature status of JSON records
+	SignatureVerified   string = "verified"   // Pinned sender, signature verified
+	SignatureNone       string = "none"       // Not signed and sender is not pinned (local messages)
+	SignatureUnverified string = "unverified" // Sender is not pinned and message is not signed
+	SignatureUnknown    string = "unknown"    // Signed, but sender is not pinned so signature was not verified
+	SignaturePartial    string = "partial"    // Pinned sender, content unverifiable due to missing fragments
+
+	// Rotated output file compression
+	CompressionGzip string = "gzip"
+	CompressionZstd string = "zstd"
+
+	gzipExtension     string = ".gz"
+	zstdExtension     string = ".zst"
+	partialExtension  string = ".tmp"                      // Compressed file that is still being written
+	rotatedTimeLayout string = "20060102T150405.000000000" // Fixed width so names sort chronologically (UTC)
+
+	// Audit chain
+	auditSignatureSuite           uint8 = 1 // ed25519
+	auditKindEntry                byte  = 0x00
+	auditKindCheckpoint           byte  = 0x01
+	defaultAuditCheckpointRecords int   = 1000
+
+	outputFileMode os.FileMode = 0640
+	outputDirMode  os.FileMode = 0750
+
+	maxPathValueLen int = 128 // Longest single path element rendered from a message field
+)
+
+// File names never matched by directory and glob inputs (rotated and compressed copies of active files)
+var defaultExcludePatterns = []string{
+	"*.[0-9]", "*.[0-9][0-9]", "*.[0-9][0-9][0-9]", // Numbered (app.log.1)
+	"*-[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]",   // Date suffix (messages-20260307)
+	"*.[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]T*", // File output rotation (app.log.20260307T093000.000000000)
+	"*.old",
+	"*.gz", "*.bz2", "*.xz", "*.zst", "*.lz4", "*.zip",
+}
+
+// Timestamp layouts tried when a profile sets a timestamp field without layouts
+var defaultTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.Stamp,
+	"02/Jan/2006:15:04:05 -0700",
+	"Jan 02 2006 15:04:05",
+	LayoutUnix,
+}
+
+// Timestamp layouts of CEF and LEEF events (epoch milliseconds or month name first)
+var securityTimestampLayouts = []string{
+	LayoutUnixMilli,
+	"Jan 02 2006 15:04:05.000 MST",
+	"Jan 02 2006 15:04:05.000",
+	"Jan 02 2006 15:04:05 MST",
+	"Jan 02 2006 15:04:05",
+	time.RFC3339Nano,
+}
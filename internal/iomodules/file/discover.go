@@ -0,0 +1,166 @@
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Reports whether the input covers a changing set of files (glob pattern, directory, or recursive) instead of a single file
+func (config InputConfig) IsDynamic() (dynamic bool) {
+	if config.Recursive || hasGlobMeta(config.Path) {
+		dynamic = true
+		return
+	}
+	info, err := os.Stat(config.Path)
+	dynamic = err == nil && info.IsDir()
+	return
+}
+
+// Finds files currently matching the input path and the directories to watch for new matches.
+// Directories match every file inside, recursive inputs match the file name pattern at any depth.
+// Rotated and compressed copies, and names matching an exclude pattern, are skipped.
+func ExpandInputPath(config InputConfig) (files []string, watchDirs []string, err error) {
+	pattern := filepath.Clean(config.Path)
+	if !hasGlobMeta(pattern) {
+		info, statErr := os.Stat(pattern)
+		if statErr == nil && info.IsDir() {
+			pattern = filepath.Join(pattern, "*")
+		}
+	}
+
+	dirPattern, namePattern := filepath.Split(pattern)
+	dirPattern = filepath.Clean(dirPattern)
+
+	_, err = filepath.Match(pattern, "")
+	if err != nil {
+		err = fmt.Errorf("invalid file input pattern %q: %w", config.Path, err)
+		return
+	}
+	for _, exclude := range config.Exclude {
+		_, err = filepath.Match(exclude, "")
+		if err != nil {
+			err = fmt.Errorf("invalid file input exclude pattern %q: %w", exclude, err)
+			return
+		}
+	}
+
+	var contentDirs []string
+	watchDirs, contentDirs = matchDirectories(dirPattern)
+
+	if config.Recursive {
+		var subDirs []string
+		for _, root := range contentDirs {
+			walkErr := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					// Unreadable subtrees are skipped
+					return nil
+				}
+				if entry.IsDir() && path != root {
+					subDirs = append(subDirs, path)
+				}
+				return nil
+			})
+			if walkErr != nil {
+				err = fmt.Errorf("failed to walk directory '%s': %w", root, walkErr)
+				return
+			}
+		}
+		watchDirs = append(watchDirs, subDirs...)
+		contentDirs = append(contentDirs, subDirs...)
+	}
+
+	for _, directory := range contentDirs {
+		entries, readErr := os.ReadDir(directory)
+		if readErr != nil {
+			// Directory removed since matching
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			matched, _ := filepath.Match(namePattern, entry.Name())
+			if matched && !config.excluded(entry.Name()) {
+				files = append(files, filepath.Join(directory, entry.Name()))
+			}
+		}
+	}
+	return
+}
+
+// Reports whether a file name matches a default or configured exclude pattern
+func (config InputConfig) excluded(name string) (excluded bool) {
+	for _, pattern := range defaultExcludePatterns {
+		excluded, _ = filepath.Match(pattern, name)
+		if excluded {
+			return
+		}
+	}
+	for _, pattern := range config.Exclude {
+		excluded, _ = filepath.Match(pattern, name)
+		if excluded {
+			return
+		}
+	}
+	return
+}
+
+// Expands a directory pattern level by level. Returns directories that could gain matches (to watch) and full matches (holding files).
+func matchDirectories(dirPattern string) (watchDirs []string, matchDirs []string) {
+	// Fixed leading path components
+	components := strings.Split(dirPattern, string(filepath.Separator))
+	staticEnd := len(components)
+	for index, component := range components {
+		if hasGlobMeta(component) {
+			staticEnd = index
+			break
+		}
+	}
+	staticPrefix := strings.Join(components[:staticEnd], string(filepath.Separator))
+	if staticPrefix == "" && filepath.IsAbs(dirPattern) {
+		staticPrefix = string(filepath.Separator)
+	} else if staticPrefix == "" {
+		staticPrefix = "."
+	}
+
+	// Missing directories are picked up once created in the closest existing parent
+	if !isDirectory(staticPrefix) {
+		parent := staticPrefix
+		for !isDirectory(parent) && filepath.Dir(parent) != parent {
+			parent = filepath.Dir(parent)
+		}
+		watchDirs = append(watchDirs, parent)
+		return
+	}
+
+	matchDirs = []string{staticPrefix}
+	watchDirs = append(watchDirs, staticPrefix)
+	for _, component := range components[staticEnd:] {
+		var nextDirs []string
+		for _, directory := range matchDirs {
+			matches, _ := filepath.Glob(filepath.Join(directory, component))
+			for _, match := range matches {
+				if isDirectory(match) {
+					nextDirs = append(nextDirs, match)
+				}
+			}
+		}
+		watchDirs = append(watchDirs, nextDirs...)
+		matchDirs = nextDirs
+	}
+	return
+}
+
+func hasGlobMeta(path string) (meta bool) {
+	meta = strings.ContainsAny(path, `*?[\`)
+	return
+}
+
+func isDirectory(path string) (directory bool) {
+	info, err := os.Stat(path)
+	directory = err == nil && info.IsDir()
+	return
+}
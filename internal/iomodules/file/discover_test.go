@@ -0,0 +1,146 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExpandInputPath(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, dir := range []string{"app", "app/nested", "app/nested/deep", "other"} {
+		err := os.MkdirAll(filepath.Join(tempDir, dir), 0755)
+		if err != nil {
+			t.Fatalf("failed to create test directory: %v", err)
+		}
+	}
+	for _, name := range []string{"app/server.log", "app/server.log.1", "app/server.log.2.gz", "app/server.log-20260307", "app/server.log.20260307T093000.000000000", "app/worker.log", "app/nested/child.log", "app/nested/deep/leaf.log", "other/other.log"} {
+		err := os.WriteFile(filepath.Join(tempDir, name), nil, 0644)
+		if err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name              string
+		config            InputConfig
+		expectedFiles     []string
+		expectedWatchDirs []string
+		expectedErr       bool
+	}{
+		{
+			name:              "glob in directory",
+			config:            InputConfig{Path: filepath.Join(tempDir, "app", "*.log")},
+			expectedFiles:     []string{"app/server.log", "app/worker.log"},
+			expectedWatchDirs: []string{"app"},
+		},
+		{
+			name:              "glob in directory component",
+			config:            InputConfig{Path: filepath.Join(tempDir, "*", "*.log")},
+			expectedFiles:     []string{"app/server.log", "app/worker.log", "other/other.log"},
+			expectedWatchDirs: []string{"", "app", "other"},
+		},
+		{
+			name:              "directory matches all files except rotated copies",
+			config:            InputConfig{Path: filepath.Join(tempDir, "app")},
+			expectedFiles:     []string{"app/server.log", "app/worker.log"},
+			expectedWatchDirs: []string{"app"},
+		},
+		{
+			name:              "glob skips rotated copies",
+			config:            InputConfig{Path: filepath.Join(tempDir, "app", "*.log*")},
+			expectedFiles:     []string{"app/server.log", "app/worker.log"},
+			expectedWatchDirs: []string{"app"},
+		},
+		{
+			name:              "configured exclude",
+			config:            InputConfig{Path: filepath.Join(tempDir, "app"), Exclude: []string{"worker*"}},
+			expectedFiles:     []string{"app/server.log"},
+			expectedWatchDirs: []string{"app"},
+		},
+		{
+			name:        "invalid exclude pattern",
+			config:      InputConfig{Path: filepath.Join(tempDir, "app"), Exclude: []string{"[worker"}},
+			expectedErr: true,
+		},
+		{
+			name:              "recursive",
+			config:            InputConfig{Path: filepath.Join(tempDir, "app", "*.log"), Recursive: true},
+			expectedFiles:     []string{"app/nested/child.log", "app/nested/deep/leaf.log", "app/server.log", "app/worker.log"},
+			expectedWatchDirs: []string{"app", "app/nested", "app/nested/deep"},
+		},
+		{
+			name:              "missing directory watches closest parent",
+			config:            InputConfig{Path: filepath.Join(tempDir, "future", "logs", "*.log")},
+			expectedWatchDirs: []string{""},
+		},
+		{
+			name:        "invalid pattern",
+			config:      InputConfig{Path: filepath.Join(tempDir, "app", "[.log")},
+			expectedErr: true,
+		},
+	}
+
+	relative := func(paths []string) (relPaths []string) {
+		for _, path := range paths {
+			relPath, _ := filepath.Rel(tempDir, path)
+			if relPath == "." {
+				relPath = ""
+			}
+			relPaths = append(relPaths, filepath.ToSlash(relPath))
+		}
+		slices.Sort(relPaths)
+		relPaths = slices.Compact(relPaths)
+		return
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, watchDirs, err := ExpandInputPath(tt.config)
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := relative(files); !slices.Equal(got, tt.expectedFiles) {
+				t.Errorf("expected files %q, got %q", tt.expectedFiles, got)
+			}
+			if got := relative(watchDirs); !slices.Equal(got, tt.expectedWatchDirs) {
+				t.Errorf("expected watched directories %q, got %q", tt.expectedWatchDirs, got)
+			}
+		})
+	}
+}
+
+func TestIsDynamic(t *testing.T) {
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "app.log")
+	err := os.WriteFile(logFile, nil, 0644)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		config   InputConfig
+		expected bool
+	}{
+		{name: "single file", config: InputConfig{Path: logFile}, expected: false},
+		{name: "glob", config: InputConfig{Path: filepath.Join(tempDir, "*.log")}, expected: true},
+		{name: "directory", config: InputConfig{Path: tempDir}, expected: true},
+		{name: "recursive", config: InputConfig{Path: logFile, Recursive: true}, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.IsDynamic(); got != tt.expected {
+				t.Errorf("expected dynamic %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
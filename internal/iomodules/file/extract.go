@@ -0,0 +1,318 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CEF extension keys (key=value where the value runs until the next key)
+var cefExtensionKey = regexp.MustCompile(`(?:^|\s)([A-Za-z0-9_.\[\]]+)=`)
+
+// Values of a JSON object line. Nested objects are flattened into dotted keys, arrays are kept as JSON text.
+func extractJSON(line string) (values map[string]any, parsed bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	var object map[string]any
+	err := decoder.Decode(&object)
+	if err != nil {
+		return
+	}
+
+	values = make(map[string]any, len(object))
+	flattenJSON("", object, values)
+	parsed = true
+	return
+}
+
+// Adds the typed values of an object under prefix
+func flattenJSON(prefix string, object map[string]any, values map[string]any) {
+	for key, value := range object {
+		key = prefix + key
+
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case string, bool:
+			values[key] = typed
+		case json.Number:
+			values[key] = jsonNumber(typed)
+		case map[string]any:
+			flattenJSON(key+".", typed, values)
+		default:
+			nested, err := json.Marshal(typed)
+			if err != nil {
+				continue
+			}
+			values[key] = string(nested)
+		}
+	}
+}
+
+// Integer numbers are int64, others float64 (text if out of range)
+func jsonNumber(number json.Number) (value any) {
+	integer, err := number.Int64()
+	if err == nil {
+		value = integer
+		return
+	}
+	float, err := number.Float64()
+	if err == nil {
+		value = float
+		return
+	}
+	value = number.String()
+	return
+}
+
+// Pairs of key=value or key="quoted value" separated by spaces (keys without a value are true).
+// Unquoted numbers and booleans are typed, quoted values are always text.
+func extractLogfmt(line string) (values map[string]any, parsed bool) {
+	values = make(map[string]any)
+
+	rest := strings.TrimSpace(line)
+	for rest != "" {
+		keyEnd := strings.IndexAny(rest, "= ")
+		if keyEnd == -1 {
+			values[rest] = true
+			break
+		}
+		key := rest[:keyEnd]
+		if rest[keyEnd] == ' ' {
+			values[key] = true
+			rest = strings.TrimLeft(rest[keyEnd+1:], " ")
+			continue
+		}
+		if key == "" {
+			// Value without a key, not logfmt
+			return
+		}
+		rest = rest[keyEnd+1:]
+
+		var value any
+		if strings.HasPrefix(rest, `"`) {
+			// Quoted value ends at the first unescaped quote
+			end := 1
+			for end < len(rest) && rest[end] != '"' {
+				if rest[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(rest) {
+				// Unterminated quote, not logfmt
+				return
+			}
+			unquoted, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return
+			}
+			value = unquoted
+			rest = rest[end+1:]
+		} else {
+			valueEnd := strings.IndexByte(rest, ' ')
+			if valueEnd == -1 {
+				valueEnd = len(rest)
+			}
+			value = logfmtValue(rest[:valueEnd])
+			rest = rest[valueEnd:]
+		}
+		values[key] = value
+		parsed = true
+		rest = strings.TrimLeft(rest, " ")
+	}
+	return
+}
+
+// Type of an unquoted logfmt value
+func logfmtValue(text string) (value any) {
+	switch text {
+	case "true":
+		value = true
+		return
+	case "false":
+		value = false
+		return
+	}
+
+	integer, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		value = integer
+		return
+	}
+	float, err := strconv.ParseFloat(text, 64)
+	if err == nil && !math.IsInf(float, 0) && !math.IsNaN(float) {
+		value = float
+		return
+	}
+	value = text
+	return
+}
+
+// CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+func extractCEF(line string) (values map[string]any, parsed bool) {
+	start := strings.Index(line, "CEF:")
+	if start == -1 {
+		return
+	}
+
+	headers, extension, complete := splitHeader(line[start+len("CEF:"):], 7)
+	if !complete {
+		return
+	}
+
+	values = map[string]any{
+		"version":       headers[0],
+		"deviceVendor":  headers[1],
+		"deviceProduct": headers[2],
+		"deviceVersion": headers[3],
+		"signatureId":   headers[4],
+		"name":          headers[5],
+		"severity":      securitySeverity(headers[6]),
+	}
+
+	matches := cefExtensionKey.FindAllStringSubmatchIndex(extension, -1)
+	for index, match := range matches {
+		valueEnd := len(extension)
+		if index+1 < len(matches) {
+			valueEnd = matches[index+1][0]
+		}
+		key := extension[match[2]:match[3]]
+		values[key] = unescapeSecurityValue(strings.TrimSpace(extension[match[1]:valueEnd]))
+	}
+	parsed = true
+	return
+}
+
+// LEEF:Version|Vendor|Product|Version|EventID|[Delimiter|]key=value<tab>key=value
+func extractLEEF(line string) (values map[string]any, parsed bool) {
+	start := strings.Index(line, "LEEF:")
+	if start == -1 {
+		return
+	}
+
+	headers, attributes, complete := splitHeader(line[start+len("LEEF:"):], 5)
+	if !complete {
+		return
+	}
+
+	values = map[string]any{
+		"version":        headers[0],
+		"vendor":         headers[1],
+		"product":        headers[2],
+		"productVersion": headers[3],
+		"eventId":        headers[4],
+	}
+
+	// LEEF 2.0 can name its own attribute delimiter (character or hex code)
+	delimiter := "\t"
+	if strings.HasPrefix(headers[0], "2") {
+		if end := strings.IndexByte(attributes, '|'); end != -1 && !strings.Contains(attributes[:end], "=") {
+			delimiter = leefDelimiter(attributes[:end])
+			attributes = attributes[end+1:]
+		}
+	}
+
+	for attribute := range strings.SplitSeq(attributes, delimiter) {
+		key, value, found := strings.Cut(attribute, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		values[key] = value
+	}
+	if sev, exists := values["sev"].(string); exists {
+		values["sev"] = securitySeverity(sev)
+	}
+	parsed = true
+	return
+}
+
+// Splits the first count pipe-separated header fields (with \| and \\ escapes) from the rest
+func splitHeader(text string, count int) (headers []string, rest string, complete bool) {
+	var field bytes.Buffer
+	for index := 0; index < len(text); index++ {
+		char := text[index]
+		if char == '\\' && index+1 < len(text) && (text[index+1] == '|' || text[index+1] == '\\') {
+			field.WriteByte(text[index+1])
+			index++
+			continue
+		}
+		if char != '|' {
+			field.WriteByte(char)
+			continue
+		}
+
+		headers = append(headers, field.String())
+		field.Reset()
+		if len(headers) == count {
+			rest = text[index+1:]
+			complete = true
+			return
+		}
+	}
+	return
+}
+
+// Delimiter character from a LEEF 2.0 header (like "^", "x09" or "0x09")
+func leefDelimiter(text string) (delimiter string) {
+	delimiter = "\t"
+	if len(text) == 1 {
+		delimiter = text
+		return
+	}
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(text), "0"), "x")
+	code, err := strconv.ParseUint(hex, 16, 8)
+	if err == nil {
+		delimiter = string(rune(code))
+	}
+	return
+}
+
+// Replaces CEF extension escapes
+func unescapeSecurityValue(value string) (unescaped string) {
+	unescaped = strings.NewReplacer(`\=`, `=`, `\\`, `\`, `\n`, "\n", `\r`, "\r").Replace(value)
+	return
+}
+
+// Syslog severity name from a CEF/LEEF severity (0-10 or Low/Medium/High/Very-High)
+func securitySeverity(text string) (severity string) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "low":
+		severity = "info"
+		return
+	case "medium":
+		severity = "warning"
+		return
+	case "high":
+		severity = "err"
+		return
+	case "very-high":
+		severity = "crit"
+		return
+	}
+
+	code, err := strconv.Atoi(strings.TrimSpace(text))
+	switch {
+	case err != nil:
+		severity = text
+	case code <= 3:
+		severity = "info"
+	case code <= 6:
+		severity = "warning"
+	case code <= 8:
+		severity = "err"
+	default:
+		severity = "crit"
+	}
+	return
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
 )
 
 // Not supporting windows intentionally
@@ -23,3 +26,15 @@ func getFileID(file os.FileInfo) (id fileID, err error) {
 	}
 	return
 }
+
+// Retrieves creation time of the file at path (not available on every filesystem)
+func getBirthTime(path string) (birth time.Time, ok bool) {
+	var stat unix.Statx_t
+	err := unix.Statx(unix.AT_FDCWD, path, 0, unix.STATX_BTIME, &stat)
+	if err != nil || stat.Mask&unix.STATX_BTIME == 0 {
+		return
+	}
+	birth = time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec))
+	ok = true
+	return
+}
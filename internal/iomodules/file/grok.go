@@ -0,0 +1,96 @@
+package file
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+)
+
+// References like %{IPV4} or %{IPV4:client}
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+
+// Built-in grok definitions (RE2 compatible subset of the common grok library)
+var grokPatterns = map[string]string{
+	"USERNAME":          `[a-zA-Z0-9._-]+`,
+	"USER":              `%{USERNAME}`,
+	"INT":               `[+-]?[0-9]+`,
+	"POSINT":            `\b[1-9][0-9]*\b`,
+	"NONNEGINT":         `\b[0-9]+\b`,
+	"NUMBER":            `[+-]?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)`,
+	"BASE16NUM":         `(?:0[xX])?[0-9A-Fa-f]+`,
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IPV4":              `(?:(?:25[0-5]|2[0-4][0-9]|1?[0-9]{1,2})\.){3}(?:25[0-5]|2[0-4][0-9]|1?[0-9]{1,2})`,
+	"IPV6":              `(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`,
+	"IP":                `%{IPV6}|%{IPV4}`,
+	"HOSTNAME":          `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\.?\b`,
+	"IPORHOST":          `%{IP}|%{HOSTNAME}`,
+	"HOSTPORT":          `%{IPORHOST}:%{POSINT}`,
+	"PATH":              `(?:/[^/\s]*)+`,
+	"URIPATH":           `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"MONTH":             `\b(?:[Jj]an(?:uary)?|[Ff]eb(?:ruary)?|[Mm]ar(?:ch)?|[Aa]pr(?:il)?|[Mm]ay|[Jj]un(?:e)?|[Jj]ul(?:y)?|[Aa]ug(?:ust)?|[Ss]ep(?:tember)?|[Oo]ct(?:ober)?|[Nn]ov(?:ember)?|[Dd]ec(?:ember)?)\b`,
+	"MONTHNUM":          `0?[1-9]|1[0-2]`,
+	"MONTHDAY":          `0[1-9]|[12][0-9]|3[01]|[1-9]`,
+	"YEAR":              `[0-9]{4}`,
+	"HOUR":              `2[0-3]|[01]?[0-9]`,
+	"MINUTE":            `[0-5][0-9]`,
+	"SECOND":            `(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?`,
+	"TIME":              `%{HOUR}:%{MINUTE}:%{SECOND}`,
+	"ISO8601_TIMEZONE":  `Z|[+-]%{HOUR}(?::?%{MINUTE})`,
+	"TIMESTAMP_ISO8601": `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?%{ISO8601_TIMEZONE}?`,
+	"SYSLOGTIMESTAMP":   `%{MONTH} +%{MONTHDAY} %{TIME}`,
+	"HTTPDATE":          `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} %{INT}`,
+	"LOGLEVEL":          `(?i:alert|trace|debug|notice|info(?:rmation)?|warn(?:ing)?|err(?:or)?|crit(?:ical)?|fatal|severe|emerg(?:ency)?)`,
+	"PROG":              `[\x21-\x5a\x5c\x5e-\x7e]+`,
+}
+
+// Expands grok references into a regular expression with a named group per %{PATTERN:name}
+func compileGrok(pattern string, customPatterns map[string]string) (regex *regexp.Regexp, err error) {
+	definitions := maps.Clone(grokPatterns)
+	maps.Copy(definitions, customPatterns)
+
+	expanded, err := expandGrok(pattern, definitions, 0)
+	if err != nil {
+		return
+	}
+	regex, err = regexp.Compile(expanded)
+	return
+}
+
+func expandGrok(pattern string, definitions map[string]string, depth int) (expanded string, err error) {
+	if depth > maxGrokDepth {
+		err = fmt.Errorf("pattern references nested more than %d levels (recursive definition?)", maxGrokDepth)
+		return
+	}
+
+	expanded = grokReference.ReplaceAllStringFunc(pattern, func(reference string) (replacement string) {
+		if err != nil {
+			return
+		}
+		parts := grokReference.FindStringSubmatch(reference)
+
+		definition, exists := definitions[parts[1]]
+		if !exists {
+			err = fmt.Errorf("unknown pattern %q", parts[1])
+			return
+		}
+		inner, innerErr := expandGrok(definition, definitions, depth+1)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		if parts[2] == "" {
+			replacement = "(?:" + inner + ")"
+		} else {
+			replacement = "(?P<" + parts[2] + ">" + inner + ")"
+		}
+		return
+	})
+	return
+}
@@ -0,0 +1,144 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Returns open handle for path, opening it (and closing the least recently used handle when the cache is full)
+func (mod *OutModule) getFile(path string) (handle *outputFile, err error) {
+	mod.useClock++
+
+	handle, open := mod.files[path]
+	if open {
+		handle.lastUsed = mod.useClock
+		return
+	}
+
+	if len(mod.files) >= mod.maxOpenFiles {
+		err = mod.closeLeastRecentlyUsed()
+		if err != nil {
+			return
+		}
+	}
+
+	if !mod.template.static {
+		err = os.MkdirAll(filepath.Dir(path), outputDirMode)
+		if err != nil {
+			return
+		}
+	}
+
+	flags := os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	if mod.auditKey != nil {
+		flags = os.O_CREATE | os.O_APPEND | os.O_RDWR // Last record is read to resume the chain
+	}
+	file, err := os.OpenFile(path, flags, outputFileMode)
+	if err != nil {
+		return
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+
+	handle = &outputFile{
+		file:     file,
+		size:     uint64(info.Size()),
+		lastUsed: mod.useClock,
+	}
+	if mod.rotateInterval > 0 {
+		handle.rotationStart = lastRotation(path)
+	}
+	if mod.auditKey != nil {
+		var discarded int64
+		handle.chain, discarded, err = resumeAuditChain(file, info.Size())
+		if err != nil {
+			_ = file.Close()
+			err = fmt.Errorf("failed to resume audit chain of %q: %w", path, err)
+			return
+		}
+		if discarded > 0 {
+			handle.size -= uint64(discarded)
+			mod.warnings = append(mod.warnings,
+				fmt.Sprintf("discarded %d byte(s) of incomplete audit record at the end of %q", discarded, path))
+		}
+	}
+	mod.files[path] = handle
+	return
+}
+
+// Closes the handle that was not written to for the longest time
+func (mod *OutModule) closeLeastRecentlyUsed() (err error) {
+	var oldestPath string
+	var oldest *outputFile
+	for path, handle := range mod.files {
+		if oldest == nil || handle.lastUsed < oldest.lastUsed {
+			oldestPath = path
+			oldest = handle
+		}
+	}
+	if oldest == nil {
+		return
+	}
+	err = mod.writeCheckpoint(oldest)
+	if err != nil {
+		return
+	}
+	_ = oldest.file.Close() // Append only, nothing is buffered
+	delete(mod.files, oldestPath)
+	return
+}
+
+// Closes all open handles
+func (mod *OutModule) closeFiles() (err error) {
+	for path, handle := range mod.files {
+		lerr := mod.writeCheckpoint(handle)
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		lerr = handle.file.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		delete(mod.files, path)
+	}
+	return
+}
+
+// Appends data to the file
+func (handle *outputFile) write(data []byte) (err error) {
+	for len(data) > 0 {
+		var n int
+		n, err = handle.file.Write(data)
+		handle.size += uint64(n)
+		if err != nil {
+			return
+		}
+		data = data[n:] // remove the bytes that were successfully written
+	}
+	return
+}
+
+// Time the current file at path was started (the newest rotation, or its creation when it was never rotated)
+func lastRotation(path string) (started time.Time) {
+	started = time.Now()
+
+	rotated, err := rotatedFiles(path)
+	if err != nil || len(rotated) == 0 {
+		// Not the time of opening, evicted handles are reopened by the handle cache
+		birth, ok := getBirthTime(path)
+		if ok && birth.Before(started) {
+			started = birth
+		}
+		return
+	}
+	stamp, valid := rotationStamp(path, rotated[len(rotated)-1])
+	if valid {
+		started = stamp
+	}
+	return
+}
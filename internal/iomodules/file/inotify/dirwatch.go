@@ -0,0 +1,181 @@
+package inotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sdsyslog/internal/logctx"
+
+	"golang.org/x/sys/unix"
+)
+
+// Initializes new directory watcher instance (directories are added with Add)
+func NewDirWatcher(ctx context.Context) (new *DirWatcher, err error) {
+	new = &DirWatcher{
+		dirChanged:  make(chan struct{}, 1),
+		directories: make(map[string]int32),
+		eventSize:   uint32(unix.SizeofInotifyEvent),
+	}
+	new.ctx, new.cancel = context.WithCancel(ctx)
+
+	new.instanceFD, err = unix.InotifyInit1(unix.IN_NONBLOCK)
+	if err != nil {
+		err = fmt.Errorf("failed to initialize inotify: %w", err)
+		return
+	}
+
+	// Use epoll to control unblocking of inotify blocking read (For shutdown draining)
+	new.wakeFD, err = unix.Eventfd(0, unix.EFD_NONBLOCK)
+	if err != nil {
+		err = fmt.Errorf("failed to create eventfd: %w", err)
+		return
+	}
+	new.epollFD, err = unix.EpollCreate1(0)
+	if err != nil {
+		err = fmt.Errorf("failed to create epoll: %w", err)
+		return
+	}
+	for _, fd := range []int{new.instanceFD, new.wakeFD} {
+		err = unix.EpollCtl(new.epollFD, unix.EPOLL_CTL_ADD, fd,
+			&unix.EpollEvent{
+				Events: unix.EPOLLIN | unix.EPOLLERR | unix.EPOLLHUP,
+				Fd:     int32(fd),
+			})
+		if err != nil {
+			err = fmt.Errorf("failed to add fd to epoll: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// Adds directory to the watch list (no-op if already watched)
+func (watcher *DirWatcher) Add(directory string) (err error) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	if _, exists := watcher.directories[directory]; exists {
+		return
+	}
+
+	watchDescriptor, err := unix.InotifyAddWatch(watcher.instanceFD,
+		directory,
+		unix.IN_CREATE|unix.IN_DELETE|unix.IN_MOVED_TO|unix.IN_MOVED_FROM|unix.IN_DELETE_SELF|unix.IN_MOVE_SELF)
+	if err != nil {
+		err = fmt.Errorf("failed to add directory '%s' to inotify watcher: %w", directory, err)
+		return
+	}
+	watcher.directories[directory] = int32(watchDescriptor)
+	return
+}
+
+// Spawns go routine for watcher worker
+func (watcher *DirWatcher) Start() {
+	watcher.wg.Add(1)
+	go watcher.run()
+}
+
+// Supplies signals when entries in any watched directory are created, moved, or removed
+func (watcher *DirWatcher) Changed() <-chan struct{} {
+	return watcher.dirChanged
+}
+
+// Gracefully stops watcher instance and cleans up file descriptors
+func (watcher *DirWatcher) Stop() {
+	watcher.cancel()
+
+	// Wake epoll (unblock inotify blocked read)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], 1)
+	_, err := unix.Write(watcher.wakeFD, buf[:])
+	if err != nil && !errors.Is(err, unix.EAGAIN) {
+		logctx.LogStdWarn(watcher.ctx, "failed to write wakefd: %w\n", err)
+	}
+
+	watcher.wg.Wait()
+
+	for _, fd := range []int{watcher.instanceFD, watcher.wakeFD, watcher.epollFD} {
+		err = unix.Close(fd)
+		if err != nil {
+			logctx.LogStdWarn(watcher.ctx, "failed to close directory watcher file descriptor: %w\n", err)
+		}
+	}
+}
+
+func (watcher *DirWatcher) run() {
+	defer watcher.wg.Done()
+
+	buf := make([]byte, watcher.eventSize+8192)
+	events := make([]unix.EpollEvent, 2)
+
+	for {
+		n, err := unix.EpollWait(watcher.epollFD, events, -1)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			logctx.LogStdErr(watcher.ctx, "epoll wait error: %v", err)
+			return
+		}
+
+		var draining bool
+		for i := range n {
+			if int(events[i].Fd) == watcher.wakeFD {
+				// Shutdown signal
+				draining = true
+				continue
+			}
+
+			for {
+				n, err := unix.Read(watcher.instanceFD, buf)
+				if err != nil {
+					if !errors.Is(err, unix.EAGAIN) {
+						logctx.LogStdErr(watcher.ctx, "read error: %v", err)
+					}
+					break
+				}
+				if n == 0 {
+					break
+				}
+				watcher.processEvents(buf[:n])
+			}
+		}
+
+		if draining {
+			return
+		}
+	}
+}
+
+// Forgets removed directories and signals any change
+func (watcher *DirWatcher) processEvents(buf []byte) {
+	var offset uint32
+	for offset+watcher.eventSize <= uint32(len(buf)) {
+		var event unix.InotifyEvent
+		err := binary.Read(bytes.NewReader(buf[offset:offset+watcher.eventSize]), binary.LittleEndian, &event)
+		if err != nil {
+			logctx.LogStdErr(watcher.ctx, "failed to read event content: %w\n", err)
+			return
+		}
+		offset += watcher.eventSize + event.Len
+
+		// Kernel removed the watch (directory deleted), allow re-adding a new directory at the same path
+		if event.Mask&unix.IN_IGNORED != 0 {
+			watcher.mu.Lock()
+			for directory, watchDescriptor := range watcher.directories {
+				if watchDescriptor == event.Wd {
+					delete(watcher.directories, directory)
+				}
+			}
+			watcher.mu.Unlock()
+		}
+
+		// Notify of change, but only when not consumed yet
+		select {
+		case watcher.dirChanged <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package inotify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/logctx"
+	"testing"
+	"time"
+)
+
+func TestDirWatcher(t *testing.T) {
+	tests := []struct {
+		name           string
+		changeFunc     func(dir string) (err error)
+		expectedSignal bool
+	}{
+		{
+			name: "File created",
+			changeFunc: func(dir string) (err error) {
+				err = os.WriteFile(filepath.Join(dir, "new.log"), nil, 0644)
+				return
+			},
+			expectedSignal: true,
+		},
+		{
+			name: "File deleted",
+			changeFunc: func(dir string) (err error) {
+				err = os.Remove(filepath.Join(dir, "existing.log"))
+				return
+			},
+			expectedSignal: true,
+		},
+		{
+			name: "File moved away",
+			changeFunc: func(dir string) (err error) {
+				err = os.Rename(filepath.Join(dir, "existing.log"), filepath.Join(filepath.Dir(dir), "moved.log"))
+				return
+			},
+			expectedSignal: true,
+		},
+		{
+			name: "File written",
+			changeFunc: func(dir string) (err error) {
+				file, err := os.OpenFile(filepath.Join(dir, "existing.log"), os.O_WRONLY|os.O_APPEND, 0)
+				if err != nil {
+					return
+				}
+				_, err = file.WriteString("test message\n")
+				if err != nil {
+					return
+				}
+				err = file.Close()
+				return
+			},
+			expectedSignal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+			watchedDir := filepath.Join(t.TempDir(), "logs")
+			err := os.Mkdir(watchedDir, 0755)
+			if err != nil {
+				t.Fatalf("failed to create watched directory: %v", err)
+			}
+			err = os.WriteFile(filepath.Join(watchedDir, "existing.log"), nil, 0644)
+			if err != nil {
+				t.Fatalf("failed to create existing file: %v", err)
+			}
+
+			watcher, err := NewDirWatcher(ctx)
+			if err != nil {
+				t.Fatalf("failed to create directory watcher: %v", err)
+			}
+			err = watcher.Add(watchedDir)
+			if err != nil {
+				t.Fatalf("failed to add directory: %v", err)
+			}
+			watcher.Start()
+			defer watcher.Stop()
+
+			err = tt.changeFunc(watchedDir)
+			if err != nil {
+				t.Fatalf("failed to run change function: %v", err)
+			}
+
+			var gotSignal bool
+			select {
+			case <-watcher.Changed():
+				gotSignal = true
+			case <-time.After(200 * time.Millisecond):
+			}
+			if gotSignal != tt.expectedSignal {
+				t.Errorf("expected change signal %v, got %v", tt.expectedSignal, gotSignal)
+			}
+		})
+	}
+}
+
+func TestDirWatcherRemovedDirectory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	watchedDir := filepath.Join(t.TempDir(), "logs")
+	err := os.Mkdir(watchedDir, 0755)
+	if err != nil {
+		t.Fatalf("failed to create watched directory: %v", err)
+	}
+
+	watcher, err := NewDirWatcher(ctx)
+	if err != nil {
+		t.Fatalf("failed to create directory watcher: %v", err)
+	}
+	err = watcher.Add(watchedDir)
+	if err != nil {
+		t.Fatalf("failed to add directory: %v", err)
+	}
+	watcher.Start()
+	defer watcher.Stop()
+
+	err = os.Remove(watchedDir)
+	if err != nil {
+		t.Fatalf("failed to remove watched directory: %v", err)
+	}
+
+	// Removed directory is forgotten so a recreated one can be watched
+	deadline := time.Now().Add(time.Second)
+	for {
+		watcher.mu.Lock()
+		_, watched := watcher.directories[watchedDir]
+		watcher.mu.Unlock()
+		if !watched {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("removed directory is still tracked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -29,3 +29,23 @@ type Watcher struct {
 	cancel context.CancelFunc // Stop instance
 	ctx    context.Context
 }
+
+type DirWatcher struct {
+	// Kernel comms
+	eventSize  uint32 // inotify event byte size
+	instanceFD int    // inotify file descriptor
+	wakeFD     int    // unblock syscall read on inotify event
+	epollFD    int    // FD to contain inotify and wake fds
+
+	// External comms
+	dirChanged chan struct{} // Entries in a watched directory changed
+
+	// State
+	mu          sync.Mutex
+	directories map[string]int32 // Watch descriptors keyed by directory path
+
+	// Lifetime
+	wg     sync.WaitGroup
+	cancel context.CancelFunc // Stop instance
+	ctx    context.Context
+}
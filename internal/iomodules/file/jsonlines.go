@@ -0,0 +1,96 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Structured log line format for outputs (one JSON object per line)
+// Fmt: '{"timestamp":"2020-01-01T10:10:10.123456789Z","hostname":"Server01","signature":"verified",...,"fields":{"ApplicationName":"MyApp","ProcessID":1234},"message":"this is a log message"}'
+func formatAsJSON(msg *protocol.Payload) (line string, err error) {
+	record := jsonRecord{
+		HostID: msg.HostID,
+		MsgID:  msg.MsgID,
+	}
+	record.Signature, record.Hostname = signatureStatus(msg.Hostname, msg.SignatureID)
+	if !msg.Timestamp.IsZero() {
+		record.Timestamp = msg.Timestamp.Format(time.RFC3339Nano)
+	}
+	if msg.RemoteIP.IsValid() {
+		record.RemoteIP = msg.RemoteIP.Unmap().String()
+	}
+
+	if len(msg.CustomFields) > 0 {
+		record.Fields = make(map[string]any, len(msg.CustomFields))
+		for key, value := range msg.CustomFields {
+			record.Fields[key] = jsonValue(value)
+		}
+	}
+
+	data := bytes.TrimRight(msg.Data, "\r\n")
+	if utf8.Valid(data) {
+		record.Message = string(data)
+	} else {
+		record.MessageBase64 = data
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(record) // Adds trailing newline
+	if err != nil {
+		return
+	}
+	line = buf.String()
+	return
+}
+
+// Splits the receivers' trust marker from the hostname into a signature status
+func signatureStatus(hostname string, signatureID uint8) (status string, bareHostname string) {
+	markers := []struct {
+		prefix string
+		status string
+	}{
+		{protocol.HostPrefixUnverified, SignatureUnverified},
+		{protocol.HostPrefixUnkSig, SignatureUnknown},
+		{protocol.HostPrefixPartial, SignaturePartial},
+	}
+	for _, marker := range markers {
+		var found bool
+		bareHostname, found = strings.CutPrefix(hostname, marker.prefix)
+		if found {
+			status = marker.status
+			return
+		}
+	}
+
+	if signatureID != 0 {
+		status = SignatureVerified
+	} else {
+		status = SignatureNone
+	}
+	return
+}
+
+// Converts custom field value into a value encoding/json can represent ([]byte is encoded as base64 by encoding/json)
+func jsonValue(value any) (converted any) {
+	switch typed := value.(type) {
+	case float32:
+		if math.IsNaN(float64(typed)) || math.IsInf(float64(typed), 0) {
+			converted = protocol.FormatValue(typed)
+			return
+		}
+	case float64:
+		if math.IsNaN(typed) || math.IsInf(typed, 0) {
+			converted = protocol.FormatValue(typed)
+			return
+		}
+	}
+	converted = value
+	return
+}
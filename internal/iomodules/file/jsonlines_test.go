@@ -0,0 +1,146 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"testing"
+	"time"
+)
+
+func TestFormatAsJSON(t *testing.T) {
+	timestamp := time.Date(2025, 3, 4, 5, 6, 7, 123456789, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    protocol.Payload
+		expected string
+	}{
+		{
+			name: "verified with typed fields",
+			input: protocol.Payload{
+				RemoteIP:    netip.MustParseAddr("::ffff:192.0.2.10"),
+				HostID:      77,
+				MsgID:       1234,
+				Timestamp:   timestamp,
+				Hostname:    "web01",
+				SignatureID: 1,
+				CustomFields: map[string]any{
+					iomodules.CFappname:   "nginx",
+					iomodules.CFprocessid: int32(4242),
+					"ratio":               float64(0.5),
+					"ok":                  true,
+					"blob":                []byte{0x00, 0xff, 0x10},
+				},
+				Data: []byte("GET /index.html <200>\n"),
+			},
+			expected: `{"timestamp":"2025-03-04T05:06:07.123456789Z","hostname":"web01","signature":"verified","remoteIP":"192.0.2.10",` +
+				`"hostID":77,"msgID":1234,"fields":{"ApplicationName":"nginx","ProcessID":4242,"blob":"AP8Q","ok":true,"ratio":0.5},"message":"GET /index.html <200>"}` + "\n",
+		},
+		{
+			name: "unverified sender",
+			input: protocol.Payload{
+				Timestamp: timestamp,
+				Hostname:  protocol.HostPrefixUnverified + "db01",
+				Data:      []byte("hello"),
+			},
+			expected: `{"timestamp":"2025-03-04T05:06:07.123456789Z","hostname":"db01","signature":"unverified","hostID":0,"msgID":0,"message":"hello"}` + "\n",
+		},
+		{
+			name: "partial and non-finite float",
+			input: protocol.Payload{
+				Timestamp:    timestamp,
+				Hostname:     protocol.HostPrefixPartial + "db01",
+				SignatureID:  1,
+				CustomFields: map[string]any{"value": math.Inf(1)},
+				Data:         []byte("hello"),
+			},
+			expected: `{"timestamp":"2025-03-04T05:06:07.123456789Z","hostname":"db01","signature":"partial","hostID":0,"msgID":0,"fields":{"value":"+Inf"},"message":"hello"}` + "\n",
+		},
+		{
+			name: "binary message",
+			input: protocol.Payload{
+				Hostname: "local",
+				Data:     []byte{0xff, 0xfe, 'a'},
+			},
+			expected: `{"hostname":"local","signature":"none","hostID":0,"msgID":0,"messageBase64":"//5h"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, err := formatAsJSON(&tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if line != tt.expected {
+				t.Errorf("unexpected JSON line\nexpected: %s\ngot:      %s", tt.expected, line)
+			}
+			if !json.Valid([]byte(line)) {
+				t.Errorf("output is not valid JSON: %s", line)
+			}
+		})
+	}
+}
+
+func TestJSONOutputOrdering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	outFilePath := filepath.Join(t.TempDir(), "output.jsonl")
+	outMod, err := NewOutput(OutputConfig{Path: outFilePath, Format: FormatJSON, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("failed to create output module: %v", err)
+	}
+
+	// Written out of order, flushed sorted by timestamp
+	startTime := time.Now()
+	for _, offset := range []int{3, 1, 2, 0} {
+		_, err = outMod.Write(ctx, &protocol.Payload{
+			Hostname:  "web01",
+			MsgID:     offset,
+			Timestamp: startTime.Add(time.Duration(offset) * time.Second),
+			Data:      []byte("message"),
+		})
+		if err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+	}
+	written, err := outMod.FlushBuffer()
+	if err != nil {
+		t.Fatalf("failed to flush write buffer: %v", err)
+	}
+	if written != 4 {
+		t.Errorf("expected 4 lines flushed, but got %d", written)
+	}
+	err = outMod.Shutdown()
+	if err != nil {
+		t.Fatalf("failed to shutdown output module: %v", err)
+	}
+
+	contents, err := os.ReadFile(outFilePath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	lines := bytes.Split(bytes.TrimSuffix(contents, []byte("\n")), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, but got %d:\n%s", len(lines), contents)
+	}
+	for index, line := range lines {
+		var record jsonRecord
+		err = json.Unmarshal(line, &record)
+		if err != nil {
+			t.Fatalf("failed to parse line %d: %v", index, err)
+		}
+		if record.MsgID != index {
+			t.Errorf("expected line %d to be message %d, but got message %d", index, index, record.MsgID)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package file
+
+import (
+	"fmt"
+	"regexp"
+	"sdsyslog/internal/iomodules/audit"
+	"strings"
+	"time"
+)
+
+// Validates multi-line rules and creates an empty grouper
+func newMultilineGrouper(config MultilineConfig) (grouper *multilineGrouper, err error) {
+	grouper = &multilineGrouper{
+		mode:         config.Mode,
+		maxLines:     config.MaxLines,
+		maxBytes:     config.MaxBytes,
+		flushTimeout: time.Duration(config.FlushTimeout),
+	}
+	if grouper.maxLines == 0 {
+		grouper.maxLines = defaultMultilineMaxLines
+	}
+	if grouper.maxBytes == 0 {
+		grouper.maxBytes = defaultMultilineMaxBytes
+	}
+	if grouper.flushTimeout == 0 {
+		grouper.flushTimeout = defaultMultilineTimeout
+	}
+	if grouper.maxLines < 0 || grouper.maxBytes < 0 || grouper.flushTimeout < 0 {
+		err = fmt.Errorf("max lines, max bytes, and flush timeout cannot be negative")
+		return
+	}
+
+	switch config.Mode {
+	case MultilineContinuation:
+		if config.StartPattern != "" {
+			err = fmt.Errorf("start pattern is only valid in %s mode", MultilinePattern)
+			return
+		}
+	case MultilineAudit:
+		if config.StartPattern != "" {
+			err = fmt.Errorf("start pattern is only valid in %s mode", MultilinePattern)
+			return
+		}
+	case MultilinePattern:
+		if config.StartPattern == "" {
+			err = fmt.Errorf("%s mode requires a start pattern", MultilinePattern)
+			return
+		}
+		grouper.startPattern, err = regexp.Compile(config.StartPattern)
+		if err != nil {
+			err = fmt.Errorf("invalid start pattern: %w", err)
+			return
+		}
+	default:
+		err = fmt.Errorf("unknown mode %q: must be one of %q, %q, %q", config.Mode, MultilineContinuation, MultilinePattern, MultilineAudit)
+		return
+	}
+	return
+}
+
+// Adds a line starting at the given file offset. Returns the previous event when this line does not continue it.
+func (grouper *multilineGrouper) add(line string, offset int64) (event string, complete bool) {
+	continues := len(grouper.lines) > 0 && grouper.continuesEvent(line)
+
+	// Caps end the pending event early, the line starts the next one
+	if continues && (len(grouper.lines)+1 > grouper.maxLines || grouper.size+1+len(line) > grouper.maxBytes) {
+		continues = false
+	}
+
+	if !continues {
+		event, complete = grouper.flush()
+		grouper.startOffset = offset
+	} else {
+		grouper.size++ // Joining newline
+	}
+	grouper.lines = append(grouper.lines, line)
+	grouper.size += len(line)
+	return
+}
+
+// Returns the pending event (if any) and resets the grouper
+func (grouper *multilineGrouper) flush() (event string, complete bool) {
+	if len(grouper.lines) == 0 {
+		return
+	}
+	event = strings.Join(grouper.lines, "\n")
+	complete = true
+
+	grouper.lines = grouper.lines[:0]
+	grouper.size = 0
+	return
+}
+
+// Reports whether a line is part of the pending event
+func (grouper *multilineGrouper) continuesEvent(line string) (continues bool) {
+	switch grouper.mode {
+	case MultilineContinuation:
+		continues = strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+	case MultilinePattern:
+		continues = !grouper.startPattern.MatchString(line)
+	case MultilineAudit:
+		serial, valid := audit.LineSerial(line)
+		eventSerial, _ := audit.LineSerial(grouper.lines[0])
+		continues = valid && serial == eventSerial
+	}
+	return
+}
+
+// Reports whether lines are waiting for the rest of their event
+func (grouper *multilineGrouper) pending() (waiting bool) {
+	waiting = grouper != nil && len(grouper.lines) > 0
+	return
+}
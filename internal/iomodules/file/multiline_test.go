@@ -0,0 +1,127 @@
+package file
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMultilineGrouper(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         MultilineConfig
+		lines          []string
+		expectedEvents []string
+	}{
+		{
+			name:           "continuation lines join previous event",
+			config:         MultilineConfig{Mode: MultilineContinuation},
+			lines:          []string{"first", "  second", "\tthird", "fourth"},
+			expectedEvents: []string{"first\n  second\n\tthird", "fourth"},
+		},
+		{
+			name:           "leading continuation line starts an event",
+			config:         MultilineConfig{Mode: MultilineContinuation},
+			lines:          []string{"  orphan", "next"},
+			expectedEvents: []string{"  orphan", "next"},
+		},
+		{
+			name:           "start pattern begins events",
+			config:         MultilineConfig{Mode: MultilinePattern, StartPattern: `^\[`},
+			lines:          []string{"[1] start", "detail", "[2] start"},
+			expectedEvents: []string{"[1] start\ndetail", "[2] start"},
+		},
+		{
+			name:   "audit records group by serial number",
+			config: MultilineConfig{Mode: MultilineAudit},
+			lines: []string{
+				"type=SYSCALL msg=audit(1700000000.100:7): syscall=59",
+				"type=CWD msg=audit(1700000000.100:7): cwd=\"/\"",
+				"type=USER_LOGIN msg=audit(1700000000.200:8): pid=1",
+				"not an audit record",
+			},
+			expectedEvents: []string{
+				"type=SYSCALL msg=audit(1700000000.100:7): syscall=59\ntype=CWD msg=audit(1700000000.100:7): cwd=\"/\"",
+				"type=USER_LOGIN msg=audit(1700000000.200:8): pid=1",
+				"not an audit record",
+			},
+		},
+		{
+			name:           "max lines splits event",
+			config:         MultilineConfig{Mode: MultilineContinuation, MaxLines: 2},
+			lines:          []string{"a", " b", " c", " d"},
+			expectedEvents: []string{"a\n b", " c\n d"},
+		},
+		{
+			name:           "max bytes splits event",
+			config:         MultilineConfig{Mode: MultilineContinuation, MaxBytes: 8},
+			lines:          []string{"abc", " de", " fg"},
+			expectedEvents: []string{"abc\n de", " fg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grouper, err := newMultilineGrouper(tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error creating grouper: %v", err)
+			}
+
+			var events []string
+			var offset int64
+			for _, line := range tt.lines {
+				event, complete := grouper.add(line, offset)
+				if complete {
+					events = append(events, event)
+				}
+				offset += int64(len(line)) + 1
+			}
+			if event, complete := grouper.flush(); complete {
+				events = append(events, event)
+			}
+			if grouper.pending() {
+				t.Errorf("expected no pending lines after flush")
+			}
+
+			if strings.Join(events, "|") != strings.Join(tt.expectedEvents, "|") {
+				t.Errorf("expected events %q, got %q", tt.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestMultilineStartOffset(t *testing.T) {
+	grouper, err := newMultilineGrouper(MultilineConfig{Mode: MultilineContinuation})
+	if err != nil {
+		t.Fatalf("unexpected error creating grouper: %v", err)
+	}
+
+	grouper.add("first", 0)
+	grouper.add("second", 6)
+	grouper.add(" detail", 13)
+	if grouper.startOffset != 6 {
+		t.Errorf("expected pending event to start at offset 6, got %d", grouper.startOffset)
+	}
+}
+
+func TestNewMultilineGrouper(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MultilineConfig
+	}{
+		{name: "missing mode", config: MultilineConfig{}},
+		{name: "unknown mode", config: MultilineConfig{Mode: "indent"}},
+		{name: "pattern mode without pattern", config: MultilineConfig{Mode: MultilinePattern}},
+		{name: "invalid pattern", config: MultilineConfig{Mode: MultilinePattern, StartPattern: "("}},
+		{name: "pattern in continuation mode", config: MultilineConfig{Mode: MultilineContinuation, StartPattern: "^x"}},
+		{name: "negative max lines", config: MultilineConfig{Mode: MultilineContinuation, MaxLines: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMultilineGrouper(tt.config)
+			if err == nil {
+				t.Errorf("expected error for %s", tt.name)
+			}
+		})
+	}
+}
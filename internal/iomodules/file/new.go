@@ -2,7 +2,6 @@ package file
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
@@ -13,10 +12,13 @@ import (
 	"sdsyslog/internal/logctx"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/pkg/protocol"
+	"strings"
+	"time"
 )
 
-// Creates new file input module. Returns nil nil if no path.
-func NewInput(ctx context.Context, filePath string, baseStateFile string, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (module *InModule, err error) {
+// Creates new file input module (nil parser auto-detects line formats). Returns nil nil if no path.
+func NewInput(ctx context.Context, config InputConfig, parser *Parser, baseStateFile string, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (module *InModule, err error) {
+	filePath := config.Path
 	if filePath == "" {
 		return
 	}
@@ -29,15 +31,26 @@ func NewInput(ctx context.Context, filePath string, baseStateFile string, filter
 		}
 	}
 
-	// Create unique state file for this source
-	stateFileDir := filepath.Dir(baseStateFile)
-	stateFileName := filepath.Base(baseStateFile)
+	// Records of an audit event are grouped unless configured otherwise
+	if config.Multiline == nil && parser != nil && parser.format == ParserAudit {
+		config.Multiline = &MultilineConfig{Mode: MultilineAudit}
+	}
+
+	var multiline *multilineGrouper
+	if config.Multiline != nil {
+		multiline, err = newMultilineGrouper(*config.Multiline)
+		if err != nil {
+			err = fmt.Errorf("invalid multiline settings for file source %q: %w", filePath, err)
+			return
+		}
+	}
 
-	newStateFileName := base64.RawURLEncoding.EncodeToString([]byte(filePath)) + "_" + stateFileName // Using full file path as prefix to state file
-	newStateFile := filepath.Join(stateFileDir, newStateFileName)
+	// Create unique state file for this source
+	newStateFile := StateFilePath(filePath, baseStateFile)
 
-	// New context for file
-	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoFile, filepath.Base(filePath))
+	// New context for file (full path, matched files of glob and recursive inputs can share a name)
+	sourceName := strings.TrimPrefix(filepath.Clean(filePath), string(filepath.Separator))
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoFile, sourceName)
 	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
 	modCtx, cancel := context.WithCancel(modCtx)
 
@@ -45,8 +58,11 @@ func NewInput(ctx context.Context, filePath string, baseStateFile string, filter
 		filePath:  filePath,
 		stateFile: newStateFile,
 		filters:   filters,
+		multiline: multiline,
+		parser:    parser,
 		outbox:    queue,
 		metrics:   MetricStorage{},
+		retire:    make(chan struct{}),
 
 		ctx:    modCtx,
 		cancel: cancel,
@@ -95,25 +111,67 @@ func NewInput(ctx context.Context, filePath string, baseStateFile string, filter
 }
 
 // Creates new file output module. Returns nil nil if no path.
-func NewOutput(filePath string, batchSize int) (module *OutModule, err error) {
-	if filePath == "" {
+// Paths without placeholders are opened immediately, templated paths are opened on first write.
+func NewOutput(config OutputConfig) (module *OutModule, err error) {
+	if config.Path == "" {
 		return
 	}
 
-	const defaultBatchSize int = 20
-	if batchSize == 0 {
-		batchSize = defaultBatchSize
+	new := &OutModule{
+		files:          make(map[string]*outputFile),
+		format:         config.Format,
+		batchSize:      config.BatchSize,
+		maxOpenFiles:   config.MaxOpenFiles,
+		maxSize:        config.MaxSizeBytes,
+		rotateInterval: time.Duration(config.RotateInterval),
+		compression:    config.Compression,
+		retention:      config.Retention,
+
+		auditCheckpointRecords: config.AuditCheckpointRecords,
+	}
+	if new.format == "" {
+		new.format = FormatText
+	}
+	if new.batchSize == 0 {
+		new.batchSize = defaultBatchSize
+	}
+	if new.maxOpenFiles == 0 {
+		new.maxOpenFiles = defaultMaxOpenFiles
+	}
+	if new.auditCheckpointRecords == 0 {
+		new.auditCheckpointRecords = defaultAuditCheckpointRecords
+	}
+	if new.batchSize < 0 || new.maxOpenFiles < 0 || new.retention < 0 || new.rotateInterval < 0 || new.auditCheckpointRecords < 0 {
+		err = fmt.Errorf("batch size, max open files, retention, rotate interval, and audit checkpoint records cannot be negative")
+		return
+	}
+	if new.format != FormatText && new.format != FormatJSON {
+		err = fmt.Errorf("unknown format %q: must be one of %q, %q", new.format, FormatText, FormatJSON)
+		return
+	}
+	if new.compression != "" && new.compression != CompressionGzip && new.compression != CompressionZstd {
+		err = fmt.Errorf("unknown compression %q: must be one of %q, %q", new.compression, CompressionGzip, CompressionZstd)
+		return
+	}
+
+	if config.AuditKeyFile != "" {
+		new.auditKey, err = loadAuditKey(config.AuditKeyFile)
+		if err != nil {
+			return
+		}
 	}
 
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	new.template, err = parsePathTemplate(config.Path)
 	if err != nil {
 		return
 	}
-
-	module = &OutModule{
-		sink:        file,
-		batchSize:   batchSize,
-		batchBuffer: &[]string{},
+	if new.template.static {
+		_, err = new.getFile(config.Path)
+		if err != nil {
+			return
+		}
 	}
+
+	module = new
 	return
 }
@@ -0,0 +1,295 @@
+package file
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/audit"
+	"sdsyslog/internal/iomodules/syslog"
+	"sdsyslog/pkg/protocol"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validates and compiles a parser profile
+func NewParser(config ParserConfig) (parser *Parser, err error) {
+	if config.Name == "" {
+		err = fmt.Errorf("parser profile name cannot be empty")
+		return
+	}
+	if config.Format == "" {
+		config.Format = ParserAuto
+	}
+
+	parser = &Parser{
+		name:             config.Name,
+		format:           config.Format,
+		timestampField:   config.TimestampField,
+		timestampLayouts: config.TimestampLayouts,
+		hostnameField:    config.HostnameField,
+		messageField:     config.MessageField,
+		fields:           make(map[string]string),
+		location:         time.Local,
+	}
+
+	// Security formats have well-known names for the common values
+	switch config.Format {
+	case ParserCEF:
+		parser.applyFormatDefaults("rt", "dvchost", "name", map[string]string{"deviceProduct": iomodules.CFappname, "severity": iomodules.CFseverity})
+	case ParserLEEF:
+		parser.applyFormatDefaults("devTime", "identHostName", "", map[string]string{"product": iomodules.CFappname, "sev": iomodules.CFseverity})
+	}
+	if config.Format == ParserCEF || config.Format == ParserLEEF {
+		if parser.timestampField != "" && len(parser.timestampLayouts) == 0 {
+			parser.timestampLayouts = securityTimestampLayouts
+		}
+	}
+	maps.Copy(parser.fields, config.Fields)
+
+	switch config.Format {
+	case ParserAuto, ParserAudit:
+		if config.Pattern != "" || config.TimestampField != "" || config.HostnameField != "" || config.MessageField != "" || len(config.Fields) > 0 {
+			err = fmt.Errorf("%s format does not take a pattern or field settings", config.Format)
+			return
+		}
+	case ParserRegex:
+		if config.Pattern == "" {
+			err = fmt.Errorf("%s format requires a pattern", config.Format)
+			return
+		}
+		parser.pattern, err = regexp.Compile(config.Pattern)
+		if err != nil {
+			err = fmt.Errorf("invalid pattern: %w", err)
+			return
+		}
+	case ParserGrok:
+		if config.Pattern == "" {
+			err = fmt.Errorf("%s format requires a pattern", config.Format)
+			return
+		}
+		parser.pattern, err = compileGrok(config.Pattern, config.GrokPatterns)
+		if err != nil {
+			err = fmt.Errorf("invalid grok pattern: %w", err)
+			return
+		}
+	case ParserJSON, ParserLogfmt, ParserCEF, ParserLEEF:
+		if config.Pattern != "" {
+			err = fmt.Errorf("%s format does not take a pattern", config.Format)
+			return
+		}
+	default:
+		err = fmt.Errorf("unknown format %q: must be one of %q, %q, %q, %q, %q, %q, %q, %q", config.Format,
+			ParserAuto, ParserRegex, ParserGrok, ParserJSON, ParserLogfmt, ParserCEF, ParserLEEF, ParserAudit)
+		return
+	}
+	if parser.pattern != nil && !slices.ContainsFunc(parser.pattern.SubexpNames(), func(name string) bool { return name != "" }) {
+		err = fmt.Errorf("pattern does not capture any named values")
+		return
+	}
+
+	if parser.timestampField != "" && len(parser.timestampLayouts) == 0 {
+		parser.timestampLayouts = defaultTimestampLayouts
+	}
+	if config.Timezone != "" {
+		parser.location, err = time.LoadLocation(config.Timezone)
+		if err != nil {
+			err = fmt.Errorf("invalid timezone: %w", err)
+			return
+		}
+	}
+
+	for source, target := range parser.fields {
+		if len(target) > protocol.MaxCtxKeyLen {
+			err = fmt.Errorf("field name %q for %q cannot be longer than %d bytes", target, source, protocol.MaxCtxKeyLen)
+			return
+		}
+	}
+	return
+}
+
+// Fills in field names not set by the profile
+func (parser *Parser) applyFormatDefaults(timestampField string, hostnameField string, messageField string, fields map[string]string) {
+	if parser.timestampField == "" {
+		parser.timestampField = timestampField
+	}
+	if parser.hostnameField == "" {
+		parser.hostnameField = hostnameField
+	}
+	if parser.messageField == "" {
+		parser.messageField = messageField
+	}
+	maps.Copy(parser.fields, fields)
+}
+
+// Creates a message from a line. Lines the profile cannot parse fall back to format auto-detection.
+func (parser *Parser) Parse(line string, localHostname string) (message *protocol.Message) {
+	if parser == nil || parser.format == ParserAuto {
+		message = parseLine(line, localHostname)
+		return
+	}
+	if parser.format == ParserAudit {
+		auditMessage, err := audit.ParseEvent(line, localHostname)
+		if err != nil {
+			message = parseLine(line, localHostname)
+			return
+		}
+		message = auditMessage
+		return
+	}
+
+	values, parsed := parser.extract(line)
+	if !parsed {
+		message = parseLine(line, localHostname)
+		return
+	}
+
+	message = &protocol.Message{}
+	message.Fields = make(map[string]any)
+
+	var overflow bool
+	for name, value := range values {
+		if name == "" || value == "" {
+			continue
+		}
+
+		switch name {
+		case parser.timestampField:
+			timestamp, valid := parser.parseTimestamp(protocol.FormatValue(value))
+			if valid {
+				message.Timestamp = timestamp
+			}
+			continue
+		case parser.hostnameField:
+			message.Hostname = protocol.FormatValue(value)
+			continue
+		case parser.messageField:
+			message.Data = []byte(protocol.FormatValue(value))
+			continue
+		}
+
+		fieldName := name
+		if mapped, exists := parser.fields[name]; exists {
+			fieldName = mapped
+		}
+		if fieldName == "" {
+			// Explicitly dropped
+			continue
+		}
+		if len(fieldName) > protocol.MaxCtxKeyLen {
+			overflow = true
+			continue
+		}
+
+		fieldValue, valid := fieldValue(fieldName, value)
+		if !valid {
+			if text, isText := value.(string); isText && len(text) > protocol.MaxCtxValLen {
+				overflow = true
+			}
+			continue
+		}
+		message.Fields[fieldName] = fieldValue
+	}
+	if overflow {
+		// Values that cannot be fields stay in the message text
+		message.Data = nil
+	}
+
+	message = setDefaults(message, strings.TrimSpace(line), localHostname)
+	return
+}
+
+// Name/value pairs of a line in the profile format
+func (parser *Parser) extract(line string) (values map[string]any, parsed bool) {
+	switch parser.format {
+	case ParserRegex, ParserGrok:
+		match := parser.pattern.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+		values = make(map[string]any)
+		for index, name := range parser.pattern.SubexpNames() {
+			if name != "" {
+				values[name] = match[index]
+			}
+		}
+		parsed = true
+	case ParserJSON:
+		values, parsed = extractJSON(line)
+	case ParserLogfmt:
+		values, parsed = extractLogfmt(line)
+	case ParserCEF:
+		values, parsed = extractCEF(line)
+	case ParserLEEF:
+		values, parsed = extractLEEF(line)
+	}
+	return
+}
+
+// Parses timestamp text with the first matching layout
+func (parser *Parser) parseTimestamp(text string) (timestamp time.Time, valid bool) {
+	for _, layout := range parser.timestampLayouts {
+		switch layout {
+		case LayoutUnix, LayoutUnixMilli:
+			seconds, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				continue
+			}
+			if layout == LayoutUnixMilli {
+				seconds /= 1000
+			}
+			timestamp = time.Unix(0, int64(seconds*float64(time.Second)))
+			valid = true
+			return
+		}
+
+		parsedTime, err := time.ParseInLocation(layout, text, parser.location)
+		if err != nil {
+			continue
+		}
+		if parsedTime.Year() == 0 {
+			// Layouts without a year (like BSD syslog) are this year
+			parsedTime = parsedTime.AddDate(time.Now().In(parser.location).Year(), 0, 0)
+		}
+		timestamp = parsedTime
+		valid = true
+		return
+	}
+	return
+}
+
+// Converts an extracted value to the value type expected for a message field.
+// Values the protocol cannot carry are left out.
+func fieldValue(fieldName string, extracted any) (value any, valid bool) {
+	text := protocol.FormatValue(extracted)
+
+	switch fieldName {
+	case iomodules.CFseverity:
+		value, valid = syslog.NormalizeSeverity(text)
+		return
+	case iomodules.CFfacility:
+		facility := strings.ToLower(text)
+		_, err := syslog.FacilityToCode(facility)
+		value, valid = facility, err == nil
+		return
+	case iomodules.CFprocessid:
+		pid, err := strconv.Atoi(text)
+		value, valid = pid, err == nil
+		return
+	}
+
+	switch extracted.(type) {
+	case int64, float64, bool:
+		value = extracted
+		valid = true
+		return
+	}
+	if len(text) > protocol.MaxCtxValLen {
+		return
+	}
+	value = text
+	valid = true
+	return
+}
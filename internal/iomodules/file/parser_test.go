@@ -0,0 +1,283 @@
+package file
+
+import (
+	"os"
+	"sdsyslog/internal/iomodules"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParserParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load test timezone: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		config            ParserConfig
+		input             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedData      string
+		expectedFields    map[string]any
+		absentFields      []string
+	}{
+		{
+			name: "regex named groups with timezone",
+			config: ParserConfig{
+				Name:             "app",
+				Format:           ParserRegex,
+				Pattern:          `^(?P<ts>\S+ \S+) (?P<level>\w+) (?P<app>\w+): (?P<msg>.*)$`,
+				TimestampField:   "ts",
+				TimestampLayouts: []string{"2006-01-02 15:04:05"},
+				Timezone:         "Europe/Berlin",
+				MessageField:     "msg",
+				Fields:           map[string]string{"level": iomodules.CFseverity, "app": iomodules.CFappname},
+			},
+			input:             "2026-03-04 10:11:12 ERROR billing: invoice failed",
+			expectedTimestamp: time.Date(2026, 3, 4, 10, 11, 12, 0, berlin),
+			expectedData:      "invoice failed",
+			expectedFields:    map[string]any{iomodules.CFseverity: "err", iomodules.CFappname: "billing"},
+		},
+		{
+			name: "unmapped values become fields and empty mapping drops",
+			config: ParserConfig{
+				Name:    "app",
+				Format:  ParserRegex,
+				Pattern: `user=(?P<user>\w+) session=(?P<session>\w+)`,
+				Fields:  map[string]string{"session": ""},
+			},
+			input:          "login user=alice session=abc123",
+			expectedData:   "login user=alice session=abc123",
+			expectedFields: map[string]any{"user": "alice"},
+			absentFields:   []string{"session"},
+		},
+		{
+			name: "grok",
+			config: ParserConfig{
+				Name:           "access",
+				Format:         ParserGrok,
+				Pattern:        `^%{IPORHOST:client} %{WORD:method} %{URIPATH:path} %{INT:status} %{HOSTNAME:host}$`,
+				HostnameField:  "host",
+				TimestampField: "missing",
+			},
+			input:            "10.1.2.3 GET /index.html 200 web01",
+			expectedHostname: "web01",
+			expectedFields:   map[string]any{"client": "10.1.2.3", "method": "GET", "path": "/index.html", "status": "200"},
+		},
+		{
+			name: "json",
+			config: ParserConfig{
+				Name:           "json",
+				Format:         ParserJSON,
+				TimestampField: "time",
+				MessageField:   "message",
+				Fields:         map[string]string{"level": iomodules.CFseverity, "pid": iomodules.CFprocessid},
+			},
+			input:             `{"time":"2026-03-04T10:11:12Z","level":"warn","message":"slow query","pid":812,"durationMs":1532,"tags":["db"],"user":null}`,
+			expectedTimestamp: time.Date(2026, 3, 4, 10, 11, 12, 0, time.UTC),
+			expectedData:      "slow query",
+			expectedFields:    map[string]any{iomodules.CFseverity: "warning", iomodules.CFprocessid: 812, "durationMs": int64(1532), "tags": `["db"]`},
+			absentFields:      []string{"user"},
+		},
+		{
+			name: "logfmt",
+			config: ParserConfig{
+				Name:             "logfmt",
+				Format:           ParserLogfmt,
+				TimestampField:   "ts",
+				TimestampLayouts: []string{LayoutUnix},
+				MessageField:     "msg",
+			},
+			input:             `ts=1772619072 msg="disk \"sda\" full" path=/var retry`,
+			expectedTimestamp: time.Unix(1772619072, 0),
+			expectedData:      `disk "sda" full`,
+			expectedFields:    map[string]any{"path": "/var", "retry": true},
+		},
+		{
+			name: "json nested objects are flattened with typed values",
+			config: ParserConfig{
+				Name:           "json",
+				Format:         ParserJSON,
+				TimestampField: "ts",
+				MessageField:   "event.message",
+				Fields:         map[string]string{"kubernetes.pod.labels.app.kubernetes.io/name": iomodules.CFappname},
+			},
+			input:             `{"ts":1772619072.5,"event":{"message":"done","ok":true,"ratio":0.25},"http":{"response":{"status":200}},"kubernetes":{"pod":{"labels":{"app.kubernetes.io/name":"api"}}}}`,
+			expectedTimestamp: time.Unix(1772619072, 500000000),
+			expectedData:      "done",
+			expectedFields:    map[string]any{"event.ok": true, "event.ratio": 0.25, "http.response.status": int64(200), iomodules.CFappname: "api"},
+		},
+		{
+			name: "logfmt typed values",
+			config: ParserConfig{
+				Name:   "logfmt",
+				Format: ParserLogfmt,
+			},
+			input:          `count=3 ratio=0.5 cached=false code="404" offset=-12 name=nan`,
+			expectedFields: map[string]any{"count": int64(3), "ratio": 0.5, "cached": false, "code": "404", "offset": int64(-12), "name": "nan"},
+		},
+		{
+			name: "json key over the key limit keeps the line in data",
+			config: ParserConfig{
+				Name:         "json",
+				Format:       ParserJSON,
+				MessageField: "msg",
+			},
+			input:          `{"msg":"request","request":{"headers":{"x-forwarded-for-original":"10.0.0.1"}},"status":500}`,
+			expectedData:   `{"msg":"request","request":{"headers":{"x-forwarded-for-original":"10.0.0.1"}},"status":500}`,
+			expectedFields: map[string]any{"status": int64(500)},
+			absentFields:   []string{"request.headers.x-forwarded-for-original", "request.headers.x-forwarded-for-"},
+		},
+		{
+			name: "logfmt value over the value limit keeps the line in data",
+			config: ParserConfig{
+				Name:         "logfmt",
+				Format:       ParserLogfmt,
+				MessageField: "msg",
+			},
+			input:          `msg=upload body="` + strings.Repeat("x", 300) + `" size=300`,
+			expectedData:   `msg=upload body="` + strings.Repeat("x", 300) + `" size=300`,
+			expectedFields: map[string]any{"size": int64(300)},
+			absentFields:   []string{"body"},
+		},
+		{
+			name:              "cef with syslog header",
+			config:            ParserConfig{Name: "cef", Format: ParserCEF},
+			input:             `Mar  4 10:11:12 fw1 CEF:0|Acme|Fire\|wall|1.2|100|Blocked connection|Medium|src=10.0.0.1 rt=1772619072000 msg=Denied a\=b by policy dvchost=fw1.example`,
+			expectedTimestamp: time.UnixMilli(1772619072000),
+			expectedHostname:  "fw1.example",
+			expectedData:      "Blocked connection",
+			expectedFields:    map[string]any{iomodules.CFappname: "Fire|wall", iomodules.CFseverity: "warning", "src": "10.0.0.1", "msg": "Denied a=b by policy", "deviceVendor": "Acme"},
+		},
+		{
+			name:           "leef 1.0",
+			config:         ParserConfig{Name: "leef", Format: ParserLEEF},
+			input:          "LEEF:1.0|Acme|IDS|2.1|attack|src=10.0.0.1\tsev=9\tusrName=bob",
+			expectedFields: map[string]any{iomodules.CFappname: "IDS", iomodules.CFseverity: "crit", "src": "10.0.0.1", "usrName": "bob", "eventId": "attack"},
+		},
+		{
+			name:           "leef 2.0 custom delimiter",
+			config:         ParserConfig{Name: "leef", Format: ParserLEEF},
+			input:          "LEEF:2.0|Acme|IDS|2.1|attack|^|src=10.0.0.1^sev=2^usrName=bob",
+			expectedFields: map[string]any{iomodules.CFappname: "IDS", iomodules.CFseverity: "info", "src": "10.0.0.1", "usrName": "bob"},
+		},
+		{
+			name:              "audit event",
+			config:            ParserConfig{Name: "audit", Format: ParserAudit},
+			input:             "type=SYSCALL msg=audit(1700000000.123:42): syscall=59 success=yes uid=0 exe=\"/usr/bin/id\" key=\"exec\"\ntype=PATH msg=audit(1700000000.123:42): item=0 name=\"/usr/bin/id\"",
+			expectedTimestamp: time.Unix(1700000000, 123000000),
+			expectedFields:    map[string]any{iomodules.CFappname: "audit", "syscall": int64(59), "success": true, "exe": "/usr/bin/id", "key": "exec", "path.name": "/usr/bin/id", "AuditSerial": int64(42)},
+		},
+		{
+			name: "unparsable line falls back to auto detection",
+			config: ParserConfig{
+				Name:   "json",
+				Format: ParserJSON,
+			},
+			input:            "Jul  9 18:05:33 Host1 sshd[22]: Accepted",
+			expectedHostname: "Host1",
+			expectedData:     "Accepted",
+			expectedFields:   map[string]any{iomodules.CFappname: "sshd", iomodules.CFprocessid: 22},
+		},
+		{
+			name: "invalid severity and oversized values are left out",
+			config: ParserConfig{
+				Name:   "json",
+				Format: ParserJSON,
+				Fields: map[string]string{"level": iomodules.CFseverity},
+			},
+			input:          `{"level":"verbose","blob":"` + strings.Repeat("x", 300) + `"}`,
+			expectedFields: map[string]any{iomodules.CFseverity: iomodules.DefaultSeverity},
+			absentFields:   []string{"blob"},
+		},
+	}
+
+	localHostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("failed to determine local hostname: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewParser(tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error creating parser: %v", err)
+			}
+
+			msg := parser.Parse(tt.input, localHostname)
+
+			if !tt.expectedTimestamp.IsZero() && !msg.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %v, got %v", tt.expectedTimestamp, msg.Timestamp)
+			}
+			expectedHostname := tt.expectedHostname
+			if expectedHostname == "" {
+				expectedHostname = localHostname
+			}
+			if msg.Hostname != expectedHostname {
+				t.Errorf("expected hostname %q, got %q", expectedHostname, msg.Hostname)
+			}
+			if tt.expectedData != "" && string(msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, msg.Data)
+			}
+			for key, expected := range tt.expectedFields {
+				if msg.Fields[key] != expected {
+					t.Errorf("expected field %q to be %v (%T), got %v (%T)", key, expected, expected, msg.Fields[key], msg.Fields[key])
+				}
+			}
+			for _, key := range tt.absentFields {
+				if _, exists := msg.Fields[key]; exists {
+					t.Errorf("expected field %q to be left out, got %v", key, msg.Fields[key])
+				}
+			}
+		})
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ParserConfig
+	}{
+		{name: "missing name", config: ParserConfig{Format: ParserJSON}},
+		{name: "unknown format", config: ParserConfig{Name: "p", Format: "xml"}},
+		{name: "regex without pattern", config: ParserConfig{Name: "p", Format: ParserRegex}},
+		{name: "regex without named groups", config: ParserConfig{Name: "p", Format: ParserRegex, Pattern: `^(\w+)$`}},
+		{name: "invalid regex", config: ParserConfig{Name: "p", Format: ParserRegex, Pattern: `(?P<a>`}},
+		{name: "unknown grok pattern", config: ParserConfig{Name: "p", Format: ParserGrok, Pattern: `%{NOPE:a}`}},
+		{name: "recursive grok pattern", config: ParserConfig{Name: "p", Format: ParserGrok, Pattern: `%{LOOP:a}`, GrokPatterns: map[string]string{"LOOP": `x%{LOOP}`}}},
+		{name: "pattern for json", config: ParserConfig{Name: "p", Format: ParserJSON, Pattern: `.*`}},
+		{name: "field settings for auto", config: ParserConfig{Name: "p", Format: ParserAuto, MessageField: "msg"}},
+		{name: "pattern for audit", config: ParserConfig{Name: "p", Format: ParserAudit, Pattern: `.*`}},
+		{name: "invalid timezone", config: ParserConfig{Name: "p", Format: ParserJSON, Timezone: "Mars/Olympus"}},
+		{name: "long field name", config: ParserConfig{Name: "p", Format: ParserJSON, Fields: map[string]string{"a": strings.Repeat("k", 33)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(tt.config)
+			if err == nil {
+				t.Errorf("expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestCompileGrokCustomPatterns(t *testing.T) {
+	regex, err := compileGrok(`^%{ORDER:order} by %{USERNAME:user}$`, map[string]string{"ORDER": `ORD-%{INT}`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	match := regex.FindStringSubmatch("ORD-1234 by alice")
+	if match == nil {
+		t.Fatalf("expected pattern to match")
+	}
+	if order := match[regex.SubexpIndex("order")]; order != "ORD-1234" {
+		t.Errorf("expected order %q, got %q", "ORD-1234", order)
+	}
+	if user := match[regex.SubexpIndex("user")]; user != "alice" {
+		t.Errorf("expected user %q, got %q", "alice", user)
+	}
+}
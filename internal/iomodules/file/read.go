@@ -9,6 +9,7 @@ import (
 	"sdsyslog/internal/iomodules"
 	"sdsyslog/internal/logctx"
 	"strings"
+	"time"
 )
 
 // Watches file path for changes and rotations (truncation or move/create).
@@ -49,11 +50,31 @@ func (mod *InModule) reader() {
 				logctx.LogStdErr(ctx, "%w\n", err)
 			}
 
-			// Block until file change, file rotation, or cancellation
+			// Pending multi-line events are sent once no continuation arrives in time
+			var flushTimeout <-chan time.Time
+			if mod.multiline.pending() {
+				flushTimeout = time.After(mod.multiline.flushTimeout)
+			}
+
+			// Block until file change, file rotation, flush timeout, retirement, or cancellation
 			select {
+			case <-mod.retire:
+				mod.drain(ctx, &lineBuf, buf)
+				return
 			case <-ctx.Done():
 				mod.watcher.Stop()
-				err = savePosition(mod.stateFile, mod.currentReadID.ino, mod.currentReadOffset)
+
+				// State file of a retired file is not ours anymore
+				if mod.retired() {
+					return
+				}
+
+				// Unsent event lines are read again on restart
+				resumeOffset := mod.currentReadOffset
+				if mod.multiline.pending() {
+					resumeOffset = mod.multiline.startOffset
+				}
+				err = savePosition(mod.stateFile, mod.currentReadID.ino, resumeOffset)
 				if err != nil {
 					logctx.LogStdErr(ctx,
 						"failed to save position in file source '%s': %w\n", mod.filePath, err)
@@ -61,12 +82,17 @@ func (mod *InModule) reader() {
 				return
 			case <-mod.watcher.FileChanged():
 				// file changed, continue scanning
+			case <-flushTimeout:
+				mod.flushMultiline(ctx)
 			case <-mod.watcher.FileRotated():
 				err = mod.fileReadAll(ctx, &lineBuf, buf)
 				if err != nil {
 					logctx.LogStdErr(ctx,
 						"error reading pre-rotation file: %w\n", err)
 				} else {
+					// Events do not span files
+					mod.flushMultiline(ctx)
+
 					err = mod.reopenLogfile(ctx)
 					if err != nil {
 						logctx.LogStdErr(ctx, "%w\n", err)
@@ -79,6 +105,7 @@ func (mod *InModule) reader() {
 			if err != nil {
 				logctx.LogStdWarn(ctx, "failed to stat current tracked file: %w\n", err)
 				// Can't do anything about the error here, just read from beginning
+				mod.flushMultiline(ctx)
 				mod.currentReadOffset = 0
 				lineBuf = lineBuf[:0]
 				return
@@ -87,6 +114,7 @@ func (mod *InModule) reader() {
 				// Truncation detected - reset state and seek to beginning
 				logctx.LogStdWarn(ctx, "file '%s' has been truncated, seeking to start of file (warning: late writes to file might be missed)\n",
 					mod.filePath)
+				mod.flushMultiline(ctx)
 				mod.currentReadOffset = 0
 				lineBuf = lineBuf[:0]
 				return
@@ -114,6 +142,51 @@ func (mod *InModule) reader() {
 	}
 }
 
+// Reports whether the file was retired
+func (mod *InModule) retired() (retired bool) {
+	select {
+	case <-mod.retire:
+		retired = true
+	default:
+	}
+	return
+}
+
+// Reads the open file until no new data arrived for the retirement idle time, then stops the reader.
+// Read position is not saved, the file is not read again.
+func (mod *InModule) drain(ctx context.Context, lineBuf *[]byte, buf []byte) {
+	mod.watcher.Stop()
+
+	lastData := time.Now()
+	for {
+		offset := mod.currentReadOffset
+		err := mod.fileReadAll(ctx, lineBuf, buf)
+		if err != nil {
+			logctx.LogStdErr(ctx, "failed to read retired file '%s': %w\n", mod.filePath, err)
+			break
+		}
+		if mod.currentReadOffset != offset {
+			lastData = time.Now()
+		}
+		if time.Since(lastData) >= mod.retireIdle {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(retirePollInterval, mod.retireIdle)):
+		}
+	}
+
+	// Nothing more is written, last line is complete even without newline
+	if len(*lineBuf) > 0 {
+		mod.processFileChunk(ctx, lineBuf, []byte{'\n'})
+	}
+	mod.flushMultiline(ctx)
+	mod.cancel()
+}
+
 // Reads file lines continuously until 0 bytes left or EOF in file
 func (mod *InModule) fileReadAll(ctx context.Context, lineBuf *[]byte, buf []byte) (err error) {
 	for {
@@ -156,26 +229,49 @@ func (mod *InModule) processFileChunk(ctx context.Context, lineBuf *[]byte, buf
 		// line complete, process it
 		mod.metrics.LinesRead.Add(1)
 
-		msg := parseLine(string(*lineBuf), mod.localHostname)
-
-		msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(ctx), "/")
-
-		var dropMsg bool
-		for _, filter := range mod.filters {
-			dropMsg = filter.Match(msg)
-			if dropMsg {
-				// First filter match wins
-				break
+		line := string(*lineBuf)
+		if mod.multiline == nil {
+			mod.emit(ctx, line)
+		} else {
+			lineStart := mod.currentReadOffset - int64(len(*lineBuf))
+			event, complete := mod.multiline.add(line, lineStart)
+			if complete {
+				mod.emit(ctx, event)
 			}
 		}
 
-		if !dropMsg {
-			mod.outbox.PushBlocking(ctx, msg, msg.Size())
-			mod.metrics.Success.Add(1)
-		}
-
 		// reset line buffer
 		*lineBuf = (*lineBuf)[:0] // alter original to remove contents
 		mod.currentReadOffset++   // move past newline
 	}
 }
+
+// Sends any pending multi-line event
+func (mod *InModule) flushMultiline(ctx context.Context) {
+	if !mod.multiline.pending() {
+		return
+	}
+	event, _ := mod.multiline.flush()
+	mod.emit(ctx, event)
+}
+
+// Parses a line (or multi-line event) and sends it unless filtered
+func (mod *InModule) emit(ctx context.Context, text string) {
+	msg := mod.parser.Parse(text, mod.localHostname)
+
+	msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(ctx), "/")
+
+	var dropMsg bool
+	for _, filter := range mod.filters {
+		dropMsg = filter.Match(msg)
+		if dropMsg {
+			// First filter match wins
+			break
+		}
+	}
+
+	if !dropMsg {
+		mod.outbox.PushBlocking(ctx, msg, msg.Size())
+		mod.metrics.Success.Add(1)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sdsyslog/internal/filtering"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/parsing"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/internal/tests/utils"
 	"sdsyslog/pkg/protocol"
@@ -27,6 +28,7 @@ func TestReader(t *testing.T) {
 		name                 string
 		inputLines           []string
 		filters              []protocol.MessageFilter
+		multiline            *MultilineConfig
 		rotationIndex        int // Rotate at this input line slice index
 		rotationFunc         func(filePath string, file *os.File) (newFile *os.File, err error)
 		expectedMsgs         []protocol.Message
@@ -158,6 +160,54 @@ func TestReader(t *testing.T) {
 			expectedLinesRead: 3,
 			expectedProcCount: 3,
 		},
+		{
+			name: "multiline continuation groups stack trace",
+			inputLines: []string{
+				"Exception in thread \"main\" java.lang.NullPointerException",
+				"\tat com.example.App.run(App.java:10)",
+				"\tat com.example.App.main(App.java:5)",
+				"next message",
+			},
+			multiline: &MultilineConfig{Mode: MultilineContinuation, FlushTimeout: parsing.Duration(50 * time.Millisecond)},
+			expectedMsgs: []protocol.Message{
+				{
+					Timestamp: time.Now(),
+					Hostname:  localHostname,
+					Data:      []byte("Exception in thread \"main\" java.lang.NullPointerException\n\tat com.example.App.run(App.java:10)\n\tat com.example.App.main(App.java:5)"),
+				},
+				{
+					Timestamp: time.Now(),
+					Hostname:  localHostname,
+					Data:      []byte("next message"),
+				},
+			},
+			expectedLinesRead: 4,
+			expectedProcCount: 2,
+		},
+		{
+			name: "multiline start pattern groups traceback",
+			inputLines: []string{
+				"2026-01-01 ERROR request failed",
+				"Traceback (most recent call last):",
+				"ValueError: bad input",
+				"2026-01-01 INFO recovered",
+			},
+			multiline: &MultilineConfig{Mode: MultilinePattern, StartPattern: `^\d{4}-\d{2}-\d{2} `, FlushTimeout: parsing.Duration(50 * time.Millisecond)},
+			expectedMsgs: []protocol.Message{
+				{
+					Timestamp: time.Now(),
+					Hostname:  localHostname,
+					Data:      []byte("2026-01-01 ERROR request failed\nTraceback (most recent call last):\nValueError: bad input"),
+				},
+				{
+					Timestamp: time.Now(),
+					Hostname:  localHostname,
+					Data:      []byte("2026-01-01 INFO recovered"),
+				},
+			},
+			expectedLinesRead: 4,
+			expectedProcCount: 2,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -187,7 +237,7 @@ func TestReader(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error creating queue: %v", err)
 			}
-			inMod, err := NewInput(ctx, logFilePath, stateFile, tt.filters, queue)
+			inMod, err := NewInput(ctx, InputConfig{Path: logFilePath, Multiline: tt.multiline}, nil, stateFile, tt.filters, queue)
 			if err != nil {
 				t.Fatalf("unexpected error creating input module: %v", err)
 			}
@@ -314,3 +364,131 @@ func TestReader(t *testing.T) {
 		})
 	}
 }
+
+func TestReaderRetire(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	logFilePath := filepath.Join(tempDir, "log")
+	stateFile := filepath.Join(tempDir, "state")
+
+	logFile, err := os.Create(logFilePath)
+	if err != nil {
+		t.Fatalf("failed to create log file: %v", err)
+	}
+	defer logFile.Close()
+
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 1024, global.MinValue(1024), global.MaxValue(1024))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+	inMod, err := NewInput(ctx, InputConfig{Path: logFilePath}, nil, stateFile, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error creating input module: %v", err)
+	}
+	err = inMod.Start()
+	if err != nil {
+		t.Fatalf("failed to start reader: %v", err)
+	}
+
+	// Writer keeps appending to the renamed file, a new file appears at the path
+	_, err = fmt.Fprintf(logFile, "before rename\n")
+	if err != nil {
+		t.Fatalf("failed to write to log file: %v", err)
+	}
+	err = os.Rename(logFilePath, logFilePath+".1")
+	if err != nil {
+		t.Fatalf("failed to rename log file: %v", err)
+	}
+	_, err = fmt.Fprintf(logFile, "after rename\nwithout newline")
+	if err != nil {
+		t.Fatalf("failed to write to renamed log file: %v", err)
+	}
+	err = os.WriteFile(logFilePath, []byte("new file\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to create new log file: %v", err)
+	}
+
+	// Directory input removes the state file when retiring, a new file at the path may use it
+	err = os.Remove(StateFilePath(logFilePath, stateFile))
+	if err != nil {
+		t.Fatalf("failed to remove state file: %v", err)
+	}
+
+	retired := make(chan error, 1)
+	go func() {
+		retired <- inMod.Retire(200 * time.Millisecond)
+	}()
+	select {
+	case err = <-retired:
+		if err != nil {
+			t.Fatalf("unexpected error retiring input module: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("retired reader did not stop")
+	}
+
+	var received []string
+	for {
+		popCtx, popCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		msg, ok := queue.Pop(popCtx)
+		popCancel()
+		if !ok {
+			break
+		}
+		received = append(received, string(msg.Data))
+	}
+	expected := []string{"before rename", "after rename", "without newline"}
+	if strings.Join(received, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected messages %q, got %q", expected, received)
+	}
+
+	_, err = os.Stat(StateFilePath(logFilePath, stateFile))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected no saved position for retired file, got %v", err)
+	}
+}
+
+func TestInputNamespace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tempDir := t.TempDir()
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 1024, global.MinValue(1024), global.MaxValue(1024))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	// Files of a glob input like <dir>/*/access.log share their name
+	var sources []string
+	for _, site := range []string{"site1", "site2"} {
+		logFilePath := filepath.Join(tempDir, site, "access.log")
+		err = os.MkdirAll(filepath.Dir(logFilePath), 0755)
+		if err != nil {
+			t.Fatalf("failed to create log directory: %v", err)
+		}
+		err = os.WriteFile(logFilePath, nil, 0644)
+		if err != nil {
+			t.Fatalf("failed to create log file: %v", err)
+		}
+
+		inMod, err := NewInput(ctx, InputConfig{Path: logFilePath}, nil, filepath.Join(tempDir, "state"), nil, queue)
+		if err != nil {
+			t.Fatalf("unexpected error creating input module: %v", err)
+		}
+		_ = inMod.sink.Close()
+
+		tags := logctx.GetTagList(inMod.ctx)
+		source := tags[len(tags)-1]
+		if !strings.HasSuffix(source, filepath.Join(site, "access.log")) {
+			t.Errorf("expected source tag to contain the path of %q, got %q", logFilePath, source)
+		}
+		sources = append(sources, source)
+	}
+	if sources[0] == sources[1] {
+		t.Errorf("expected distinct source tags for files with the same name, got %q twice", sources[0])
+	}
+}
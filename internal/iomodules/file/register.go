@@ -0,0 +1,29 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/iomodules"
+)
+
+const OutputType string = "file"
+
+func init() {
+	iomodules.RegisterOutput(OutputType, newOutputFromConfig)
+}
+
+// Creates file output from its JSON configuration block
+func newOutputFromConfig(config json.RawMessage) (module iomodules.Output, err error) {
+	var options OutputConfig
+	err = iomodules.DecodeOutputConfig(config, &options)
+	if err != nil {
+		return
+	}
+	if options.Path == "" {
+		err = fmt.Errorf("file output requires a path")
+		return
+	}
+
+	module, err = NewOutput(options)
+	return
+}
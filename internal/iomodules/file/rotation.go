@@ -18,6 +18,11 @@ func (mod *InModule) reopenLogfile(ctx context.Context) (err error) {
 
 	var fileInfo os.FileInfo
 	for range maxRetries {
+		// File at path belongs to another reader once this one is retired
+		if mod.retired() {
+			return
+		}
+
 		fileInfo, err = os.Stat(mod.filePath)
 		if err == nil {
 			break
@@ -1,5 +1,7 @@
 package file
 
+import "time"
+
 // Starts reader for file
 func (mod *InModule) Start() (err error) {
 	// Start reader in go routine
@@ -26,13 +28,30 @@ func (mod *InModule) Shutdown() (err error) {
 	return
 }
 
-// Gracefully stops module
+// Stops module once the open file is read to its end and no new data arrived for idleTimeout (or on Shutdown).
+// Used when the path no longer matches, the file is followed past renames and the read position is not saved.
+func (mod *InModule) Retire(idleTimeout time.Duration) (err error) {
+	if mod == nil {
+		return
+	}
+
+	mod.retireIdle = idleTimeout
+	close(mod.retire)
+	mod.wg.Wait()
+
+	err = mod.Shutdown()
+	return
+}
+
+// Gracefully stops module, waiting for rotated files to finish compressing
 func (mod *OutModule) Shutdown() (err error) {
 	if mod == nil {
 		return
 	}
-	if mod.sink != nil {
-		err = mod.sink.Close()
+	err = mod.closeFiles()
+	mod.archivers.Wait()
+	if err == nil {
+		err = mod.takeArchiveError()
 	}
 	return
 }
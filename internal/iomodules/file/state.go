@@ -1,6 +1,7 @@
 package file
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,16 @@ import (
 	"strings"
 )
 
+// Creates the unique state file of a source from the base state file
+func StateFilePath(filePath string, baseStateFile string) (stateFile string) {
+	stateFileDir := filepath.Dir(baseStateFile)
+	stateFileName := filepath.Base(baseStateFile)
+
+	newStateFileName := base64.RawURLEncoding.EncodeToString([]byte(filePath)) + "_" + stateFileName // Using full file path as prefix to state file
+	stateFile = filepath.Join(stateFileDir, newStateFileName)
+	return
+}
+
 // Retrieve last read position for the log file from the state file
 func getLastPosition(logFilePath string, stateFilePath string) (inode uint64, position int64, err error) {
 	stateDirectory := filepath.Dir(stateFilePath)
@@ -0,0 +1,118 @@
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Placeholders available in output path templates
+var templateFields = []string{
+	"hostname", "appname", "facility", "severity", "remoteip", "hostid",
+	"date", "year", "month", "day", "hour",
+}
+
+// Parses output path containing {placeholder} fields
+func parsePathTemplate(path string) (template pathTemplate, err error) {
+	template.static = true
+
+	rest := path
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			template.parts = append(template.parts, templatePart{literal: rest})
+			break
+		}
+		if start > 0 {
+			template.parts = append(template.parts, templatePart{literal: rest[:start]})
+		}
+
+		length := strings.IndexByte(rest[start:], '}')
+		if length == -1 {
+			err = fmt.Errorf("unterminated placeholder in output path %q", path)
+			return
+		}
+		name := rest[start+1 : start+length]
+		if !slices.Contains(templateFields, name) {
+			err = fmt.Errorf("unknown placeholder {%s} in output path (must be one of %q)", name, templateFields)
+			return
+		}
+		template.parts = append(template.parts, templatePart{placeholder: name})
+		template.static = false
+
+		rest = rest[start+length+1:]
+	}
+	return
+}
+
+// Builds the file path for a message
+func (template pathTemplate) render(msg *protocol.Payload) (path string) {
+	var builder strings.Builder
+	for _, part := range template.parts {
+		if part.placeholder == "" {
+			builder.WriteString(part.literal)
+			continue
+		}
+		builder.WriteString(sanitizePathValue(templateValue(msg, part.placeholder)))
+	}
+	path = filepath.Clean(builder.String())
+	return
+}
+
+// Retrieves message value for placeholder (dates use local time of the message timestamp)
+func templateValue(msg *protocol.Payload, placeholder string) (value string) {
+	timestamp := msg.Timestamp.Local()
+
+	switch placeholder {
+	case "hostname":
+		value = msg.Hostname
+	case "appname":
+		value = protocol.FormatValue(msg.CustomFields[iomodules.CFappname])
+	case "facility":
+		value = protocol.FormatValue(msg.CustomFields[iomodules.CFfacility])
+	case "severity":
+		value = protocol.FormatValue(msg.CustomFields[iomodules.CFseverity])
+	case "remoteip":
+		if msg.RemoteIP.IsValid() {
+			value = msg.RemoteIP.Unmap().String()
+		}
+	case "hostid":
+		value = strconv.Itoa(msg.HostID)
+	case "date":
+		value = timestamp.Format("2006-01-02")
+	case "year":
+		value = timestamp.Format("2006")
+	case "month":
+		value = timestamp.Format("01")
+	case "day":
+		value = timestamp.Format("02")
+	case "hour":
+		value = timestamp.Format("15")
+	}
+	return
+}
+
+// Makes (sender controlled) message value safe to use as part of a single path element
+func sanitizePathValue(value string) (safe string) {
+	safe = strings.Map(func(char rune) rune {
+		if char == '/' || char == '\\' || unicode.IsControl(char) {
+			return '_'
+		}
+		return char
+	}, value)
+	if len(safe) > maxPathValueLen {
+		safe = strings.ToValidUTF8(safe[:maxPathValueLen], "")
+	}
+	if safe == "" {
+		safe = protocol.EmptyFieldChar
+	} else if strings.Trim(safe, ".") == "" {
+		// No directory traversal
+		safe = strings.Repeat("_", len(safe))
+	}
+	return
+}
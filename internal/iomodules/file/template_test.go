@@ -0,0 +1,119 @@
+package file
+
+import (
+	"net/netip"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPathTemplate(t *testing.T) {
+	timestamp := time.Date(2026, 3, 7, 9, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name           string
+		template       string
+		msg            protocol.Payload
+		expectedPath   string
+		expectedStatic bool
+		expectedErr    bool
+	}{
+		{
+			name:           "static path",
+			template:       "/var/log/remote.log",
+			expectedPath:   "/var/log/remote.log",
+			expectedStatic: true,
+		},
+		{
+			name:     "host and app per day",
+			template: "/var/log/remote/{hostname}/{appname}-{date}.log",
+			msg: protocol.Payload{
+				Hostname:     "web01",
+				Timestamp:    timestamp,
+				CustomFields: map[string]any{iomodules.CFappname: "nginx"},
+			},
+			expectedPath: "/var/log/remote/web01/nginx-2026-03-07.log",
+		},
+		{
+			name:     "date parts and ids",
+			template: "/logs/{year}/{month}/{day}/{hour}/{hostid}-{remoteip}-{severity}.log",
+			msg: protocol.Payload{
+				HostID:       42,
+				RemoteIP:     netip.MustParseAddr("::ffff:192.0.2.10"),
+				Timestamp:    timestamp,
+				CustomFields: map[string]any{iomodules.CFseverity: "err"},
+			},
+			expectedPath: "/logs/2026/03/07/09/42-192.0.2.10-err.log",
+		},
+		{
+			name:     "missing values",
+			template: "/logs/{hostname}/{appname}.log",
+			msg: protocol.Payload{
+				Timestamp: timestamp,
+			},
+			expectedPath: "/logs/-/-.log",
+		},
+		{
+			name:     "traversal in hostname",
+			template: "/logs/{hostname}/{appname}.log",
+			msg: protocol.Payload{
+				Hostname:     "../../etc",
+				Timestamp:    timestamp,
+				CustomFields: map[string]any{iomodules.CFappname: ".."},
+			},
+			expectedPath: "/logs/.._.._etc/__.log",
+		},
+		{
+			name:     "control characters and separators",
+			template: "/logs/{hostname}.log",
+			msg: protocol.Payload{
+				Hostname:  "a\\b\nc",
+				Timestamp: timestamp,
+			},
+			expectedPath: "/logs/a_b_c.log",
+		},
+		{
+			name:     "long value truncated",
+			template: "/logs/{hostname}",
+			msg: protocol.Payload{
+				Hostname:  strings.Repeat("h", 300),
+				Timestamp: timestamp,
+			},
+			expectedPath: "/logs/" + strings.Repeat("h", maxPathValueLen),
+		},
+		{
+			name:        "unknown placeholder",
+			template:    "/logs/{nope}.log",
+			expectedErr: true,
+		},
+		{
+			name:        "unterminated placeholder",
+			template:    "/logs/{hostname.log",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := parsePathTemplate(tt.template)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error for template %q, but got none", tt.template)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if template.static != tt.expectedStatic {
+				t.Errorf("expected static to be %t, but got %t", tt.expectedStatic, template.static)
+			}
+
+			path := template.render(&tt.msg)
+			if path != tt.expectedPath {
+				t.Errorf("expected path %q, but got %q", tt.expectedPath, path)
+			}
+		})
+	}
+}
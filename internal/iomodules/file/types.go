@@ -3,26 +3,138 @@ package file
 
 import (
 	"context"
-	"io"
+	"crypto/sha256"
 	"os"
+	"regexp"
+	"sdsyslog/internal/parsing"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/pkg/protocol"
 	"sync"
+	"time"
 )
 
 type OutModule struct {
-	sink        io.WriteCloser
-	batchSize   int
-	batchBuffer *[]string
+	template    pathTemplate
+	files       map[string]*outputFile // Open handles keyed by rendered path
+	useClock    uint64                 // Incremented on every handle use (for least recently used eviction)
+	batchBuffer []bufferedLine
+
+	// Background compression/pruning of rotated files
+	archivers    sync.WaitGroup
+	archiveMu    sync.Mutex // One archive run at a time (pruning must not race compression)
+	archiveErrMu sync.Mutex
+	archiveErr   error
+
+	warnings []string // Recovered problems to log on the next write
+
+	// Config
+	format         string
+	batchSize      int
+	maxOpenFiles   int
+	maxSize        uint64
+	rotateInterval time.Duration
+	compression    string
+	retention      int
+
+	// Audit chain (enabled with signing key)
+	auditKey               []byte
+	auditCheckpointRecords int
+}
+
+// JSON Lines record
+type jsonRecord struct {
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Hostname      string         `json:"hostname"`
+	Signature     string         `json:"signature"`
+	RemoteIP      string         `json:"remoteIP,omitempty"`
+	HostID        int            `json:"hostID"`
+	MsgID         int            `json:"msgID"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	MessageBase64 []byte         `json:"messageBase64,omitempty"` // Message that is not valid UTF-8
+}
+
+// Result of walking the hash chain of audit files
+type ArchiveReport struct {
+	Records     int           // Log records in the chain
+	Checkpoints int           // Checkpoints with valid signatures
+	FirstSeq    uint64        // Sequence the chain starts at (above 1 when older files were removed)
+	Unsigned    int           // Trailing records not covered by a signed checkpoint
+	Broken      *ArchiveBreak // First broken link (nil if intact)
+}
+
+// Location of the first broken link in an audit chain
+type ArchiveBreak struct {
+	Path   string
+	Line   int
+	Seq    uint64 // Expected sequence number
+	Reason string
+}
+
+// Audit chain walker
+type archiveVerifier struct {
+	publicKey []byte
+	started   bool
+	seq       uint64
+	hash      [sha256.Size]byte
+	report    *ArchiveReport
+}
+
+// Formatted line waiting for batch flush
+type bufferedLine struct {
+	path      string
+	text      string
+	timestamp time.Time
+}
+
+// Open output file with rotation state
+type outputFile struct {
+	file          *os.File
+	size          uint64
+	rotationStart time.Time
+	lastUsed      uint64
+	chain         *auditChain // Only in audit mode
+}
+
+// Hash chain state of an audit file
+type auditChain struct {
+	seq             uint64
+	hash            [sha256.Size]byte
+	sinceCheckpoint int // Records not yet covered by a signed checkpoint
+}
+
+// Single line of an audit file
+type auditRecord struct {
+	Seq        uint64 `json:"seq"`
+	Prev       string `json:"prev"`  // Hash of previous record (hex)
+	Entry      string `json:"entry"` // Formatted line or checkpoint description
+	Checkpoint bool   `json:"checkpoint,omitempty"`
+	Suite      uint8  `json:"suite,omitempty"`     // Signature suite ID (checkpoints only)
+	Signature  []byte `json:"signature,omitempty"` // Signature over hash (checkpoints only)
+	Hash       string `json:"hash"`                // SHA-256 of prev, seq, kind, and entry (hex)
+}
+
+// Parsed output path template
+type pathTemplate struct {
+	parts  []templatePart
+	static bool // No placeholders
+}
+
+// Literal path text or a placeholder replaced with a message value
+type templatePart struct {
+	literal     string
+	placeholder string
 }
 
 type InModule struct {
 	localHostname string
 
 	// Read Source
-	sink     *os.File
-	filePath string
-	filters  []protocol.MessageFilter
+	sink      *os.File
+	filePath  string
+	filters   []protocol.MessageFilter
+	multiline *multilineGrouper // Groups lines into events (nil is one message per line)
+	parser    *Parser           // Line parser profile (nil is auto-detect)
 
 	watcher xWatcher
 
@@ -31,6 +143,10 @@ type InModule struct {
 	currentReadOffset int64
 	currentReadID     fileID
 
+	// Retirement (file no longer belongs to the input)
+	retire     chan struct{} // Closed to read the open file to its end and stop
+	retireIdle time.Duration // Time without new data before a retired file is done
+
 	outbox  *mpmc.Queue[*protocol.Message]
 	metrics MetricStorage
 
@@ -39,6 +155,64 @@ type InModule struct {
 	ctx    context.Context
 }
 
+// Input configuration of a single file
+type InputConfig struct {
+	Path      string           `json:"path"`                // File, directory, or glob pattern
+	Recursive bool             `json:"recursive,omitempty"` // Match the file name pattern in all subdirectories
+	Exclude   []string         `json:"exclude,omitempty"`   // File name patterns to skip, in addition to rotated and compressed copies
+	Parser    string           `json:"parser,omitempty"`    // Name of the parser profile for lines (auto-detect if empty)
+	Multiline *MultilineConfig `json:"multiline,omitempty"` // Group related lines into a single message
+}
+
+// Named description of how to turn lines of a file into messages
+type ParserConfig struct {
+	Name             string            `json:"name"`
+	Format           string            `json:"format"`                     // auto, regex, grok, json, logfmt, cef, or leef
+	Pattern          string            `json:"pattern,omitempty"`          // Regex with named groups or grok pattern
+	GrokPatterns     map[string]string `json:"grokPatterns,omitempty"`     // Additional grok pattern definitions
+	TimestampField   string            `json:"timestampField,omitempty"`   // Extracted value holding the message time
+	TimestampLayouts []string          `json:"timestampLayouts,omitempty"` // Go time layouts (or unix, unixMilli) tried in order
+	Timezone         string            `json:"timezone,omitempty"`         // Location of timestamps without an offset (default local)
+	HostnameField    string            `json:"hostnameField,omitempty"`    // Extracted value holding the hostname
+	MessageField     string            `json:"messageField,omitempty"`     // Extracted value holding the message text (default whole line)
+	Fields           map[string]string `json:"fields,omitempty"`           // Extracted value name to message field name (empty name drops it)
+}
+
+// Compiled parser profile (safe for concurrent use)
+type Parser struct {
+	name             string
+	format           string
+	pattern          *regexp.Regexp
+	timestampField   string
+	timestampLayouts []string
+	location         *time.Location
+	hostnameField    string
+	messageField     string
+	fields           map[string]string
+}
+
+// Rules for grouping consecutive lines into one event
+type MultilineConfig struct {
+	Mode         string           `json:"mode"`                   // continuation (indented lines continue an event) or pattern
+	StartPattern string           `json:"startPattern,omitempty"` // Pattern mode: lines matching start a new event
+	MaxLines     int              `json:"maxLines,omitempty"`     // Lines per event before it is sent
+	MaxBytes     int              `json:"maxBytes,omitempty"`     // Event size before it is sent
+	FlushTimeout parsing.Duration `json:"flushTimeout,omitempty"` // Send a pending event after no new lines for this long
+}
+
+// Pending multi-line event of a file input
+type multilineGrouper struct {
+	mode         string
+	startPattern *regexp.Regexp
+	maxLines     int
+	maxBytes     int
+	flushTimeout time.Duration
+
+	lines       []string
+	size        int   // Bytes of joined lines
+	startOffset int64 // File offset of the first pending line
+}
+
 // Cross-platform file watching worker
 type xWatcher interface {
 	Start()
@@ -51,3 +225,18 @@ type fileID struct {
 	dev uint64
 	ino uint64
 }
+
+// Output configuration block
+type OutputConfig struct {
+	Path           string           `json:"path"`                     // File messages are appended to (may contain {placeholders})
+	Format         string           `json:"format,omitempty"`         // Line format (text or json)
+	BatchSize      int              `json:"batchSize,omitempty"`      // Lines buffered before writing to disk
+	MaxOpenFiles   int              `json:"maxOpenFiles,omitempty"`   // Open file handles kept for templated paths
+	MaxSizeBytes   uint64           `json:"maxSizeBytes,omitempty"`   // Rotate before a file grows past this size
+	RotateInterval parsing.Duration `json:"rotateInterval,omitempty"` // Rotate files started longer than this ago
+	Compression    string           `json:"compression,omitempty"`    // Compression of rotated files (gzip or zstd)
+	Retention      int              `json:"retention,omitempty"`      // Rotated files kept per path (0 keeps all)
+
+	AuditKeyFile           string `json:"auditKeyFile,omitempty"`           // Ed25519 private key enabling hash chained audit records
+	AuditCheckpointRecords int    `json:"auditCheckpointRecords,omitempty"` // Records between signed checkpoints
+}
@@ -2,34 +2,47 @@ package file
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"sdsyslog/internal/logctx"
 	"sdsyslog/pkg/protocol"
-	"sort"
+	"slices"
 	"strings"
 	"time"
 )
 
-// Writes log message and associated metadata in one line to configured file
+// Writes log message and associated metadata in one line to the file selected by the path template
 func (mod *OutModule) Write(ctx context.Context, msg *protocol.Payload) (linesWritten int, err error) {
 	if mod == nil {
 		return
 	}
+	defer mod.logWarnings(ctx)
 
-	newEntry := formatAsText(ctx, msg)
-
-	// Always ensure outputs have only one trailing newline
-	var lineParts []string
-	if !strings.HasSuffix(newEntry, "\n") {
-		lineParts = append(lineParts, newEntry+"\n")
+	var newLine string
+	if mod.format == FormatJSON {
+		newLine, err = formatAsJSON(msg)
+		if err != nil {
+			err = fmt.Errorf("failed to format message as JSON: %w", err)
+			return
+		}
 	} else {
-		lineParts = []string{newEntry}
+		newLine = formatAsText(ctx, msg)
+
+		// Always ensure outputs have only one trailing newline
+		if !strings.HasSuffix(newLine, "\n") {
+			newLine += "\n"
+		}
 	}
-	newLine := strings.Join(lineParts, " ")
 
 	// Buffer small amount to reorder and write in batches
-	*mod.batchBuffer = append(*mod.batchBuffer, newLine)
+	mod.batchBuffer = append(mod.batchBuffer, bufferedLine{
+		path:      mod.template.render(msg),
+		text:      newLine,
+		timestamp: msg.Timestamp,
+	})
 
 	// Flush buffer if full
-	if len(*mod.batchBuffer) > mod.batchSize {
+	if len(mod.batchBuffer) > mod.batchSize {
 		linesWritten, err = mod.FlushBuffer()
 		if err != nil {
 			return
@@ -39,56 +52,116 @@ func (mod *OutModule) Write(ctx context.Context, msg *protocol.Payload) (linesWr
 	return
 }
 
-// Flushes line buffer to the file
+// Logs recovered problems from earlier flushes
+func (mod *OutModule) logWarnings(ctx context.Context) {
+	for _, warning := range mod.warnings {
+		logctx.LogStdWarn(ctx, "%s\n", warning)
+	}
+	mod.warnings = nil
+}
+
+// Flushes line buffer to the files, rotating files that reached their size or age limit
 func (mod *OutModule) FlushBuffer() (flushedCnt int, err error) {
 	if mod == nil {
 		return
 	}
 
-	if mod.batchBuffer == nil {
+	defer func() {
+		// Keep lines that were not written for the next flush
+		mod.batchBuffer = mod.batchBuffer[flushedCnt:]
+
+		if err == nil {
+			err = mod.takeArchiveError()
+		}
+	}()
+
+	if len(mod.batchBuffer) == 0 {
 		return
 	}
 
-	if len(*mod.batchBuffer) == 0 {
+	// Newest last
+	slices.SortStableFunc(mod.batchBuffer, func(a, b bufferedLine) int {
+		return a.timestamp.Compare(b.timestamp)
+	})
+
+	now := time.Now()
+	for _, line := range mod.batchBuffer {
+		err = mod.writeLine(line, now)
+		if err != nil {
+			return
+		}
+		flushedCnt++
+	}
+
+	return
+}
+
+// Appends a single line to its file (as the next audit record in audit mode), rotating the file first when required
+func (mod *OutModule) writeLine(line bufferedLine, now time.Time) (err error) {
+	handle, err := mod.getFile(line.path)
+	if err != nil {
 		return
 	}
 
-	sort.Slice(*mod.batchBuffer, func(i, j int) bool {
-		// Extract timestamp prefix (up to first space)
-		getTime := func(s string) time.Time {
-			ts := s
-			if idx := strings.IndexByte(s, ' '); idx != -1 {
-				ts = s[:idx]
-			}
-			t, err := time.Parse(time.RFC3339Nano, ts)
-			if err != nil {
-				return time.Time{} // zero time on error
-			}
-			return t
+	data := []byte(line.text)
+	var record auditRecord
+	var hash [sha256.Size]byte
+	if handle.chain != nil {
+		record, hash = handle.chain.next(line.text)
+		data, err = record.line()
+		if err != nil {
+			err = fmt.Errorf("failed to serialize audit record: %w", err)
+			return
 		}
+	}
 
-		ti := getTime((*mod.batchBuffer)[i])
-		tj := getTime((*mod.batchBuffer)[j])
+	if mod.needsRotation(handle, len(data), now) {
+		// Rotated file ends with a signed checkpoint, chain continues in the new file
+		err = mod.writeCheckpoint(handle)
+		if err != nil {
+			return
+		}
 
-		// Newest last
-		return tj.After(ti)
-	})
+		chain := handle.chain
+		err = mod.rotate(line.path, handle, now)
+		if err != nil {
+			return
+		}
+		handle, err = mod.getFile(line.path)
+		if err != nil {
+			return
+		}
+		handle.rotationStart = now
+		handle.chain = chain
 
-	for _, line := range *mod.batchBuffer {
-		data := []byte(line)
-		for len(data) > 0 {
-			var n int
-			n, err = mod.sink.Write(data)
+		// New file starts with a signed checkpoint, the record follows it
+		if handle.chain != nil {
+			err = mod.appendCheckpoint(handle)
+			if err != nil {
+				return
+			}
+			record, hash = handle.chain.next(line.text)
+			data, err = record.line()
 			if err != nil {
+				err = fmt.Errorf("failed to serialize audit record: %w", err)
 				return
 			}
-			data = data[n:] // remove the bytes that were successfully written
 		}
-		flushedCnt++
 	}
 
-	// All writes succeeded, empty buffer
-	*mod.batchBuffer = []string{}
+	err = handle.write(data)
+	if err != nil {
+		return
+	}
 
+	if handle.chain != nil {
+		handle.chain.commit(record, hash)
+		if handle.chain.sinceCheckpoint >= mod.auditCheckpointRecords {
+			err = mod.writeCheckpoint(handle)
+			if err != nil {
+				return
+			}
+		}
+	}
 	return
 }
@@ -174,7 +174,7 @@ func TestWriter(t *testing.T) {
 				_ = os.Remove(outFilePath)
 			}()
 
-			outMod, err := NewOutput(outFilePath, tt.batchSize)
+			outMod, err := NewOutput(OutputConfig{Path: outFilePath, BatchSize: tt.batchSize})
 			if err != nil {
 				t.Fatalf("failed to create output module: %v", err)
 			}
@@ -0,0 +1,29 @@
+package journald
+
+import (
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/iomodules"
+)
+
+const OutputType string = "journald"
+
+func init() {
+	iomodules.RegisterOutput(OutputType, newOutputFromConfig)
+}
+
+// Creates journald output from its JSON configuration block
+func newOutputFromConfig(config json.RawMessage) (module iomodules.Output, err error) {
+	var options OutputConfig
+	err = iomodules.DecodeOutputConfig(config, &options)
+	if err != nil {
+		return
+	}
+	if options.URL == "" {
+		err = fmt.Errorf("journald output requires a url")
+		return
+	}
+
+	module, err = NewOutput(options.URL)
+	return
+}
@@ -43,3 +43,8 @@ type InModule struct {
 	cancel context.CancelFunc // cancel instance
 	ctx    context.Context
 }
+
+// Output configuration block
+type OutputConfig struct {
+	URL string `json:"url"` // systemd-journal-remote HTTP endpoint
+}
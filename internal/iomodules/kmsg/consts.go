@@ -0,0 +1,21 @@
+package kmsg
+
+import "sdsyslog/pkg/protocol"
+
+const (
+	DefaultPath string = "/dev/kmsg"
+
+	// Custom fields
+	CFsequence string = "KernelSequence" // Ring buffer sequence number of the record
+
+	FieldTruncationSuffix string = "[...TRUNCATED]"
+	MaxTruncatedFieldLen  int    = protocol.MaxCtxValLen - len(FieldTruncationSuffix)
+
+	// Application name of records logged by the kernel itself (kern facility)
+	kernelAppName string = "kernel"
+
+	// Every read returns one whole record (message up to 1024 bytes plus dictionary)
+	maxRecordSize int = 8192
+
+	bootIDPath string = "/proc/sys/kernel/random/boot_id"
+)
@@ -0,0 +1,87 @@
+package kmsg
+
+import (
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/metrics"
+	"sync/atomic"
+	"time"
+)
+
+type MetricStorage struct {
+	RecordsRead   atomic.Uint64 // number of records read from the ring buffer
+	ParseFailures atomic.Uint64 // number of records that could not be parsed
+	Missed        atomic.Uint64 // number of records overwritten before they were read
+	Success       atomic.Uint64 // number of messages processed successfully
+}
+
+const (
+	MTRecordsRead string = "records_read"
+	MTParseFail   string = "parse_failures"
+	MTMissed      string = "records_missed"
+	MTSuc         string = "success_processed"
+)
+
+func (mod *InModule) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	// Read and clear
+	read := mod.metrics.RecordsRead.Swap(0)
+	parseFails := mod.metrics.ParseFailures.Swap(0)
+	missed := mod.metrics.Missed.Swap(0)
+	suc := mod.metrics.Success.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	namespace := logctx.GetTagList(mod.ctx)
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTRecordsRead,
+			Description: "Total records read from the kernel ring buffer in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      read,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTParseFail,
+			Description: "Total kernel ring buffer records that failed parsing in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      parseFails,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTMissed,
+			Description: "Total kernel ring buffer records overwritten before being read in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      missed,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSuc,
+			Description: "Total processed messages extracted from the kernel ring buffer in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      suc,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package kmsg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Creates new kernel ring buffer reader module. Resumes after the last sent record of the current boot.
+func NewInput(ctx context.Context, devicePath string, baseStateFile string, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (new *InModule, err error) {
+	if devicePath == "" {
+		devicePath = DefaultPath
+	}
+
+	for index, filter := range filters {
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter at index %d: %w", index, err)
+			return
+		}
+	}
+
+	// Create unique state file for kernel messages
+	stateFileDir := filepath.Dir(baseStateFile)
+	stateFileName := filepath.Base(baseStateFile)
+	newStateFile := filepath.Join(stateFileDir, "kmsg_"+stateFileName)
+
+	lastPosition, err := getLastPosition(newStateFile)
+	if err != nil {
+		return
+	}
+
+	bootID, err := os.ReadFile(bootIDPath)
+	if err != nil {
+		err = fmt.Errorf("failed to determine local boot id: %w", err)
+		return
+	}
+
+	bootTime, err := monotonicZero()
+	if err != nil {
+		return
+	}
+
+	source, err := os.Open(devicePath)
+	if err != nil {
+		err = fmt.Errorf("failed to open kernel ring buffer %q: %w", devicePath, err)
+		return
+	}
+
+	// New context for kernel reader
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoKmsg)
+	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
+	modCtx, cancel := context.WithCancel(modCtx)
+
+	new = &InModule{
+		ctx:       modCtx,
+		source:    source,
+		bootTime:  bootTime,
+		stateFile: newStateFile,
+		bootID:    strings.TrimSpace(string(bootID)),
+		filters:   filters,
+		outbox:    queue,
+		metrics:   MetricStorage{},
+		cancel:    cancel,
+	}
+	if lastPosition.bootID == new.bootID {
+		new.lastSeq = lastPosition.sequence
+		new.havePosition = true
+	}
+
+	new.localHostname, err = os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve current local hostname: %w", err)
+		_ = source.Close()
+		return
+	}
+	return
+}
+
+// Wall clock time at which the monotonic clock (used for record timestamps) was zero
+func monotonicZero() (bootTime time.Time, err error) {
+	var monotonic unix.Timespec
+	err = unix.ClockGettime(unix.CLOCK_MONOTONIC, &monotonic)
+	if err != nil {
+		err = fmt.Errorf("failed to read monotonic clock: %w", err)
+		return
+	}
+	bootTime = time.Now().Add(-time.Duration(monotonic.Nano()))
+	return
+}
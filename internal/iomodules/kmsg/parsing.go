@@ -0,0 +1,143 @@
+package kmsg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/syslog"
+	"sdsyslog/pkg/protocol"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses one ring buffer record:
+// <priority>,<sequence>,<monotonic usec>,<flags>[,...];<message>\n followed by " KEY=value\n" dictionary lines.
+// https://www.kernel.org/doc/Documentation/ABI/testing/dev-kmsg
+func parseRecord(record []byte, bootTime time.Time, localHostname string) (message *protocol.Message, sequence uint64, err error) {
+	header, body, found := bytes.Cut(record, []byte(";"))
+	if !found {
+		err = fmt.Errorf("record has no header separator")
+		return
+	}
+
+	prefix := strings.Split(string(header), ",")
+	if len(prefix) < 4 {
+		err = fmt.Errorf("record header %q has %d fields, expected at least 4", header, len(prefix))
+		return
+	}
+
+	priority, err := strconv.ParseUint(prefix[0], 10, 16)
+	if err != nil {
+		err = fmt.Errorf("invalid record priority %q: %w", prefix[0], err)
+		return
+	}
+	sequence, err = strconv.ParseUint(prefix[1], 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid record sequence number %q: %w", prefix[1], err)
+		return
+	}
+	monotonicUsec, err := strconv.ParseInt(prefix[2], 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid record timestamp %q: %w", prefix[2], err)
+		return
+	}
+
+	message = &protocol.Message{
+		Timestamp: bootTime.Add(time.Duration(monotonicUsec) * time.Microsecond),
+		Hostname:  localHostname,
+		Fields:    make(map[string]any),
+	}
+
+	// Message is the first line, dictionary lines follow indented by a space
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	text := unescape(lines[0])
+	for _, line := range lines[1:] {
+		key, value, found := strings.Cut(strings.TrimPrefix(line, " "), "=")
+		if !found || key == "" || len(key) > protocol.MaxCtxKeyLen {
+			continue
+		}
+
+		// Truncate to size for protocol compliance
+		value = unescape(value)
+		if len(value) > protocol.MaxCtxValLen {
+			value = value[:MaxTruncatedFieldLen] + FieldTruncationSuffix
+		}
+		message.Fields[key] = value
+	}
+
+	// Facility codes above local7 are not valid syslog facilities
+	facility, facilityErr := syslog.CodeToFacility(uint16(priority >> 3))
+	if facilityErr != nil {
+		facility = iomodules.DefaultFacility
+	}
+	message.Fields[iomodules.CFfacility] = facility
+	message.Fields[iomodules.CFseverity], err = syslog.CodeToSeverity(uint16(priority & 7))
+	if err != nil {
+		err = fmt.Errorf("invalid record severity: %w", err)
+		return
+	}
+	message.Fields[CFsequence] = int64(sequence)
+
+	// Kernel records have no process, records written by programs usually start with their tag
+	message.Fields[iomodules.CFappname] = kernelAppName
+	message.Fields[iomodules.CFprocessid] = os.Getpid()
+	if priority>>3 != 0 {
+		message.Fields[iomodules.CFappname] = protocol.EmptyFieldChar
+		text = parseTag(text, message.Fields)
+	}
+
+	if text == "" {
+		text = protocol.EmptyFieldChar
+	}
+	message.Data = []byte(text)
+	return
+}
+
+// Moves a leading "name[pid]: " or "name: " tag into the common fields, returns the remaining text
+func parseTag(text string, fields map[string]any) (rest string) {
+	rest = text
+
+	tag, remaining, found := strings.Cut(text, ": ")
+	if !found || tag == "" || strings.ContainsAny(tag, " \t") || len(tag) > protocol.MaxCtxValLen {
+		return
+	}
+
+	name := tag
+	if open := strings.IndexByte(tag, '['); open > 0 && strings.HasSuffix(tag, "]") {
+		pid, err := strconv.Atoi(tag[open+1 : len(tag)-1])
+		if err != nil {
+			return
+		}
+		name = tag[:open]
+		fields[iomodules.CFprocessid] = pid
+	}
+	fields[iomodules.CFappname] = name
+	rest = remaining
+	return
+}
+
+// Replaces the \xNN escapes the kernel uses for unprintable bytes and backslashes
+func unescape(text string) (unescaped string) {
+	if !strings.Contains(text, `\x`) {
+		unescaped = text
+		return
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(text))
+	for index := 0; index < len(text); index++ {
+		if text[index] == '\\' && index+3 < len(text) && text[index+1] == 'x' {
+			code, err := strconv.ParseUint(text[index+2:index+4], 16, 8)
+			if err == nil {
+				builder.WriteByte(byte(code))
+				index += 3
+				continue
+			}
+		}
+		builder.WriteByte(text[index])
+	}
+	unescaped = builder.String()
+	return
+}
@@ -0,0 +1,138 @@
+package kmsg
+
+import (
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecord(t *testing.T) {
+	bootTime := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		record            string
+		expectedSequence  uint64
+		expectedTimestamp time.Time
+		expectedData      string
+		expectedFields    map[string]any
+		absentFields      []string
+		expectErr         bool
+	}{
+		{
+			name:              "kernel record",
+			record:            "6,339,5140900,-;NET: Registered PF_INET6 protocol family\n",
+			expectedSequence:  339,
+			expectedTimestamp: bootTime.Add(5140900 * time.Microsecond),
+			expectedData:      "NET: Registered PF_INET6 protocol family",
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "kern",
+				iomodules.CFseverity:  "info",
+				iomodules.CFappname:   kernelAppName,
+				iomodules.CFprocessid: os.Getpid(),
+				CFsequence:            int64(339),
+			},
+		},
+		{
+			name:             "dictionary fields",
+			record:           "3,1024,99000000,-,caller=T1;ahci 0000:00:1f.2: port 1 hard reset failed\n SUBSYSTEM=pci\n DEVICE=+pci:0000:00:1f.2\n",
+			expectedSequence: 1024,
+			expectedData:     "ahci 0000:00:1f.2: port 1 hard reset failed",
+			expectedFields: map[string]any{
+				iomodules.CFseverity: "err",
+				iomodules.CFappname:  kernelAppName,
+				"SUBSYSTEM":          "pci",
+				"DEVICE":             "+pci:0000:00:1f.2",
+			},
+		},
+		{
+			name:             "escaped bytes",
+			record:           `4,7,1,-;path C:\x5cdata\x09tab` + "\n VALUE=a\\x3db\n",
+			expectedSequence: 7,
+			expectedData:     "path C:\\data\ttab",
+			expectedFields:   map[string]any{iomodules.CFseverity: "warning", "VALUE": "a=b"},
+		},
+		{
+			name:             "program record with tag",
+			record:           "30,12,2000,-;systemd[1]: Started Journal Service.\n",
+			expectedSequence: 12,
+			expectedData:     "Started Journal Service.",
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "daemon",
+				iomodules.CFseverity:  "info",
+				iomodules.CFappname:   "systemd",
+				iomodules.CFprocessid: 1,
+			},
+		},
+		{
+			name:             "program record without tag",
+			record:           "14,13,2000,-;hello from userspace\n",
+			expectedSequence: 13,
+			expectedData:     "hello from userspace",
+			expectedFields:   map[string]any{iomodules.CFfacility: "user", iomodules.CFappname: protocol.EmptyFieldChar},
+		},
+		{
+			name:             "oversized dictionary values are truncated and long keys skipped",
+			record:           "6,20,1,-;msg\n LONG=" + strings.Repeat("v", 300) + "\n " + strings.Repeat("K", 40) + "=x\n",
+			expectedSequence: 20,
+			expectedFields:   map[string]any{"LONG": strings.Repeat("v", MaxTruncatedFieldLen) + FieldTruncationSuffix},
+			absentFields:     []string{strings.Repeat("K", 40)},
+		},
+		{
+			name:      "missing separator",
+			record:    "6,1,0,- no separator\n",
+			expectErr: true,
+		},
+		{
+			name:      "short header",
+			record:    "6,1;msg\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid sequence",
+			record:    "6,x,0,-;msg\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, sequence, err := parseRecord([]byte(tt.record), bootTime, "host1")
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got message %+v", msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sequence != tt.expectedSequence {
+				t.Errorf("expected sequence %d, got %d", tt.expectedSequence, sequence)
+			}
+			if !tt.expectedTimestamp.IsZero() && !msg.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %v, got %v", tt.expectedTimestamp, msg.Timestamp)
+			}
+			if msg.Hostname != "host1" {
+				t.Errorf("expected hostname %q, got %q", "host1", msg.Hostname)
+			}
+			if tt.expectedData != "" && string(msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, msg.Data)
+			}
+			for key, expected := range tt.expectedFields {
+				if msg.Fields[key] != expected {
+					t.Errorf("expected field %q to be %v (%T), got %v (%T)", key, expected, expected, msg.Fields[key], msg.Fields[key])
+				}
+			}
+			for _, key := range tt.absentFields {
+				if _, exists := msg.Fields[key]; exists {
+					t.Errorf("expected field %q to be left out", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package kmsg
+
+import (
+	"errors"
+	"os"
+	"runtime/debug"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"strings"
+	"syscall"
+)
+
+func (mod *InModule) reader() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	defer func() {
+		if !mod.havePosition {
+			return
+		}
+		err := savePosition(position{bootID: mod.bootID, sequence: mod.lastSeq}, mod.stateFile)
+		if err != nil {
+			logctx.LogStdErr(ctx,
+				"failed to save position in kernel ring buffer source: %w\n", err)
+		}
+	}()
+
+	var iter uint64
+	const refreshMask = 1024 - 1
+
+	record := make([]byte, maxRecordSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// Every read returns exactly one record
+		n, err := mod.source.Read(record)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, os.ErrClosed) {
+				return
+			}
+			if errors.Is(err, syscall.EPIPE) {
+				// Records were overwritten before being read, reading continues with the oldest remaining
+				continue
+			}
+			logctx.LogStdErr(ctx,
+				"failed to read kernel ring buffer: %w\n", err)
+			return
+		}
+
+		func() {
+			// Record panics and continue working
+			defer func() {
+				if fatalError := recover(); fatalError != nil {
+					stack := debug.Stack()
+					logctx.LogStdErr(ctx,
+						"panic in kernel ring buffer reader thread: %v\n%s", fatalError, stack)
+				}
+			}()
+
+			mod.metrics.RecordsRead.Add(1)
+
+			msg, sequence, err := parseRecord(record[:n], mod.bootTime, mod.localHostname)
+			if err != nil {
+				mod.metrics.ParseFailures.Add(1)
+				logctx.LogEvent(ctx, logctx.VerbosityData, logctx.WarnLog,
+					"failed to parse kernel record: %w\n", err)
+				return
+			}
+
+			// Already handled before a restart
+			if mod.havePosition && sequence <= mod.lastSeq {
+				return
+			}
+
+			// Gaps in sequence numbers are records overwritten before being read
+			if mod.havePosition && sequence > mod.lastSeq+1 {
+				mod.metrics.Missed.Add(sequence - mod.lastSeq - 1)
+			}
+
+			msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(ctx), "/")
+
+			var filtered bool
+			for _, filter := range mod.filters {
+				if filter.Match(msg) {
+					// First filter match wins - drop message
+					filtered = true
+					break
+				}
+			}
+			if !filtered {
+				mod.outbox.PushBlocking(ctx, msg, msg.Size())
+				if ctx.Err() != nil {
+					// Not queued, read again after restart
+					return
+				}
+				mod.metrics.Success.Add(1)
+			}
+			mod.lastSeq = sequence
+			mod.havePosition = true
+
+			// Local hostname periodic refresh
+			iter++
+			if iter&refreshMask == 0 {
+				newName, err := os.Hostname()
+				if err == nil && newName != mod.localHostname {
+					mod.localHostname = newName
+				} else if err != nil {
+					logctx.LogStdWarn(ctx, "failed to refresh current local hostname: %w\n", err)
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,113 @@
+package kmsg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Ring buffer stand-in returning one record per read, blocking once drained until closed
+type fakeDevice struct {
+	records chan string
+	closed  chan struct{}
+}
+
+func (device *fakeDevice) Read(buffer []byte) (n int, err error) {
+	select {
+	case record := <-device.records:
+		n = copy(buffer, record)
+	case <-device.closed:
+		err = os.ErrClosed
+	}
+	return
+}
+
+func (device *fakeDevice) Close() (err error) {
+	close(device.closed)
+	return
+}
+
+func TestReaderResumesAfterLastSequence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	queue, err := mpmc.New[*protocol.Message]([]string{logctx.NSTest}, 16, global.MinValue(16), global.MaxValue(16))
+	if err != nil {
+		t.Fatalf("unexpected error creating queue: %v", err)
+	}
+
+	bootID, err := os.ReadFile(bootIDPath)
+	if err != nil {
+		t.Skipf("boot id not available: %v", err)
+	}
+
+	stateDir := t.TempDir()
+	baseStateFile := filepath.Join(stateDir, "state")
+	stateFile := filepath.Join(stateDir, "kmsg_state")
+	err = os.WriteFile(stateFile, []byte(strings.TrimSpace(string(bootID))+" 2\n"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error writing state file: %v", err)
+	}
+
+	// Any readable file works for opening, records come from the fake device
+	devicePath := filepath.Join(stateDir, "kmsg")
+	err = os.WriteFile(devicePath, nil, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error creating device file: %v", err)
+	}
+
+	mod, err := NewInput(ctx, devicePath, baseStateFile, nil, queue)
+	if err != nil {
+		t.Fatalf("unexpected error creating input: %v", err)
+	}
+	_ = mod.source.Close()
+
+	device := &fakeDevice{records: make(chan string, 8), closed: make(chan struct{})}
+	device.records <- "6,1,100,-;already sent\n"
+	device.records <- "6,2,200,-;already sent too\n"
+	device.records <- "6,3,300,-;third\n"
+	device.records <- "6,6,600,-;sixth\n"
+	mod.source = device
+
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting input: %v", err)
+	}
+
+	popCtx, popCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer popCancel()
+	for _, expectedData := range []string{"third", "sixth"} {
+		msg, ok := queue.Pop(popCtx)
+		if !ok {
+			t.Fatalf("timed out waiting for message %q", expectedData)
+		}
+		if string(msg.Data) != expectedData {
+			t.Errorf("expected data %q, got %q", expectedData, msg.Data)
+		}
+	}
+
+	err = mod.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected error stopping input: %v", err)
+	}
+
+	if missed := mod.metrics.Missed.Load(); missed != 2 {
+		t.Errorf("expected 2 missed records, got %d", missed)
+	}
+
+	saved, err := getLastPosition(stateFile)
+	if err != nil {
+		t.Fatalf("unexpected error reading state file: %v", err)
+	}
+	if saved.bootID != strings.TrimSpace(string(bootID)) || saved.sequence != 6 {
+		t.Errorf("expected saved position at sequence 6 of the current boot, got %+v", saved)
+	}
+}
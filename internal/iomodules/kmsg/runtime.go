@@ -0,0 +1,27 @@
+package kmsg
+
+// Starts ring buffer reader in background
+func (mod *InModule) Start() (err error) {
+	mod.wg.Add(1)
+	go mod.reader()
+	return
+}
+
+// Gracefully stops module and saves the position of the last read record
+func (mod *InModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+
+	// Unblock reader
+	if mod.source != nil {
+		err = mod.source.Close()
+	}
+
+	mod.wg.Wait()
+	return
+}
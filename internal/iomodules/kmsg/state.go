@@ -0,0 +1,76 @@
+package kmsg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Reads the last sent position (empty if none)
+func getLastPosition(stateFilePath string) (last position, err error) {
+	stateDirectory := filepath.Dir(stateFilePath)
+
+	_, err = os.Stat(stateDirectory)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(stateDirectory, 0700)
+		if err != nil {
+			err = fmt.Errorf("failed to create missing state directory '%s': %w", stateDirectory, err)
+			return
+		}
+	} else if err != nil {
+		err = fmt.Errorf("unable to access state directory: %w", err)
+		return
+	}
+
+	data, err := os.ReadFile(stateFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("unable to read position file: %w", err)
+		return
+	}
+
+	// Format: <boot id> <sequence> - restart from the oldest record otherwise
+	bootID, sequence, found := strings.Cut(strings.TrimSpace(string(data)), " ")
+	if !found || bootID == "" {
+		return
+	}
+	seq, parseErr := strconv.ParseUint(sequence, 10, 64)
+	if parseErr != nil {
+		return
+	}
+	last = position{bootID: bootID, sequence: seq}
+	return
+}
+
+func savePosition(current position, stateFilePath string) (err error) {
+	// Don't nuke existing position
+	if current.bootID == "" {
+		return
+	}
+
+	stateDirectory := filepath.Dir(stateFilePath)
+
+	_, err = os.Stat(stateDirectory)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(stateDirectory, 0700)
+		if err != nil {
+			err = fmt.Errorf("failed to create missing state directory '%s': %w", stateDirectory, err)
+			return
+		}
+	} else if err != nil {
+		err = fmt.Errorf("unable to access state directory: %w", err)
+		return
+	}
+
+	err = os.WriteFile(stateFilePath, fmt.Appendf(nil, "%s %d\n", current.bootID, current.sequence), 0600)
+	if err != nil {
+		err = fmt.Errorf("failed to write current log position to state file: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,42 @@
+// IOModule for the kernel ring buffer (/dev/kmsg)
+package kmsg
+
+import (
+	"context"
+	"io"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"time"
+)
+
+type InModule struct {
+	// Settings
+	filters       []protocol.MessageFilter
+	localHostname string
+
+	// Input
+	source   io.ReadCloser
+	bootTime time.Time // Wall clock time of the monotonic clock zero
+
+	// Output
+	outbox *mpmc.Queue[*protocol.Message]
+
+	// State
+	stateFile    string
+	bootID       string
+	lastSeq      uint64 // Sequence number of the last handled record
+	havePosition bool   // Sequence numbers restart at zero on boot, so positions of other boots are not used
+
+	metrics MetricStorage
+
+	wg     sync.WaitGroup     // Waiter for instance
+	cancel context.CancelFunc // cancel instance
+	ctx    context.Context
+}
+
+// Position in the ring buffer of a boot
+type position struct {
+	bootID   string
+	sequence uint64
+}
@@ -0,0 +1,66 @@
+package iomodules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Creates an output module from its JSON configuration block
+type OutputConstructor func(config json.RawMessage) (module Output, err error)
+
+var outputRegistryMu sync.RWMutex
+var outputRegistry = make(map[string]OutputConstructor)
+
+// Makes an output type available to configuration. Called by output modules during package initialization.
+func RegisterOutput(outputType string, constructor OutputConstructor) {
+	outputRegistryMu.Lock()
+	defer outputRegistryMu.Unlock()
+
+	_, exists := outputRegistry[outputType]
+	if exists || outputType == "" || constructor == nil {
+		panic(fmt.Sprintf("invalid or duplicate output module registration for type %q", outputType))
+	}
+	outputRegistry[outputType] = constructor
+}
+
+// Creates new output module of the given registered type
+func NewOutput(outputType string, config json.RawMessage) (module Output, err error) {
+	outputRegistryMu.RLock()
+	constructor, exists := outputRegistry[outputType]
+	outputRegistryMu.RUnlock()
+	if !exists {
+		err = fmt.Errorf("unknown output type %q: must be one of %q", outputType, OutputTypes())
+		return
+	}
+
+	module, err = constructor(config)
+	return
+}
+
+// Sorted list of registered output types
+func OutputTypes() (types []string) {
+	outputRegistryMu.RLock()
+	defer outputRegistryMu.RUnlock()
+
+	types = slices.Sorted(maps.Keys(outputRegistry))
+	return
+}
+
+// Decodes an output configuration block into the modules' options, rejecting unknown fields. Empty blocks leave options unchanged.
+func DecodeOutputConfig(config json.RawMessage, options any) (err error) {
+	if len(bytes.TrimSpace(config)) == 0 {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(options)
+	if err != nil {
+		err = fmt.Errorf("invalid output config: %w", err)
+	}
+	return
+}
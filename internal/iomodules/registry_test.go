@@ -0,0 +1,68 @@
+package iomodules
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOutputConfig(t *testing.T) {
+	type options struct {
+		Address string `json:"address"`
+		Retries int    `json:"retries,omitempty"`
+	}
+
+	tests := []struct {
+		name          string
+		config        string
+		expected      options
+		expectedError string
+	}{
+		{
+			name:     "empty block keeps defaults",
+			config:   "",
+			expected: options{Retries: 3},
+		},
+		{
+			name:     "overrides defaults",
+			config:   `{"address": "localhost:514", "retries": 5}`,
+			expected: options{Address: "localhost:514", Retries: 5},
+		},
+		{
+			name:          "unknown field",
+			config:        `{"adress": "localhost:514"}`,
+			expectedError: `unknown field "adress"`,
+		},
+		{
+			name:          "wrong type",
+			config:        `{"retries": "five"}`,
+			expectedError: "invalid output config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := options{Retries: 3}
+			err := DecodeOutputConfig(json.RawMessage(tt.config), &decoded)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decoded != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestNewOutputUnknownType(t *testing.T) {
+	_, err := NewOutput("does-not-exist", nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown output type") {
+		t.Fatalf("expected unknown output type error, got %v", err)
+	}
+}
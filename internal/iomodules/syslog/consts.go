@@ -0,0 +1,45 @@
+package syslog
+
+import "time"
+
+const (
+	DefaultPort    int = 514
+	DefaultTLSPort int = 6514 // RFC5425
+
+	DefaultMaxSendAttempts int = 6
+
+	// Output transports
+	TransportUDP string = "udp"
+	TransportTCP string = "tcp" // Octet-counting framing (RFC6587)
+	TransportTLS string = "tls" // RFC5425
+
+	// Custom fields (network listener only)
+	CFsourceIP string = "SourceIP"        // Address of the remote peer that sent the message
+	CFmsgID    string = "SyslogMessageID" // RFC5424 MSGID header field
+
+	FieldTruncationSuffix string = "[...TRUNCATED]"
+
+	// Priority used when a message omits the PRI part (RFC3164 section 4.3.3)
+	defaultPriority uint16 = 13
+
+	rfc5424Version     string = "1"
+	rfc3164TimeLayout  string = "Jan _2 15:04:05"
+	utf8ByteOrderMark  string = "\xEF\xBB\xBF"
+	maxTagLen          int    = 48
+	maxUDPMessageSize  int    = 65535
+	maxTCPFrameSize    int    = 1024 * 1024
+	maxOctetCountChars int    = 7 // Enough digits to cover maxTCPFrameSize
+
+	// Output
+	rfc5424TimeLayout    string        = "2006-01-02T15:04:05.000000Z07:00"
+	fieldsSDID           string        = "fields@32473" // SD-ID for custom fields (example private enterprise number from RFC5424)
+	maxHostnameLen       int           = 255
+	maxProcIDLen         int           = 128
+	maxMsgIDLen          int           = 32
+	maxSDNameLen         int           = 32
+	maxUDPOutputSize     int           = 65507 // Largest IPv4 UDP payload
+	outputTimeout        time.Duration = 3 * time.Second
+	minReconnectInterval time.Duration = 500 * time.Millisecond
+	maxReconnectInterval time.Duration = 30 * time.Second
+	peerProbeTimeout     time.Duration = 10 * time.Millisecond
+)
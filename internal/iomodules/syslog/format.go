@@ -0,0 +1,172 @@
+package syslog
+
+import (
+	"bytes"
+	"net/netip"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Renders message as a single RFC5424 syslog message (without transport framing)
+func formatRFC5424(msg *protocol.Payload) (line []byte) {
+	var appname, processid, msgid string
+	facility := iomodules.DefaultFacility
+	severity := iomodules.DefaultSeverity
+
+	var sdFields []string
+	for key, value := range msg.CustomFields {
+		switch key {
+		case iomodules.CFappname:
+			appname = protocol.FormatValue(value)
+			continue
+		case iomodules.CFprocessid:
+			processid = protocol.FormatValue(value)
+			continue
+		case iomodules.CFfacility:
+			facility = protocol.FormatValue(value)
+			continue
+		case iomodules.CFseverity:
+			severity = protocol.FormatValue(value)
+			continue
+		case CFmsgID:
+			msgid = protocol.FormatValue(value)
+			continue
+		}
+		sdFields = append(sdFields, key)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(int(priorityCode(facility, severity))))
+	buf.WriteByte('>')
+	buf.WriteString(rfc5424Version)
+	buf.WriteByte(' ')
+	if msg.Timestamp.IsZero() {
+		buf.WriteString(protocol.EmptyFieldChar)
+	} else {
+		buf.WriteString(msg.Timestamp.UTC().Format(rfc5424TimeLayout))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(msg.Hostname, maxHostnameLen))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(appname, maxTagLen))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(processid, maxProcIDLen))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(msgid, maxMsgIDLen))
+	buf.WriteByte(' ')
+
+	// Structured data
+	writeOrigin(&buf, msg.RemoteIP)
+	if len(sdFields) > 0 {
+		slices.Sort(sdFields)
+
+		buf.WriteString("[" + fieldsSDID)
+		for _, key := range sdFields {
+			value := protocol.FormatValue(msg.CustomFields[key])
+			buf.WriteByte(' ')
+			buf.WriteString(sdParamName(key))
+			buf.WriteString(`="`)
+			buf.WriteString(sdParamValue(value))
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+	if !msg.RemoteIP.IsValid() && len(sdFields) == 0 {
+		buf.WriteString(protocol.EmptyFieldChar)
+	}
+
+	// Message text
+	data := bytes.TrimRight(msg.Data, "\r\n")
+	if len(data) > 0 {
+		buf.WriteByte(' ')
+		if utf8.Valid(data) {
+			buf.WriteString(utf8ByteOrderMark)
+		}
+		buf.Write(data)
+	}
+
+	line = buf.Bytes()
+	return
+}
+
+// Combines facility and severity names (or numeric codes) into the PRI value, unknown values use defaults
+func priorityCode(facility, severity string) (priority uint16) {
+	facilityCode, err := FacilityToCode(strings.ToLower(facility))
+	if err != nil {
+		code, convErr := strconv.ParseUint(facility, 10, 16)
+		if convErr == nil && code <= 23 {
+			facilityCode = uint16(code)
+		} else {
+			facilityCode, _ = FacilityToCode(iomodules.DefaultFacility)
+		}
+	}
+	severityCode, err := SeverityToCode(strings.ToLower(severity))
+	if err != nil {
+		code, convErr := strconv.ParseUint(severity, 10, 16)
+		if convErr == nil && code <= 7 {
+			severityCode = uint16(code)
+		} else {
+			severityCode, _ = SeverityToCode(iomodules.DefaultSeverity)
+		}
+	}
+	priority = facilityCode*8 + severityCode
+	return
+}
+
+// Writes the origin structured data element carrying the senders' address
+func writeOrigin(buf *bytes.Buffer, remoteIP netip.Addr) {
+	if !remoteIP.IsValid() {
+		return
+	}
+	buf.WriteString("[origin ip=\"")
+	buf.WriteString(remoteIP.Unmap().String())
+	buf.WriteString("\"]")
+}
+
+// Restricts header field to printable US-ASCII within max length (empty fields become the nil value)
+func headerField(value string, maxLen int) (field string) {
+	if value == "" || value == protocol.EmptyFieldChar {
+		field = protocol.EmptyFieldChar
+		return
+	}
+	field = strings.Map(func(char rune) rune {
+		if char < 33 || char > 126 {
+			return '_'
+		}
+		return char
+	}, value)
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	return
+}
+
+// Restricts custom field name to a valid SD-PARAM name
+func sdParamName(key string) (name string) {
+	name = strings.Map(func(char rune) rune {
+		if char < 33 || char > 126 || char == '=' || char == ']' || char == '"' {
+			return '_'
+		}
+		return char
+	}, key)
+	if len(name) > maxSDNameLen {
+		name = name[:maxSDNameLen]
+	}
+	if name == "" {
+		name = "_"
+	}
+	return
+}
+
+// Escapes characters that must not appear unescaped inside an SD-PARAM value
+func sdParamValue(value string) (escaped string) {
+	escaped = sdValueEscaper.Replace(strings.ToValidUTF8(value, string(utf8.RuneError)))
+	return
+}
+
+var sdValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
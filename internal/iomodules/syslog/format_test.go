@@ -0,0 +1,140 @@
+package syslog
+
+import (
+	"net/netip"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"testing"
+	"time"
+)
+
+func TestFormatRFC5424(t *testing.T) {
+	timestamp := time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    *protocol.Payload
+		expected string
+	}{
+		{
+			name: "header fields and custom fields",
+			input: &protocol.Payload{
+				RemoteIP:  netip.MustParseAddr("192.0.2.10"),
+				Timestamp: timestamp,
+				Hostname:  "web01",
+				CustomFields: map[string]any{
+					iomodules.CFfacility:  "auth",
+					iomodules.CFseverity:  "err",
+					iomodules.CFappname:   "sshd",
+					iomodules.CFprocessid: 4321,
+					CFmsgID:               "LOGIN",
+					"User":                "root",
+					"Attempts":            3,
+				},
+				Data: []byte("failed password\n"),
+			},
+			expected: "<35>1 2025-03-04T05:06:07.123456Z web01 sshd 4321 LOGIN " +
+				`[origin ip="192.0.2.10"][fields@32473 Attempts="3" User="root"] ` +
+				utf8ByteOrderMark + "failed password",
+		},
+		{
+			name: "defaults and nil values",
+			input: &protocol.Payload{
+				Data: []byte("text"),
+			},
+			expected: "<30>1 - - - - - - " + utf8ByteOrderMark + "text",
+		},
+		{
+			name: "unverified hostname prefix preserved",
+			input: &protocol.Payload{
+				RemoteIP:  netip.MustParseAddr("::ffff:198.51.100.7"),
+				Timestamp: timestamp,
+				Hostname:  "[UNVERIFIED]db 01",
+				Data:      []byte("x"),
+			},
+			expected: "<30>1 2025-03-04T05:06:07.123456Z [UNVERIFIED]db_01 - - - " +
+				`[origin ip="198.51.100.7"] ` + utf8ByteOrderMark + "x",
+		},
+		{
+			name: "numeric priority codes",
+			input: &protocol.Payload{
+				CustomFields: map[string]any{
+					iomodules.CFfacility: "16",
+					iomodules.CFseverity: "4",
+				},
+			},
+			expected: "<132>1 - - - - - -",
+		},
+		{
+			name: "unknown priority names use defaults",
+			input: &protocol.Payload{
+				CustomFields: map[string]any{
+					iomodules.CFfacility: "bogus",
+					iomodules.CFseverity: "99",
+				},
+			},
+			expected: "<30>1 - - - - - -",
+		},
+		{
+			name: "structured data escaping",
+			input: &protocol.Payload{
+				CustomFields: map[string]any{
+					`a "b"=c]`:                            `say "hi" \ [x]`,
+					"_SYSTEMD_UNIT_WITH_A_VERY_LONG_NAME": "unit",
+				},
+			},
+			expected: `<30>1 - - - - - [fields@32473 _SYSTEMD_UNIT_WITH_A_VERY_LONG_N="unit" a__b__c_="say \"hi\" \\ [x\]"]`,
+		},
+		{
+			name: "invalid utf8 message without byte order mark",
+			input: &protocol.Payload{
+				Data: []byte{0xff, 0xfe, 'a'},
+			},
+			expected: "<30>1 - - - - - - \xff\xfea",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := string(formatRFC5424(tt.input))
+			if output != tt.expected {
+				t.Errorf("unexpected output:\n  got:  %q\n  want: %q", output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatRFC5424RoundTrip(t *testing.T) {
+	input := &protocol.Payload{
+		RemoteIP:  netip.MustParseAddr("192.0.2.10"),
+		Timestamp: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Hostname:  "web01",
+		CustomFields: map[string]any{
+			iomodules.CFfacility: "local3",
+			iomodules.CFseverity: "notice",
+			iomodules.CFappname:  "app",
+			CFmsgID:              "ID7",
+		},
+		Data: []byte("round trip"),
+	}
+
+	message, err := parseMessage(formatRFC5424(input), "192.0.2.1")
+	if err != nil {
+		t.Fatalf("failed to parse formatted message: %v", err)
+	}
+
+	if message.Hostname != input.Hostname {
+		t.Errorf("expected hostname %q, got %q", input.Hostname, message.Hostname)
+	}
+	if !message.Timestamp.Equal(input.Timestamp) {
+		t.Errorf("expected timestamp %v, got %v", input.Timestamp, message.Timestamp)
+	}
+	if string(message.Data) != string(input.Data) {
+		t.Errorf("expected data %q, got %q", input.Data, message.Data)
+	}
+	for _, key := range []string{iomodules.CFfacility, iomodules.CFseverity, iomodules.CFappname, CFmsgID} {
+		if message.Fields[key] != input.CustomFields[key] {
+			t.Errorf("expected field %s=%v, got %v", key, input.CustomFields[key], message.Fields[key])
+		}
+	}
+}
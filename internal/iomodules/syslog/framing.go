@@ -0,0 +1,93 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Reads a single message from a syslog stream.
+// Supports both octet-counting and non-transparent (newline) framing (RFC6587), detected per frame.
+func readFrame(reader *bufio.Reader) (frame []byte, err error) {
+	for {
+		var first byte
+		first, err = reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		// Skip stray delimiters between frames
+		if first == '\n' || first == '\r' || first == 0 {
+			continue
+		}
+
+		// Octet counting: 'MSG-LEN SP SYSLOG-MSG'
+		if first >= '1' && first <= '9' {
+			digits := []byte{first}
+			for {
+				var char byte
+				char, err = reader.ReadByte()
+				if err != nil {
+					return
+				}
+				if char == ' ' {
+					break
+				}
+				if char < '0' || char > '9' || len(digits) >= maxOctetCountChars {
+					err = fmt.Errorf("invalid octet count prefix %q", append(digits, char))
+					return
+				}
+				digits = append(digits, char)
+			}
+
+			var length int
+			length, err = strconv.Atoi(string(digits))
+			if err != nil {
+				err = fmt.Errorf("invalid octet count %q: %w", digits, err)
+				return
+			}
+			if length > maxTCPFrameSize {
+				err = fmt.Errorf("frame length %d exceeds maximum of %d bytes", length, maxTCPFrameSize)
+				return
+			}
+
+			frame = make([]byte, length)
+			_, err = io.ReadFull(reader, frame)
+			if err != nil {
+				err = fmt.Errorf("failed reading octet counted frame: %w", err)
+				return
+			}
+			return
+		}
+
+		// Non-transparent framing: read until newline
+		err = reader.UnreadByte()
+		if err != nil {
+			return
+		}
+
+		var line []byte
+		for {
+			var chunk []byte
+			chunk, err = reader.ReadSlice('\n')
+			line = append(line, chunk...)
+			if len(line) > maxTCPFrameSize {
+				err = fmt.Errorf("frame exceeds maximum of %d bytes without a delimiter", maxTCPFrameSize)
+				return
+			}
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			break
+		}
+		if err != nil && !(err == io.EOF && len(line) > 0) {
+			return
+		}
+		err = nil
+
+		frame = bytes.TrimRight(line, "\r\n")
+		return
+	}
+}
@@ -0,0 +1,89 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadFrame(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedFrames []string
+		expectedErr    bool
+	}{
+		{
+			name:           "newline framing",
+			input:          "<13>first message\n<13>second message\r\n",
+			expectedFrames: []string{"<13>first message", "<13>second message"},
+		},
+		{
+			name:           "octet counting",
+			input:          "17 <13>first message18 <13>second\nmessage",
+			expectedFrames: []string{"<13>first message", "<13>second\nmessage"},
+		},
+		{
+			name:           "mixed framing with stray delimiters",
+			input:          "\n\n5 <13>a<13>b\n\x00",
+			expectedFrames: []string{"<13>a", "<13>b"},
+		},
+		{
+			name:           "trailing frame without delimiter",
+			input:          "<13>unterminated",
+			expectedFrames: []string{"<13>unterminated"},
+		},
+		{
+			name:        "invalid octet count",
+			input:       "12a <13>text",
+			expectedErr: true,
+		},
+		{
+			name:        "octet count exceeds maximum",
+			input:       "9999999 <13>text",
+			expectedErr: true,
+		},
+		{
+			name:        "truncated octet counted frame",
+			input:       "50 <13>short",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+
+			var frames []string
+			var err error
+			for {
+				var frame []byte
+				frame, err = readFrame(reader)
+				if err != nil {
+					break
+				}
+				frames = append(frames, string(frame))
+			}
+
+			if tt.expectedErr {
+				if err == io.EOF {
+					t.Fatalf("expected framing error, got EOF")
+				}
+				return
+			}
+			if err != io.EOF {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(frames) != len(tt.expectedFrames) {
+				t.Fatalf("expected %d frames, got %d: %q", len(tt.expectedFrames), len(frames), frames)
+			}
+			for i := range frames {
+				if frames[i] != tt.expectedFrames[i] {
+					t.Errorf("frame %d: expected %q, got %q", i, tt.expectedFrames[i], frames[i])
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,9 @@
-// Package containing lookup information related to the Syslog protocol (for use in parsing elsewhere)
+// IOModule for the syslog protocol (RFC3164/RFC5424) and lookup information for its facility/severity codes
 package syslog
 
 import (
 	"fmt"
+	"sdsyslog/internal/filtering"
 	"sync"
 )
 
@@ -33,6 +34,7 @@ var logFacility = LogFacility{
 	},
 	CodeToFacility: nil,
 }
+
 var severityMu sync.RWMutex
 var logSeverity = LogSeverity{
 	SeverityToCode: map[string]uint16{
@@ -126,3 +128,14 @@ func CodeToSeverity(code uint16) (severity string, err error) {
 	}
 	return
 }
+
+// Syslog severity name from common application level names (like warn or error) and numeric codes
+func NormalizeSeverity(text string) (severity string, valid bool) {
+	code, valid := filtering.SeverityCode(text)
+	if !valid {
+		return
+	}
+	severity, err := CodeToSeverity(uint16(code))
+	valid = err == nil
+	return
+}
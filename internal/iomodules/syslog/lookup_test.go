@@ -129,3 +129,39 @@ func TestFacilityMappings(t *testing.T) {
 		}
 	})
 }
+
+func TestNormalizeSeverity(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectInvalid bool
+	}{
+		{input: "warning", expected: "warning"},
+		{input: " WARN ", expected: "warning"},
+		{input: "Error", expected: "err"},
+		{input: "fatal", expected: "crit"},
+		{input: "trace", expected: "debug"},
+		{input: "3", expected: "err"},
+		{input: "8", expectInvalid: true},
+		{input: "-1", expectInvalid: true},
+		{input: "verbose", expectInvalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			severity, valid := NormalizeSeverity(tt.input)
+			if tt.expectInvalid {
+				if valid {
+					t.Fatalf("expected %q to be invalid, got %q", tt.input, severity)
+				}
+				return
+			}
+			if !valid {
+				t.Fatalf("expected %q to be valid", tt.input)
+			}
+			if severity != tt.expected {
+				t.Fatalf("expected severity %q, got %q", tt.expected, severity)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package syslog
+
+import (
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/metrics"
+	"sync/atomic"
+	"time"
+)
+
+type MetricStorage struct {
+	MessagesRead  atomic.Uint64 // number of messages received from the network
+	ParseFailures atomic.Uint64 // number of messages that could not be parsed
+	Connections   atomic.Uint64 // number of accepted TCP connections
+	Success       atomic.Uint64 // number of messages processed successfully
+	Dropped       atomic.Uint64 // number of messages dropped due to full queue
+}
+
+const (
+	MTMsgsRead    string = "messages_read"
+	MTParseFail   string = "parse_failures"
+	MTConnections string = "tcp_connections"
+	MTSuc         string = "success_processed"
+)
+
+func (mod *InModule) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	// Read and clear
+	read := mod.metrics.MessagesRead.Swap(0)
+	parseFails := mod.metrics.ParseFailures.Swap(0)
+	conns := mod.metrics.Connections.Swap(0)
+	suc := mod.metrics.Success.Swap(0)
+	dropped := mod.metrics.Dropped.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	namespace := logctx.GetTagList(mod.ctx)
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTMsgsRead,
+			Description: "Total messages received by syslog listeners in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      read,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTParseFail,
+			Description: "Total received syslog messages that failed parsing in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      parseFails,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTConnections,
+			Description: "Total accepted syslog TCP connections in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      conns,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSuc,
+			Description: "Total processed messages extracted from syslog listeners in the interval",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      suc,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        metrics.MTDropped,
+			Description: metrics.DescDropped,
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      dropped,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
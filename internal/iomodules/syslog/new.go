@@ -0,0 +1,165 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+)
+
+// Creates new syslog network listener module. Binds the given UDP and/or TCP addresses. Returns nil nil if no addresses.
+func NewInput(ctx context.Context, udpAddress string, tcpAddress string, filters []protocol.MessageFilter, queue *mpmc.Queue[*protocol.Message]) (module *InModule, err error) {
+	if udpAddress == "" && tcpAddress == "" {
+		return
+	}
+
+	for index, filter := range filters {
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter at index %d: %w", index, err)
+			return
+		}
+	}
+
+	// New context for listener
+	newNamespace := append(logctx.GetTagList(ctx), logctx.NSoSyslog)
+	modCtx := logctx.OverwriteCtxTag(ctx, newNamespace)
+	modCtx, cancel := context.WithCancel(modCtx)
+
+	new := &InModule{
+		filters:  filters,
+		tcpConns: make(map[net.Conn]struct{}),
+		outbox:   queue,
+		metrics:  MetricStorage{},
+		ctx:      modCtx,
+		cancel:   cancel,
+	}
+
+	new.localHostname, err = os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve local hostname: %w", err)
+		return
+	}
+
+	if udpAddress != "" {
+		var addr *net.UDPAddr
+		addr, err = net.ResolveUDPAddr("udp", udpAddress)
+		if err != nil {
+			err = fmt.Errorf("invalid syslog UDP listen address %q: %w", udpAddress, err)
+			return
+		}
+		new.udpConn, err = net.ListenUDP("udp", addr)
+		if err != nil {
+			err = fmt.Errorf("failed to listen on syslog UDP address %q: %w", udpAddress, err)
+			return
+		}
+	}
+
+	if tcpAddress != "" {
+		new.tcpListener, err = net.Listen("tcp", tcpAddress)
+		if err != nil {
+			err = fmt.Errorf("failed to listen on syslog TCP address %q: %w", tcpAddress, err)
+			if new.udpConn != nil {
+				_ = new.udpConn.Close()
+			}
+			return
+		}
+	}
+
+	module = new
+	return
+}
+
+// Creates new syslog forwarding output module. Connects on first write. Returns nil nil if no address.
+func NewOutput(config OutputConfig) (module *OutModule, err error) {
+	if config.Address == "" {
+		return
+	}
+
+	host, _, err := net.SplitHostPort(config.Address)
+	if err != nil {
+		err = fmt.Errorf("invalid syslog server address %q: %w", config.Address, err)
+		return
+	}
+
+	new := &OutModule{
+		address:        config.Address,
+		transport:      config.Transport,
+		maxSendRetries: config.MaxSendAttempts,
+	}
+	if new.transport == "" {
+		new.transport = TransportUDP
+	}
+	if new.maxSendRetries == 0 {
+		new.maxSendRetries = DefaultMaxSendAttempts
+	} else if new.maxSendRetries < 0 {
+		err = fmt.Errorf("syslog output max send attempts cannot be negative")
+		return
+	}
+
+	switch new.transport {
+	case TransportUDP, TransportTCP:
+		if config.CAFile != "" || config.CertFile != "" || config.KeyFile != "" || config.ServerName != "" {
+			err = fmt.Errorf("TLS settings require transport %q (transport is %q)", TransportTLS, new.transport)
+			return
+		}
+	case TransportTLS:
+		new.tlsConfig, err = newClientTLSConfig(config, host)
+		if err != nil {
+			return
+		}
+	default:
+		err = fmt.Errorf("unknown syslog transport %q: must be one of %q, %q, %q",
+			new.transport, TransportUDP, TransportTCP, TransportTLS)
+		return
+	}
+
+	// Connection is opened by the first write, so an unreachable server does not prevent startup
+	module = new
+	return
+}
+
+// Builds client TLS settings from configured CA and client certificate files
+func newClientTLSConfig(config OutputConfig, host string) (tlsConfig *tls.Config, err error) {
+	tlsConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.ServerName,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	if config.CAFile != "" {
+		var caPEM []byte
+		caPEM, err = os.ReadFile(config.CAFile)
+		if err != nil {
+			err = fmt.Errorf("failed to read syslog server CA file: %w", err)
+			return
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			err = fmt.Errorf("syslog server CA file %q contains no PEM certificates", config.CAFile)
+			return
+		}
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			err = fmt.Errorf("syslog client certificate requires both a certificate and key file")
+			return
+		}
+		var clientCert tls.Certificate
+		clientCert, err = tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			err = fmt.Errorf("failed to load syslog client certificate: %w", err)
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	return
+}
@@ -0,0 +1,353 @@
+package syslog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses a single syslog message (RFC5424 or RFC3164) received from the network and extracts metadata.
+// Source host is used as the hostname when the message does not carry one.
+func parseMessage(raw []byte, sourceHost string) (message *protocol.Message, err error) {
+	message, err = parse(raw, sourceHost, true)
+	return
+}
+
+// Parses a single syslog message written by a local process (e.g. to /dev/log) and extracts metadata.
+// Local messages are always attributed to the local hostname.
+func ParseLocalMessage(raw []byte, localHostname string) (message *protocol.Message, err error) {
+	message, err = parse(raw, localHostname, false)
+	if err != nil {
+		return
+	}
+	message.Hostname = localHostname
+	return
+}
+
+// Shared parsing for network and local messages.
+// Remote controls whether RFC3164 messages are expected to carry a hostname after the timestamp.
+func parse(raw []byte, sourceHost string, remote bool) (message *protocol.Message, err error) {
+	raw = bytes.TrimRight(raw, "\r\n\x00")
+	if len(raw) == 0 {
+		err = fmt.Errorf("empty message")
+		return
+	}
+
+	message = &protocol.Message{}
+	message.Fields = make(map[string]any)
+
+	// PRI
+	priority, rest, err := parsePriority(raw)
+	if err != nil {
+		return
+	}
+	message.Fields[iomodules.CFfacility], err = CodeToFacility(priority / 8)
+	if err != nil {
+		err = fmt.Errorf("invalid priority %d: %w", priority, err)
+		return
+	}
+	message.Fields[iomodules.CFseverity], err = CodeToSeverity(priority % 8)
+	if err != nil {
+		err = fmt.Errorf("invalid priority %d: %w", priority, err)
+		return
+	}
+
+	// Version field only exists in RFC5424
+	line := string(rest)
+	if strings.HasPrefix(line, rfc5424Version+" ") {
+		err = parseRFC5424(message, line[len(rfc5424Version)+1:])
+		if err != nil {
+			err = fmt.Errorf("invalid RFC5424 message: %w", err)
+			return
+		}
+	} else {
+		parseRFC3164(message, line, remote)
+	}
+
+	setDefaults(message, sourceHost)
+	return
+}
+
+// Extracts the leading <PRI> value. Messages without one get the RFC3164 default priority.
+func parsePriority(raw []byte) (priority uint16, rest []byte, err error) {
+	if raw[0] != '<' {
+		priority = defaultPriority
+		rest = raw
+		return
+	}
+
+	end := bytes.IndexByte(raw, '>')
+	if end < 2 || end > 4 {
+		err = fmt.Errorf("invalid PRI part: expected 1-3 digits between angle brackets")
+		return
+	}
+
+	value, err := strconv.ParseUint(string(raw[1:end]), 10, 8)
+	if err != nil {
+		err = fmt.Errorf("invalid PRI value %q: %w", raw[1:end], err)
+		return
+	}
+	if value > 191 {
+		err = fmt.Errorf("invalid PRI value %d: must be between 0 and 191", value)
+		return
+	}
+
+	priority = uint16(value)
+	rest = raw[end+1:]
+	return
+}
+
+// Fmt: 'TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ELEMENT...] MSG'
+func parseRFC5424(message *protocol.Message, line string) (err error) {
+	var header [5]string
+	for i := range header {
+		header[i], line = nextToken(line)
+		if header[i] == "" {
+			err = fmt.Errorf("missing header field %d", i+1)
+			return
+		}
+	}
+	timestamp, hostname, appname, procID, msgID := header[0], header[1], header[2], header[3], header[4]
+
+	if timestamp != protocol.EmptyFieldChar {
+		message.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			err = fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+			return
+		}
+	}
+	if hostname != protocol.EmptyFieldChar {
+		message.Hostname = hostname
+	}
+	if appname != protocol.EmptyFieldChar {
+		message.Fields[iomodules.CFappname] = appname
+	}
+	if pid, lerr := strconv.Atoi(procID); lerr == nil {
+		message.Fields[iomodules.CFprocessid] = pid
+	}
+	if msgID != protocol.EmptyFieldChar {
+		message.Fields[CFmsgID] = msgID
+	}
+
+	// Structured data
+	if strings.HasPrefix(line, protocol.EmptyFieldChar) {
+		line = strings.TrimPrefix(line, protocol.EmptyFieldChar)
+	} else if strings.HasPrefix(line, "[") {
+		line, err = parseStructuredData(message.Fields, line)
+		if err != nil {
+			err = fmt.Errorf("invalid structured data: %w", err)
+			return
+		}
+	} else {
+		err = fmt.Errorf("missing structured data field")
+		return
+	}
+
+	line = strings.TrimPrefix(line, " ")
+	line = strings.TrimPrefix(line, utf8ByteOrderMark)
+	message.Data = []byte(line)
+	return
+}
+
+// Parses all SD-ELEMENTs at start of line into custom fields (keyed by 'SD-ID.PARAM-NAME') and returns remaining text
+func parseStructuredData(fields map[string]any, line string) (rest string, err error) {
+	rest = line
+	for strings.HasPrefix(rest, "[") {
+		rest = rest[1:]
+
+		var sdID string
+		sdID, rest = nextName(rest)
+		if sdID == "" {
+			err = fmt.Errorf("empty SD-ID")
+			return
+		}
+
+		for {
+			rest = strings.TrimPrefix(rest, " ")
+			if strings.HasPrefix(rest, "]") {
+				rest = rest[1:]
+				break
+			}
+
+			var name string
+			name, rest = nextName(rest)
+			if name == "" || !strings.HasPrefix(rest, `="`) {
+				err = fmt.Errorf("invalid SD-PARAM in element %q", sdID)
+				return
+			}
+			rest = rest[2:]
+
+			var value strings.Builder
+			var closed bool
+			for i := 0; i < len(rest); i++ {
+				char := rest[i]
+				if char == '\\' && i+1 < len(rest) && strings.IndexByte(`"\]`, rest[i+1]) >= 0 {
+					value.WriteByte(rest[i+1])
+					i++
+					continue
+				}
+				if char == '"' {
+					rest = rest[i+1:]
+					closed = true
+					break
+				}
+				value.WriteByte(char)
+			}
+			if !closed {
+				err = fmt.Errorf("unterminated value for SD-PARAM %q in element %q", name, sdID)
+				return
+			}
+
+			addStructuredField(fields, sdID, name, value.String())
+		}
+	}
+	return
+}
+
+// Adds a single SD-PARAM as a custom field while staying within protocol limits
+func addStructuredField(fields map[string]any, sdID string, name string, value string) {
+	key := sdID + "." + name
+	if len(key) > protocol.MaxCtxKeyLen {
+		key = name
+	}
+
+	// Never override the common fields
+	switch key {
+	case iomodules.CFappname, iomodules.CFprocessid, iomodules.CFfacility, iomodules.CFseverity, iomodules.CtxKey:
+		return
+	}
+
+	if len(value) > protocol.MaxCtxValLen {
+		value = value[:protocol.MaxCtxValLen-len(FieldTruncationSuffix)] + FieldTruncationSuffix
+	}
+	fields[key] = value
+}
+
+// Fmt: '[TIMESTAMP] [HOSTNAME] TAG[PID]: MSG' (timestamp and hostname are optional in the wild)
+// Local socket writers (libc syslog(3)) never include a hostname, so it is only searched for when hasHostname is set.
+func parseRFC3164(message *protocol.Message, line string, hasHostname bool) {
+	line = strings.TrimLeft(line, " ")
+
+	// Timestamp (BSD or high precision ISO)
+	var hasTimestamp bool
+	if len(line) >= len(rfc3164TimeLayout) {
+		ts, err := time.Parse(rfc3164TimeLayout, line[:len(rfc3164TimeLayout)])
+		if err == nil {
+			message.Timestamp = withCurrentYear(ts)
+			line = strings.TrimLeft(line[len(rfc3164TimeLayout):], " ")
+			hasTimestamp = true
+		}
+	}
+	if !hasTimestamp {
+		token, rest := nextToken(line)
+		ts, err := time.Parse(time.RFC3339Nano, token)
+		if err == nil {
+			message.Timestamp = ts
+			line = rest
+			hasTimestamp = true
+		}
+	}
+
+	// Hostname is only present after a timestamp, and never looks like a tag
+	if hasTimestamp && hasHostname {
+		token, rest := nextToken(line)
+		if token != "" && rest != "" && !strings.HasSuffix(token, ":") && !strings.Contains(token, "[") {
+			message.Hostname = token
+			line = rest
+		}
+	}
+
+	// TAG[PID]:
+	tagEnd := strings.IndexAny(line, "[: ")
+	if tagEnd > 0 && tagEnd <= maxTagLen && line[tagEnd] != ' ' {
+		tag := line[:tagEnd]
+		rest := line[tagEnd:]
+
+		if rest[0] == '[' {
+			pidEnd := strings.IndexByte(rest, ']')
+			if pidEnd > 1 {
+				if pid, err := strconv.Atoi(rest[1:pidEnd]); err == nil {
+					message.Fields[iomodules.CFprocessid] = pid
+				}
+				rest = rest[pidEnd+1:]
+			}
+		}
+
+		if strings.HasPrefix(rest, ":") {
+			message.Fields[iomodules.CFappname] = tag
+			line = strings.TrimPrefix(rest[1:], " ")
+		} else {
+			// Not a tag after all
+			delete(message.Fields, iomodules.CFprocessid)
+		}
+	}
+
+	message.Data = []byte(line)
+}
+
+// Returns text up to the next space and the remaining text after that space
+func nextToken(line string) (token string, rest string) {
+	end := strings.IndexByte(line, ' ')
+	if end < 0 {
+		token = line
+		return
+	}
+	token = line[:end]
+	rest = line[end+1:]
+	return
+}
+
+// Returns SD-NAME text up to the next delimiter (space, equals, closing bracket)
+func nextName(line string) (name string, rest string) {
+	end := strings.IndexAny(line, ` =]"`)
+	if end < 0 {
+		rest = line
+		return
+	}
+	name = line[:end]
+	rest = line[end:]
+	return
+}
+
+// Adds year (and timezone) to timestamps that do not have one
+func withCurrentYear(old time.Time) (new time.Time) {
+	now := time.Now()
+	new = time.Date(
+		now.Year(),
+		old.Month(),
+		old.Day(),
+		old.Hour(),
+		old.Minute(),
+		old.Second(),
+		0,
+		time.Local,
+	)
+	return
+}
+
+// Replaces empty fields with expected defaults
+func setDefaults(message *protocol.Message, sourceHost string) {
+	if message.Timestamp.IsZero() {
+		message.Timestamp = time.Now()
+	}
+	if message.Hostname == "" {
+		message.Hostname = sourceHost
+	}
+	if len(message.Data) == 0 {
+		message.Data = []byte(protocol.EmptyFieldChar)
+	}
+
+	_, ok := message.Fields[iomodules.CFappname]
+	if !ok {
+		message.Fields[iomodules.CFappname] = protocol.EmptyFieldChar
+	}
+	_, ok = message.Fields[iomodules.CFprocessid]
+	if !ok {
+		message.Fields[iomodules.CFprocessid] = os.Getpid()
+	}
+}
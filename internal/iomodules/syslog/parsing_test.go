@@ -0,0 +1,248 @@
+package syslog
+
+import (
+	"os"
+	"reflect"
+	"sdsyslog/internal/iomodules"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMessage(t *testing.T) {
+	testPid := os.Getpid()
+
+	tests := []struct {
+		name              string
+		input             string
+		sourceHost        string
+		expectedHostname  string
+		expectedData      string
+		expectedTimestamp time.Time // zero = expect now
+		expectedFields    map[string]any
+		expectedErr       bool
+	}{
+		{
+			name:              "RFC5424 full example",
+			input:             `<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su 77 ID47 - 'su root' failed for lonvick on /dev/pts/8`,
+			sourceHost:        "192.0.2.1",
+			expectedHostname:  "mymachine.example.com",
+			expectedData:      `'su root' failed for lonvick on /dev/pts/8`,
+			expectedTimestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "auth",
+				iomodules.CFseverity:  "crit",
+				iomodules.CFappname:   "su",
+				iomodules.CFprocessid: 77,
+				CFmsgID:               "ID47",
+			},
+		},
+		{
+			name:              "RFC5424 structured data with escapes and BOM",
+			input:             "<165>1 2003-10-11T22:14:15.003-07:00 host1 evntslog - ID47 [exampleSDID@32473 iut=\"3\" eventSource=\"App\\\"lication\"][origin ip=\"192.0.2.1\"] \xEF\xBB\xBFAn application event",
+			sourceHost:        "192.0.2.1",
+			expectedHostname:  "host1",
+			expectedData:      "An application event",
+			expectedTimestamp: time.Date(2003, 10, 12, 5, 14, 15, 3000000, time.UTC),
+			expectedFields: map[string]any{
+				iomodules.CFfacility:            "local4",
+				iomodules.CFseverity:            "notice",
+				iomodules.CFappname:             "evntslog",
+				iomodules.CFprocessid:           testPid,
+				CFmsgID:                         "ID47",
+				"exampleSDID@32473.iut":         "3",
+				"exampleSDID@32473.eventSource": `App"lication`,
+				"origin.ip":                     "192.0.2.1",
+			},
+		},
+		{
+			name:             "RFC5424 nil fields and no message",
+			input:            `<14>1 - - - - - -`,
+			sourceHost:       "192.0.2.5",
+			expectedHostname: "192.0.2.5",
+			expectedData:     "-",
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "user",
+				iomodules.CFseverity:  "info",
+				iomodules.CFappname:   "-",
+				iomodules.CFprocessid: testPid,
+			},
+		},
+		{
+			name:        "RFC5424 unterminated structured data",
+			input:       `<14>1 - host app - - [id key="value] message`,
+			sourceHost:  "192.0.2.5",
+			expectedErr: true,
+		},
+		{
+			name:              "RFC3164 with hostname and pid",
+			input:             `<13>Feb  5 17:32:18 router01 sshd[4711]: Accepted publickey for admin`,
+			sourceHost:        "192.0.2.9",
+			expectedHostname:  "router01",
+			expectedData:      "Accepted publickey for admin",
+			expectedTimestamp: withCurrentYear(time.Date(0, 2, 5, 17, 32, 18, 0, time.UTC)),
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "user",
+				iomodules.CFseverity:  "notice",
+				iomodules.CFappname:   "sshd",
+				iomodules.CFprocessid: 4711,
+			},
+		},
+		{
+			name:              "RFC3164 without hostname",
+			input:             `<30>Feb  5 17:32:18 dhcpd: DHCPACK on 10.0.0.2`,
+			sourceHost:        "192.0.2.9",
+			expectedHostname:  "192.0.2.9",
+			expectedData:      "DHCPACK on 10.0.0.2",
+			expectedTimestamp: withCurrentYear(time.Date(0, 2, 5, 17, 32, 18, 0, time.UTC)),
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "daemon",
+				iomodules.CFseverity:  "info",
+				iomodules.CFappname:   "dhcpd",
+				iomodules.CFprocessid: testPid,
+			},
+		},
+		{
+			name:             "RFC3164 no timestamp or tag",
+			input:            `<191>link down on port 4`,
+			sourceHost:       "192.0.2.9",
+			expectedHostname: "192.0.2.9",
+			expectedData:     "link down on port 4",
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "local7",
+				iomodules.CFseverity:  "debug",
+				iomodules.CFappname:   "-",
+				iomodules.CFprocessid: testPid,
+			},
+		},
+		{
+			name:             "missing PRI uses default priority",
+			input:            "plain text line\n",
+			sourceHost:       "192.0.2.9",
+			expectedHostname: "192.0.2.9",
+			expectedData:     "plain text line",
+			expectedFields: map[string]any{
+				iomodules.CFfacility:  "user",
+				iomodules.CFseverity:  "notice",
+				iomodules.CFappname:   "-",
+				iomodules.CFprocessid: testPid,
+			},
+		},
+		{
+			name:        "PRI out of range",
+			input:       `<192>Feb  5 17:32:18 host app: text`,
+			expectedErr: true,
+		},
+		{
+			name:        "PRI not numeric",
+			input:       `<ab>text`,
+			expectedErr: true,
+		},
+		{
+			name:        "empty",
+			input:       "\r\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := parseMessage([]byte(tt.input), tt.sourceHost)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if msg.Hostname != tt.expectedHostname {
+				t.Errorf("expected hostname %q, got %q", tt.expectedHostname, msg.Hostname)
+			}
+			if string(msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, string(msg.Data))
+			}
+			if tt.expectedTimestamp.IsZero() {
+				if time.Since(msg.Timestamp) > time.Minute {
+					t.Errorf("expected current timestamp, got %v", msg.Timestamp)
+				}
+			} else if !msg.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %v, got %v", tt.expectedTimestamp, msg.Timestamp)
+			}
+			if !reflect.DeepEqual(msg.Fields, tt.expectedFields) {
+				t.Errorf("fields mismatch:\nexpected: %#v\ngot:      %#v", tt.expectedFields, msg.Fields)
+			}
+		})
+	}
+}
+
+func TestAddStructuredField(t *testing.T) {
+	fields := map[string]any{iomodules.CFappname: "original"}
+
+	addStructuredField(fields, "short", "key", "value")
+	addStructuredField(fields, "averyveryverylongidentifier@32473", "param", "value")
+	addStructuredField(fields, "meta", "long", strings.Repeat("a", 300))
+	addStructuredField(fields, "averyveryverylongidentifier@32473", iomodules.CFappname, "overwritten")
+
+	if fields["short.key"] != "value" {
+		t.Errorf("expected namespaced key, got %#v", fields)
+	}
+	if fields["param"] != "value" {
+		t.Errorf("expected long SD-ID to fall back to parameter name, got %#v", fields)
+	}
+	truncated, _ := fields["meta.long"].(string)
+	if len(truncated) != 255 || !strings.HasSuffix(truncated, FieldTruncationSuffix) {
+		t.Errorf("expected truncated value of 255 bytes with suffix, got %d bytes", len(truncated))
+	}
+	if fields[iomodules.CFappname] != "original" {
+		t.Errorf("expected common field to be preserved, got %v", fields[iomodules.CFappname])
+	}
+}
+
+func TestParseLocalMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		expectedData    string
+		expectedAppname string
+	}{
+		{
+			name:            "libc format without hostname",
+			input:           "<14>Oct 16 12:00:00 cron[99]: job started",
+			expectedData:    "job started",
+			expectedAppname: "cron",
+		},
+		{
+			name:            "first word is not mistaken for hostname",
+			input:           "<14>Oct 16 12:00:00 hello world",
+			expectedData:    "hello world",
+			expectedAppname: "-",
+		},
+		{
+			name:            "RFC5424 hostname is replaced",
+			input:           "<14>1 2024-01-01T00:00:00Z spoofed app - - - text",
+			expectedData:    "text",
+			expectedAppname: "app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseLocalMessage([]byte(tt.input), "localhost.test")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if msg.Hostname != "localhost.test" {
+				t.Errorf("expected local hostname, got %q", msg.Hostname)
+			}
+			if string(msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, string(msg.Data))
+			}
+			if msg.Fields[iomodules.CFappname] != tt.expectedAppname {
+				t.Errorf("expected application name %q, got %v", tt.expectedAppname, msg.Fields[iomodules.CFappname])
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package syslog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"runtime/debug"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"strings"
+)
+
+// Reads datagrams from the UDP listener. Each datagram is exactly one message.
+func (mod *InModule) udpReader() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	buf := make([]byte, maxUDPMessageSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		n, remote, err := mod.udpConn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logctx.LogStdErr(ctx, "failed to read from syslog UDP listener: %w\n", err)
+			continue
+		}
+
+		msg := mod.handleMessage(buf[:n], remote.IP.String())
+		if msg == nil {
+			continue
+		}
+
+		// Non-blocking for datagrams - senders cannot be slowed down anyways
+		err = mod.outbox.Push(msg, uint64(msg.Size()))
+		if err != nil {
+			mod.metrics.Dropped.Add(1)
+			continue
+		}
+		mod.metrics.Success.Add(1)
+	}
+}
+
+// Accepts new TCP connections until shutdown
+func (mod *InModule) tcpAcceptor() {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	for {
+		conn, err := mod.tcpListener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logctx.LogStdErr(ctx, "failed to accept syslog TCP connection: %w\n", err)
+			continue
+		}
+
+		mod.tcpConnMu.Lock()
+		if ctx.Err() != nil {
+			// Shutdown raced with accept
+			mod.tcpConnMu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		mod.tcpConns[conn] = struct{}{}
+		mod.tcpConnMu.Unlock()
+
+		mod.metrics.Connections.Add(1)
+		mod.wg.Add(1)
+		go mod.tcpReader(conn)
+	}
+}
+
+// Reads framed messages from a single TCP connection
+func (mod *InModule) tcpReader(conn net.Conn) {
+	defer mod.wg.Done()
+	ctx := mod.ctx
+
+	defer func() {
+		mod.tcpConnMu.Lock()
+		delete(mod.tcpConns, conn)
+		mod.tcpConnMu.Unlock()
+		_ = conn.Close()
+	}()
+
+	// Record panics and drop connection
+	defer func() {
+		if fatalError := recover(); fatalError != nil {
+			stack := debug.Stack()
+			logctx.LogStdErr(ctx,
+				"panic in syslog TCP reader thread: %v\n%s", fatalError, stack)
+		}
+	}()
+
+	var remoteHost string
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		remoteHost = addr.IP.String()
+	}
+
+	reader := bufio.NewReaderSize(conn, 64*1024)
+	for {
+		frame, err := readFrame(reader)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logctx.LogStdErr(ctx, "closing syslog TCP connection from %s: %w\n", remoteHost, err)
+			return
+		}
+
+		msg := mod.handleMessage(frame, remoteHost)
+		if msg == nil {
+			continue
+		}
+
+		// Stream senders get back-pressure instead of drops
+		mod.outbox.PushBlocking(ctx, msg, msg.Size())
+		mod.metrics.Success.Add(1)
+	}
+}
+
+// Parses and filters a raw message. Returns nil when the message should not be forwarded.
+func (mod *InModule) handleMessage(raw []byte, remoteHost string) (msg *protocol.Message) {
+	mod.metrics.MessagesRead.Add(1)
+
+	if remoteHost == "" {
+		remoteHost = mod.localHostname
+	}
+
+	msg, err := parseMessage(raw, remoteHost)
+	if err != nil {
+		mod.metrics.ParseFailures.Add(1)
+		logctx.LogEvent(mod.ctx, logctx.VerbosityData, logctx.WarnLog,
+			"failed to parse syslog message from %s: %w\n", remoteHost, err)
+		msg = nil
+		return
+	}
+
+	msg.Fields[iomodules.CtxKey] = strings.Join(logctx.GetTagList(mod.ctx), "/")
+	if remoteHost != mod.localHostname {
+		msg.Fields[CFsourceIP] = remoteHost
+	}
+
+	for _, filter := range mod.filters {
+		if filter.Match(msg) {
+			// First filter match wins - drop message
+			msg = nil
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package syslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/iomodules"
+)
+
+const OutputType string = "syslog"
+
+func init() {
+	iomodules.RegisterOutput(OutputType, newOutputFromConfig)
+}
+
+// Creates syslog forwarding output from its JSON configuration block
+func newOutputFromConfig(config json.RawMessage) (module iomodules.Output, err error) {
+	var options OutputConfig
+	err = iomodules.DecodeOutputConfig(config, &options)
+	if err != nil {
+		return
+	}
+	if options.Address == "" {
+		err = fmt.Errorf("syslog output requires an address")
+		return
+	}
+
+	module, err = NewOutput(options)
+	return
+}
@@ -0,0 +1,63 @@
+package syslog
+
+// Starts listeners in background
+func (mod *InModule) Start() (err error) {
+	if mod.udpConn != nil {
+		mod.wg.Add(1)
+		go mod.udpReader()
+	}
+	if mod.tcpListener != nil {
+		mod.wg.Add(1)
+		go mod.tcpAcceptor()
+	}
+	return
+}
+
+// Gracefully stops module
+func (mod *InModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+
+	// Unblock readers
+	if mod.udpConn != nil {
+		lerr := mod.udpConn.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	if mod.tcpListener != nil {
+		lerr := mod.tcpListener.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	mod.tcpConnMu.Lock()
+	for conn := range mod.tcpConns {
+		_ = conn.Close()
+	}
+	mod.tcpConnMu.Unlock()
+
+	mod.wg.Wait()
+	return
+}
+
+// Closes connection to remote server
+func (mod *OutModule) Shutdown() (err error) {
+	if mod == nil {
+		return
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	if mod.conn != nil {
+		err = mod.conn.Close()
+		mod.conn = nil
+	}
+	return
+}
@@ -1,5 +1,15 @@
 package syslog
 
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"time"
+)
+
 type LogFacility struct {
 	FacilityToCode map[string]uint16
 	CodeToFacility map[uint16]string
@@ -9,3 +19,50 @@ type LogSeverity struct {
 	SeverityToCode map[string]uint16
 	CodeToSeverity map[uint16]string
 }
+
+type InModule struct {
+	// Settings
+	filters       []protocol.MessageFilter
+	localHostname string
+
+	// Inputs
+	udpConn     *net.UDPConn
+	tcpListener net.Listener
+	tcpConnMu   sync.Mutex
+	tcpConns    map[net.Conn]struct{}
+
+	// Output
+	outbox *mpmc.Queue[*protocol.Message]
+
+	metrics MetricStorage
+
+	wg     sync.WaitGroup     // Waiter for instance
+	cancel context.CancelFunc // cancel instance
+	ctx    context.Context
+}
+
+// Settings for forwarding messages to a remote syslog server
+type OutputConfig struct {
+	Address         string `json:"address"`                   // Remote server host:port
+	Transport       string `json:"transport,omitempty"`       // udp (default), tcp, or tls
+	CAFile          string `json:"caFile,omitempty"`          // PEM CA bundle used to verify the server (tls only, defaults to system roots)
+	CertFile        string `json:"certFile,omitempty"`        // PEM client certificate (tls only, optional)
+	KeyFile         string `json:"keyFile,omitempty"`         // PEM client private key (tls only, required with CertFile)
+	ServerName      string `json:"serverName,omitempty"`      // Expected server certificate name (tls only, defaults to address host)
+	MaxSendAttempts int    `json:"maxSendAttempts,omitempty"` // Attempts (with reconnects) before a message is dropped
+}
+
+type OutModule struct {
+	mu   sync.Mutex
+	conn net.Conn
+
+	// Reconnect backoff
+	retryInterval time.Duration
+	nextDial      time.Time
+
+	// Config
+	address        string
+	transport      string
+	tlsConfig      *tls.Config
+	maxSendRetries int
+}
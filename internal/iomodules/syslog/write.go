@@ -0,0 +1,175 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sdsyslog/pkg/protocol"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Forwards log message to configured syslog server as RFC5424
+func (mod *OutModule) Write(ctx context.Context, msg *protocol.Payload) (logsSent int, err error) {
+	if mod == nil {
+		return
+	}
+
+	frame := mod.frame(formatRFC5424(msg))
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	for range mod.maxSendRetries {
+		if mod.conn != nil && mod.peerClosed() {
+			_ = mod.conn.Close()
+			mod.conn = nil
+		}
+		if mod.conn == nil {
+			err = mod.reconnect()
+			if err != nil {
+				return
+			}
+		}
+
+		err = mod.conn.SetWriteDeadline(time.Now().Add(outputTimeout))
+		if err == nil {
+			_, err = mod.conn.Write(frame)
+		}
+		if err == nil {
+			logsSent = 1
+			return
+		}
+
+		// Stream may hold a partial frame, always start over on a new connection
+		_ = mod.conn.Close()
+		mod.conn = nil
+	}
+	err = fmt.Errorf("failed to send to syslog server after %d attempts: %w", mod.maxSendRetries, err)
+	return
+}
+
+// No-op - satisfies common type
+func (mod *OutModule) FlushBuffer() (flushedCnt int, err error) {
+	return
+}
+
+// Adds transport framing to message. Datagrams carry a single message, streams use octet counting.
+func (mod *OutModule) frame(line []byte) (frame []byte) {
+	if mod.transport == TransportUDP {
+		frame = line
+		if len(frame) > maxUDPOutputSize {
+			frame = frame[:maxUDPOutputSize]
+		}
+		return
+	}
+
+	frame = make([]byte, 0, maxOctetCountChars+1+len(line))
+	frame = strconv.AppendInt(frame, int64(len(line)), 10)
+	frame = append(frame, ' ')
+	frame = append(frame, line...)
+	return
+}
+
+// Re-opens connection to server, backing off after failed attempts so an unreachable server does not stall every write
+func (mod *OutModule) reconnect() (err error) {
+	if time.Now().Before(mod.nextDial) {
+		err = fmt.Errorf("syslog server unreachable, next reconnect in %s", time.Until(mod.nextDial).Round(time.Millisecond))
+		return
+	}
+
+	mod.conn, err = mod.dial()
+	if err != nil {
+		mod.retryInterval = min(max(mod.retryInterval*2, minReconnectInterval), maxReconnectInterval)
+		mod.nextDial = time.Now().Add(mod.retryInterval)
+		err = fmt.Errorf("failed re-connection to syslog server: %w", err)
+		return
+	}
+	mod.retryInterval = 0
+	return
+}
+
+// Detects streams the server has closed, which would otherwise silently accept (and lose) the next write
+func (mod *OutModule) peerClosed() (closed bool) {
+	if mod.transport == TransportUDP {
+		return
+	}
+
+	pending, closed := mod.peekSocket()
+	if closed || !pending {
+		return
+	}
+
+	// Pending bytes may be TLS records (like TLS 1.3 session tickets) that never surface as data, so read them
+	// through the connection itself. A close notify or EOF returns an error, anything else sent is discarded.
+	var probe [64]byte
+	err := mod.conn.SetReadDeadline(time.Now().Add(peerProbeTimeout))
+	if err != nil {
+		closed = true
+		return
+	}
+	for {
+		_, err = mod.conn.Read(probe[:])
+		if err != nil {
+			break
+		}
+	}
+	var netErr net.Error
+	closed = !errors.As(err, &netErr) || !netErr.Timeout()
+	if !closed {
+		err = mod.conn.SetReadDeadline(time.Time{})
+		closed = err != nil
+	}
+	return
+}
+
+// Checks the underlying socket without consuming anything. Closed is set on EOF or socket errors.
+func (mod *OutModule) peekSocket() (pending, closed bool) {
+	conn := mod.conn
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	sysConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return
+	}
+	rawConn, err := sysConn.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	var probe [1]byte
+	ctrlErr := rawConn.Control(func(fd uintptr) {
+		n, _, err := unix.Recvfrom(int(fd), probe[:], unix.MSG_PEEK|unix.MSG_DONTWAIT)
+		if err != nil {
+			closed = !errors.Is(err, unix.EAGAIN)
+			return
+		}
+		pending = n > 0
+		closed = n == 0
+	})
+	if ctrlErr != nil {
+		closed = true
+	}
+	return
+}
+
+// Opens new connection using configured transport
+func (mod *OutModule) dial() (conn net.Conn, err error) {
+	dialer := &net.Dialer{Timeout: outputTimeout}
+
+	switch mod.transport {
+	case TransportTLS:
+		conn, err = tls.DialWithDialer(dialer, "tcp", mod.address, mod.tlsConfig)
+	case TransportTCP:
+		conn, err = dialer.Dial("tcp", mod.address)
+	default:
+		conn, err = dialer.Dial("udp", mod.address)
+	}
+	return
+}
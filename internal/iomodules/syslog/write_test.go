@@ -0,0 +1,168 @@
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"sdsyslog/internal/tests/utils"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutputReconnect(t *testing.T) {
+	certFile, keyFile, _ := utils.WriteTestCertificate(t)
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("failed to load certificate: %v", err)
+	}
+
+	tests := []struct {
+		transport string
+		caFile    string
+	}{
+		{transport: TransportTCP},
+		{transport: TransportTLS, caFile: certFile}, // Session tickets stay unread in the socket
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			if tt.transport == TransportTLS {
+				listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{certificate}})
+			}
+			defer listener.Close()
+
+			frames := make(chan string, 4)
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					reader := bufio.NewReader(conn)
+					frame, err := readFrame(reader)
+
+					// Server drops every connection after one message
+					_ = conn.Close()
+					if err == nil {
+						frames <- string(frame)
+					}
+				}
+			}()
+
+			module, err := NewOutput(OutputConfig{
+				Address:   listener.Addr().String(),
+				Transport: tt.transport,
+				CAFile:    tt.caFile,
+			})
+			if err != nil {
+				t.Fatalf("failed to create output: %v", err)
+			}
+			defer module.Shutdown()
+
+			for _, text := range []string{"first", "second"} {
+				msg := &protocol.Payload{Hostname: "host", Data: []byte(text)}
+
+				sent, err := module.Write(context.Background(), msg)
+				if err != nil {
+					t.Fatalf("unexpected write error: %v", err)
+				}
+				if sent != 1 {
+					t.Fatalf("expected 1 message sent, got %d", sent)
+				}
+
+				select {
+				case frame := <-frames:
+					if !strings.HasSuffix(frame, text) {
+						t.Errorf("expected frame ending with %q, got %q", text, frame)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("server did not receive message %q", text)
+				}
+			}
+		})
+	}
+}
+
+func TestOutputStartsWithoutServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	module, err := NewOutput(OutputConfig{
+		Address:         address,
+		Transport:       TransportTCP,
+		MaxSendAttempts: 1,
+	})
+	if err != nil {
+		t.Fatalf("expected output to start without a reachable server, got %v", err)
+	}
+	defer module.Shutdown()
+
+	_, err = module.Write(context.Background(), &protocol.Payload{Hostname: "host", Data: []byte("lost")})
+	if err == nil {
+		t.Fatalf("expected write error while server is unreachable")
+	}
+}
+
+func TestNewOutputValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      OutputConfig
+		expectedErr string
+	}{
+		{
+			name:   "disabled",
+			config: OutputConfig{},
+		},
+		{
+			name:        "missing port",
+			config:      OutputConfig{Address: "localhost"},
+			expectedErr: "invalid syslog server address",
+		},
+		{
+			name:        "unknown transport",
+			config:      OutputConfig{Address: "localhost:514", Transport: "sctp"},
+			expectedErr: "unknown syslog transport",
+		},
+		{
+			name:        "tls settings without tls transport",
+			config:      OutputConfig{Address: "localhost:514", CAFile: "/tmp/ca.pem"},
+			expectedErr: "TLS settings require transport",
+		},
+		{
+			name:        "client certificate without key",
+			config:      OutputConfig{Address: "localhost:6514", Transport: TransportTLS, CertFile: "/tmp/cert.pem"},
+			expectedErr: "requires both a certificate and key file",
+		},
+		{
+			name:   "udp",
+			config: OutputConfig{Address: "127.0.0.1:514"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module, err := NewOutput(tt.config)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = module.Shutdown()
+		})
+	}
+}
@@ -11,6 +11,7 @@ const (
 	ReadyMessage             string        = "READY"
 	EnvNameReadinessFD       string        = "READY_FD"
 	EnvNameSelfUpdate        string        = "UPDATING_CHILD_PID"
+	EnvNameInheritedFDs      string        = "INHERITED_FDS" // JSON object of preserved file names to descriptor numbers
 
 	FullUpdateSignal       syscall.Signal = syscall.SIGHUP
 	SigningKeyReloadSignal syscall.Signal = syscall.SIGUSR1
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// Files (usually listening sockets) that must stay open across the execve of a self update.
+// Keyed by a caller chosen unique name (e.g. socket type and path).
+var preserved = struct {
+	sync.Mutex
+	loaded bool
+	files  map[string]*os.File
+}{
+	files: make(map[string]*os.File),
+}
+
+// Registers file to be handed to the replacement process on self update.
+// Registry takes ownership of the file, callers should use their own duplicate of the descriptor.
+func PreserveFile(name string, file *os.File) {
+	preserved.Lock()
+	defer preserved.Unlock()
+	loadInheritedFiles()
+
+	old, exists := preserved.files[name]
+	if exists && old != file {
+		_ = old.Close()
+	}
+	preserved.files[name] = file
+}
+
+// Retrieves a preserved file by name, either registered earlier in this process or inherited from the previous process.
+// Returns nil if no file by that name exists.
+func PreservedFile(name string) (file *os.File) {
+	preserved.Lock()
+	defer preserved.Unlock()
+	loadInheritedFiles()
+
+	file = preserved.files[name]
+	return
+}
+
+// Loads files inherited from previous process by environment variable (once per process).
+// Caller must hold the registry lock.
+func loadInheritedFiles() {
+	if preserved.loaded {
+		return
+	}
+	preserved.loaded = true
+
+	encoded := os.Getenv(EnvNameInheritedFDs)
+	if encoded == "" {
+		return
+	}
+	_ = os.Unsetenv(EnvNameInheritedFDs)
+
+	var fdList map[string]int
+	err := json.Unmarshal([]byte(encoded), &fdList)
+	if err != nil {
+		return
+	}
+
+	for name, fd := range fdList {
+		// Only take descriptors that are actually open
+		_, err = unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0)
+		if err != nil {
+			continue
+		}
+
+		// Never let these leak into unrelated child processes
+		unix.CloseOnExec(fd)
+		preserved.files[name] = os.NewFile(uintptr(fd), name)
+	}
+}
+
+// Prepares all preserved files for inheritance by the replacement process.
+// Returns the environment variable entry describing the descriptors (empty if none).
+func inheritableFilesEnv() (envEntry string, err error) {
+	preserved.Lock()
+	defer preserved.Unlock()
+
+	if len(preserved.files) == 0 {
+		return
+	}
+
+	fdList := make(map[string]int, len(preserved.files))
+	for name, file := range preserved.files {
+		var fd int
+		fd, err = rawFD(file)
+		if err != nil {
+			err = fmt.Errorf("failed to retrieve descriptor of preserved file %q: %w", name, err)
+			return
+		}
+
+		// Clear close-on-exec
+		_, err = unix.FcntlInt(uintptr(fd), unix.F_SETFD, 0)
+		if err != nil {
+			err = fmt.Errorf("failed to mark preserved file %q as inheritable: %w", name, err)
+			return
+		}
+		fdList[name] = fd
+	}
+
+	encoded, err := json.Marshal(fdList)
+	if err != nil {
+		err = fmt.Errorf("failed to encode preserved file list: %w", err)
+		return
+	}
+	envEntry = EnvNameInheritedFDs + "=" + string(encoded)
+	return
+}
+
+// Reverts inheritance preparation after a failed execve so descriptors do not leak into other child processes.
+func resetInheritableFiles() {
+	preserved.Lock()
+	defer preserved.Unlock()
+
+	for _, file := range preserved.files {
+		fd, err := rawFD(file)
+		if err != nil {
+			continue
+		}
+		unix.CloseOnExec(fd)
+	}
+}
+
+// Retrieves underlying descriptor without switching it to blocking mode (unlike os.File.Fd).
+// Blocking mode is shared with every duplicate of the descriptor, including ones in use by the net poller.
+func rawFD(file *os.File) (fd int, err error) {
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		return
+	}
+	err = rawConn.Control(func(sysFD uintptr) {
+		fd = int(sysFD)
+	})
+	return
+}
@@ -0,0 +1,90 @@
+package lifecycle
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPreservedFileInheritance(t *testing.T) {
+	// Isolate registry
+	resetRegistry := func() {
+		preserved.Lock()
+		preserved.loaded = true
+		preserved.files = make(map[string]*os.File)
+		preserved.Unlock()
+	}
+	resetRegistry()
+	t.Cleanup(resetRegistry)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error creating pipe: %v", err)
+	}
+	defer writer.Close()
+
+	PreserveFile("test:pipe", reader)
+	if PreservedFile("test:pipe") != reader {
+		t.Fatalf("expected registered file to be returned")
+	}
+	if PreservedFile("test:missing") != nil {
+		t.Fatalf("expected nil for unknown name")
+	}
+
+	envEntry, err := inheritableFilesEnv()
+	if err != nil {
+		t.Fatalf("unexpected error preparing inheritance: %v", err)
+	}
+	if !strings.HasPrefix(envEntry, EnvNameInheritedFDs+"=") {
+		t.Fatalf("unexpected environment entry %q", envEntry)
+	}
+
+	fd, err := rawFD(reader)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving descriptor: %v", err)
+	}
+	flags, err := unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reading descriptor flags: %v", err)
+	}
+	if flags&unix.FD_CLOEXEC != 0 {
+		t.Errorf("expected close-on-exec to be cleared before exec")
+	}
+
+	resetInheritableFiles()
+	flags, err = unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reading descriptor flags: %v", err)
+	}
+	if flags&unix.FD_CLOEXEC == 0 {
+		t.Errorf("expected close-on-exec to be restored after failed exec")
+	}
+
+	// Simulate new process reading the environment
+	preserved.Lock()
+	preserved.loaded = false
+	preserved.files = make(map[string]*os.File)
+	preserved.Unlock()
+	t.Setenv(EnvNameInheritedFDs, strings.TrimPrefix(envEntry, EnvNameInheritedFDs+"="))
+
+	inherited := PreservedFile("test:pipe")
+	if inherited == nil {
+		t.Fatalf("expected file to be inherited from environment")
+	}
+	if _, set := os.LookupEnv(EnvNameInheritedFDs); set {
+		t.Errorf("expected environment variable to be cleared after loading")
+	}
+
+	_, err = writer.Write([]byte("x"))
+	if err != nil {
+		t.Fatalf("unexpected error writing pipe: %v", err)
+	}
+	buf := make([]byte, 1)
+	_, err = inherited.Read(buf)
+	if err != nil || buf[0] != 'x' {
+		t.Errorf("expected inherited descriptor to be usable, got %q err=%v", buf, err)
+	}
+	_ = inherited.Close()
+}
@@ -160,9 +160,20 @@ func updateAndExit(childPID int) (err error) {
 		env = append(env, EnvNameSelfUpdate+"="+strconv.Itoa(childPID))
 	}
 
+	// Hand over any preserved sockets to the new process
+	inheritEnv, err := inheritableFilesEnv()
+	if err != nil {
+		resetInheritableFiles()
+		return
+	}
+	if inheritEnv != "" {
+		env = append(env, inheritEnv)
+	}
+
 	// Will not return. Call below terminates this execution immediately if no error.
 	err = syscallExec(argv[0], argv, env)
 	if err != nil {
+		resetInheritableFiles()
 		return
 	}
 	// Should never get here
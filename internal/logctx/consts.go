@@ -39,9 +39,15 @@ const (
 	NSAssm            string = "Assembler"
 	NSOut             string = "Output"
 	NSQueue           string = "Queue"
+	NSSpill           string = "Spill"
 	NSListen          string = "Listener"
 	NSWorker          string = "Worker"
 	NSWatcher         string = "Watcher"
+	NSKey             string = "Key"
+	NSRoute           string = "Route"
+	NSTransform       string = "Transform"
+	NSRateLimit       string = "RateLimit"
+	NSDedup           string = "Dedup"
 	NSmIngest         string = "Ingest"
 	NSmInput          string = "In"
 	NSmOutput         string = "Out"
@@ -52,6 +58,11 @@ const (
 	NSoStdIn          string = "Stdin"
 	NSoJrnl           string = "Journal"
 	NSoRaw            string = "Raw"
+	NSoSyslog         string = "Syslog"
+	NSoDevLog         string = "DevLog"
+	NSoBeats          string = "Beats"
+	NSoKmsg           string = "Kmsg"
+	NSoAudit          string = "Audit"
 
 	// Deduplication
 	dedupWindow      = 5 * time.Second
@@ -0,0 +1,18 @@
+package spill
+
+import "errors"
+
+const (
+	DefaultMaxBytes     uint64 = 1024 * 1024 * 1024 // 1GiB
+	DefaultSegmentBytes uint64 = 16 * 1024 * 1024   // 16MiB
+
+	segmentExtension string = ".seg"
+	cursorFileName   string = "cursor"
+	frontFileName    string = "front" // Requeued records, replayed before all segments
+	recordHeaderLen  int    = 8       // length(4) + crc32(4)
+)
+
+var (
+	ErrFull   = errors.New("spill queue is full")
+	ErrClosed = errors.New("spill queue is closed")
+)
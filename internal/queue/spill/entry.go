@@ -0,0 +1,377 @@
+package spill
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sdsyslog/internal/atomics"
+	"sdsyslog/internal/logctx"
+)
+
+// Opens (or creates) a spill queue in dir. Existing unread segments from previous runs are kept for replay.
+// Zero sizes use defaults.
+func Open(namespace []string, dir string, maxBytes uint64, segmentBytes uint64) (new *Queue, err error) {
+	if dir == "" {
+		err = fmt.Errorf("empty spill directory")
+		return
+	}
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if segmentBytes == 0 {
+		segmentBytes = DefaultSegmentBytes
+	}
+	if segmentBytes > maxBytes {
+		segmentBytes = maxBytes
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		err = fmt.Errorf("failed to create spill directory: %w", err)
+		return
+	}
+
+	new = &Queue{
+		Namespace: append(namespace, logctx.NSQueue, logctx.NSSpill),
+		dir:       dir,
+		maxBytes:  maxBytes,
+		segSize:   segmentBytes,
+		Metrics:   &MetricStorage{},
+	}
+
+	new.segments, err = listSegments(dir)
+	if err != nil {
+		err = fmt.Errorf("failed to list spill segments: %w", err)
+		return
+	}
+
+	cursorID, cursorOffset, err := loadCursor(dir)
+	if err != nil {
+		return
+	}
+
+	// Remove segments already fully replayed
+	var remaining []uint64
+	for _, id := range new.segments {
+		if id < cursorID {
+			_ = os.Remove(new.segmentPath(id))
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	new.segments = remaining
+
+	// Recover accounting for unread data
+	for _, id := range new.segments {
+		var offset uint64
+		if id == cursorID {
+			offset = cursorOffset
+			new.cursorOffset = cursorOffset
+		}
+		records, bytes, lerr := scanSegment(new.segmentPath(id), offset, new.maxBytes)
+		if lerr != nil {
+			err = fmt.Errorf("failed to scan spill segment %d: %w", id, lerr)
+			return
+		}
+		new.Metrics.Depth.Add(records)
+		new.Metrics.Bytes.Add(bytes)
+	}
+	new.Metrics.Segments.Store(uint64(len(new.segments)))
+
+	// Requeued records from previous run are kept on disk until the next Close
+	front, corrupted, err := loadFront(dir, new.maxBytes)
+	if err != nil {
+		err = fmt.Errorf("failed to load requeued spill records: %w", err)
+		return
+	}
+	if corrupted {
+		new.Metrics.Corrupted.Add(1)
+	}
+	for _, data := range front {
+		new.Metrics.Depth.Add(1)
+		new.Metrics.Bytes.Add(uint64(recordHeaderLen + len(data)))
+	}
+	new.front = front
+
+	// Never append to segments from a previous run (may have a torn tail)
+	if len(new.segments) > 0 {
+		new.writeID = new.segments[len(new.segments)-1] + 1
+	} else {
+		new.writeID = cursorID + 1
+	}
+	return
+}
+
+// Number of unread records
+func (queue *Queue) Len() (depth uint64) {
+	depth = queue.Metrics.Depth.Load()
+	return
+}
+
+// Appends a record to the queue. Returns ErrFull if the record would exceed the size bound.
+func (queue *Queue) Push(data []byte) (err error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if queue.closed {
+		err = ErrClosed
+		return
+	}
+
+	recordLen := uint64(recordHeaderLen + len(data))
+	if queue.Metrics.Bytes.Load()+recordLen > queue.maxBytes {
+		queue.Metrics.Dropped.Add(1)
+		err = ErrFull
+		return
+	}
+
+	// Rotate to new segment
+	if queue.writer == nil || (queue.writeBytes > 0 && queue.writeBytes+recordLen > queue.segSize) {
+		err = queue.rotateWriter()
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = queue.writer.Write(encodeRecord(data))
+	if err != nil {
+		err = fmt.Errorf("failed to write spill record: %w", err)
+		return
+	}
+
+	queue.writeBytes += recordLen
+	queue.Metrics.Depth.Add(1)
+	queue.Metrics.Bytes.Add(recordLen)
+	queue.Metrics.SpilledBytes.Add(uint64(len(data)))
+	return
+}
+
+// Returns a record to the head of the queue, ahead of everything else (like a popped record that could not be sent).
+// Never refused for size, the record was already accounted for before it was removed.
+func (queue *Queue) PushFront(data []byte) (err error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if queue.closed {
+		err = ErrClosed
+		return
+	}
+
+	queue.front = append([][]byte{data}, queue.front...)
+	queue.Metrics.Depth.Add(1)
+	queue.Metrics.Bytes.Add(uint64(recordHeaderLen + len(data)))
+	return
+}
+
+// Removes and returns the oldest record. Returns false when the queue is empty.
+func (queue *Queue) Pop() (data []byte, ok bool, err error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if queue.closed {
+		err = ErrClosed
+		return
+	}
+
+	if len(queue.front) > 0 {
+		data = queue.front[0]
+		queue.front = queue.front[1:]
+		atomics.Subtract(&queue.Metrics.Depth, 1, 4)
+		atomics.Subtract(&queue.Metrics.Bytes, uint64(recordHeaderLen+len(data)), 4)
+		ok = true
+		return
+	}
+
+	for len(queue.segments) > 0 {
+		if queue.reader == nil {
+			err = queue.openReader()
+			if err != nil {
+				return
+			}
+		}
+
+		var readErr error
+		data, readErr = readRecord(queue.readBuf, queue.maxBytes)
+		if readErr == nil {
+			recordLen := uint64(recordHeaderLen + len(data))
+			queue.readOffset += recordLen
+			atomics.Subtract(&queue.Metrics.Depth, 1, 4)
+			atomics.Subtract(&queue.Metrics.Bytes, recordLen, 4)
+			queue.Metrics.ReplayedBytes.Add(uint64(len(data)))
+			ok = true
+			return
+		}
+
+		// Reached end of the segment still being written - nothing more to read yet.
+		// Reader stays open, records are always appended whole under lock so the next read continues cleanly.
+		if queue.readID == queue.writeID && queue.writer != nil {
+			if readErr != io.EOF {
+				err = fmt.Errorf("failed reading active spill segment: %w", readErr)
+			}
+			data = nil
+			return
+		}
+
+		if readErr != io.EOF {
+			// Torn write from a crash or corruption, remainder of segment is unusable
+			queue.Metrics.Corrupted.Add(1)
+			queue.dropRemainingAccounting()
+		}
+
+		// Segment fully consumed
+		err = queue.removeHeadSegment()
+		if err != nil {
+			return
+		}
+	}
+
+	data = nil
+	return
+}
+
+// Persists read position and closes all files. Unread records remain for the next Open.
+func (queue *Queue) Close() (err error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if queue.closed {
+		return
+	}
+	queue.closed = true
+
+	if queue.writer != nil {
+		lerr := queue.writer.Sync()
+		if lerr != nil {
+			err = lerr
+		}
+		lerr = queue.writer.Close()
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		queue.writer = nil
+	}
+
+	lerr := saveFront(queue.dir, queue.front)
+	if lerr != nil && err == nil {
+		err = fmt.Errorf("failed to save requeued spill records: %w", lerr)
+	}
+
+	if queue.reader != nil {
+		_ = queue.reader.Close()
+	}
+	if len(queue.segments) > 0 {
+		headID := queue.segments[0]
+		offset := queue.cursorOffset
+		if queue.reader != nil {
+			offset = queue.readOffset
+		}
+		if queue.Metrics.Depth.Load() == uint64(len(queue.front)) {
+			// Everything replayed, nothing to keep
+			for _, id := range queue.segments {
+				_ = os.Remove(queue.segmentPath(id))
+			}
+			headID = queue.writeID + 1
+			offset = 0
+		}
+		lerr := saveCursor(queue.dir, headID, offset)
+		if lerr != nil && err == nil {
+			err = fmt.Errorf("failed to save spill cursor: %w", lerr)
+		}
+	}
+	queue.reader = nil
+	return
+}
+
+// Closes current write segment and starts the next one.
+// Caller must hold lock.
+func (queue *Queue) rotateWriter() (err error) {
+	if queue.writer != nil {
+		err = queue.writer.Sync()
+		if err != nil {
+			err = fmt.Errorf("failed to sync spill segment: %w", err)
+			return
+		}
+		err = queue.writer.Close()
+		if err != nil {
+			err = fmt.Errorf("failed to close spill segment: %w", err)
+			return
+		}
+		queue.writer = nil
+		queue.writeID++
+	}
+
+	queue.writer, err = os.OpenFile(queue.segmentPath(queue.writeID), os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, 0600)
+	if err != nil {
+		err = fmt.Errorf("failed to create spill segment: %w", err)
+		return
+	}
+	queue.writeBytes = 0
+	queue.segments = append(queue.segments, queue.writeID)
+	queue.Metrics.Segments.Store(uint64(len(queue.segments)))
+	return
+}
+
+// Opens oldest segment for reading at the resume position.
+// Caller must hold lock.
+func (queue *Queue) openReader() (err error) {
+	queue.readID = queue.segments[0]
+	queue.reader, err = os.Open(queue.segmentPath(queue.readID))
+	if err != nil {
+		err = fmt.Errorf("failed to open spill segment for reading: %w", err)
+		return
+	}
+
+	queue.readOffset = queue.cursorOffset
+	queue.cursorOffset = 0
+	_, err = queue.reader.Seek(int64(queue.readOffset), io.SeekStart)
+	if err != nil {
+		err = fmt.Errorf("failed to seek spill segment: %w", err)
+		return
+	}
+	queue.readBuf = bufio.NewReader(queue.reader)
+	return
+}
+
+// Removes accounting of all records after the current read position in the head segment.
+// Caller must hold lock.
+func (queue *Queue) dropRemainingAccounting() {
+	records, bytes, err := scanSegment(queue.segmentPath(queue.readID), queue.readOffset, queue.maxBytes)
+	if err != nil {
+		return
+	}
+	atomics.Subtract(&queue.Metrics.Depth, records, 4)
+	atomics.Subtract(&queue.Metrics.Bytes, bytes, 4)
+}
+
+// Deletes fully read oldest segment and persists the new read position.
+// Caller must hold lock.
+func (queue *Queue) removeHeadSegment() (err error) {
+	if queue.reader != nil {
+		_ = queue.reader.Close()
+	}
+	queue.reader = nil
+	queue.readBuf = nil
+
+	err = os.Remove(queue.segmentPath(queue.readID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("failed to remove replayed spill segment: %w", err)
+		return
+	}
+	err = nil
+
+	queue.segments = queue.segments[1:]
+	queue.Metrics.Segments.Store(uint64(len(queue.segments)))
+
+	nextID := queue.readID + 1
+	if len(queue.segments) > 0 {
+		nextID = queue.segments[0]
+	}
+	err = saveCursor(queue.dir, nextID, 0)
+	if err != nil {
+		err = fmt.Errorf("failed to save spill cursor: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,289 @@
+package spill
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQueueOrderAndRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	queue, err := Open(nil, dir, 1024*1024, 64)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	defer queue.Close()
+
+	const total = 50
+	for i := range total {
+		err = queue.Push([]byte(fmt.Sprintf("record-%03d", i)))
+		if err != nil {
+			t.Fatalf("unexpected error pushing record %d: %v", i, err)
+		}
+
+		// Interleave reads with writes on the active segment
+		if i%10 == 0 {
+			data, ok, err := queue.Pop()
+			if err != nil || !ok {
+				t.Fatalf("unexpected pop failure: ok=%v err=%v", ok, err)
+			}
+			expected := fmt.Sprintf("record-%03d", i/10)
+			if string(data) != expected {
+				t.Fatalf("expected %q, got %q", expected, data)
+			}
+		}
+	}
+
+	if queue.Metrics.Segments.Load() < 2 {
+		t.Errorf("expected multiple segments with small segment size, got %d", queue.Metrics.Segments.Load())
+	}
+
+	for i := 5; i < total; i++ {
+		data, ok, err := queue.Pop()
+		if err != nil || !ok {
+			t.Fatalf("unexpected pop failure at %d: ok=%v err=%v", i, ok, err)
+		}
+		expected := fmt.Sprintf("record-%03d", i)
+		if string(data) != expected {
+			t.Fatalf("expected %q, got %q", expected, data)
+		}
+	}
+
+	_, ok, err := queue.Pop()
+	if err != nil || ok {
+		t.Fatalf("expected empty queue, got ok=%v err=%v", ok, err)
+	}
+	if queue.Len() != 0 || queue.Metrics.Bytes.Load() != 0 {
+		t.Errorf("expected zero depth and bytes, got %d and %d", queue.Len(), queue.Metrics.Bytes.Load())
+	}
+	if queue.Metrics.Segments.Load() != 1 {
+		t.Errorf("expected only active segment to remain, got %d", queue.Metrics.Segments.Load())
+	}
+}
+
+func TestQueuePersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	queue, err := Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	for i := range 20 {
+		err = queue.Push([]byte(fmt.Sprintf("record-%03d", i)))
+		if err != nil {
+			t.Fatalf("unexpected error pushing: %v", err)
+		}
+	}
+	for i := range 7 {
+		data, ok, err := queue.Pop()
+		if err != nil || !ok || string(data) != fmt.Sprintf("record-%03d", i) {
+			t.Fatalf("unexpected pop result %q ok=%v err=%v", data, ok, err)
+		}
+	}
+	err = queue.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing queue: %v", err)
+	}
+
+	// Reopen resumes after last read record
+	queue, err = Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reopening queue: %v", err)
+	}
+	if queue.Len() != 13 {
+		t.Fatalf("expected 13 records after reopen, got %d", queue.Len())
+	}
+	err = queue.Push([]byte("after-reopen"))
+	if err != nil {
+		t.Fatalf("unexpected error pushing: %v", err)
+	}
+
+	for i := 7; i < 20; i++ {
+		data, ok, err := queue.Pop()
+		if err != nil || !ok || string(data) != fmt.Sprintf("record-%03d", i) {
+			t.Fatalf("unexpected pop result at %d: %q ok=%v err=%v", i, data, ok, err)
+		}
+	}
+	data, ok, err := queue.Pop()
+	if err != nil || !ok || string(data) != "after-reopen" {
+		t.Fatalf("unexpected pop result %q ok=%v err=%v", data, ok, err)
+	}
+
+	// Fully drained queue leaves no segments behind
+	err = queue.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing queue: %v", err)
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		t.Fatalf("unexpected error listing segments: %v", err)
+	}
+	if len(segments) != 0 {
+		t.Errorf("expected no segments after full drain, got %v", segments)
+	}
+
+	queue, err = Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reopening queue: %v", err)
+	}
+	defer queue.Close()
+	if queue.Len() != 0 {
+		t.Errorf("expected empty queue, got %d", queue.Len())
+	}
+}
+
+func TestQueuePushFront(t *testing.T) {
+	dir := t.TempDir()
+
+	queue, err := Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	for i := range 5 {
+		err = queue.Push([]byte(fmt.Sprintf("record-%03d", i)))
+		if err != nil {
+			t.Fatalf("unexpected error pushing: %v", err)
+		}
+	}
+
+	// Popped record that could not be sent goes back ahead of the rest
+	data, ok, err := queue.Pop()
+	if err != nil || !ok || string(data) != "record-000" {
+		t.Fatalf("unexpected pop result %q ok=%v err=%v", data, ok, err)
+	}
+	err = queue.PushFront(data)
+	if err != nil {
+		t.Fatalf("unexpected error requeueing: %v", err)
+	}
+	if queue.Len() != 5 {
+		t.Fatalf("expected 5 records after requeue, got %d", queue.Len())
+	}
+	err = queue.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing queue: %v", err)
+	}
+
+	// Requeued record survives restart and is replayed first
+	queue, err = Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reopening queue: %v", err)
+	}
+	if queue.Len() != 5 {
+		t.Fatalf("expected 5 records after reopen, got %d", queue.Len())
+	}
+	for i := range 5 {
+		data, ok, err := queue.Pop()
+		if err != nil || !ok || string(data) != fmt.Sprintf("record-%03d", i) {
+			t.Fatalf("unexpected pop result at %d: %q ok=%v err=%v", i, data, ok, err)
+		}
+	}
+	err = queue.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing queue: %v", err)
+	}
+
+	// Fully drained queue leaves nothing to replay
+	queue, err = Open(nil, dir, 1024*1024, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reopening queue: %v", err)
+	}
+	defer queue.Close()
+	if queue.Len() != 0 {
+		t.Errorf("expected empty queue, got %d", queue.Len())
+	}
+}
+
+func TestQueueBound(t *testing.T) {
+	queue, err := Open(nil, t.TempDir(), 64, 64)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	defer queue.Close()
+
+	record := make([]byte, 24) // 32 bytes with header
+	for range 2 {
+		err = queue.Push(record)
+		if err != nil {
+			t.Fatalf("unexpected error pushing: %v", err)
+		}
+	}
+	err = queue.Push(record)
+	if err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if queue.Metrics.Dropped.Load() != 1 {
+		t.Errorf("expected 1 dropped record, got %d", queue.Metrics.Dropped.Load())
+	}
+
+	// Space is released by reads
+	_, _, err = queue.Pop()
+	if err != nil {
+		t.Fatalf("unexpected error popping: %v", err)
+	}
+	err = queue.Push(record)
+	if err != nil {
+		t.Fatalf("expected push to succeed after pop, got %v", err)
+	}
+}
+
+func TestQueueTornSegment(t *testing.T) {
+	dir := t.TempDir()
+
+	queue, err := Open(nil, dir, 1024*1024, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	for i := range 3 {
+		err = queue.Push([]byte(fmt.Sprintf("record-%d", i)))
+		if err != nil {
+			t.Fatalf("unexpected error pushing: %v", err)
+		}
+	}
+	err = queue.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing queue: %v", err)
+	}
+
+	// Simulate crash mid-write
+	segments, err := listSegments(dir)
+	if err != nil || len(segments) != 1 {
+		t.Fatalf("expected one segment, got %v (err=%v)", segments, err)
+	}
+	segPath := filepath.Join(dir, fmt.Sprintf("%020d%s", segments[0], segmentExtension))
+	file, err := os.OpenFile(segPath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error opening segment: %v", err)
+	}
+	_, err = file.Write(encodeRecord([]byte("torn-record"))[:10])
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	_ = file.Close()
+
+	queue, err = Open(nil, dir, 1024*1024, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error reopening queue: %v", err)
+	}
+	defer queue.Close()
+	if queue.Len() != 3 {
+		t.Fatalf("expected torn record to be excluded, got depth %d", queue.Len())
+	}
+
+	err = queue.Push([]byte("new-record"))
+	if err != nil {
+		t.Fatalf("unexpected error pushing: %v", err)
+	}
+
+	expected := []string{"record-0", "record-1", "record-2", "new-record"}
+	for _, exp := range expected {
+		data, ok, err := queue.Pop()
+		if err != nil || !ok || string(data) != exp {
+			t.Fatalf("expected %q, got %q ok=%v err=%v", exp, data, ok, err)
+		}
+	}
+	if queue.Metrics.Corrupted.Load() != 1 {
+		t.Errorf("expected 1 corrupted segment tail, got %d", queue.Metrics.Corrupted.Load())
+	}
+}
@@ -0,0 +1,124 @@
+package spill
+
+import (
+	"sdsyslog/internal/metrics"
+	"time"
+)
+
+// Metric Names
+const (
+	MTDepth         string = "depth"
+	MTBytes         string = "total_bytes"
+	MTSegments      string = "segments"
+	MTSpilledBytes  string = "spilled_bytes"
+	MTReplayedBytes string = "replayed_bytes"
+	MTCorrupted     string = "corrupted_records"
+)
+
+func (queue *Queue) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	if queue == nil {
+		return
+	}
+
+	// Read current state
+	depth := queue.Metrics.Depth.Load()
+	bytes := queue.Metrics.Bytes.Load()
+	segments := queue.Metrics.Segments.Load()
+
+	// Read and clear
+	spilled := queue.Metrics.SpilledBytes.Swap(0)
+	replayed := queue.Metrics.ReplayedBytes.Swap(0)
+	dropped := queue.Metrics.Dropped.Swap(0)
+	corrupted := queue.Metrics.Corrupted.Swap(0)
+
+	// Record read time
+	recordTime := time.Now()
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTDepth,
+			Description: "Current records waiting on disk",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      depth,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Gauge,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTBytes,
+			Description: "Current bytes waiting on disk",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      bytes,
+				Unit:     "bytes",
+				Interval: interval,
+			},
+			Type:      metrics.Gauge,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSegments,
+			Description: "Current segment files on disk",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      segments,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Gauge,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTSpilledBytes,
+			Description: "Total bytes spilled to disk in the interval",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      spilled,
+				Unit:     "bytes",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTReplayedBytes,
+			Description: "Total bytes replayed from disk in the interval",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      replayed,
+				Unit:     "bytes",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTCorrupted,
+			Description: "Total corrupted records or segment tails skipped in the interval",
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      corrupted,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        metrics.MTDropped,
+			Description: metrics.DescDropped,
+			Namespace:   queue.Namespace,
+			Value: metrics.MetricValue{
+				Raw:      dropped,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
@@ -0,0 +1,194 @@
+package spill
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fmt: '%020d.seg' so lexical order matches numeric order
+func (queue *Queue) segmentPath(id uint64) (path string) {
+	path = filepath.Join(queue.dir, fmt.Sprintf("%020d%s", id, segmentExtension))
+	return
+}
+
+// Retrieves all segment IDs in the directory, oldest first
+func listSegments(dir string) (ids []uint64, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+		id, lerr := strconv.ParseUint(strings.TrimSuffix(name, segmentExtension), 10, 64)
+		if lerr != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return
+}
+
+// Fmt: 'SEGMENTID OFFSET'
+func loadCursor(dir string) (segmentID uint64, offset uint64, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, cursorFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+
+	_, err = fmt.Sscanf(strings.TrimSpace(string(data)), "%d %d", &segmentID, &offset)
+	if err != nil {
+		err = fmt.Errorf("invalid cursor file contents %q: %w", data, err)
+		return
+	}
+	return
+}
+
+// Atomically persists read position (temp file then rename)
+func saveCursor(dir string, segmentID uint64, offset uint64) (err error) {
+	cursorPath := filepath.Join(dir, cursorFileName)
+	tmpPath := cursorPath + ".tmp"
+
+	err = os.WriteFile(tmpPath, []byte(fmt.Sprintf("%d %d\n", segmentID, offset)), 0600)
+	if err != nil {
+		return
+	}
+	err = os.Rename(tmpPath, cursorPath)
+	return
+}
+
+// Reads requeued records. Anything after a torn or corrupted record is skipped.
+func loadFront(dir string, maxLen uint64) (records [][]byte, corrupted bool, err error) {
+	file, err := os.Open(filepath.Join(dir, frontFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	for {
+		data, lerr := readRecord(reader, maxLen)
+		if lerr != nil {
+			corrupted = lerr != io.EOF
+			return
+		}
+		records = append(records, data)
+	}
+}
+
+// Atomically persists requeued records (temp file then rename). No records removes the file.
+func saveFront(dir string, records [][]byte) (err error) {
+	frontPath := filepath.Join(dir, frontFileName)
+	if len(records) == 0 {
+		err = os.Remove(frontPath)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+
+	var contents []byte
+	for _, data := range records {
+		contents = append(contents, encodeRecord(data)...)
+	}
+
+	tmpPath := frontPath + ".tmp"
+	err = os.WriteFile(tmpPath, contents, 0600)
+	if err != nil {
+		return
+	}
+	err = os.Rename(tmpPath, frontPath)
+	return
+}
+
+// Encodes a single record (header+data) for a single write call
+func encodeRecord(data []byte) (record []byte) {
+	record = make([]byte, recordHeaderLen+len(data))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(data))
+	copy(record[recordHeaderLen:], data)
+	return
+}
+
+// Reads next record. Returns io.EOF at a clean end of segment.
+// Torn or corrupted records return io.ErrUnexpectedEOF or a checksum error.
+func readRecord(reader *bufio.Reader, maxLen uint64) (data []byte, err error) {
+	var header [recordHeaderLen]byte
+	_, err = io.ReadFull(reader, header[:])
+	if err != nil {
+		return
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	checksum := binary.BigEndian.Uint32(header[4:8])
+	if uint64(length) > maxLen {
+		err = fmt.Errorf("record length %d exceeds maximum of %d bytes", length, maxLen)
+		return
+	}
+
+	data = make([]byte, length)
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	if crc32.ChecksumIEEE(data) != checksum {
+		err = fmt.Errorf("record checksum mismatch")
+		return
+	}
+	return
+}
+
+// Counts valid records (and their bytes) in a segment starting at offset
+func scanSegment(path string, offset uint64, maxLen uint64) (records uint64, bytes uint64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = file.Seek(int64(offset), io.SeekStart)
+	if err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		data, lerr := readRecord(reader, maxLen)
+		if lerr != nil {
+			// Anything after first invalid record is ignored at replay too
+			return
+		}
+		records++
+		bytes += uint64(recordHeaderLen + len(data))
+	}
+}
@@ -0,0 +1,47 @@
+// Bounded on-disk FIFO queue made of append-only segment files.
+// Used to spill in-memory queue contents to disk when the memory queue grows too large or the network is unavailable.
+package spill
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+type Queue struct {
+	Namespace []string
+	dir       string
+	maxBytes  uint64 // Upper bound of unread data on disk
+	segSize   uint64 // Size at which a new segment file is started
+
+	mu       sync.Mutex
+	closed   bool
+	segments []uint64 // Segment IDs on disk, oldest first
+	front    [][]byte // Requeued records returned before any segment, oldest first
+
+	// Write side (always the newest segment)
+	writer     *os.File
+	writeID    uint64
+	writeBytes uint64
+
+	// Read side (always the oldest segment)
+	reader       *os.File
+	readBuf      *bufio.Reader
+	readID       uint64
+	readOffset   uint64
+	cursorOffset uint64 // Offset to resume the first opened segment from (persisted cursor)
+
+	Metrics *MetricStorage
+}
+
+type MetricStorage struct {
+	Depth    atomic.Uint64 // Current records on disk
+	Bytes    atomic.Uint64 // Current unread bytes on disk (including record headers)
+	Segments atomic.Uint64 // Current segment files on disk
+
+	SpilledBytes  atomic.Uint64 // Data bytes written to disk
+	ReplayedBytes atomic.Uint64 // Data bytes read back from disk
+	Dropped       atomic.Uint64 // Records refused due to full disk queue
+	Corrupted     atomic.Uint64 // Records or segment tails skipped due to corruption
+}
@@ -12,6 +12,10 @@ type MetricStorage struct {
 	SumNs            atomic.Uint64 // sum of elapsed ns for all ops
 	MaxNs            atomic.Uint64 // max observed op duration
 	Dropped          atomic.Uint64
+
+	RecoveredMessages     atomic.Uint64 // messages with lost fragments rebuilt from parity
+	UnrecoverableMessages atomic.Uint64 // messages with parity that lost too many fragments
+	ContentSigFailures    atomic.Uint64 // messages dropped for invalid content signature
 }
 
 // Metric Names
@@ -21,6 +25,9 @@ const (
 	MTMaxWorkTime    string = "elapsed_time_max_ns"
 	MTInstanceCount  string = "instance_count"
 	MTPacketDeadline string = "packet_deadline"
+	MTRecovered      string = "parity_recovered_messages"
+	MTUnrecoverable  string = "parity_unrecoverable_messages"
+	MTContentSigFail string = "content_signature_failures"
 )
 
 func (manager *Manager) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
@@ -64,6 +71,9 @@ func (instance *Instance) CollectMetrics(interval time.Duration) (collection []m
 	sumNs := instance.Metrics.SumNs.Swap(0)
 	maxNs := instance.Metrics.MaxNs.Swap(0)
 	dropped := instance.Metrics.Dropped.Swap(0)
+	recovered := instance.Metrics.RecoveredMessages.Swap(0)
+	unrecoverable := instance.Metrics.UnrecoverableMessages.Swap(0)
+	contentSigFailures := instance.Metrics.ContentSigFailures.Swap(0)
 
 	namespace := logctx.GetTagList(instance.ctx)
 
@@ -107,6 +117,42 @@ func (instance *Instance) CollectMetrics(interval time.Duration) (collection []m
 			Type:      metrics.Summary,
 			Timestamp: recordTime,
 		},
+		{
+			Name:        MTRecovered,
+			Description: "Number of messages in the interval with missing fragments rebuilt from parity fragments",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      recovered,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTUnrecoverable,
+			Description: "Number of messages in the interval with parity fragments that lost too many fragments to rebuild",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      unrecoverable,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTContentSigFail,
+			Description: "Number of reassembled messages dropped in the interval due to an invalid content signature",
+			Namespace:   namespace,
+			Value: metrics.MetricValue{
+				Raw:      contentSigFailures,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
 		{
 			Name:        metrics.MTDropped,
 			Description: metrics.DescDropped,
@@ -60,12 +60,35 @@ func (instance *Instance) run() {
 				fragSlice = append(fragSlice, fragment)
 			}
 
+			// Rebuild lost fragments for messages sent with parity
+			fragSlice, parityResult, err := protocol.RecoverFragments(fragSlice)
+			if err != nil {
+				logctx.LogStdErr(ctx, "Failed assembly: %w\n", err)
+				return
+			}
+			switch parityResult {
+			case protocol.ParityRecovered:
+				instance.Metrics.RecoveredMessages.Add(1)
+			case protocol.ParityUnrecoverable:
+				instance.Metrics.UnrecoverableMessages.Add(1)
+			}
+			complete := len(fragSlice) > 0 && len(fragSlice) == fragSlice[0].MessageSeqMax+1
+
 			finalMsg, err := protocol.Defragment(fragSlice)
 			if err != nil {
 				logctx.LogStdErr(ctx, "Failed assembly: %w\n", err)
 				return
 			}
 
+			// Content signatures can only be checked against the whole message
+			err = protocol.VerifyContentSignature(finalMsg, complete)
+			if err != nil {
+				logctx.LogStdErr(ctx, "Dropping message: host id %d, message id %d: %w\n",
+					finalMsg.HostID, finalMsg.MsgID, err)
+				instance.Metrics.ContentSigFailures.Add(1)
+				return
+			}
+
 			// Record time metrics post-validation
 			durNs := time.Since(start).Nanoseconds()
 			instance.Metrics.SumNs.Add(uint64(durNs))
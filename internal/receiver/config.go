@@ -34,8 +34,18 @@ func (daemon *Daemon) LoadConfig(path string) (err error) {
 }
 
 // Retrieves private key from disk from configured path
+// Returns no key when only rotation keys are configured (loaded during init)
 func (daemon *Daemon) LoadKey() (key []byte, err error) {
-	privateKey, err := os.ReadFile(daemon.opts.PrivateKeyFile)
+	if daemon.opts.PrivateKeyFile == "" && len(daemon.opts.PrivateKeys) > 0 {
+		return
+	}
+	key, err = readKeyFile(daemon.opts.PrivateKeyFile)
+	return
+}
+
+// Reads base64 encoded key file
+func readKeyFile(path string) (key []byte, err error) {
+	privateKey, err := os.ReadFile(path)
 	if err != nil {
 		err = fmt.Errorf("failed reading private key file: %w", err)
 		return
@@ -58,6 +68,10 @@ func (opts *JSONOptions) setDefaults() {
 			opts.Crypto.TransportSuite = suiteInfo.Name
 		}
 	}
+	if len(opts.Crypto.AcceptedTransportSuites) == 0 {
+		// Only accept the configured suite
+		opts.Crypto.AcceptedTransportSuites = []string{opts.Crypto.TransportSuite}
+	}
 	if opts.Crypto.SignatureSuite == "" && opts.PinnedSigningKeysPath == "" {
 		// Default to no signatures with no signer public keys
 		sigInfo, valid := registry.GetSignatureInfo(0)
@@ -0,0 +1,55 @@
+package receiver
+
+import (
+	"fmt"
+	"sdsyslog/internal/crypto/wrappers"
+)
+
+// Name of the key loaded from privateKeyFile in metrics and logs
+const primaryKeyName string = "primary"
+
+// Builds decryption key list from the primary key (privateKeyFile) followed by configured rotation keys
+func (daemon *Daemon) loadDecryptionKeys(primaryKey []byte) (err error) {
+	daemon.cfg.DecryptionKeys = make([]*wrappers.DecryptionKey, 0, len(daemon.opts.PrivateKeys)+1)
+	seenNames := make(map[string]struct{}, len(daemon.opts.PrivateKeys)+1)
+
+	if len(primaryKey) > 0 {
+		daemon.cfg.DecryptionKeys = append(daemon.cfg.DecryptionKeys, &wrappers.DecryptionKey{
+			Name:       primaryKeyName,
+			PrivateKey: primaryKey,
+		})
+		seenNames[primaryKeyName] = struct{}{}
+	}
+
+	for index, keyOpts := range daemon.opts.PrivateKeys {
+		if keyOpts.Name == "" {
+			err = fmt.Errorf("private key %d: name is required", index)
+			return
+		}
+		if _, duplicate := seenNames[keyOpts.Name]; duplicate {
+			err = fmt.Errorf("private key %q: name is already in use", keyOpts.Name)
+			return
+		}
+		seenNames[keyOpts.Name] = struct{}{}
+
+		if !keyOpts.ValidFrom.IsZero() && !keyOpts.ValidUntil.IsZero() && !keyOpts.ValidUntil.After(keyOpts.ValidFrom) {
+			err = fmt.Errorf("private key %q: validUntil must be after validFrom", keyOpts.Name)
+			return
+		}
+
+		var key []byte
+		key, err = readKeyFile(keyOpts.File)
+		if err != nil {
+			err = fmt.Errorf("private key %q: %w", keyOpts.Name, err)
+			return
+		}
+
+		daemon.cfg.DecryptionKeys = append(daemon.cfg.DecryptionKeys, &wrappers.DecryptionKey{
+			Name:       keyOpts.Name,
+			PrivateKey: key,
+			ValidFrom:  keyOpts.ValidFrom,
+			ValidUntil: keyOpts.ValidUntil,
+		})
+	}
+	return
+}
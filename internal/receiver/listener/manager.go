@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sdsyslog/internal/logctx"
 	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/crypto/registry"
 )
 
 // Creates new instance manager
@@ -31,6 +32,10 @@ func (config *ManagerConfig) NewManager(ctx context.Context, dataOut *mpmc.Queue
 	}
 	new.Instances.Store(&startInstances)
 
+	for _, suiteID := range config.AcceptedSuiteIDs {
+		new.acceptedSuites[suiteID] = true
+	}
+
 	// Background cleanup loop for replay protection cache
 	go new.replayCache.cleanupLoop(ctx, new.Config.replayCleanInterval)
 
@@ -57,5 +62,17 @@ func (config *ManagerConfig) validate() (err error) {
 	if config.replayCleanInterval == 0 {
 		err = fmt.Errorf("empty replayCleanInterval")
 	}
+	for _, suiteID := range config.AcceptedSuiteIDs {
+		_, validID := registry.GetSuiteInfo(suiteID)
+		if !validID {
+			err = fmt.Errorf("accepted crypto suite ID %d is not registered", suiteID)
+		}
+	}
+	return
+}
+
+// Reports whether packets using the (registered) crypto suite pass pre-validation
+func (manager *Manager) isAcceptedSuite(suiteID uint8) (accepted bool) {
+	accepted = len(manager.Config.AcceptedSuiteIDs) == 0 || manager.acceptedSuites[suiteID]
 	return
 }
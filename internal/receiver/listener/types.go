@@ -13,25 +13,28 @@ import (
 type ManagerConfig struct {
 	MinInstanceCount       atomic.Uint32 // Minimum number of instances at any one time
 	MaxInstanceCount       atomic.Uint32 // Maximum number of instances at any one time
-	ListenSocket          *net.UDPAddr  // Source listen address
+	ListenSocket           *net.UDPAddr  // Source listen address
 	ReplayProtectionWindow time.Duration // +/- time duration from packet reception time where a duplicate public key will cause packet to be dropped
+	AcceptedSuiteIDs       []uint8       // Crypto suite IDs allowed through pre-validation (empty allows any registered suite)
 	replayCleanInterval    time.Duration // Eviction check interval for seen public keys
 }
 
 type Manager struct {
-	Config      *ManagerConfig              // Configuration values
-	Instances   atomic.Pointer[[]*Instance] // Existing running instances
-	replayCache *replayCache
-	outbox      *mpmc.Queue[Container]
-	ctx         context.Context
+	Config         *ManagerConfig              // Configuration values
+	Instances      atomic.Pointer[[]*Instance] // Existing running instances
+	replayCache    *replayCache
+	acceptedSuites [256]bool // Indexed by suite ID
+	outbox         *mpmc.Queue[Container]
+	ctx            context.Context
 }
 
 type Instance struct {
-	conn       *net.UDPConn
-	outbox     *mpmc.Queue[Container]
-	minLen     int
-	Metrics    MetricStorage
-	isReplayed func(pubKey []byte) (replayed bool)
+	conn            *net.UDPConn
+	outbox          *mpmc.Queue[Container]
+	minLen          int
+	Metrics         MetricStorage
+	isReplayed      func(pubKey []byte) (replayed bool)
+	isAcceptedSuite func(suiteID uint8) (accepted bool)
 
 	ctx    context.Context
 	wg     sync.WaitGroup     // Waiter for instance
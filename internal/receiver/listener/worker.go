@@ -16,11 +16,12 @@ func (manager *Manager) newWorker(conn *net.UDPConn) (new *Instance) {
 	}
 
 	new = &Instance{
-		conn:       conn,
-		outbox:     manager.outbox,
-		minLen:     protocol.MinOuterPayloadLen,
-		Metrics:    MetricStorage{},
-		isReplayed: manager.replayCache.isReplayed,
+		conn:            conn,
+		outbox:          manager.outbox,
+		minLen:          protocol.MinOuterPayloadLen,
+		Metrics:         MetricStorage{},
+		isReplayed:      manager.replayCache.isReplayed,
+		isAcceptedSuite: manager.isAcceptedSuite,
 	}
 	return
 }
@@ -80,7 +81,8 @@ func (instance *Instance) run() {
 
 			// Pre validation
 			suiteInfo, validSuiteID := registry.GetSuiteInfo(payload[0])
-			if len(payload) < instance.minLen || !validSuiteID {
+			if len(payload) < instance.minLen || !validSuiteID || !instance.isAcceptedSuite(payload[0]) ||
+				len(payload) < instance.minLen+suiteInfo.HeaderKeySize()+suiteInfo.NonceSize {
 				instance.Metrics.InvalidPackets.Add(1)
 				instance.Metrics.BusyNs.Add(uint64(time.Since(start)))
 				logctx.LogEvent(ctx, logctx.VerbosityProgress, logctx.WarnLog,
@@ -89,7 +91,8 @@ func (instance *Instance) run() {
 			}
 
 			// Replay attack protection - level 1
-			//   minLen protects against packets smaller than key sizes
+			//   length check above protects against packets smaller than key sizes
+			//   ephemeral public key alone identifies the packet (KEM ciphertext follows it)
 			pubKey := payload[registry.SuiteIDLen : registry.SuiteIDLen+suiteInfo.KeySize]
 			if instance.isReplayed(pubKey) {
 				instance.Metrics.InvalidPackets.Add(1)
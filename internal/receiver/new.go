@@ -86,6 +86,16 @@ func (daemon *Daemon) Init(serverPriv []byte) (err error) {
 		daemon.cfg.PinnedSigningKeys = make(map[string][]byte)
 	}
 
+	// Primary key followed by any rotation keys
+	err = daemon.loadDecryptionKeys(serverPriv)
+	if err != nil {
+		err = fmt.Errorf("failed loading private keys: %w", err)
+		return
+	}
+	if len(daemon.cfg.DecryptionKeys) > 0 {
+		serverPriv = daemon.cfg.DecryptionKeys[0].PrivateKey
+	}
+
 	transportSuiteID, validName := registry.SuiteNameToID(daemon.opts.Crypto.TransportSuite)
 	if !validName {
 		err = fmt.Errorf("invalid transport suite name %s", daemon.opts.Crypto.TransportSuite)
@@ -100,6 +110,29 @@ func (daemon *Daemon) Init(serverPriv []byte) (err error) {
 	if err != nil {
 		return
 	}
+
+	// Multiple suites can be accepted while senders migrate between them (each needs at least one usable key)
+	daemon.cfg.acceptedSuiteIDs = make([]uint8, 0, len(daemon.opts.Crypto.AcceptedTransportSuites))
+	for _, suiteName := range daemon.opts.Crypto.AcceptedTransportSuites {
+		suiteID, validName := registry.SuiteNameToID(suiteName)
+		if !validName {
+			err = fmt.Errorf("invalid accepted transport suite name %s", suiteName)
+			return
+		}
+		acceptedInfo, _ := registry.GetSuiteInfo(suiteID)
+		err = acceptedInfo.ValidateKey(serverPriv)
+		for _, key := range daemon.cfg.DecryptionKeys {
+			if err == nil {
+				break
+			}
+			err = acceptedInfo.ValidateKey(key.PrivateKey)
+		}
+		if err != nil {
+			err = fmt.Errorf("no private key can be used with accepted transport suite %s: %w", suiteName, err)
+			return
+		}
+		daemon.cfg.acceptedSuiteIDs = append(daemon.cfg.acceptedSuiteIDs, suiteID)
+	}
 	serverPub, err := info.DerivePublicKey(serverPriv)
 	if err != nil {
 		return
@@ -109,7 +142,7 @@ func (daemon *Daemon) Init(serverPriv []byte) (err error) {
 		err = fmt.Errorf("failed to setup encryption function: %w", err)
 		return
 	}
-	err = wrappers.SetupDecryptInnerPayload(serverPriv)
+	err = wrappers.SetupDecryptInnerPayloadKeys(daemon.cfg.DecryptionKeys)
 	if err != nil {
 		err = fmt.Errorf("failed to setup decryption function: %w", err)
 		return
@@ -124,6 +157,11 @@ func (daemon *Daemon) Init(serverPriv []byte) (err error) {
 		err = fmt.Errorf("failed to setup signature verification function: %w", err)
 		return
 	}
+	err = daemon.loadOutputs()
+	if err != nil {
+		err = fmt.Errorf("invalid output configuration: %w", err)
+		return
+	}
 	daemon.cfg.sourceSocket, err = network.ParseUDPAddress(daemon.opts.Network.Address, daemon.opts.Network.Port)
 	if err != nil {
 		err = fmt.Errorf("invalid network address: %w", err)
@@ -0,0 +1,6 @@
+package output
+
+const (
+	rawOutputName    string = "raw"     // Name of the internal-only raw writer output
+	defaultRouteName string = "default" // Name of the route used by messages no rule matched
+)
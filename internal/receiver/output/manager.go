@@ -48,14 +48,31 @@ func (config *ManagerConfig) validate() (err error) {
 	if int(config.MinQueueCapacity) >= int(config.MaxQueueCapacity) {
 		err = fmt.Errorf("minimum queue capacity cannot be equal to or less than max queue capacity")
 	}
-	if config.FilePath == "" &&
-		config.JournaldURL == "" &&
-		config.BeatsAddress == "" &&
-		config.RawWriter == nil &&
-		!config.EnableDBUSNotify {
+	if len(config.Outputs) == 0 && config.RawWriter == nil {
 		err = fmt.Errorf("no outputs enabled/configured")
 		return
 	}
+	names := make(map[string]struct{}, len(config.Outputs))
+	for index, output := range config.Outputs {
+		if output.Name == "" {
+			err = fmt.Errorf("output at index %d has no name", index)
+			return
+		}
+		if output.Type == "" {
+			err = fmt.Errorf("output %q has no type", output.Name)
+			return
+		}
+		_, duplicate := names[output.Name]
+		if duplicate || (output.Name == rawOutputName && config.RawWriter != nil) {
+			err = fmt.Errorf("duplicate output name %q", output.Name)
+			return
+		}
+		names[output.Name] = struct{}{}
+	}
+	err = config.validateRoutes()
+	if err != nil {
+		return
+	}
 	if config.ConsecutiveFailureShutdownInterval == 0 {
 		err = fmt.Errorf("empty ConsecutiveFailureShutdownInterval")
 	}
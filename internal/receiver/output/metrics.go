@@ -7,37 +7,26 @@ import (
 )
 
 type MetricStorage struct {
-	ReceivedMessages       atomic.Uint64
-	SuccessfulFileWrites   atomic.Uint64
-	SuccessfulJrnlWrites   atomic.Uint64
-	SuccessfulBeatsWrites  atomic.Uint64
-	SuccessfulRawWrites    atomic.Uint64
-	SuccessfulNotifyWrites atomic.Uint64
-	Dropped                atomic.Uint64
+	ReceivedMessages atomic.Uint64
+	SuccessfulWrites atomic.Uint64 // Sum of writes across all outputs
+	Dropped          atomic.Uint64
 }
 
 const (
-	MTRecvMsgs        string = "received_messages"
-	MTWrittenMsgs     string = "written_messages"
-	MTFileWritesSuc   string = "success_file_writes"
-	MTJrnlWritesSuc   string = "success_journal_writes"
-	MTBeatsWritesSuc  string = "success_beats_writes"
-	MTRawWritesSuc    string = "success_raw_writes"
-	MTNotifyWritesSuc string = "success_notify_writes"
+	MTRecvMsgs          string = "received_messages"
+	MTWrittenMsgs       string = "written_messages"
+	MTOutputWritesSuc   string = "success_writes"
+	MTOutputWritesFail  string = "failed_writes"
+	MTOutputConsecFails string = "consecutive_failed_writes"
+	MTRouteHits         string = "route_matches"
 )
 
 func (instance *Instance) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
 	// Read and clear
 	recvMsgs := instance.Metrics.ReceivedMessages.Swap(0)
-	fileWrites := instance.Metrics.SuccessfulFileWrites.Swap(0)
-	jrnlWrites := instance.Metrics.SuccessfulJrnlWrites.Swap(0)
-	beatsWrites := instance.Metrics.SuccessfulBeatsWrites.Swap(0)
-	rawWrites := instance.Metrics.SuccessfulRawWrites.Swap(0)
-	notifyWrites := instance.Metrics.SuccessfulNotifyWrites.Swap(0)
+	totalWrites := instance.Metrics.SuccessfulWrites.Swap(0)
 	dropped := instance.Metrics.Dropped.Swap(0)
 
-	totalWrites := fileWrites + jrnlWrites + beatsWrites + rawWrites + notifyWrites
-
 	// Record read time
 	recordTime := time.Now()
 
@@ -67,35 +56,44 @@ func (instance *Instance) CollectMetrics(interval time.Duration) (collection []m
 			Timestamp: recordTime,
 		},
 		{
-			Name:        MTFileWritesSuc,
-			Description: "Total writes to any file outputs",
-			Namespace:   instance.namespace,
-			Value: metrics.MetricValue{
-				Raw:      fileWrites,
-				Unit:     "count",
-				Interval: interval,
-			},
-			Type:      metrics.Counter,
-			Timestamp: recordTime,
-		},
-		{
-			Name:        MTJrnlWritesSuc,
-			Description: "Total writes to journal output",
+			Name:        metrics.MTDropped,
+			Description: metrics.DescDropped,
 			Namespace:   instance.namespace,
 			Value: metrics.MetricValue{
-				Raw:      jrnlWrites,
+				Raw:      dropped,
 				Unit:     "count",
 				Interval: interval,
 			},
 			Type:      metrics.Counter,
 			Timestamp: recordTime,
 		},
+	}
+
+	for _, output := range instance.outputs {
+		collection = append(collection, output.collectMetrics(interval, recordTime)...)
+	}
+	for _, route := range instance.routes {
+		collection = append(collection, route.collectMetrics(interval, recordTime))
+	}
+	if instance.defaultRoute != nil {
+		collection = append(collection, instance.defaultRoute.collectMetrics(interval, recordTime))
+	}
+	return
+}
+
+// Collects metrics of a single named output
+func (output *namedOutput) collectMetrics(interval time.Duration, recordTime time.Time) (collection []metrics.Metric) {
+	writes := output.metrics.Writes.Swap(0)
+	failures := output.metrics.Failures.Swap(0)
+	consecutiveFailures := output.metrics.ConsecutiveFailures.Load()
+
+	collection = []metrics.Metric{
 		{
-			Name:        MTBeatsWritesSuc,
-			Description: "Total writes to beats output",
-			Namespace:   instance.namespace,
+			Name:        MTOutputWritesSuc,
+			Description: "Total messages written to output",
+			Namespace:   output.namespace,
 			Value: metrics.MetricValue{
-				Raw:      beatsWrites,
+				Raw:      writes,
 				Unit:     "count",
 				Interval: interval,
 			},
@@ -103,11 +101,11 @@ func (instance *Instance) CollectMetrics(interval time.Duration) (collection []m
 			Timestamp: recordTime,
 		},
 		{
-			Name:        MTRawWritesSuc,
-			Description: "Total writes to raw output",
-			Namespace:   instance.namespace,
+			Name:        MTOutputWritesFail,
+			Description: "Total failed writes to output",
+			Namespace:   output.namespace,
 			Value: metrics.MetricValue{
-				Raw:      rawWrites,
+				Raw:      failures,
 				Unit:     "count",
 				Interval: interval,
 			},
@@ -115,29 +113,34 @@ func (instance *Instance) CollectMetrics(interval time.Duration) (collection []m
 			Timestamp: recordTime,
 		},
 		{
-			Name:        MTNotifyWritesSuc,
-			Description: "Total writes to dbus notify output",
-			Namespace:   instance.namespace,
+			Name:        MTOutputConsecFails,
+			Description: "Failed writes to output since its last successful write",
+			Namespace:   output.namespace,
 			Value: metrics.MetricValue{
-				Raw:      notifyWrites,
+				Raw:      consecutiveFailures,
 				Unit:     "count",
 				Interval: interval,
 			},
-			Type:      metrics.Counter,
+			Type:      metrics.Gauge,
 			Timestamp: recordTime,
 		},
-		{
-			Name:        metrics.MTDropped,
-			Description: metrics.DescDropped,
-			Namespace:   instance.namespace,
-			Value: metrics.MetricValue{
-				Raw:      dropped,
-				Unit:     "count",
-				Interval: interval,
-			},
-			Type:      metrics.Counter,
-			Timestamp: recordTime,
+	}
+	return
+}
+
+// Collects hit counter of a single routing rule
+func (route *route) collectMetrics(interval time.Duration, recordTime time.Time) (metric metrics.Metric) {
+	metric = metrics.Metric{
+		Name:        MTRouteHits,
+		Description: "Total messages routed by rule",
+		Namespace:   route.namespace,
+		Value: metrics.MetricValue{
+			Raw:      route.hits.Swap(0),
+			Unit:     "count",
+			Interval: interval,
 		},
+		Type:      metrics.Counter,
+		Timestamp: recordTime,
 	}
 	return
 }
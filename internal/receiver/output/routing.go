@@ -0,0 +1,195 @@
+package output
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"slices"
+)
+
+// Reports whether every configured matcher of the rule matches the message (rules without matchers match everything)
+func (rule RouteRule) Match(msg *protocol.Payload) (matches bool) {
+	if rule.Hostname != nil && !rule.Hostname.Match([]byte(msg.Hostname)) {
+		return
+	}
+	if rule.RemoteIP != nil {
+		var remoteIP string
+		if msg.RemoteIP.IsValid() {
+			remoteIP = msg.RemoteIP.Unmap().String()
+		}
+		if !rule.RemoteIP.Match([]byte(remoteIP)) {
+			return
+		}
+	}
+	if rule.Severity != nil && !rule.Severity.Match([]byte(fieldText(msg, iomodules.CFseverity))) {
+		return
+	}
+	if rule.AppName != nil && !rule.AppName.Match([]byte(fieldText(msg, iomodules.CFappname))) {
+		return
+	}
+	if rule.Message != nil {
+		message := &protocol.Message{
+			Timestamp: msg.Timestamp,
+			Hostname:  msg.Hostname,
+			Fields:    msg.CustomFields,
+			Data:      msg.Data,
+		}
+		if !rule.Message.Match(message) {
+			return
+		}
+	}
+	matches = true
+	return
+}
+
+// Checks rule matchers and output list
+func (rule RouteRule) Validate() (err error) {
+	if rule.Hostname != nil {
+		err = rule.Hostname.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid hostname filter: %w", err)
+			return
+		}
+	}
+	if rule.RemoteIP != nil {
+		err = rule.RemoteIP.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid remote IP filter: %w", err)
+			return
+		}
+	}
+	if rule.Severity != nil {
+		err = rule.Severity.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid severity filter: %w", err)
+			return
+		}
+	}
+	if rule.AppName != nil {
+		err = rule.AppName.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid application name filter: %w", err)
+			return
+		}
+	}
+	if rule.Message != nil {
+		err = rule.Message.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid message filter: %w", err)
+			return
+		}
+	}
+	if len(rule.Outputs) == 0 {
+		err = fmt.Errorf("no outputs selected")
+		return
+	}
+	return
+}
+
+// Formatted value of a custom field (empty if missing)
+func fieldText(msg *protocol.Payload, key string) (text string) {
+	value, ok := msg.CustomFields[key]
+	if !ok {
+		return
+	}
+	text = protocol.FormatValue(value)
+	return
+}
+
+// Checks routing rules reference known outputs and have unique names
+func (config *ManagerConfig) validateRoutes() (err error) {
+	outputNames := make([]string, 0, len(config.Outputs)+1)
+	for _, output := range config.Outputs {
+		outputNames = append(outputNames, output.Name)
+	}
+	if config.RawWriter != nil {
+		outputNames = append(outputNames, rawOutputName)
+	}
+
+	ruleNames := make(map[string]struct{}, len(config.Routes))
+	for index, rule := range config.Routes {
+		if rule.Name == "" {
+			err = fmt.Errorf("route at index %d has no name", index)
+			return
+		}
+		if rule.Name == defaultRouteName {
+			err = fmt.Errorf("route name %q is reserved", defaultRouteName)
+			return
+		}
+		if _, duplicate := ruleNames[rule.Name]; duplicate {
+			err = fmt.Errorf("duplicate route name %q", rule.Name)
+			return
+		}
+		ruleNames[rule.Name] = struct{}{}
+
+		err = rule.Validate()
+		if err != nil {
+			err = fmt.Errorf("route %q: %w", rule.Name, err)
+			return
+		}
+		for _, name := range rule.Outputs {
+			if !slices.Contains(outputNames, name) {
+				err = fmt.Errorf("route %q: unknown output %q", rule.Name, name)
+				return
+			}
+		}
+	}
+	if config.DefaultOutputs != nil && len(config.DefaultOutputs) == 0 {
+		err = fmt.Errorf("default route has no outputs (omit default outputs to use all outputs)")
+		return
+	}
+	for _, name := range config.DefaultOutputs {
+		if !slices.Contains(outputNames, name) {
+			err = fmt.Errorf("default route: unknown output %q", name)
+			return
+		}
+	}
+	return
+}
+
+// Resolves route output names to the instances' outputs (called after all outputs are added)
+func (instance *Instance) setupRoutes(rules []RouteRule, defaultOutputs []string) {
+	byName := make(map[string]*namedOutput, len(instance.outputs))
+	for _, output := range instance.outputs {
+		byName[output.name] = output
+	}
+	resolve := func(names []string) (outputs []*namedOutput) {
+		for _, name := range names {
+			outputs = append(outputs, byName[name])
+		}
+		return
+	}
+
+	instance.routes = make([]*route, 0, len(rules)+1)
+	for _, rule := range rules {
+		instance.routes = append(instance.routes, &route{
+			rule:      rule,
+			outputs:   resolve(rule.Outputs),
+			namespace: append(slices.Clone(instance.namespace), logctx.NSRoute, rule.Name),
+		})
+	}
+
+	instance.defaultRoute = &route{
+		outputs:   instance.outputs,
+		namespace: append(slices.Clone(instance.namespace), logctx.NSRoute, defaultRouteName),
+	}
+	if defaultOutputs != nil {
+		instance.defaultRoute.outputs = resolve(defaultOutputs)
+	}
+}
+
+// Selects outputs for message from the first matching route (or the default route)
+func (instance *Instance) selectRoute(msg *protocol.Payload) (selected *route) {
+	for _, candidate := range instance.routes {
+		if candidate.rule.Match(msg) {
+			selected = candidate
+			break
+		}
+	}
+	if selected == nil {
+		selected = instance.defaultRoute
+	}
+	selected.hits.Add(1)
+	return
+}
@@ -0,0 +1,198 @@
+package output
+
+import (
+	"net/netip"
+	"sdsyslog/internal/filtering"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSelectRoute(t *testing.T) {
+	rules := []RouteRule{
+		{
+			Name:     "debug-local",
+			Severity: &filtering.Filter{Exact: "debug"},
+			Outputs:  []string{"file"},
+		},
+		{
+			Name: "auth-siem",
+			Message: &protocol.MessageFilter{
+				FieldsValue: &filtering.Filter{Or: []filtering.Filter{{Exact: "auth"}, {Exact: "authpriv"}}},
+			},
+			Outputs: []string{"siem", "file"},
+		},
+		{
+			Name:     "lab-hosts",
+			RemoteIP: &filtering.Filter{Prefix: "10.99."},
+			AppName:  &filtering.Filter{Exact: "kernel"},
+			Outputs:  []string{"lab"},
+		},
+		{
+			Name:     "unverified",
+			Hostname: &filtering.Filter{Prefix: "[UNVERIFIED]"},
+			Outputs:  []string{"file"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		msg             *protocol.Payload
+		expectedRoute   string
+		expectedOutputs []string
+	}{
+		{
+			name: "first matching rule wins",
+			msg: &protocol.Payload{
+				CustomFields: map[string]any{iomodules.CFseverity: "debug", iomodules.CFfacility: "auth"},
+			},
+			expectedRoute:   "debug-local",
+			expectedOutputs: []string{"file"},
+		},
+		{
+			name: "message filter on custom fields",
+			msg: &protocol.Payload{
+				CustomFields: map[string]any{iomodules.CFseverity: "info", iomodules.CFfacility: "authpriv"},
+			},
+			expectedRoute:   "auth-siem",
+			expectedOutputs: []string{"siem", "file"},
+		},
+		{
+			name: "all matchers of a rule must match",
+			msg: &protocol.Payload{
+				Hostname:     "lab01",
+				RemoteIP:     netip.MustParseAddr("10.99.0.4"),
+				CustomFields: map[string]any{iomodules.CFappname: "sshd"},
+			},
+			expectedRoute:   defaultRouteName,
+			expectedOutputs: []string{"file", "siem"},
+		},
+		{
+			name: "remote ip and application name",
+			msg: &protocol.Payload{
+				RemoteIP:     netip.MustParseAddr("::ffff:10.99.0.4"),
+				CustomFields: map[string]any{iomodules.CFappname: "kernel"},
+			},
+			expectedRoute:   "lab-hosts",
+			expectedOutputs: []string{"lab"},
+		},
+		{
+			name:            "hostname with verification prefix",
+			msg:             &protocol.Payload{Hostname: "[UNVERIFIED]web01"},
+			expectedRoute:   "unverified",
+			expectedOutputs: []string{"file"},
+		},
+	}
+
+	instance := &Instance{}
+	for _, name := range []string{"file", "siem", "lab"} {
+		instance.addOutput(name, nil)
+	}
+	instance.setupRoutes(rules, []string{"file", "siem"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := instance.selectRoute(tt.msg)
+
+			routeName := defaultRouteName
+			if selected != instance.defaultRoute {
+				routeName = selected.rule.Name
+			}
+			if routeName != tt.expectedRoute {
+				t.Fatalf("expected route %q, got %q", tt.expectedRoute, routeName)
+			}
+
+			var outputNames []string
+			for _, output := range selected.outputs {
+				outputNames = append(outputNames, output.name)
+			}
+			if !slices.Equal(outputNames, tt.expectedOutputs) {
+				t.Errorf("expected outputs %v, got %v", tt.expectedOutputs, outputNames)
+			}
+		})
+	}
+
+	// Hit counters
+	expectedHits := map[string]uint64{"debug-local": 1, "auth-siem": 1, "lab-hosts": 1, "unverified": 1}
+	for _, route := range instance.routes {
+		if hits := route.hits.Load(); hits != expectedHits[route.rule.Name] {
+			t.Errorf("expected route %q to have %d hits, got %d", route.rule.Name, expectedHits[route.rule.Name], hits)
+		}
+	}
+	if hits := instance.defaultRoute.hits.Load(); hits != 1 {
+		t.Errorf("expected default route to have 1 hit, got %d", hits)
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	outputs := []OutputConfig{{Name: "file", Type: "file"}, {Name: "siem", Type: "syslog"}}
+
+	tests := []struct {
+		name           string
+		routes         []RouteRule
+		defaultOutputs []string
+		expectedError  string
+	}{
+		{
+			name:   "valid",
+			routes: []RouteRule{{Name: "all", Outputs: []string{"siem"}}},
+		},
+		{
+			name:          "unknown output",
+			routes:        []RouteRule{{Name: "a", Outputs: []string{"missing"}}},
+			expectedError: `route "a": unknown output "missing"`,
+		},
+		{
+			name:          "no outputs",
+			routes:        []RouteRule{{Name: "a"}},
+			expectedError: "no outputs selected",
+		},
+		{
+			name:          "duplicate names",
+			routes:        []RouteRule{{Name: "a", Outputs: []string{"file"}}, {Name: "a", Outputs: []string{"file"}}},
+			expectedError: `duplicate route name "a"`,
+		},
+		{
+			name:          "reserved name",
+			routes:        []RouteRule{{Name: defaultRouteName, Outputs: []string{"file"}}},
+			expectedError: "is reserved",
+		},
+		{
+			name:          "invalid filter",
+			routes:        []RouteRule{{Name: "a", Severity: &filtering.Filter{}, Outputs: []string{"file"}}},
+			expectedError: "invalid severity filter",
+		},
+		{
+			name:           "unknown default output",
+			defaultOutputs: []string{"missing"},
+			expectedError:  `default route: unknown output "missing"`,
+		},
+		{
+			name:           "empty default outputs",
+			defaultOutputs: []string{},
+			expectedError:  "default route has no outputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ManagerConfig{
+				Outputs:        outputs,
+				Routes:         tt.routes,
+				DefaultOutputs: tt.defaultOutputs,
+			}
+			err := config.validateRoutes()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
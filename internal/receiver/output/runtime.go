@@ -2,12 +2,18 @@ package output
 
 import (
 	"context"
-	"sdsyslog/internal/iomodules/beats"
-	"sdsyslog/internal/iomodules/dbusnotify"
-	"sdsyslog/internal/iomodules/file"
+	"fmt"
+	"sdsyslog/internal/iomodules"
 	"sdsyslog/internal/iomodules/generic"
-	"sdsyslog/internal/iomodules/journald"
 	"sdsyslog/internal/logctx"
+	"slices"
+
+	// Output modules register their types with the output registry
+	_ "sdsyslog/internal/iomodules/beats"
+	_ "sdsyslog/internal/iomodules/dbusnotify"
+	_ "sdsyslog/internal/iomodules/file"
+	_ "sdsyslog/internal/iomodules/journald"
+	_ "sdsyslog/internal/iomodules/syslog"
 )
 
 // Create and start new output instance
@@ -18,26 +24,30 @@ func (manager *Manager) AddWorkers() (err error) {
 	manager.cancel = cancelInstance
 	manager.Instance = *manager.newWorker()
 
-	const defaultFileBatchSize int = 20
-
 	// Add outputs
-	manager.Instance.fileMod, err = file.NewOutput(manager.Config.FilePath, defaultFileBatchSize)
-	if err != nil {
-		return
-	}
-	manager.Instance.jrnlMod, err = journald.NewOutput(manager.Config.JournaldURL)
-	if err != nil {
-		return
-	}
-	manager.Instance.beatsMod, err = beats.NewOutput(manager.Config.BeatsAddress, 6)
-	if err != nil {
-		return
+	for _, output := range manager.Config.Outputs {
+		var module iomodules.Output
+		module, err = iomodules.NewOutput(output.Type, output.Config)
+		if err != nil {
+			err = fmt.Errorf("failed to create output %q (type %s): %w", output.Name, output.Type, err)
+
+			// Outputs created before the failing one were never started
+			for _, created := range manager.Instance.outputs {
+				lerr := created.module.Shutdown()
+				if lerr != nil {
+					logctx.LogStdWarn(manager.ctx, "failed to shutdown output %q: %w\n", created.name, lerr)
+				}
+			}
+			manager.Instance.outputs = nil
+			cancelInstance()
+			return
+		}
+		manager.Instance.addOutput(output.Name, module)
 	}
-	manager.Instance.rawMod = generic.NewOutput(manager.Config.RawWriter)
-	manager.Instance.DBUSnotify, err = dbusnotify.NewOutput(manager.Config.EnableDBUSNotify)
-	if err != nil {
-		return
+	if manager.Config.RawWriter != nil {
+		manager.Instance.addOutput(rawOutputName, generic.NewOutput(manager.Config.RawWriter))
 	}
+	manager.Instance.setupRoutes(manager.Config.Routes, manager.Config.DefaultOutputs)
 
 	// Start worker
 	manager.wg.Go(func() {
@@ -47,6 +57,15 @@ func (manager *Manager) AddWorkers() (err error) {
 	return
 }
 
+// Adds output module to the instances' write list
+func (instance *Instance) addOutput(name string, module iomodules.Output) {
+	instance.outputs = append(instance.outputs, &namedOutput{
+		name:      name,
+		namespace: append(slices.Clone(instance.namespace), logctx.NSOut, name),
+		module:    module,
+	})
+}
+
 // Shutdown existing file output instance
 func (manager *Manager) RemoveWorkers() {
 	if manager.cancel != nil {
@@ -54,29 +73,11 @@ func (manager *Manager) RemoveWorkers() {
 	}
 	manager.wg.Wait()
 
-	err := manager.Instance.fileMod.Shutdown()
-	if err != nil {
-		logctx.LogStdErr(manager.ctx,
-			"failed to shutdown file module: %w\n", err)
-	}
-	err = manager.Instance.jrnlMod.Shutdown()
-	if err != nil {
-		logctx.LogStdErr(manager.ctx,
-			"failed to shutdown journal module: %w\n", err)
-	}
-	err = manager.Instance.beatsMod.Shutdown()
-	if err != nil {
-		logctx.LogStdErr(manager.ctx,
-			"failed to shutdown beats module: %w\n", err)
-	}
-	err = manager.Instance.rawMod.Shutdown()
-	if err != nil {
-		logctx.LogStdErr(manager.ctx,
-			"failed to shutdown raw module: %w\n", err)
-	}
-	err = manager.Instance.DBUSnotify.Shutdown()
-	if err != nil {
-		logctx.LogStdErr(manager.ctx,
-			"failed to shutdown DBUS notify module: %w\n", err)
+	for _, output := range manager.Instance.outputs {
+		err := output.module.Shutdown()
+		if err != nil {
+			logctx.LogStdErr(manager.ctx,
+				"failed to shutdown output %q: %w\n", output.name, err)
+		}
 	}
 }
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sync"
+	"testing"
+	"time"
+)
+
+const closeTrackingOutputType string = "test-close-tracking"
+
+// Outputs created through the registry by this test type (configured with {"fail": true} to fail creation)
+var closeTracking struct {
+	mu      sync.Mutex
+	created []*closeTrackingOutput
+}
+
+type closeTrackingOutput struct {
+	stubOutput
+	closed bool
+}
+
+func (output *closeTrackingOutput) Shutdown() (err error) {
+	output.closed = true
+	return
+}
+
+func init() {
+	iomodules.RegisterOutput(closeTrackingOutputType, func(config json.RawMessage) (module iomodules.Output, err error) {
+		var options struct {
+			Fail bool `json:"fail"`
+		}
+		err = iomodules.DecodeOutputConfig(config, &options)
+		if err != nil {
+			return
+		}
+		if options.Fail {
+			err = fmt.Errorf("refusing to create output")
+			return
+		}
+
+		output := &closeTrackingOutput{}
+		closeTracking.mu.Lock()
+		closeTracking.created = append(closeTracking.created, output)
+		closeTracking.mu.Unlock()
+		module = output
+		return
+	})
+}
+
+func TestAddWorkersClosesOutputsOnError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	config := &ManagerConfig{
+		Outputs: []OutputConfig{
+			{Name: "first", Type: closeTrackingOutputType},
+			{Name: "second", Type: closeTrackingOutputType},
+			{Name: "broken", Type: closeTrackingOutputType, Config: json.RawMessage(`{"fail": true}`)},
+		},
+		ConsecutiveFailureShutdownInterval: time.Minute,
+		MinQueueCapacity:                   global.MinValue(16),
+		MaxQueueCapacity:                   global.MaxValue(32),
+	}
+	manager, err := config.NewManager(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error creating manager: %v", err)
+	}
+
+	closeTracking.mu.Lock()
+	closeTracking.created = nil
+	closeTracking.mu.Unlock()
+
+	err = manager.AddWorkers()
+	if err == nil {
+		t.Fatalf("expected error for output that cannot be created")
+	}
+
+	closeTracking.mu.Lock()
+	defer closeTracking.mu.Unlock()
+	if len(closeTracking.created) != 2 {
+		t.Fatalf("expected 2 outputs to be created before the failure, got %d", len(closeTracking.created))
+	}
+	for index, output := range closeTracking.created {
+		if !output.closed {
+			t.Errorf("output %d was not closed after the failure", index)
+		}
+	}
+	if len(manager.Instance.outputs) != 0 {
+		t.Errorf("expected no outputs left on the instance, got %d", len(manager.Instance.outputs))
+	}
+}
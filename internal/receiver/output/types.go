@@ -2,21 +2,23 @@ package output
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"sdsyslog/internal/filtering"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/iomodules"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/pkg/protocol"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type ManagerConfig struct {
-	FilePath         string
-	JournaldURL      string
-	BeatsAddress     string
-	RawWriter        io.WriteCloser
-	EnableDBUSNotify bool
+	Outputs        []OutputConfig // Named outputs created through the output module registry
+	RawWriter      io.WriteCloser // Internal-only output (not configurable)
+	Routes         []RouteRule    // Evaluated in order, first match selects the outputs for a message
+	DefaultOutputs []string       // Outputs for messages no route matched (nil is all outputs)
 
 	ConsecutiveFailureShutdownInterval time.Duration
 
@@ -35,13 +37,29 @@ type Manager struct {
 	ctx context.Context
 }
 
+// Named output with its modules' JSON configuration block
+type OutputConfig struct {
+	Name   string
+	Type   string
+	Config json.RawMessage
+}
+
+// Routing rule selecting outputs by name for matching messages. All configured matchers must match.
+type RouteRule struct {
+	Name     string                  `json:"name"`
+	Hostname *filtering.Filter       `json:"hostname,omitempty"` // Includes verification prefix (like [UNVERIFIED])
+	RemoteIP *filtering.Filter       `json:"remoteIP,omitempty"`
+	Severity *filtering.Filter       `json:"severity,omitempty"`
+	AppName  *filtering.Filter       `json:"applicationName,omitempty"`
+	Message  *protocol.MessageFilter `json:"message,omitempty"` // Message text and custom fields
+	Outputs  []string                `json:"outputs"`
+}
+
 type Instance struct {
-	namespace  []string
-	fileMod    iomodules.Output
-	jrnlMod    iomodules.Output
-	beatsMod   iomodules.Output
-	rawMod     iomodules.Output
-	DBUSnotify iomodules.Output
+	namespace    []string
+	outputs      []*namedOutput
+	routes       []*route
+	defaultRoute *route
 
 	failures failureTracker
 
@@ -49,6 +67,28 @@ type Instance struct {
 	Metrics MetricStorage
 }
 
+// Output module instance with its own metrics
+type namedOutput struct {
+	name      string
+	namespace []string
+	module    iomodules.Output
+	metrics   outputMetrics
+}
+
+// Routing rule resolved to output instances
+type route struct {
+	rule      RouteRule
+	outputs   []*namedOutput
+	namespace []string
+	hits      atomic.Uint64 // Messages routed by this rule (cleared by metric collection)
+}
+
+type outputMetrics struct {
+	Writes              atomic.Uint64 // Messages written (cleared by metric collection)
+	Failures            atomic.Uint64 // Failed writes (cleared by metric collection)
+	ConsecutiveFailures atomic.Uint64 // Failed writes since the last success
+}
+
 type failureTracker struct {
 	consecutiveCount int
 	deadline         time.Time
@@ -52,24 +52,12 @@ func (instance *Instance) run(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			if instance.fileMod != nil {
-				_, err := instance.fileMod.FlushBuffer()
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"failed to flush file line buffer to disk: %w\n", err)
-				}
-			}
+			instance.flushOutputs(ctx)
 			return
 		case <-ticker.C:
-			if instance.fileMod != nil {
-				// Periodic flush of file output event buffer
-				// Buffer might never fill and flush if we don't get enough messages
-				_, err := instance.fileMod.FlushBuffer()
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"failed to flush file line buffer to disk: %w\n", err)
-				}
-			}
+			// Periodic flush of output event buffers
+			// Buffer might never fill and flush if we don't get enough messages
+			instance.flushOutputs(ctx)
 		case msg, ok := <-popCh:
 			func() {
 				// Record panics and continue output
@@ -88,45 +76,21 @@ func (instance *Instance) run(ctx context.Context) {
 				}
 				instance.Metrics.ReceivedMessages.Add(1)
 
-				// Write message to all outputs
-				n1, err := instance.fileMod.Write(ctx, msg)
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"Failed to write message(s) to file output: %w\n", err)
-				}
-				instance.Metrics.SuccessfulFileWrites.Add(uint64(n1))
-
-				n2, err := instance.jrnlMod.Write(ctx, msg)
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"Failed to write message(s) to journald output: %w\n", err)
-				}
-				instance.Metrics.SuccessfulJrnlWrites.Add(uint64(n2))
-
-				n3, err := instance.beatsMod.Write(ctx, msg)
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"Failed to write message(s) to beats output: %w\n", err)
-				}
-				instance.Metrics.SuccessfulBeatsWrites.Add(uint64(n3))
-
-				n4, err := instance.rawMod.Write(ctx, msg)
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"Failed to write message(s) to raw output: %w\n", err)
-				}
-				instance.Metrics.SuccessfulRawWrites.Add(uint64(n4))
-
-				n5, err := instance.DBUSnotify.Write(ctx, msg)
-				if err != nil {
-					logctx.LogStdErr(ctx,
-						"Failed to write message(s) to DBUS notify output: %w\n", err)
+				// Write message to all outputs selected by routing rules
+				// Buffering outputs write nothing until flushed, only errors are failures
+				var totalWritten, failedOutputs int
+				outputs := instance.selectRoute(msg).outputs
+				for _, output := range outputs {
+					written, failed := output.write(ctx, msg)
+					totalWritten += written
+					if failed {
+						failedOutputs++
+					}
 				}
-				instance.Metrics.SuccessfulNotifyWrites.Add(uint64(n5))
+				instance.Metrics.SuccessfulWrites.Add(uint64(totalWritten))
 
 				// Record consecutive total failures
-				totalWritten := n1 + n2 + n3 + n4 + n5
-				if totalWritten == 0 {
+				if failedOutputs == len(outputs) {
 					instance.Metrics.Dropped.Add(1)
 
 					// Initialize deadline
@@ -148,7 +112,7 @@ func (instance *Instance) run(ctx context.Context) {
 					// Long term output failures means our own logs about output failures would go unnoticed
 					// Stop entire program for better visibility into fatal conditions like this
 					// Using OS signals to conduct the graceful shutdown through the signal handler in lifecycle
-					err = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+					err := syscall.Kill(os.Getpid(), syscall.SIGTERM)
 					if err != nil {
 						logctx.LogStdFatal(ctx, "Failed to issue SIGTERM to self process after fatal amount of output write failures.\n")
 					}
@@ -159,3 +123,36 @@ func (instance *Instance) run(ctx context.Context) {
 		}
 	}
 }
+
+// Writes message to a single output, recording its success/failure
+func (output *namedOutput) write(ctx context.Context, msg *protocol.Payload) (written int, failed bool) {
+	written, err := output.module.Write(ctx, msg)
+	if err != nil {
+		logctx.LogStdErr(ctx,
+			"Failed to write message(s) to output %q: %w\n", output.name, err)
+	}
+	if written > 0 {
+		output.metrics.Writes.Add(uint64(written))
+	}
+	if err != nil {
+		output.metrics.Failures.Add(1)
+		output.metrics.ConsecutiveFailures.Add(1)
+		failed = true
+	} else {
+		output.metrics.ConsecutiveFailures.Store(0)
+	}
+	return
+}
+
+// Flushes buffered events of every output
+func (instance *Instance) flushOutputs(ctx context.Context) {
+	for _, output := range instance.outputs {
+		flushed, err := output.module.FlushBuffer()
+		if err != nil {
+			logctx.LogStdErr(ctx,
+				"failed to flush buffer of output %q: %w\n", output.name, err)
+		}
+		output.metrics.Writes.Add(uint64(flushed))
+		instance.Metrics.SuccessfulWrites.Add(uint64(flushed))
+	}
+}
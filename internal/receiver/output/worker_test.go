@@ -0,0 +1,59 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"testing"
+)
+
+// Output returning a fixed write result
+type stubOutput struct {
+	written int
+	err     error
+}
+
+func (stub *stubOutput) Write(ctx context.Context, msg *protocol.Payload) (entriesWritten int, err error) {
+	entriesWritten, err = stub.written, stub.err
+	return
+}
+
+func (stub *stubOutput) FlushBuffer() (flushedCnt int, err error) { return }
+
+func (stub *stubOutput) Shutdown() (err error) { return }
+
+func TestNamedOutputWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	tests := []struct {
+		name                string
+		module              *stubOutput
+		expectedFailed      bool
+		expectedWrites      uint64
+		expectedConsecutive uint64
+	}{
+		{name: "written", module: &stubOutput{written: 1}, expectedWrites: 1},
+		{name: "buffered until flush", module: &stubOutput{}},
+		{name: "error", module: &stubOutput{err: fmt.Errorf("disk full")}, expectedFailed: true, expectedConsecutive: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &namedOutput{name: "test", module: tt.module}
+
+			written, failed := output.write(ctx, &protocol.Payload{})
+			if failed != tt.expectedFailed {
+				t.Errorf("expected failed %v, got %v", tt.expectedFailed, failed)
+			}
+			if uint64(written) != tt.expectedWrites || output.metrics.Writes.Load() != tt.expectedWrites {
+				t.Errorf("expected %d writes, got %d (metric %d)", tt.expectedWrites, written, output.metrics.Writes.Load())
+			}
+			if output.metrics.ConsecutiveFailures.Load() != tt.expectedConsecutive {
+				t.Errorf("expected %d consecutive failures, got %d", tt.expectedConsecutive, output.metrics.ConsecutiveFailures.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package receiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/beats"
+	"sdsyslog/internal/iomodules/dbusnotify"
+	"sdsyslog/internal/iomodules/file"
+	"sdsyslog/internal/iomodules/journald"
+	"sdsyslog/internal/iomodules/syslog"
+	"sdsyslog/internal/receiver/output"
+	"slices"
+)
+
+// Builds named output list from the single output fields (named after their type) followed by configured destinations
+func (daemon *Daemon) loadOutputs() (err error) {
+	opts := daemon.opts.Outputs
+	daemon.cfg.outputs = make([]output.OutputConfig, 0, len(opts.Destinations)+5)
+
+	addOutput := func(name, outputType string, config any) (err error) {
+		rawConfig, err := json.Marshal(config)
+		if err != nil {
+			err = fmt.Errorf("failed to encode %s output config: %w", outputType, err)
+			return
+		}
+		daemon.cfg.outputs = append(daemon.cfg.outputs, output.OutputConfig{
+			Name:   name,
+			Type:   outputType,
+			Config: rawConfig,
+		})
+		return
+	}
+
+	if opts.FilePath != "" {
+		err = addOutput(file.OutputType, file.OutputType, file.OutputConfig{Path: opts.FilePath})
+		if err != nil {
+			return
+		}
+	}
+	if opts.JournaldURL != "" {
+		err = addOutput(journald.OutputType, journald.OutputType, journald.OutputConfig{URL: opts.JournaldURL})
+		if err != nil {
+			return
+		}
+	}
+	if opts.BeatsAddress != "" {
+		err = addOutput(beats.OutputType, beats.OutputType, beats.OutputConfig{Address: opts.BeatsAddress})
+		if err != nil {
+			return
+		}
+	}
+	if opts.Syslog != nil {
+		err = addOutput(syslog.OutputType, syslog.OutputType, *opts.Syslog)
+		if err != nil {
+			return
+		}
+	}
+	if opts.DBUSNotify {
+		err = addOutput(dbusnotify.OutputType, dbusnotify.OutputType, dbusnotify.OutputConfig{})
+		if err != nil {
+			return
+		}
+	}
+
+	seenNames := make(map[string]struct{}, cap(daemon.cfg.outputs))
+	for _, existing := range daemon.cfg.outputs {
+		seenNames[existing.Name] = struct{}{}
+	}
+	for index, destination := range opts.Destinations {
+		if destination.Type == "" {
+			err = fmt.Errorf("output destination %d: type is required", index)
+			return
+		}
+		if !slices.Contains(iomodules.OutputTypes(), destination.Type) {
+			err = fmt.Errorf("output destination %d: unknown type %q (must be one of %q)", index, destination.Type, iomodules.OutputTypes())
+			return
+		}
+		name := destination.Name
+		if name == "" {
+			name = destination.Type
+		}
+		if _, duplicate := seenNames[name]; duplicate {
+			err = fmt.Errorf("output destination %d: name %q is already in use", index, name)
+			return
+		}
+		seenNames[name] = struct{}{}
+
+		daemon.cfg.outputs = append(daemon.cfg.outputs, output.OutputConfig{
+			Name:   name,
+			Type:   destination.Type,
+			Config: destination.Config,
+		})
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package receiver
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadOutputs(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          string
+		expectedNames []string
+		expectedTypes []string
+		expectedError string
+	}{
+		{
+			name:          "single output fields",
+			opts:          `{"filePath": "/var/log/all.log", "beatsAddress": "localhost:5044"}`,
+			expectedNames: []string{"file", "beats"},
+			expectedTypes: []string{"file", "beats"},
+		},
+		{
+			name:          "syslog block from earlier configs",
+			opts:          `{"syslog": {"address": "siem:6514", "transport": "tls", "serverName": "siem"}}`,
+			expectedNames: []string{"syslog"},
+			expectedTypes: []string{"syslog"},
+		},
+		{
+			name: "multiple destinations of the same type",
+			opts: `{"filePath": "/var/log/all.log", "destinations": [
+				{"name": "archive", "type": "file", "config": {"path": "/srv/archive.log"}},
+				{"name": "siem", "type": "syslog", "config": {"address": "siem:514"}},
+				{"type": "journald", "config": {"url": "http://localhost:19532"}}
+			]}`,
+			expectedNames: []string{"file", "archive", "siem", "journald"},
+			expectedTypes: []string{"file", "file", "syslog", "journald"},
+		},
+		{
+			name:          "destination name collides with single output",
+			opts:          `{"filePath": "/var/log/all.log", "destinations": [{"type": "file", "config": {"path": "/tmp/x"}}]}`,
+			expectedError: `name "file" is already in use`,
+		},
+		{
+			name:          "unknown type",
+			opts:          `{"destinations": [{"name": "x", "type": "carrier-pigeon"}]}`,
+			expectedError: `unknown type "carrier-pigeon"`,
+		},
+		{
+			name:          "missing type",
+			opts:          `{"destinations": [{"name": "x"}]}`,
+			expectedError: "type is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daemon := &Daemon{}
+			err := json.Unmarshal([]byte(tt.opts), &daemon.opts.Outputs)
+			if err != nil {
+				t.Fatalf("invalid test options: %v", err)
+			}
+
+			err = daemon.loadOutputs()
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var names, types []string
+			for _, output := range daemon.cfg.outputs {
+				names = append(names, output.Name)
+				types = append(types, output.Type)
+			}
+			if !slices.Equal(names, tt.expectedNames) {
+				t.Errorf("expected names %v, got %v", tt.expectedNames, names)
+			}
+			if !slices.Equal(types, tt.expectedTypes) {
+				t.Errorf("expected types %v, got %v", tt.expectedTypes, types)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package processor
 import (
 	"sdsyslog/internal/logctx"
 	"sdsyslog/internal/metrics"
+	"slices"
 	"sync/atomic"
 	"time"
 )
@@ -22,6 +23,9 @@ const (
 	MTSumWorkTime     string = "elapsed_time_sum_ns"
 	MTMaxWorkTime     string = "elapsed_time_max_ns"
 	MTInstanceCount   string = "instance_count"
+	MTKeyDecrypted    string = "key_decrypted_payloads_total"
+	MTKeyFailed       string = "key_failed_attempts_total"
+	MTKeyCurrent      string = "key_currently_valid"
 )
 
 func (manager *Manager) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
@@ -50,6 +54,55 @@ func (manager *Manager) CollectMetrics(interval time.Duration) (collection []met
 			Timestamp: recordTime,
 		},
 	}
+
+	// Per-key usage shows when senders stopped using a key (safe to retire)
+	for _, key := range manager.Config.DecryptionKeys {
+		keyNamespace := slices.Concat(namespace, []string{logctx.NSKey, key.Name})
+
+		var current uint64
+		if key.IsValidAt(recordTime) {
+			current = 1
+		}
+
+		collection = append(collection,
+			metrics.Metric{
+				Name:        MTKeyDecrypted,
+				Description: "Total payloads decrypted with this private key in the interval",
+				Namespace:   keyNamespace,
+				Value: metrics.MetricValue{
+					Raw:      key.Decrypted.Swap(0),
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTKeyFailed,
+				Description: "Total failed decryption attempts with this private key in the interval (payload was for another key or invalid)",
+				Namespace:   keyNamespace,
+				Value: metrics.MetricValue{
+					Raw:      key.Failed.Swap(0),
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTKeyCurrent,
+				Description: "Whether this private key is inside its validity window (1) or not (0)",
+				Namespace:   keyNamespace,
+				Value: metrics.MetricValue{
+					Raw:      current,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Gauge,
+				Timestamp: recordTime,
+			},
+		)
+	}
 	return
 }
 
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"context"
+	"sdsyslog/internal/crypto/wrappers"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/internal/receiver/listener"
@@ -12,12 +13,13 @@ import (
 )
 
 type ManagerConfig struct {
-	MinQueueCapacity global.MinValue // Minimum queue size (also starting size)
-	MaxQueueCapacity global.MaxValue // Maximum queue size
-	MinInstanceCount atomic.Uint32   // Minimum number of instances at any one time
-	MaxInstanceCount atomic.Uint32   // Maximum number of instances at any one time
-	PastMsgCutoff    time.Duration   // Oldest time in the past messages can have
-	FutureMsgCutoff  time.Duration   // Max time in the future messages can have
+	MinQueueCapacity global.MinValue           // Minimum queue size (also starting size)
+	MaxQueueCapacity global.MaxValue           // Maximum queue size
+	MinInstanceCount atomic.Uint32             // Minimum number of instances at any one time
+	MaxInstanceCount atomic.Uint32             // Maximum number of instances at any one time
+	PastMsgCutoff    time.Duration             // Oldest time in the past messages can have
+	FutureMsgCutoff  time.Duration             // Max time in the future messages can have
+	DecryptionKeys   []*wrappers.DecryptionKey // Receiver keys used by payload decryption (for per-key metrics)
 }
 
 type Manager struct {
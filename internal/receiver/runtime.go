@@ -46,11 +46,10 @@ func (daemon *Daemon) Start() (err error) {
 
 	// Stage 4 - Output Manager
 	outMgrConf := &output.ManagerConfig{
-		FilePath:                           daemon.opts.Outputs.FilePath,
-		JournaldURL:                        daemon.opts.Outputs.JournaldURL,
-		BeatsAddress:                       daemon.opts.Outputs.BeatsAddress,
+		Outputs:                            daemon.cfg.outputs,
 		RawWriter:                          daemon.RawWriter,
-		EnableDBUSNotify:                   daemon.opts.Outputs.DBUSNotify,
+		Routes:                             daemon.opts.Outputs.Routes,
+		DefaultOutputs:                     daemon.opts.Outputs.DefaultOutputs,
 		ConsecutiveFailureShutdownInterval: time.Duration(daemon.opts.Outputs.MaxConsecutiveFailures),
 		MinQueueCapacity:                   daemon.opts.AutoScaling.MinOutQueueSize,
 		MaxQueueCapacity:                   daemon.opts.AutoScaling.MaxOutQueueSize,
@@ -110,6 +109,7 @@ func (daemon *Daemon) Start() (err error) {
 		MaxQueueCapacity: daemon.opts.AutoScaling.MaxProcQueueSize,
 		PastMsgCutoff:    time.Duration(daemon.opts.ReplayProtection.PastValidityWindow),
 		FutureMsgCutoff:  time.Duration(daemon.opts.ReplayProtection.FutureValidityWindow),
+		DecryptionKeys:   daemon.cfg.DecryptionKeys,
 	}
 	procMgrConf.MinInstanceCount.Store(uint32(daemon.opts.AutoScaling.MinProcessors))
 	procMgrConf.MaxInstanceCount.Store(uint32(daemon.opts.AutoScaling.MaxProcessors))
@@ -131,6 +131,7 @@ func (daemon *Daemon) Start() (err error) {
 	inMgrConf := &listener.ManagerConfig{
 		ListenSocket:           daemon.cfg.sourceSocket,
 		ReplayProtectionWindow: time.Duration(daemon.opts.ReplayProtection.ProtectionWindow),
+		AcceptedSuiteIDs:       daemon.cfg.acceptedSuiteIDs,
 	}
 	inMgrConf.MaxInstanceCount.Store(uint32(daemon.opts.AutoScaling.MaxListeners))
 	inMgrConf.MinInstanceCount.Store(uint32(daemon.opts.AutoScaling.MinListeners))
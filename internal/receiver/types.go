@@ -2,13 +2,17 @@ package receiver
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"net/http"
+	"sdsyslog/internal/crypto/wrappers"
 	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules/syslog"
 	metricGlb "sdsyslog/internal/metrics"
 	"sdsyslog/internal/parsing"
 	"sdsyslog/internal/receiver/metrics"
+	"sdsyslog/internal/receiver/output"
 	"sdsyslog/internal/receiver/shard/fiprrecv"
 	"sdsyslog/internal/receiver/shared"
 	"sync"
@@ -17,11 +21,13 @@ import (
 
 // User supplied options
 type JSONOptions struct {
-	PrivateKeyFile        string `json:"privateKeyFile"`
-	PinnedSigningKeysPath string `json:"senderSigningKeysFile,omitempty"`
+	PrivateKeyFile        string           `json:"privateKeyFile"`
+	PrivateKeys           []JSONPrivateKey `json:"privateKeys,omitempty"` // Additional keys for rotation
+	PinnedSigningKeysPath string           `json:"senderSigningKeysFile,omitempty"`
 	Crypto                struct {
-		TransportSuite string `json:"transportSuite,omitempty"`
-		SignatureSuite string `json:"signatureSuite,omitempty"`
+		TransportSuite          string   `json:"transportSuite,omitempty"`
+		AcceptedTransportSuites []string `json:"acceptedTransportSuites,omitempty"` // Suites decrypted during migrations (defaults to transportSuite only)
+		SignatureSuite          string   `json:"signatureSuite,omitempty"`
 	} `json:"crypto,omitempty"`
 	ReplayProtection struct {
 		ProtectionWindow     parsing.Duration `json:"shortTermWindow,omitempty"`      // Short term replay protection window size (For Listener)
@@ -36,12 +42,16 @@ type JSONOptions struct {
 		Port    int    `json:"port"`
 	} `json:"network"`
 	Outputs struct {
-		FilePath               string           `json:"filePath,omitempty"`
-		JournaldURL            string           `json:"journaldURL,omitempty"`
-		BeatsAddress           string           `json:"beatsAddress,omitempty"`
-		DBUSNotify             bool             `json:"desktopNotifications,omitempty"`
-		InternalLogs           bool             `json:"internalLogs,omitempty"`
-		MaxConsecutiveFailures parsing.Duration `json:"maximumConsecutiveFailures,omitempty"` // Max failures before program shutdown
+		FilePath               string               `json:"filePath,omitempty"`
+		JournaldURL            string               `json:"journaldURL,omitempty"`
+		BeatsAddress           string               `json:"beatsAddress,omitempty"`
+		Syslog                 *syslog.OutputConfig `json:"syslog,omitempty"` // Remote syslog server
+		DBUSNotify             bool                 `json:"desktopNotifications,omitempty"`
+		InternalLogs           bool                 `json:"internalLogs,omitempty"`
+		MaxConsecutiveFailures parsing.Duration     `json:"maximumConsecutiveFailures,omitempty"` // Max failures before program shutdown
+		Destinations           []JSONOutput         `json:"destinations,omitempty"`               // Named outputs (in addition to the single output fields above)
+		Routes                 []output.RouteRule   `json:"routes,omitempty"`                     // First matching rule selects outputs of a message
+		DefaultOutputs         []string             `json:"defaultOutputs,omitempty"`             // Outputs for messages no route matched (defaults to all)
 	} `json:"outputs"`
 	Metrics struct {
 		Interval          parsing.Duration `json:"collectionInterval"`
@@ -65,13 +75,33 @@ type JSONOptions struct {
 	} `json:"autoscaling"`
 }
 
+// Additional receiver private key with validity window (RFC3339 times, empty is unbounded)
+type JSONPrivateKey struct {
+	Name       string    `json:"name"`
+	File       string    `json:"file"`
+	ValidFrom  time.Time `json:"validFrom,omitzero"`
+	ValidUntil time.Time `json:"validUntil,omitzero"`
+}
+
+// Named output, config block is passed to the output module registered for the type
+type JSONOutput struct {
+	Name   string          `json:"name,omitempty"` // Defaults to type
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
 // Runtime Config
 type Config struct {
 	// Signature Verification
 	PinnedSigningKeys map[string][]byte
 
+	// Decryption keys (primary key first)
+	DecryptionKeys []*wrappers.DecryptionKey
+
 	// Parsed Input
-	sourceSocket *net.UDPAddr
+	sourceSocket     *net.UDPAddr
+	acceptedSuiteIDs []uint8
+	outputs          []output.OutputConfig
 }
 
 type Daemon struct {
@@ -75,6 +75,17 @@ func (config *ManagerConfig) NewManager(ctx context.Context, outbox *mpmc.Queue[
 		return
 	}
 
+	// Suites with large header key fields (KEM) must still leave room for message data in every packet
+	baseOverhead, err := protocol.CalculateProtocolOverhead(new.Config.cryptoSuiteID, &protocol.Payload{})
+	if err != nil {
+		return
+	}
+	if config.MaxPayloadSize <= baseOverhead {
+		err = fmt.Errorf("max payload size %d bytes cannot fit crypto suite %s protocol overhead of %d bytes",
+			config.MaxPayloadSize, config.CryptoSuiteName, baseOverhead)
+		return
+	}
+
 	new.Instances.Store(&startInstances)
 	return
 }
@@ -114,6 +125,9 @@ func (config *ManagerConfig) validate() (err error) {
 	if config.SigSuiteName == "" {
 		err = fmt.Errorf("uninitialized signature suite name")
 	}
+	if config.ParityPercent < 0 || config.ParityPercent > protocol.MaxParityPercent {
+		err = fmt.Errorf("parity percent must be between 0 and %d", protocol.MaxParityPercent)
+	}
 	if config.ThrottlingEnabled {
 		if config.OutputThrottlingThreshold == 0 {
 			err = fmt.Errorf("uninitialized output throttling threshold")
@@ -4,6 +4,9 @@ import (
 	"context"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/internal/sender/dedup"
+	"sdsyslog/internal/sender/ratelimit"
+	"sdsyslog/internal/sender/transform"
 	"sdsyslog/pkg/protocol"
 	"sync"
 	"sync/atomic"
@@ -24,8 +27,12 @@ type ManagerConfig struct {
 	cryptoSuiteID             uint8
 	sigSuiteID                uint8
 	ThrottlingEnabled         bool
-	OutputThrottlingThreshold int           // Minimum number of fragments for a message to start throttling
-	OutputThrottlingTime      time.Duration // Sleep between each fragment (packet) when throttling
+	OutputThrottlingThreshold int                 // Minimum number of fragments for a message to start throttling
+	OutputThrottlingTime      time.Duration       // Sleep between each fragment (packet) when throttling
+	ParityPercent             int                 // Parity fragments to add per message as percent of data fragments (0 = disabled)
+	Dedup                     *dedup.Deduplicator // Collapses repeated messages before rate limits (nil is disabled)
+	RateLimits                *ratelimit.Limiter  // Checked for every message after transforms and deduplication (nil is disabled)
+	Transforms                *transform.Pipeline // Applied to every message before assembly (nil is disabled)
 }
 
 type Manager struct {
@@ -45,6 +52,10 @@ type Instance struct {
 	sigSuiteID     uint8
 	hostID         int // ID for all sent messages
 	maxPayloadSize int // maximum payload size for configured destination
+	parityPercent  int // erasure coding parity fragments percent
+	dedup          *dedup.Deduplicator
+	rateLimits     *ratelimit.Limiter
+	transforms     *transform.Pipeline
 
 	throttlingEnabled         bool
 	outputThrottlingThreshold int
@@ -19,11 +19,15 @@ func (manager *Manager) newWorker() (new *Instance) {
 		Metrics:                   MetricStorage{},
 		hostID:                    manager.Config.HostID,
 		maxPayloadSize:            manager.Config.MaxPayloadSize,
+		parityPercent:             manager.Config.ParityPercent,
 		cryptoSuiteID:             manager.Config.cryptoSuiteID,
 		sigSuiteID:                manager.Config.sigSuiteID,
 		throttlingEnabled:         manager.Config.ThrottlingEnabled,
 		outputThrottlingThreshold: manager.Config.OutputThrottlingThreshold,
 		outputThrottlingTime:      manager.Config.OutputThrottlingTime,
+		dedup:                     manager.Config.Dedup,
+		rateLimits:                manager.Config.RateLimits,
+		transforms:                manager.Config.Transforms,
 	}
 	return
 }
@@ -72,6 +76,23 @@ func (instance *Instance) run() {
 				Data:      container.Data,
 			}
 
+			// Collapsed repeat records are built from an already transformed and deduplicated message
+			if !instance.dedup.Collapsed(container) {
+				// Redaction and field changes configured by the user
+				// Applied first so suppression and limits see the final message
+				instance.transforms.Apply(newMsg)
+
+				// Repeats are counted and sent later as one collapsed record
+				if !instance.dedup.Allow(newMsg) {
+					return
+				}
+			}
+
+			// Over-limit messages are counted by the limiter itself
+			if !instance.rateLimits.Allow(newMsg) {
+				return
+			}
+
 			msgLengthB := uint64(len(newMsg.Data))
 
 			instance.Metrics.TotalMessages.Add(1)
@@ -86,7 +107,8 @@ func (instance *Instance) run() {
 				instance.hostID,
 				instance.maxPayloadSize,
 				instance.cryptoSuiteID,
-				instance.sigSuiteID)
+				instance.sigSuiteID,
+				instance.parityPercent)
 			if err != nil {
 				logctx.LogStdErr(ctx, "failed serialization: %w\n", err)
 				instance.Metrics.Dropped.Add(1)
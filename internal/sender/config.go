@@ -11,8 +11,13 @@ import (
 	"runtime"
 	"sdsyslog/internal/crypto/wrappers"
 	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules/file"
 	"sdsyslog/internal/metrics/server"
 	"sdsyslog/internal/parsing"
+	"sdsyslog/internal/queue/spill"
+	"sdsyslog/internal/sender/dedup"
+	"sdsyslog/internal/sender/output"
+	"sdsyslog/internal/sender/ratelimit"
 	"sdsyslog/pkg/crypto/registry"
 	"sdsyslog/pkg/protocol"
 	"slices"
@@ -39,6 +44,33 @@ func (daemon *Daemon) LoadConfig(path string) (err error) {
 	return
 }
 
+// Compiles a parser profile from a sender config (including input include files)
+func LoadParserProfile(configPath string, name string) (parser *file.Parser, err error) {
+	daemon := &Daemon{}
+	err = daemon.LoadConfig(configPath)
+	if err != nil {
+		return
+	}
+	err = daemon.opts.loadInputs()
+	if err != nil {
+		err = fmt.Errorf("failed loading input configuration: %w", err)
+		return
+	}
+
+	for _, profile := range daemon.opts.Inputs.Parsers {
+		if profile.Name != name {
+			continue
+		}
+		parser, err = file.NewParser(profile)
+		if err != nil {
+			err = fmt.Errorf("invalid parser profile %q: %w", name, err)
+		}
+		return
+	}
+	err = fmt.Errorf("no parser profile %q in config '%s'", name, configPath)
+	return
+}
+
 // Retrieves private key from disk from configured path
 func (daemon *Daemon) LoadPubKey() (key []byte, err error) {
 	key, err = base64.StdEncoding.DecodeString(daemon.opts.PublicKey)
@@ -152,6 +184,27 @@ func (opts *JSONInputs) mergeConfigInputs(fullPath string) (err error) {
 	if newCfg.JournalEnabled {
 		opts.JournalEnabled = newCfg.JournalEnabled
 	}
+	if newCfg.KernelEnabled {
+		opts.KernelEnabled = newCfg.KernelEnabled
+	}
+	if newCfg.AuditEnabled {
+		opts.AuditEnabled = newCfg.AuditEnabled
+	}
+	if newCfg.SyslogUDPAddress != "" {
+		opts.SyslogUDPAddress = newCfg.SyslogUDPAddress
+	}
+	if newCfg.SyslogTCPAddress != "" {
+		opts.SyslogTCPAddress = newCfg.SyslogTCPAddress
+	}
+	if newCfg.UnixSocketPath != "" {
+		opts.UnixSocketPath = newCfg.UnixSocketPath
+	}
+	if newCfg.UnixStreamPath != "" {
+		opts.UnixStreamPath = newCfg.UnixStreamPath
+	}
+	if newCfg.Beats.Address != "" {
+		opts.Beats = newCfg.Beats
+	}
 
 	for _, newPath := range newCfg.FilePaths {
 		if slices.Contains(opts.FilePaths, newPath) {
@@ -159,6 +212,24 @@ func (opts *JSONInputs) mergeConfigInputs(fullPath string) (err error) {
 		}
 		opts.FilePaths = append(opts.FilePaths, newPath)
 	}
+	for _, newFile := range newCfg.Files {
+		exists := slices.ContainsFunc(opts.Files, func(existing file.InputConfig) bool {
+			return existing.Path == newFile.Path
+		})
+		if exists {
+			continue
+		}
+		opts.Files = append(opts.Files, newFile)
+	}
+
+	opts.RateLimits, err = mergeNamed(opts.RateLimits, newCfg.RateLimits, "rate limit", func(rule ratelimit.Rule) string { return rule.Name })
+	if err != nil {
+		return
+	}
+	opts.Parsers, err = mergeNamed(opts.Parsers, newCfg.Parsers, "parser profile", func(parser file.ParserConfig) string { return parser.Name })
+	if err != nil {
+		return
+	}
 
 	if opts.DropFilters == nil && newCfg.DropFilters == nil {
 		return
@@ -208,6 +279,52 @@ func (opts *JSONInputs) mergeConfigInputs(fullPath string) (err error) {
 	return
 }
 
+// Appends named definitions not yet present. The same definition in main config and include is fine, conflicting definitions are not.
+func mergeNamed[T any](existing []T, additions []T, kind string, name func(T) string) (merged []T, err error) {
+	merged = existing
+	for _, addition := range additions {
+		index := slices.IndexFunc(merged, func(current T) bool {
+			return name(current) == name(addition)
+		})
+		if index == -1 {
+			merged = append(merged, addition)
+			continue
+		}
+
+		var existingText, newText []byte
+		existingText, err = json.Marshal(merged[index])
+		if err != nil {
+			err = fmt.Errorf("failed to marshal %s %q: %w", kind, name(addition), err)
+			return
+		}
+		newText, err = json.Marshal(addition)
+		if err != nil {
+			err = fmt.Errorf("failed to marshal new %s %q: %w", kind, name(addition), err)
+			return
+		}
+		if !bytes.Equal(existingText, newText) {
+			err = fmt.Errorf("conflicting definitions for %s %q", kind, name(addition))
+			return
+		}
+	}
+	return
+}
+
+// Combines plain file paths and configured file inputs (configured settings win for the same path)
+func (opts *JSONInputs) fileInputs() (inputs []file.InputConfig) {
+	for _, path := range opts.FilePaths {
+		configured := slices.ContainsFunc(opts.Files, func(existing file.InputConfig) bool {
+			return existing.Path == path
+		})
+		if configured {
+			continue
+		}
+		inputs = append(inputs, file.InputConfig{Path: path})
+	}
+	inputs = append(inputs, opts.Files...)
+	return
+}
+
 // Sets defaults for any missing/invalid values
 func (opts *JSONOptions) setDefaults() {
 	// Crypto
@@ -298,6 +415,35 @@ func (opts *JSONOptions) setDefaults() {
 		opts.Metrics.Interval = parsing.Duration(15 * time.Second)
 	}
 
+	// Deduplication
+	if opts.Deduplication.Enabled {
+		if opts.Deduplication.Window == 0 {
+			opts.Deduplication.Window = parsing.Duration(dedup.DefaultWindow)
+		}
+		if opts.Deduplication.MaxEntries == 0 {
+			opts.Deduplication.MaxEntries = dedup.DefaultMaxEntries
+		}
+	}
+
+	// Disk spill
+	if opts.DiskSpill.Enabled {
+		if opts.DiskSpill.Directory == "" {
+			opts.DiskSpill.Directory = DefaultSpillDirectory
+		}
+		if opts.DiskSpill.HighWatermark == 0 {
+			opts.DiskSpill.HighWatermark = output.DefaultSpillHighWatermark
+		}
+		if opts.DiskSpill.MaxSizeBytes == 0 {
+			opts.DiskSpill.MaxSizeBytes = spill.DefaultMaxBytes
+		}
+		if opts.DiskSpill.SegmentSizeBytes == 0 {
+			opts.DiskSpill.SegmentSizeBytes = spill.DefaultSegmentBytes
+		}
+		if opts.DiskSpill.RetryInterval == 0 {
+			opts.DiskSpill.RetryInterval = parsing.Duration(output.DefaultSpillRetryInterval)
+		}
+	}
+
 	if opts.Throttling.MinFragmentThreshold == 0 {
 		opts.Throttling.MinFragmentThreshold = DefaultOutputThrottlingThreshold
 	}
@@ -0,0 +1,23 @@
+package dedup
+
+import "time"
+
+const (
+	DefaultWindow     time.Duration = 30 * time.Second
+	DefaultMaxEntries int           = 10000
+
+	// Custom fields added to collapsed records
+	CFrepeatCount    string = "RepeatCount"
+	CFfirstTimestamp string = "FirstTimestamp"
+	CFlastTimestamp  string = "LastTimestamp"
+
+	flushCheckInterval time.Duration = 1 * time.Second
+)
+
+// Metric Names
+const (
+	MTSuppressed string = "dedup_suppressed"
+	MTRecords    string = "dedup_records"
+	MTUntracked  string = "dedup_untracked"
+	MTEntries    string = "dedup_entries"
+)
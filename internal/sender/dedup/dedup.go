@@ -0,0 +1,158 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"maps"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"time"
+)
+
+// Reports whether the message should be sent. Repeats of a message within its window are counted and dropped.
+func (dedup *Deduplicator) Allow(msg *protocol.Message) (keep bool) {
+	keep = true
+	if dedup == nil {
+		return
+	}
+
+	key := messageKey(msg)
+
+	dedup.mu.Lock()
+	defer dedup.mu.Unlock()
+
+	existing, exists := dedup.entries[key]
+	if exists {
+		existing.repeats++
+		existing.lastRepeat = msg.Timestamp
+		dedup.metrics.Suppressed.Add(1)
+		keep = false
+		return
+	}
+
+	if len(dedup.entries) >= dedup.maxEntries {
+		dedup.metrics.Untracked.Add(1)
+		return
+	}
+
+	// Keep a private copy, later pipeline stages modify the message in place
+	dedup.entries[key] = &entry{
+		original: &protocol.Message{
+			Timestamp: msg.Timestamp,
+			Hostname:  msg.Hostname,
+			Fields:    maps.Clone(msg.Fields),
+			Data:      bytes.Clone(msg.Data),
+		},
+		windowStart: dedup.now(),
+	}
+	return
+}
+
+// Periodically pushes collapsed records of expired windows to the assembler queue until context is cancelled.
+// Repeats of windows still open at cancellation are pushed before returning.
+func (dedup *Deduplicator) Run(ctx context.Context, outbox *mpmc.Queue[*protocol.Message]) {
+	if dedup == nil {
+		return
+	}
+
+	ticker := time.NewTicker(flushCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			dedup.push(ctx, outbox, dedup.flush(dedup.now(), true))
+			return
+		case <-ticker.C:
+		}
+
+		dedup.push(ctx, outbox, dedup.flush(dedup.now(), false))
+	}
+}
+
+// Sends collapsed records to the assembler queue
+func (dedup *Deduplicator) push(ctx context.Context, outbox *mpmc.Queue[*protocol.Message], records []*protocol.Message) {
+	for _, record := range records {
+		err := outbox.Push(record, uint64(record.Size()))
+		if err != nil {
+			logctx.LogStdWarn(ctx, "failed to push collapsed repeat record to assembler queue: %w\n", err)
+			dedup.Collapsed(record)
+		}
+	}
+}
+
+// Reports whether the message is a collapsed record created by this deduplicator (once per record).
+// Collapsed records were already transformed and deduplicated as their first occurrence.
+func (dedup *Deduplicator) Collapsed(msg *protocol.Message) (collapsed bool) {
+	if dedup == nil {
+		return
+	}
+
+	dedup.mu.Lock()
+	defer dedup.mu.Unlock()
+
+	_, collapsed = dedup.collapsed[msg]
+	delete(dedup.collapsed, msg)
+	return
+}
+
+// Removes entries whose window has ended (or all entries), creating collapsed records for those with repeats
+func (dedup *Deduplicator) flush(now time.Time, all bool) (records []*protocol.Message) {
+	dedup.mu.Lock()
+	defer dedup.mu.Unlock()
+
+	for key, entry := range dedup.entries {
+		if !all && now.Sub(entry.windowStart) < dedup.window {
+			continue
+		}
+		delete(dedup.entries, key)
+
+		if entry.repeats == 0 {
+			continue
+		}
+		record := entry.collapsedRecord()
+		dedup.collapsed[record] = struct{}{}
+		records = append(records, record)
+	}
+	dedup.metrics.Records.Add(uint64(len(records)))
+	return
+}
+
+// Copy of the first occurrence carrying the repeat count and first/last timestamps
+func (entry *entry) collapsedRecord() (record *protocol.Message) {
+	record = &protocol.Message{
+		Timestamp: entry.lastRepeat,
+		Hostname:  entry.original.Hostname,
+		Fields:    maps.Clone(entry.original.Fields),
+		Data:      entry.original.Data,
+	}
+	if record.Fields == nil {
+		record.Fields = make(map[string]any)
+	}
+	record.Fields[CFrepeatCount] = int64(entry.repeats) // Protocol context values are signed
+	record.Fields[CFfirstTimestamp] = entry.original.Timestamp.Format(time.RFC3339Nano)
+	record.Fields[CFlastTimestamp] = entry.lastRepeat.Format(time.RFC3339Nano)
+	return
+}
+
+// Digest of hostname, application name and message text (length prefixed so fields cannot run into each other)
+func messageKey(msg *protocol.Message) (key [32]byte) {
+	var appname string
+	if value, exists := msg.Fields[iomodules.CFappname]; exists {
+		appname = protocol.FormatValue(value)
+	}
+
+	hasher := sha256.New()
+	for _, part := range [][]byte{[]byte(msg.Hostname), []byte(appname), msg.Data} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		hasher.Write(length[:])
+		hasher.Write(part)
+	}
+	hasher.Sum(key[:0])
+	return
+}
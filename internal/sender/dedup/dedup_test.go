@@ -0,0 +1,200 @@
+package dedup
+
+import (
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/pkg/protocol"
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	// Hostname, application name, and text of each message
+	type sample [3]string
+
+	tests := []struct {
+		name         string
+		messages     []sample
+		expectedKept []bool
+	}{
+		{
+			name:         "repeats are held",
+			messages:     []sample{{"h1", "svc", "crash"}, {"h1", "svc", "crash"}, {"h1", "svc", "crash"}},
+			expectedKept: []bool{true, false, false},
+		},
+		{
+			name: "hostname application and text distinguish messages",
+			messages: []sample{
+				{"h1", "svc", "crash"},
+				{"h2", "svc", "crash"},
+				{"h1", "other", "crash"},
+				{"h1", "svc", "crash again"},
+			},
+			expectedKept: []bool{true, true, true, true},
+		},
+		{
+			name:         "field boundaries are part of the key",
+			messages:     []sample{{"ab", "c", "d"}, {"a", "bc", "d"}},
+			expectedKept: []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dedup, err := New(nil, time.Minute, 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for index, values := range tt.messages {
+				kept := dedup.Allow(&protocol.Message{
+					Hostname: values[0],
+					Fields:   map[string]any{iomodules.CFappname: values[1]},
+					Data:     []byte(values[2]),
+				})
+				if kept != tt.expectedKept[index] {
+					t.Errorf("message %d: expected kept %v, got %v", index, tt.expectedKept[index], kept)
+				}
+			}
+		})
+	}
+
+	// Fields named like the ones of collapsed records do not exempt a message from deduplication
+	dedup, err := New(nil, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for index, expected := range []bool{true, false} {
+		kept := dedup.Allow(&protocol.Message{
+			Hostname: "h1",
+			Fields:   map[string]any{CFrepeatCount: int64(2)},
+			Data:     []byte("crash"),
+		})
+		if kept != expected {
+			t.Errorf("message %d with repeat count field: expected kept %v, got %v", index, expected, kept)
+		}
+	}
+}
+
+func TestFlush(t *testing.T) {
+	windowStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dedup, err := New(nil, 30*time.Second, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dedup.now = func() time.Time { return windowStart }
+
+	original := &protocol.Message{
+		Timestamp: windowStart,
+		Hostname:  "h1",
+		Fields:    map[string]any{iomodules.CFappname: "svc", iomodules.CFseverity: "err"},
+		Data:      []byte("crash"),
+	}
+	dedup.Allow(original)
+	lastRepeat := windowStart
+	for range 4 {
+		lastRepeat = lastRepeat.Add(time.Second)
+		dedup.Allow(&protocol.Message{
+			Timestamp: lastRepeat,
+			Hostname:  "h1",
+			Fields:    map[string]any{iomodules.CFappname: "svc"},
+			Data:      []byte("crash"),
+		})
+	}
+	dedup.Allow(&protocol.Message{Hostname: "h1", Data: []byte("single")})
+
+	// Later stages modify messages in place, the held copy must not change
+	original.Fields["Site"] = "changed"
+
+	if records := dedup.flush(windowStart.Add(29*time.Second), false); len(records) != 0 {
+		t.Fatalf("expected no records within the window, got %d", len(records))
+	}
+	records := dedup.flush(windowStart.Add(30*time.Second), false)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 collapsed record, got %d", len(records))
+	}
+
+	record := records[0]
+	if string(record.Data) != "crash" || record.Hostname != "h1" {
+		t.Errorf("unexpected collapsed record %q from %q", record.Data, record.Hostname)
+	}
+	if record.Fields[CFrepeatCount] != int64(4) {
+		t.Errorf("expected repeat count 4, got %v", record.Fields[CFrepeatCount])
+	}
+	if record.Fields[CFfirstTimestamp] != windowStart.Format(time.RFC3339Nano) {
+		t.Errorf("unexpected first timestamp %v", record.Fields[CFfirstTimestamp])
+	}
+	if record.Fields[CFlastTimestamp] != lastRepeat.Format(time.RFC3339Nano) || !record.Timestamp.Equal(lastRepeat) {
+		t.Errorf("unexpected last timestamp %v (record timestamp %v)", record.Fields[CFlastTimestamp], record.Timestamp)
+	}
+	if _, exists := record.Fields["Site"]; exists {
+		t.Errorf("collapsed record picked up changes made to the original message")
+	}
+	if record.Fields[iomodules.CFseverity] != "err" {
+		t.Errorf("expected original fields to be kept, got %v", record.Fields)
+	}
+
+	// Only records created by flush are marked as collapsed, once
+	if !dedup.Collapsed(record) {
+		t.Errorf("expected flushed record to be marked as collapsed")
+	}
+	if dedup.Collapsed(record) || dedup.Collapsed(original) {
+		t.Errorf("expected collapsed mark to be taken once and only for flushed records")
+	}
+
+	// Windows are cleared, next occurrence starts a new window
+	if len(dedup.entries) != 0 {
+		t.Errorf("expected expired entries to be removed, %d remain", len(dedup.entries))
+	}
+	if !dedup.Allow(original) {
+		t.Errorf("expected first message of a new window to pass")
+	}
+
+	// Shutdown sends repeats of windows that are still open
+	dedup.Allow(original)
+	records = dedup.flush(windowStart, true)
+	if len(records) != 1 || records[0].Fields[CFrepeatCount] != int64(1) {
+		t.Fatalf("expected 1 collapsed record with 1 repeat on shutdown, got %v", records)
+	}
+	if len(dedup.entries) != 0 {
+		t.Errorf("expected all entries to be removed on shutdown, %d remain", len(dedup.entries))
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	dedup, err := New(nil, time.Minute, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message := func(text string) (msg *protocol.Message) {
+		msg = &protocol.Message{Hostname: "h1", Data: []byte(text)}
+		return
+	}
+
+	dedup.Allow(message("a"))
+	dedup.Allow(message("b"))
+
+	// Window is full, new messages are never held
+	for range 3 {
+		if !dedup.Allow(message("c")) {
+			t.Errorf("expected untracked message to pass")
+		}
+	}
+	if untracked := dedup.metrics.Untracked.Load(); untracked != 3 {
+		t.Errorf("expected 3 untracked messages, got %d", untracked)
+	}
+
+	// Tracked messages are still deduplicated
+	if dedup.Allow(message("a")) {
+		t.Errorf("expected repeat of tracked message to be held")
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, 0, 10)
+	if err == nil {
+		t.Errorf("expected error for empty window")
+	}
+	_, err = New(nil, time.Second, 0)
+	if err == nil {
+		t.Errorf("expected error for empty maximum entries")
+	}
+}
@@ -0,0 +1,76 @@
+package dedup
+
+import (
+	"sdsyslog/internal/metrics"
+	"time"
+)
+
+func (dedup *Deduplicator) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	if dedup == nil {
+		return
+	}
+
+	// Read and clear
+	suppressed := dedup.metrics.Suppressed.Swap(0)
+	records := dedup.metrics.Records.Swap(0)
+	untracked := dedup.metrics.Untracked.Swap(0)
+
+	dedup.mu.Lock()
+	entries := uint64(len(dedup.entries))
+	dedup.mu.Unlock()
+
+	// Record read time
+	recordTime := time.Now()
+
+	collection = []metrics.Metric{
+		{
+			Name:        MTSuppressed,
+			Description: "Total repeated messages collapsed",
+			Namespace:   dedup.namespace,
+			Value: metrics.MetricValue{
+				Raw:      suppressed,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTRecords,
+			Description: "Total collapsed repeat records emitted",
+			Namespace:   dedup.namespace,
+			Value: metrics.MetricValue{
+				Raw:      records,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTUntracked,
+			Description: "Total messages sent without deduplication because the window was full",
+			Namespace:   dedup.namespace,
+			Value: metrics.MetricValue{
+				Raw:      untracked,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Counter,
+			Timestamp: recordTime,
+		},
+		{
+			Name:        MTEntries,
+			Description: "Current number of distinct messages tracked in the window",
+			Namespace:   dedup.namespace,
+			Value: metrics.MetricValue{
+				Raw:      entries,
+				Unit:     "count",
+				Interval: interval,
+			},
+			Type:      metrics.Gauge,
+			Timestamp: recordTime,
+		},
+	}
+	return
+}
@@ -0,0 +1,30 @@
+// Suppression of repeated identical messages within a window, collapsed into a single record with a repeat count
+package dedup
+
+import (
+	"fmt"
+	"sdsyslog/pkg/protocol"
+	"time"
+)
+
+// Creates new deduplication window
+func New(namespace []string, window time.Duration, maxEntries int) (new *Deduplicator, err error) {
+	if window <= 0 {
+		err = fmt.Errorf("window must be a positive duration")
+		return
+	}
+	if maxEntries <= 0 {
+		err = fmt.Errorf("maximum entries must be a positive number")
+		return
+	}
+
+	new = &Deduplicator{
+		window:     window,
+		maxEntries: maxEntries,
+		namespace:  namespace,
+		now:        time.Now,
+		entries:    make(map[[32]byte]*entry),
+		collapsed:  make(map[*protocol.Message]struct{}),
+	}
+	return
+}
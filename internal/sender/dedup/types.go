@@ -0,0 +1,36 @@
+package dedup
+
+import (
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Collapses identical messages (hostname, application name and text) seen within a window. Shared by all assembler instances.
+type Deduplicator struct {
+	window     time.Duration
+	maxEntries int
+	namespace  []string
+	now        func() time.Time
+
+	mu        sync.Mutex
+	entries   map[[32]byte]*entry
+	collapsed map[*protocol.Message]struct{} // Records created by flush and not yet taken by an assembler
+
+	metrics metricStorage
+}
+
+// First occurrence of a message and its repeats within the current window
+type entry struct {
+	original    *protocol.Message // Copy of the first occurrence (sent immediately)
+	windowStart time.Time
+	repeats     uint64
+	lastRepeat  time.Time // Timestamp of the latest repeat
+}
+
+type metricStorage struct {
+	Suppressed atomic.Uint64 // Repeats collapsed (cleared by metric collection)
+	Records    atomic.Uint64 // Collapsed records emitted (cleared by metric collection)
+	Untracked  atomic.Uint64 // Messages passed without deduplication because the window was full (cleared by metric collection)
+}
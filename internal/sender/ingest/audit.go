@@ -0,0 +1,33 @@
+package ingest
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules/audit"
+)
+
+// Create kernel audit ingest instance
+func (manager *Manager) AddAuditInstance() (err error) {
+	if manager.AuditSource != nil {
+		err = fmt.Errorf("cannot start a new audit instance with one running")
+		return
+	}
+
+	filters := manager.Config.SourceDropFilters[AuditSource]
+	module, err := audit.NewInput(manager.ctx, filters, manager.outQueue)
+	if err != nil {
+		return
+	}
+	manager.AuditSource = module
+
+	err = manager.AuditSource.Start()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Remove existing kernel audit ingest instance
+func (manager *Manager) RemoveAuditInstance() (err error) {
+	err = manager.AuditSource.Shutdown()
+	return
+}
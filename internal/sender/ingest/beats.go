@@ -0,0 +1,37 @@
+package ingest
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules/beats"
+)
+
+// Create beats (lumberjack) server ingest instance
+func (manager *Manager) AddBeatsInstance(config beats.InputConfig) (err error) {
+	if manager.BeatsSource != nil {
+		err = fmt.Errorf("cannot start a new beats instance with one running")
+		return
+	}
+
+	filters := manager.Config.SourceDropFilters[BeatsSource]
+	module, err := beats.NewInput(manager.ctx, config, filters, manager.outQueue)
+	if err != nil {
+		return
+	}
+	if module == nil {
+		err = fmt.Errorf("no beats listen address provided")
+		return
+	}
+	manager.BeatsSource = module
+
+	err = manager.BeatsSource.Start()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Remove existing beats ingest instance
+func (manager *Manager) RemoveBeatsInstance() (err error) {
+	err = manager.BeatsSource.Shutdown()
+	return
+}
@@ -1,7 +1,21 @@
 package ingest
 
+import "time"
+
 const (
 	// For main config filter identification
-	FileSource string = "file"
-	JrnlSource string = "journald"
+	FileSource   string = "file"
+	JrnlSource   string = "journald"
+	SyslogSource string = "syslog"
+	DevLogSource string = "devlog"
+	BeatsSource  string = "beats"
+	KmsgSource   string = "kmsg"
+	AuditSource  string = "audit"
+)
+
+const (
+	// Wait for directory changes to settle before rescanning (rotation renames and recreates in quick succession)
+	fileWatchSettleDelay time.Duration = 250 * time.Millisecond
+	// Files that stopped matching (like renamed on rotation) are read until no new data arrived for this long
+	fileRetireIdleTimeout time.Duration = 5 * time.Second
 )
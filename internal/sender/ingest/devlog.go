@@ -0,0 +1,37 @@
+package ingest
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules/devlog"
+)
+
+// Create local log socket ingest instance
+func (manager *Manager) AddDevLogInstance(datagramPath string, streamPath string) (err error) {
+	if manager.DevLogSource != nil {
+		err = fmt.Errorf("cannot start a new local socket instance with one running")
+		return
+	}
+
+	filters := manager.Config.SourceDropFilters[DevLogSource]
+	module, err := devlog.NewInput(manager.ctx, datagramPath, streamPath, filters, manager.outQueue)
+	if err != nil {
+		return
+	}
+	if module == nil {
+		err = fmt.Errorf("no local socket paths provided")
+		return
+	}
+	manager.DevLogSource = module
+
+	err = manager.DevLogSource.Start()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Remove existing local log socket ingest instance
+func (manager *Manager) RemoveDevLogInstance() (err error) {
+	err = manager.DevLogSource.Shutdown()
+	return
+}
@@ -2,25 +2,35 @@ package ingest
 
 import (
 	"fmt"
-	"path/filepath"
+	"sdsyslog/internal/iomodules"
 	"sdsyslog/internal/iomodules/file"
 )
 
 // Create file ingest instance
-func (manager *Manager) AddFileInstance(filePath string, stateFile string) (err error) {
+func (manager *Manager) AddFileInstance(config file.InputConfig, stateFile string) (err error) {
+	filePath := config.Path
+
 	manager.FileSourceMu.Lock()
 	defer manager.FileSourceMu.Unlock()
 
-	filename := filepath.Base(filePath)
-	_, ok := manager.FileSources[filename]
+	_, ok := manager.FileSources[filePath]
 	if ok {
 		err = fmt.Errorf("cannot start a new file instance with one running for path '%s'", filePath)
 		return
 	}
 
+	var parser *file.Parser
+	if config.Parser != "" {
+		parser, ok = manager.Config.FileParsers[config.Parser]
+		if !ok {
+			err = fmt.Errorf("unknown parser profile %q for path '%s'", config.Parser, filePath)
+			return
+		}
+	}
+
 	// Worker for this file
 	filters := manager.Config.SourceDropFilters[FileSource]
-	new, err := file.NewInput(manager.ctx, filePath, stateFile, filters, manager.outQueue)
+	new, err := file.NewInput(manager.ctx, config, parser, stateFile, filters, manager.outQueue)
 	if err != nil {
 		return
 	}
@@ -35,13 +45,13 @@ func (manager *Manager) AddFileInstance(filePath string, stateFile string) (err
 }
 
 // Remove existing file ingest instance
-func (manager *Manager) RemoveFileInstance(filename string) (err error) {
+func (manager *Manager) RemoveFileInstance(filePath string) (err error) {
 	manager.FileSourceMu.Lock()
 	defer manager.FileSourceMu.Unlock()
 
-	fileSource, ok := manager.FileSources[filename]
+	fileSource, ok := manager.FileSources[filePath]
 	if !ok {
-		err = fmt.Errorf("no file source for '%s'", filename)
+		err = fmt.Errorf("no file source for '%s'", filePath)
 		return
 	}
 
@@ -49,6 +59,20 @@ func (manager *Manager) RemoveFileInstance(filename string) (err error) {
 	if err != nil {
 		return
 	}
-	manager.FileSources[filename] = nil
+	delete(manager.FileSources, filePath)
+	return
+}
+
+// Removes file ingest instance from the manager without stopping it, the caller takes over stopping it
+func (manager *Manager) DetachFileInstance(filePath string) (fileSource iomodules.Input, err error) {
+	manager.FileSourceMu.Lock()
+	defer manager.FileSourceMu.Unlock()
+
+	fileSource, ok := manager.FileSources[filePath]
+	if !ok {
+		err = fmt.Errorf("no file source for '%s'", filePath)
+		return
+	}
+	delete(manager.FileSources, filePath)
 	return
 }
@@ -0,0 +1,194 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sdsyslog/internal/iomodules/file"
+	"sdsyslog/internal/iomodules/file/inotify"
+	"sdsyslog/internal/logctx"
+	"time"
+)
+
+// Starts file ingest instances for all files matching a glob or directory input, then follows files being created and removed
+func (manager *Manager) AddFileWatch(config file.InputConfig, stateFile string) (err error) {
+	watch := &FileWatch{
+		config:    config,
+		stateFile: stateFile,
+		sources:   make(map[string]struct{}),
+		retiring:  make(map[*file.InModule]struct{}),
+		manager:   manager,
+	}
+	watch.ctx, watch.cancel = context.WithCancel(manager.ctx)
+
+	watch.watcher, err = inotify.NewDirWatcher(watch.ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to create directory watcher for file input %q: %w", config.Path, err)
+		watch.cancel()
+		return
+	}
+
+	err = watch.sync()
+	if err != nil {
+		watch.cancel()
+		watch.watcher.Stop()
+		return
+	}
+
+	watch.watcher.Start()
+	watch.wg.Add(1)
+	go watch.run()
+
+	manager.FileSourceMu.Lock()
+	manager.FileWatches = append(manager.FileWatches, watch)
+	manager.FileSourceMu.Unlock()
+	return
+}
+
+// Stops following glob and directory inputs (already started file instances keep running, retired files stop reading)
+func (manager *Manager) RemoveFileWatches() {
+	manager.FileSourceMu.Lock()
+	watches := manager.FileWatches
+	manager.FileWatches = nil
+	manager.FileSourceMu.Unlock()
+
+	for _, watch := range watches {
+		watch.cancel()
+		watch.wg.Wait()
+		watch.watcher.Stop()
+
+		watch.retireMu.Lock()
+		for source := range watch.retiring {
+			_ = source.Shutdown()
+		}
+		watch.retireMu.Unlock()
+		watch.retireWG.Wait()
+	}
+}
+
+// Rescans the input after directory changes until cancelled
+func (watch *FileWatch) run() {
+	defer watch.wg.Done()
+
+	for {
+		select {
+		case <-watch.ctx.Done():
+			return
+		case <-watch.watcher.Changed():
+		}
+
+		// Let related changes (like rename and recreate on rotation) settle
+		select {
+		case <-watch.ctx.Done():
+			return
+		case <-time.After(fileWatchSettleDelay):
+		}
+		select {
+		case <-watch.watcher.Changed():
+		default:
+		}
+
+		err := watch.sync()
+		if err != nil {
+			logctx.LogStdWarn(watch.ctx, "%w\n", err)
+		}
+	}
+}
+
+// Starts file instances for new matching files and retires those of removed files.
+// Only fails when the input path cannot be expanded.
+func (watch *FileWatch) sync() (err error) {
+	files, watchDirs, err := file.ExpandInputPath(watch.config)
+	if err != nil {
+		return
+	}
+
+	for _, directory := range watchDirs {
+		lerr := watch.watcher.Add(directory)
+		if lerr != nil {
+			// Directory might have been removed since matching
+			logctx.LogStdWarn(watch.ctx, "%w\n", lerr)
+		}
+	}
+
+	current := make(map[string]struct{}, len(files))
+	for _, filePath := range files {
+		current[filePath] = struct{}{}
+		if _, tracked := watch.sources[filePath]; tracked {
+			continue
+		}
+
+		// Already read by an explicit path or another pattern
+		watch.manager.FileSourceMu.RLock()
+		_, exists := watch.manager.FileSources[filePath]
+		watch.manager.FileSourceMu.RUnlock()
+		if exists {
+			continue
+		}
+
+		fileConfig := watch.config
+		fileConfig.Path = filePath
+		fileConfig.Recursive = false
+
+		// Unreadable file does not stop the other files, it is tried again on the next sync
+		lerr := watch.manager.AddFileInstance(fileConfig, watch.stateFile)
+		if lerr != nil {
+			logctx.LogStdWarn(watch.ctx, "failed adding file ingest instance for '%s': %w\n", filePath, lerr)
+			continue
+		}
+		watch.sources[filePath] = struct{}{}
+
+		logctx.LogEvent(watch.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"Started file ingest instance for '%s'\n", filePath)
+	}
+
+	for filePath := range watch.sources {
+		if _, exists := current[filePath]; exists {
+			continue
+		}
+		watch.retireFile(filePath)
+	}
+	return
+}
+
+// Stops the file instance of a file that no longer matches once it has read the file to its end (in the background).
+// Path is free for a new instance right away, like a file created at the same path after a rotation.
+func (watch *FileWatch) retireFile(filePath string) {
+	source, err := watch.manager.DetachFileInstance(filePath)
+	if err != nil {
+		logctx.LogStdWarn(watch.ctx, "failed to retire file ingest instance for removed file '%s': %w\n", filePath, err)
+		delete(watch.sources, filePath)
+		return
+	}
+	delete(watch.sources, filePath)
+
+	// Removed files will not be resumed, a new file at the path starts over
+	err = os.Remove(file.StateFilePath(filePath, watch.stateFile))
+	if err != nil && !os.IsNotExist(err) {
+		logctx.LogStdWarn(watch.ctx, "failed to remove state file of removed file '%s': %w\n", filePath, err)
+	}
+
+	fileSource, ok := source.(*file.InModule)
+	if !ok {
+		_ = source.Shutdown()
+		return
+	}
+
+	watch.retireMu.Lock()
+	watch.retiring[fileSource] = struct{}{}
+	watch.retireMu.Unlock()
+
+	watch.retireWG.Go(func() {
+		err := fileSource.Retire(fileRetireIdleTimeout)
+		if err != nil {
+			logctx.LogStdWarn(watch.ctx, "failed to stop file ingest instance for removed file '%s': %w\n", filePath, err)
+		}
+
+		watch.retireMu.Lock()
+		delete(watch.retiring, fileSource)
+		watch.retireMu.Unlock()
+
+		logctx.LogEvent(watch.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"Stopped file ingest instance for removed file '%s'\n", filePath)
+	})
+}
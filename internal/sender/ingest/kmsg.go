@@ -0,0 +1,33 @@
+package ingest
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules/kmsg"
+)
+
+// Create kernel ring buffer ingest instance
+func (manager *Manager) AddKmsgInstance(stateFile string) (err error) {
+	if manager.KmsgSource != nil {
+		err = fmt.Errorf("cannot start a new kernel ring buffer instance with one running")
+		return
+	}
+
+	filters := manager.Config.SourceDropFilters[KmsgSource]
+	module, err := kmsg.NewInput(manager.ctx, kmsg.DefaultPath, stateFile, filters, manager.outQueue)
+	if err != nil {
+		return
+	}
+	manager.KmsgSource = module
+
+	err = manager.KmsgSource.Start()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Remove existing kernel ring buffer ingest instance
+func (manager *Manager) RemoveKmsgInstance() (err error) {
+	err = manager.KmsgSource.Shutdown()
+	return
+}
@@ -0,0 +1,37 @@
+package ingest
+
+import (
+	"fmt"
+	"sdsyslog/internal/iomodules/syslog"
+)
+
+// Create syslog network listener ingest instance
+func (manager *Manager) AddSyslogInstance(udpAddress string, tcpAddress string) (err error) {
+	if manager.SyslogSource != nil {
+		err = fmt.Errorf("cannot start a new syslog instance with one running")
+		return
+	}
+
+	filters := manager.Config.SourceDropFilters[SyslogSource]
+	module, err := syslog.NewInput(manager.ctx, udpAddress, tcpAddress, filters, manager.outQueue)
+	if err != nil {
+		return
+	}
+	if module == nil {
+		err = fmt.Errorf("no syslog listen addresses provided")
+		return
+	}
+	manager.SyslogSource = module
+
+	err = manager.SyslogSource.Start()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Remove existing syslog ingest instance
+func (manager *Manager) RemoveSyslogInstance() (err error) {
+	err = manager.SyslogSource.Shutdown()
+	return
+}
@@ -3,6 +3,8 @@ package ingest
 import (
 	"context"
 	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/iomodules/file"
+	"sdsyslog/internal/iomodules/file/inotify"
 	"sdsyslog/internal/queue/mpmc"
 	"sdsyslog/pkg/protocol"
 	"sync"
@@ -10,14 +12,38 @@ import (
 
 type ManagerConfig struct {
 	SourceDropFilters map[string][]protocol.MessageFilter
+	FileParsers       map[string]*file.Parser // Compiled parser profiles keyed by name
 }
 
 type Manager struct {
 	Config        *ManagerConfig
 	FileSourceMu  sync.RWMutex
 	FileSources   map[string]iomodules.Input // File sources keyed by path
+	FileWatches   []*FileWatch               // Glob and directory inputs starting/retiring file sources
 	JournalSource iomodules.Input
+	SyslogSource  iomodules.Input                // Syslog network listener (UDP/TCP)
+	DevLogSource  iomodules.Input                // Local syslog sockets (/dev/log)
+	BeatsSource   iomodules.Input                // Beats (lumberjack) server
+	KmsgSource    iomodules.Input                // Kernel ring buffer (/dev/kmsg)
+	AuditSource   iomodules.Input                // Kernel audit records (netlink multicast)
 	RawSource     iomodules.Input                // Pass through of raw io reader from daemon config
 	outQueue      *mpmc.Queue[*protocol.Message] // Queue for worked completed by the pair
 	ctx           context.Context
 }
+
+// Keeps file sources in sync with the files matching a dynamic file input
+type FileWatch struct {
+	config    file.InputConfig
+	stateFile string
+	watcher   *inotify.DirWatcher
+	sources   map[string]struct{} // Paths of file sources started by this watch
+	manager   *Manager
+
+	retireMu sync.Mutex
+	retiring map[*file.InModule]struct{} // Sources of files that stopped matching, reading to the end of the file
+	retireWG sync.WaitGroup
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+	ctx    context.Context
+}
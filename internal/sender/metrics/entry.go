@@ -103,6 +103,36 @@ func (gatherer *Gatherer) runIntervalTasks(ctx context.Context, timeSlice time.T
 		gatherer.Registry.Add(timeSlice, m0)
 	}
 
+	// Kernel ring buffer input
+	if gatherer.Ingest.KmsgSource != nil {
+		m0 := gatherer.Ingest.KmsgSource.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m0)
+	}
+
+	// Kernel audit input
+	if gatherer.Ingest.AuditSource != nil {
+		m0 := gatherer.Ingest.AuditSource.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m0)
+	}
+
+	// Syslog network input
+	if gatherer.Ingest.SyslogSource != nil {
+		m0 := gatherer.Ingest.SyslogSource.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m0)
+	}
+
+	// Local socket input
+	if gatherer.Ingest.DevLogSource != nil {
+		m0 := gatherer.Ingest.DevLogSource.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m0)
+	}
+
+	// Beats server input
+	if gatherer.Ingest.BeatsSource != nil {
+		m0 := gatherer.Ingest.BeatsSource.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m0)
+	}
+
 	// Raw Input
 	if gatherer.Ingest.RawSource != nil {
 		m0 := gatherer.Ingest.RawSource.CollectMetrics(interval)
@@ -119,10 +149,24 @@ func (gatherer *Gatherer) runIntervalTasks(ctx context.Context, timeSlice time.T
 		gatherer.Registry.Add(timeSlice, m2)
 	}
 
+	m3 := gatherer.Assembler.Config.Dedup.CollectMetrics(interval)
+	gatherer.Registry.Add(timeSlice, m3)
+
+	m4 := gatherer.Assembler.Config.RateLimits.CollectMetrics(interval)
+	gatherer.Registry.Add(timeSlice, m4)
+
+	m5 := gatherer.Assembler.Config.Transforms.CollectMetrics(interval)
+	gatherer.Registry.Add(timeSlice, m5)
+
 	// Output
 	collection := gatherer.Output.InQueue.CollectMetrics(interval)
 	gatherer.Registry.Add(timeSlice, collection)
 
+	if gatherer.Output.Spill != nil {
+		m1 := gatherer.Output.Spill.CollectMetrics(interval)
+		gatherer.Registry.Add(timeSlice, m1)
+	}
+
 	outputInstances := gatherer.Output.Instances.Load()
 	for _, instance := range *outputInstances {
 		m2 := instance.CollectMetrics(interval)
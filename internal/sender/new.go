@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"sdsyslog/internal/crypto/wrappers"
 	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules/file"
 	"sdsyslog/internal/logctx"
 	"sdsyslog/internal/network"
 	"sdsyslog/internal/parsing"
+	"sdsyslog/internal/sender/dedup"
+	"sdsyslog/internal/sender/ratelimit"
+	"sdsyslog/internal/sender/transform"
 	"sdsyslog/pkg/crypto/registry"
 	"slices"
 	"time"
@@ -56,6 +60,42 @@ func (daemon *Daemon) Init(serverPub []byte) (err error) {
 		return
 	}
 
+	// File parser profiles
+	daemon.cfg.fileParsers = make(map[string]*file.Parser, len(daemon.opts.Inputs.Parsers))
+	for _, parserConfig := range daemon.opts.Inputs.Parsers {
+		if _, exists := daemon.cfg.fileParsers[parserConfig.Name]; exists {
+			err = fmt.Errorf("duplicate parser profile name %q", parserConfig.Name)
+			return
+		}
+		daemon.cfg.fileParsers[parserConfig.Name], err = file.NewParser(parserConfig)
+		if err != nil {
+			err = fmt.Errorf("invalid parser profile %q: %w", parserConfig.Name, err)
+			return
+		}
+	}
+	for _, fileInput := range daemon.opts.Inputs.Files {
+		if _, exists := daemon.cfg.fileParsers[fileInput.Parser]; fileInput.Parser != "" && !exists {
+			err = fmt.Errorf("file input '%s' uses unknown parser profile %q", fileInput.Path, fileInput.Parser)
+			return
+		}
+	}
+
+	// Rate limit settings
+	rateLimitNamespace := append(logctx.GetTagList(daemon.ctx), logctx.NSmPack, logctx.NSRateLimit)
+	daemon.cfg.rateLimits, err = ratelimit.New(rateLimitNamespace, daemon.opts.Inputs.RateLimits)
+	if err != nil {
+		err = fmt.Errorf("invalid rate limit configuration: %w", err)
+		return
+	}
+
+	// Transform settings
+	transformNamespace := append(logctx.GetTagList(daemon.ctx), logctx.NSmPack, logctx.NSTransform)
+	daemon.cfg.transforms, err = transform.New(transformNamespace, daemon.opts.Transforms)
+	if err != nil {
+		err = fmt.Errorf("invalid transform configuration: %w", err)
+		return
+	}
+
 	// Metric settings
 	err = parsing.VerifyWholeDuration(time.Duration(daemon.opts.Metrics.Interval))
 	if err != nil {
@@ -65,6 +105,18 @@ func (daemon *Daemon) Init(serverPub []byte) (err error) {
 
 	daemon.opts.setDefaults()
 
+	// Deduplication settings
+	if daemon.opts.Deduplication.Enabled {
+		dedupNamespace := append(logctx.GetTagList(daemon.ctx), logctx.NSmPack, logctx.NSDedup)
+		daemon.cfg.dedup, err = dedup.New(dedupNamespace,
+			time.Duration(daemon.opts.Deduplication.Window),
+			daemon.opts.Deduplication.MaxEntries)
+		if err != nil {
+			err = fmt.Errorf("invalid deduplication configuration: %w", err)
+			return
+		}
+	}
+
 	err = wrappers.SetupEncryptInnerPayload(serverPub)
 	if err != nil {
 		err = fmt.Errorf("failed to setup encryption function: %w", err)
@@ -0,0 +1,9 @@
+package output
+
+import "time"
+
+const (
+	DefaultSpillHighWatermark int           = 80 // Percent of memory queue capacity
+	DefaultSpillRetryInterval time.Duration = 1 * time.Second
+	spillPollInterval         time.Duration = 50 * time.Millisecond
+)
@@ -7,6 +7,7 @@ import (
 	"net"
 	"sdsyslog/internal/logctx"
 	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/internal/queue/spill"
 )
 
 // Creates new instance manager (and its own inbox)
@@ -37,12 +38,27 @@ func (config *ManagerConfig) NewManager(ctx context.Context) (new *Manager, err
 		return
 	}
 
+	// Setup disk overflow (persisted fragments from a previous run are replayed first)
+	var spillQueue *spill.Queue
+	if config.SpillDirectory != "" {
+		spillQueue, err = spill.Open(logctx.GetTagList(ctx), config.SpillDirectory, config.SpillMaxBytes, config.SpillSegmentBytes)
+		if err != nil {
+			err = fmt.Errorf("failed to open disk spill queue: %w", err)
+			return
+		}
+		if spillQueue.Len() > 0 {
+			logctx.LogStdInfo(ctx,
+				"Found %d fragment(s) on disk from previous run, replaying before new fragments\n", spillQueue.Len())
+		}
+	}
+
 	startInstances := make([]*Instance, 0, config.MinInstanceCount.Load())
 
 	new = &Manager{
 		Config:  config,
 		InQueue: inQueue,
 		outDest: destinationConnection,
+		Spill:   spillQueue,
 		ctx:     ctx,
 	}
 	new.Instances.Store(&startInstances)
@@ -75,5 +91,16 @@ func (config *ManagerConfig) validate() (err error) {
 	if config.DestAddress == nil {
 		err = fmt.Errorf("empty destination address")
 	}
+	if config.SpillDirectory != "" {
+		if config.SpillHighWatermark == 0 {
+			config.SpillHighWatermark = DefaultSpillHighWatermark
+		}
+		if config.SpillHighWatermark < 1 || config.SpillHighWatermark > 100 {
+			err = fmt.Errorf("spill high watermark must be between 1 and 100 percent")
+		}
+		if config.SpillRetryInterval == 0 {
+			config.SpillRetryInterval = DefaultSpillRetryInterval
+		}
+	}
 	return
 }
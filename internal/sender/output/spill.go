@@ -0,0 +1,124 @@
+package output
+
+import (
+	"context"
+	"runtime/debug"
+	"sdsyslog/internal/logctx"
+	"time"
+)
+
+// Starts background mover of memory queue fragments to disk. No-op when spilling is disabled.
+func (manager *Manager) StartSpiller() {
+	if manager == nil || manager.Spill == nil {
+		return
+	}
+
+	ctx := logctx.AppendCtxTag(manager.ctx, logctx.NSSpill)
+	ctx, manager.spillCancel = context.WithCancel(ctx)
+	manager.spillWg.Go(func() {
+		manager.spiller(ctx)
+	})
+}
+
+// Stops spiller, moves whatever is left in memory to disk, and closes the disk queue.
+// Must be called after all output instances are stopped.
+func (manager *Manager) StopSpiller() (err error) {
+	if manager == nil || manager.Spill == nil {
+		return
+	}
+
+	if manager.spillCancel != nil {
+		manager.spillCancel()
+	}
+	manager.spillWg.Wait()
+
+	// Persist remaining fragments for the next process
+	var persisted int
+	for manager.spillOne(manager.ctx) {
+		persisted++
+	}
+	if persisted > 0 {
+		logctx.LogStdInfo(manager.ctx,
+			"Persisted %d queued fragment(s) to disk for delivery after restart\n", persisted)
+	}
+
+	err = manager.Spill.Close()
+	return
+}
+
+// Moves oldest fragments from memory to disk while the memory queue is above the high watermark (down to the low watermark)
+// or while the network is unavailable (everything).
+func (manager *Manager) spiller(ctx context.Context) {
+	ticker := time.NewTicker(spillPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		func() {
+			// Record panics and continue working
+			defer func() {
+				if fatalError := recover(); fatalError != nil {
+					stack := debug.Stack()
+					logctx.LogStdErr(ctx,
+						"panic in output spill thread: %v\n%s", fatalError, stack)
+				}
+			}()
+
+			highMark, lowMark := manager.watermarks()
+			if !manager.linkDown.Load() && manager.memoryDepth() < highMark {
+				return
+			}
+
+			var moved int
+			for ctx.Err() == nil {
+				depth := manager.memoryDepth()
+				if depth == 0 || (!manager.linkDown.Load() && depth <= lowMark) {
+					break
+				}
+				if !manager.spillOne(ctx) {
+					break
+				}
+				moved++
+			}
+
+			logctx.LogEvent(ctx, logctx.VerbosityData, logctx.InfoLog,
+				"Spilled %d fragment(s) to disk (%d on disk)\n", moved, manager.Spill.Len())
+		}()
+	}
+}
+
+// Moves a single fragment from memory to disk. Returns false if memory queue is empty.
+func (manager *Manager) spillOne(ctx context.Context) (moved bool) {
+	popCtx, cancel := context.WithTimeout(ctx, spillPollInterval)
+	frag, ok := manager.InQueue.Pop(popCtx)
+	cancel()
+	if !ok {
+		return
+	}
+	moved = true
+
+	err := manager.Spill.Push(frag)
+	if err != nil {
+		logctx.LogStdErr(ctx, "Failed to spill fragment to disk: %w\n", err)
+	}
+	return
+}
+
+// Current number of fragments in memory queue
+func (manager *Manager) memoryDepth() (depth uint64) {
+	depth = manager.InQueue.ActiveWrite.Load().Metrics.Depth.Load()
+	return
+}
+
+// Depth thresholds based on current memory queue capacity
+func (manager *Manager) watermarks() (high uint64, low uint64) {
+	capacity := manager.InQueue.ActiveWrite.Load().Metrics.Capacity.Load()
+	high = capacity * uint64(manager.Config.SpillHighWatermark) / 100
+	low = high / 2
+	return
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/spill"
+	"testing"
+	"time"
+)
+
+func TestSpiller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logctx.New(ctx, logctx.NSTest, 1, ctx.Done())
+
+	spillDir := t.TempDir()
+
+	config := &ManagerConfig{
+		MinQueueCapacity:   global.MinValue(64),
+		MaxQueueCapacity:   global.MaxValue(128),
+		SourceAddress:      &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)},
+		DestAddress:        &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9},
+		SpillDirectory:     spillDir,
+		SpillHighWatermark: 50,
+	}
+	config.MinInstanceCount.Store(1)
+	config.MaxInstanceCount.Store(2)
+
+	manager, err := config.NewManager(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error creating manager: %v", err)
+	}
+
+	// Fill memory queue past high watermark (no workers running)
+	const total = 60
+	for i := range total {
+		frag := []byte(fmt.Sprintf("fragment-%02d", i))
+		err = manager.InQueue.Push(frag, uint64(len(frag)))
+		if err != nil {
+			t.Fatalf("unexpected error pushing fragment %d: %v", i, err)
+		}
+	}
+
+	manager.StartSpiller()
+
+	highMark, lowMark := manager.watermarks()
+	deadline := time.Now().Add(2 * time.Second)
+	for manager.memoryDepth() > lowMark && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if manager.memoryDepth() > lowMark {
+		t.Fatalf("expected memory depth at or below low watermark %d (high %d), got %d", lowMark, highMark, manager.memoryDepth())
+	}
+	spilled := manager.Spill.Len()
+	if spilled == 0 {
+		t.Fatalf("expected fragments to be spilled to disk")
+	}
+
+	// Shutdown persists the rest
+	err = manager.StopSpiller()
+	if err != nil {
+		t.Fatalf("unexpected error stopping spiller: %v", err)
+	}
+	if manager.memoryDepth() != 0 {
+		t.Errorf("expected memory queue to be empty after stop, got %d", manager.memoryDepth())
+	}
+
+	// Next process replays everything in original order
+	reopened, err := spill.Open(nil, spillDir, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reopening spill queue: %v", err)
+	}
+	defer reopened.Close()
+
+	if reopened.Len() != total {
+		t.Fatalf("expected %d fragments on disk, got %d", total, reopened.Len())
+	}
+	for i := range total {
+		data, ok, err := reopened.Pop()
+		if err != nil || !ok {
+			t.Fatalf("unexpected pop failure at %d: ok=%v err=%v", i, ok, err)
+		}
+		expected := fmt.Sprintf("fragment-%02d", i)
+		if string(data) != expected {
+			t.Fatalf("expected %q, got %q", expected, data)
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"net"
 	"sdsyslog/internal/global"
 	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/internal/queue/spill"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type ManagerConfig struct {
@@ -16,6 +18,13 @@ type ManagerConfig struct {
 	MaxInstanceCount atomic.Uint32   // Maximum number of instances at any one time
 	SourceAddress    *net.UDPAddr    // Source listen address
 	DestAddress      *net.UDPAddr
+
+	// Disk spill (disabled when directory is empty)
+	SpillDirectory     string
+	SpillMaxBytes      uint64 // Upper bound of unsent data on disk
+	SpillSegmentBytes  uint64 // Size of individual segment files
+	SpillHighWatermark int    // Percent of memory queue capacity that starts spilling
+	SpillRetryInterval time.Duration
 }
 
 type Manager struct {
@@ -23,12 +32,19 @@ type Manager struct {
 	Instances atomic.Pointer[[]*Instance] // Existing running instances
 	InQueue   *mpmc.Queue[[]byte]         // Shared inbox for all workers
 	outDest   *net.UDPConn                // Destination for all workers
+	Spill     *spill.Queue                // Overflow for inbox (nil when disabled)
+	linkDown  atomic.Bool                 // Set while sends are failing, forces all queued fragments to disk
 	ctx       context.Context
+
+	spillWg     sync.WaitGroup
+	spillCancel context.CancelFunc
 }
 
 type Instance struct {
 	inbox   *mpmc.Queue[[]byte]
+	spill   *spill.Queue
 	conn    *net.UDPConn
+	manager *Manager
 	Metrics MetricStorage
 
 	ctx    context.Context
@@ -1,9 +1,12 @@
 package output
 
 import (
+	"context"
+	"fmt"
 	"runtime/debug"
 	"sdsyslog/internal/atomics"
 	"sdsyslog/internal/logctx"
+	"time"
 )
 
 func (manager *Manager) newWorker() (new *Instance) {
@@ -13,7 +16,9 @@ func (manager *Manager) newWorker() (new *Instance) {
 
 	new = &Instance{
 		inbox:   manager.InQueue,
+		spill:   manager.Spill,
 		conn:    manager.outDest,
+		manager: manager,
 		Metrics: MetricStorage{},
 	}
 	return
@@ -43,20 +48,26 @@ func (instance *Instance) run() {
 				}
 			}()
 
-			frag, ok := instance.inbox.Pop(ctx)
+			frag, fromDisk, ok := instance.nextFragment(ctx)
 			if !ok {
 				return
 			}
-			// Subtract data size from sum
-			atomics.Subtract(&instance.inbox.ActiveWrite.Load().Metrics.Bytes, uint64(len(frag)), 4)
+			if !fromDisk {
+				// Subtract data size from sum
+				atomics.Subtract(&instance.inbox.ActiveWrite.Load().Metrics.Bytes, uint64(len(frag)), 4)
+			}
 
-			_, err := instance.conn.Write(frag)
+			sent, err := instance.send(ctx, frag)
 			if err != nil {
 				logctx.LogStdErr(ctx,
 					"Failed to send fragment: %w\n", err)
 				instance.Metrics.Dropped.Add(1)
 				return
 			}
+			if !sent {
+				// Persisted to disk at shutdown
+				return
+			}
 
 			pktLengthB := uint64(len(frag))
 			instance.Metrics.SumPacketBytes.Add(pktLengthB)
@@ -73,3 +84,67 @@ func (instance *Instance) run() {
 		}()
 	}
 }
+
+// Retrieves next fragment to send. Fragments on disk are older than anything in memory, so they always go first.
+func (instance *Instance) nextFragment(ctx context.Context) (frag []byte, fromDisk bool, ok bool) {
+	if instance.spill != nil && instance.spill.Len() > 0 {
+		var err error
+		frag, ok, err = instance.spill.Pop()
+		if err != nil {
+			logctx.LogStdErr(ctx,
+				"Failed to read fragment from disk spill queue: %w\n", err)
+		}
+		if ok {
+			fromDisk = true
+			return
+		}
+	}
+
+	frag, ok = instance.inbox.Pop(ctx)
+	return
+}
+
+// Writes fragment to network.
+// With spilling enabled, failed writes are retried until the network recovers (or shutdown, where the fragment is persisted to the head of the disk queue instead).
+func (instance *Instance) send(ctx context.Context, frag []byte) (sent bool, err error) {
+	_, err = instance.conn.Write(frag)
+	if err == nil {
+		sent = true
+		if instance.manager.linkDown.CompareAndSwap(true, false) {
+			logctx.LogStdInfo(ctx, "Fragment sending recovered, replaying fragments from disk\n")
+		}
+		return
+	}
+	if instance.spill == nil {
+		return
+	}
+
+	if !instance.manager.linkDown.Swap(true) {
+		logctx.LogStdWarn(ctx,
+			"Fragment sending failed, buffering fragments on disk until network recovers: %w\n", err)
+	}
+
+	ticker := time.NewTicker(instance.manager.Config.SpillRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Fragment is older than everything queued, so it is replayed first
+			err = instance.spill.PushFront(frag)
+			if err != nil {
+				err = fmt.Errorf("failed to persist unsent fragment: %w", err)
+			}
+			return
+		case <-ticker.C:
+		}
+
+		_, err = instance.conn.Write(frag)
+		if err == nil {
+			sent = true
+			if instance.manager.linkDown.CompareAndSwap(true, false) {
+				logctx.LogStdInfo(ctx, "Fragment sending recovered, replaying fragments from disk\n")
+			}
+			return
+		}
+	}
+}
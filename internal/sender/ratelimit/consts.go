@@ -0,0 +1,37 @@
+package ratelimit
+
+import "time"
+
+const (
+	// Over-limit actions
+	ActionDrop      string = "drop"
+	ActionSample    string = "sample"
+	ActionSummarize string = "summarize"
+
+	// Bucket keys
+	KeyInput    string = "input"
+	KeyAppName  string = "applicationName"
+	KeySeverity string = "severity"
+
+	DefaultSummaryInterval time.Duration = 1 * time.Minute
+	DefaultMaxKeys         int           = 1000
+
+	// Custom fields added to limited messages and summary records
+	CFsampleRate      string = "SampleRate"
+	CFrateLimit       string = "RateLimit"
+	CFsuppressedCount string = "SuppressedCount"
+	CFsuppressedInput string = "SuppressedInput"
+
+	summarySeverity      string        = "notice"
+	summaryCheckInterval time.Duration = 1 * time.Second
+	overflowKey          string        = "\x00overflow" // Bucket shared by new keys once maximum keys are tracked
+	keySeparator         string        = "\x00"
+)
+
+// Metric Names
+const (
+	MTSuppressed string = "ratelimit_suppressed"
+	MTSampled    string = "ratelimit_sampled"
+	MTSummaries  string = "ratelimit_summaries"
+	MTKeys       string = "ratelimit_keys"
+)
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"time"
+)
+
+// Reports whether the message is within its rate limit. Sampled messages are annotated with the sample rate.
+func (limiter *Limiter) Allow(msg *protocol.Message) (keep bool) {
+	keep = true
+	if limiter == nil {
+		return
+	}
+
+	// Summary records are never limited
+	if source, _ := msg.Fields[iomodules.CtxKey].(string); source == logctx.NSRateLimit {
+		return
+	}
+
+	for _, rule := range limiter.rules {
+		if rule.Match != nil && !rule.Match.Match(msg) {
+			continue
+		}
+		keep = rule.allow(msg, limiter.now())
+		return
+	}
+	return
+}
+
+// Takes a token from the messages' bucket and applies the over-limit action when empty
+func (rule *rule) allow(msg *protocol.Message, now time.Time) (keep bool) {
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+
+	bucket := rule.bucketFor(msg, now)
+	bucket.refill(now, rule.Rate, rule.Burst)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		keep = true
+		return
+	}
+
+	switch rule.Action {
+	case ActionSample:
+		bucket.overLimit++
+		if (bucket.overLimit-1)%uint64(rule.SampleRate) == 0 {
+			if msg.Fields == nil {
+				msg.Fields = make(map[string]any)
+			}
+			msg.Fields[CFsampleRate] = rule.SampleRate
+			rule.metrics.Sampled.Add(1)
+			keep = true
+			return
+		}
+	case ActionSummarize:
+		bucket.suppressed++
+	}
+	rule.metrics.Suppressed.Add(1)
+	return
+}
+
+// Finds or creates the bucket for the message key (caller holds lock)
+func (rule *rule) bucketFor(msg *protocol.Message, now time.Time) (selected *bucket) {
+	keyValues := make([]string, 0, len(rule.KeyBy))
+	for _, keyName := range rule.KeyBy {
+		keyValues = append(keyValues, keyValue(msg, keyName))
+	}
+	key := strings.Join(keyValues, keySeparator)
+
+	selected, exists := rule.buckets[key]
+	if exists {
+		return
+	}
+
+	if len(rule.buckets) >= rule.MaxKeys {
+		rule.pruneIdle(now)
+	}
+	if len(rule.buckets) >= rule.MaxKeys {
+		// Remaining new keys share a single bucket until existing keys go idle
+		key = overflowKey
+		keyValues = nil
+		selected, exists = rule.buckets[key]
+		if exists {
+			return
+		}
+	}
+
+	selected = &bucket{
+		keyValues: keyValues,
+		tokens:    float64(rule.Burst),
+		updated:   now,
+	}
+	rule.buckets[key] = selected
+	return
+}
+
+// Removes buckets that are full again and have nothing left to summarize (caller holds lock)
+func (rule *rule) pruneIdle(now time.Time) {
+	for key, bucket := range rule.buckets {
+		bucket.refill(now, rule.Rate, rule.Burst)
+		if bucket.tokens >= float64(rule.Burst) && bucket.suppressed == 0 {
+			delete(rule.buckets, key)
+		}
+	}
+}
+
+// Adds tokens for the time elapsed since the last update, up to the burst size
+func (bucket *bucket) refill(now time.Time, rate float64, burst int) {
+	elapsed := now.Sub(bucket.updated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	bucket.tokens = min(float64(burst), bucket.tokens+elapsed*rate)
+	bucket.updated = now
+}
+
+// Text value of the messages' field used as bucket key (empty if missing)
+func keyValue(msg *protocol.Message, keyName string) (value string) {
+	var field string
+	switch keyName {
+	case KeyInput:
+		field = iomodules.CtxKey
+	case KeyAppName:
+		field = iomodules.CFappname
+	case KeySeverity:
+		field = iomodules.CFseverity
+	}
+
+	raw, exists := msg.Fields[field]
+	if !exists {
+		return
+	}
+	value = protocol.FormatValue(raw)
+	return
+}
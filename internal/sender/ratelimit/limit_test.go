@@ -0,0 +1,329 @@
+package ratelimit
+
+import (
+	"sdsyslog/internal/crypto/wrappers"
+	"sdsyslog/internal/filtering"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/parsing"
+	"sdsyslog/pkg/crypto/registry"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Creates limiter with a manually advanced clock
+func newTestLimiter(t *testing.T, rules []Rule) (limiter *Limiter, clock *time.Time) {
+	t.Helper()
+
+	limiter, err := New([]string{"Test"}, rules)
+	if err != nil {
+		t.Fatalf("unexpected error creating limiter: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock = &now
+	limiter.now = func() time.Time { return *clock }
+	return
+}
+
+func testMessage(appname, severity string) (msg *protocol.Message) {
+	msg = &protocol.Message{
+		Data: []byte("text"),
+		Fields: map[string]any{
+			iomodules.CtxKey:     "Sender/Ingest/File",
+			iomodules.CFappname:  appname,
+			iomodules.CFseverity: severity,
+		},
+	}
+	return
+}
+
+func TestAllow(t *testing.T) {
+	tests := []struct {
+		name               string
+		rule               Rule
+		messages           []*protocol.Message
+		advance            time.Duration // Clock advance before each message
+		expectedKept       int
+		expectedSuppressed uint64
+		expectedSampled    uint64
+	}{
+		{
+			name:               "drop over burst",
+			rule:               Rule{Name: "r", Rate: 1, Burst: 3},
+			messages:           repeat(testMessage("app", "info"), 10),
+			expectedKept:       3,
+			expectedSuppressed: 7,
+		},
+		{
+			name:         "refill at rate",
+			rule:         Rule{Name: "r", Rate: 2, Burst: 1},
+			messages:     repeat(testMessage("app", "info"), 10),
+			advance:      500 * time.Millisecond,
+			expectedKept: 10,
+		},
+		{
+			name:               "sample one in N over limit",
+			rule:               Rule{Name: "r", Rate: 1, Burst: 2, Action: ActionSample, SampleRate: 3},
+			messages:           repeat(testMessage("app", "info"), 11),
+			expectedKept:       5, // 2 burst + over-limit messages 1, 4, 7
+			expectedSuppressed: 6,
+			expectedSampled:    3,
+		},
+		{
+			name: "separate buckets per key",
+			rule: Rule{Name: "r", Rate: 1, Burst: 1, KeyBy: []string{KeyAppName}},
+			messages: []*protocol.Message{
+				testMessage("a", "info"), testMessage("a", "info"),
+				testMessage("b", "info"), testMessage("b", "info"),
+			},
+			expectedKept:       2,
+			expectedSuppressed: 2,
+		},
+		{
+			name: "unmatched messages are not limited",
+			rule: Rule{
+				Name:  "r",
+				Rate:  1,
+				Burst: 1,
+				Match: &protocol.MessageFilter{Fields: map[string]*filtering.Filter{iomodules.CFappname: {Exact: "other"}}},
+			},
+			messages:     repeat(testMessage("app", "info"), 3),
+			expectedKept: 3,
+		},
+		{
+			name: "summary records pass",
+			rule: Rule{Name: "r", Rate: 1, Burst: 1},
+			messages: repeat(&protocol.Message{
+				Fields: map[string]any{iomodules.CtxKey: logctx.NSRateLimit},
+			}, 5),
+			expectedKept: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, clock := newTestLimiter(t, []Rule{tt.rule})
+
+			var kept int
+			for _, msg := range tt.messages {
+				*clock = clock.Add(tt.advance)
+				if limiter.Allow(msg) {
+					kept++
+				}
+			}
+
+			rule := limiter.rules[0]
+			if kept != tt.expectedKept {
+				t.Errorf("expected %d kept messages, got %d", tt.expectedKept, kept)
+			}
+			if suppressed := rule.metrics.Suppressed.Load(); suppressed != tt.expectedSuppressed {
+				t.Errorf("expected %d suppressed messages, got %d", tt.expectedSuppressed, suppressed)
+			}
+			if sampled := rule.metrics.Sampled.Load(); sampled != tt.expectedSampled {
+				t.Errorf("expected %d sampled messages, got %d", tt.expectedSampled, sampled)
+			}
+		})
+	}
+}
+
+func TestAllowSampleAnnotation(t *testing.T) {
+	limiter, _ := newTestLimiter(t, []Rule{{Name: "r", Rate: 1, Burst: 1, Action: ActionSample, SampleRate: 10}})
+
+	first := testMessage("app", "info")
+	limiter.Allow(first)
+	if _, exists := first.Fields[CFsampleRate]; exists {
+		t.Errorf("message within limit should not carry a sample rate")
+	}
+
+	sampled := testMessage("app", "info")
+	if !limiter.Allow(sampled) {
+		t.Fatalf("expected first over-limit message to be sampled")
+	}
+	if sampled.Fields[CFsampleRate] != 10 {
+		t.Errorf("expected sample rate field 10, got %v", sampled.Fields[CFsampleRate])
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	limiter, clock := newTestLimiter(t, []Rule{{
+		Name:            "flood",
+		Rate:            1,
+		Burst:           1,
+		Action:          ActionSummarize,
+		SummaryInterval: parsing.Duration(time.Minute),
+		KeyBy:           []string{KeyAppName, KeySeverity},
+	}})
+
+	// Starts the summary interval
+	if records := limiter.summaries(*clock, false); len(records) != 0 {
+		t.Fatalf("expected no summary records before any suppression, got %d", len(records))
+	}
+
+	for range 5 {
+		limiter.Allow(testMessage("noisy", "err"))
+	}
+	limiter.Allow(testMessage("quiet", "info"))
+
+	*clock = clock.Add(30 * time.Second)
+	if records := limiter.summaries(*clock, false); len(records) != 0 {
+		t.Fatalf("expected no summary records within the interval, got %d", len(records))
+	}
+
+	*clock = clock.Add(30 * time.Second)
+	records := limiter.summaries(*clock, false)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 summary record, got %d", len(records))
+	}
+
+	record := records[0]
+	expectedText := `4 messages suppressed by rate limit "flood" (applicationName=noisy, severity=err)`
+	if string(record.Data) != expectedText {
+		t.Errorf("expected summary text %q, got %q", expectedText, record.Data)
+	}
+	if record.Fields[CFsuppressedCount] != int64(4) {
+		t.Errorf("expected suppressed count 4, got %v", record.Fields[CFsuppressedCount])
+	}
+	if record.Fields[CFrateLimit] != "flood" || record.Fields[iomodules.CFappname] != "noisy" {
+		t.Errorf("unexpected summary fields: %v", record.Fields)
+	}
+	if !limiter.Allow(record) {
+		t.Errorf("summary record should not be rate limited")
+	}
+
+	// Counts were reset and idle buckets pruned
+	*clock = clock.Add(time.Minute)
+	if records := limiter.summaries(*clock, false); len(records) != 0 {
+		t.Errorf("expected no summary records after reset, got %d", len(records))
+	}
+	if len(limiter.rules[0].buckets) != 0 {
+		t.Errorf("expected idle buckets to be pruned, %d remain", len(limiter.rules[0].buckets))
+	}
+
+	// Shutdown reports suppressed counts before the interval has elapsed
+	for range 3 {
+		limiter.Allow(testMessage("noisy", "err"))
+	}
+	*clock = clock.Add(time.Second)
+	records = limiter.summaries(*clock, true)
+	if len(records) != 1 || records[0].Fields[CFsuppressedCount] != int64(2) {
+		t.Errorf("expected 1 summary record with 2 suppressed on shutdown, got %v", records)
+	}
+}
+
+func TestSummaryRecordSerializes(t *testing.T) {
+	info, _ := registry.GetSuiteInfo(1)
+	_, mockPub, err := info.NewKey()
+	if err != nil {
+		t.Fatalf("failed to generate test keys: %v", err)
+	}
+	err = wrappers.SetupEncryptInnerPayload(mockPub)
+	if err != nil {
+		t.Fatalf("unexpected error setting up encryption func: %v", err)
+	}
+
+	limiter, clock := newTestLimiter(t, []Rule{{
+		Name:            "flood",
+		Rate:            1,
+		Burst:           1,
+		Action:          ActionSummarize,
+		SummaryInterval: parsing.Duration(time.Minute),
+		KeyBy:           []string{KeyInput, KeyAppName},
+	}})
+	limiter.hostname = "host1"
+	limiter.summaries(*clock, false)
+	for range 3 {
+		limiter.Allow(testMessage("noisy", "err"))
+	}
+
+	*clock = clock.Add(time.Minute)
+	records := limiter.summaries(*clock, false)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 summary record, got %d", len(records))
+	}
+
+	// Every field value must be a type the protocol can carry
+	packets, err := protocol.Create(records[0], 1, 1400, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("summary record cannot be sent: %v", err)
+	}
+	if len(packets) == 0 {
+		t.Errorf("expected packets for summary record")
+	}
+}
+
+func TestMaxKeys(t *testing.T) {
+	limiter, clock := newTestLimiter(t, []Rule{{Name: "r", Rate: 1, Burst: 1, KeyBy: []string{KeyAppName}, MaxKeys: 2}})
+
+	limiter.Allow(testMessage("a", "info"))
+	limiter.Allow(testMessage("b", "info"))
+
+	// New keys share the overflow bucket while tracked keys are busy
+	if !limiter.Allow(testMessage("c", "info")) {
+		t.Errorf("expected first overflow message to pass")
+	}
+	if limiter.Allow(testMessage("d", "info")) {
+		t.Errorf("expected second overflow message to share the exhausted overflow bucket")
+	}
+	if len(limiter.rules[0].buckets) != 3 {
+		t.Errorf("expected 2 keys and the overflow bucket, got %d buckets", len(limiter.rules[0].buckets))
+	}
+
+	// Idle keys are pruned to make room
+	*clock = clock.Add(time.Minute)
+	if !limiter.Allow(testMessage("e", "info")) {
+		t.Errorf("expected new key to get its own bucket after idle keys were pruned")
+	}
+	if _, exists := limiter.rules[0].buckets["e"]; !exists {
+		t.Errorf("expected bucket for new key after pruning")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         []Rule
+		expectedError string
+	}{
+		{
+			name:  "valid rules",
+			rules: []Rule{{Name: "a", Rate: 0.5}, {Name: "b", Rate: 10, Action: ActionSummarize, KeyBy: []string{KeyInput}}},
+		},
+		{name: "missing name", rules: []Rule{{Rate: 1}}, expectedError: "has no name"},
+		{name: "duplicate name", rules: []Rule{{Name: "a", Rate: 1}, {Name: "a", Rate: 1}}, expectedError: "duplicate rate limit name"},
+		{name: "zero rate", rules: []Rule{{Name: "a"}}, expectedError: "positive number"},
+		{name: "unknown key", rules: []Rule{{Name: "a", Rate: 1, KeyBy: []string{"hostname"}}}, expectedError: "invalid key"},
+		{name: "duplicate key", rules: []Rule{{Name: "a", Rate: 1, KeyBy: []string{KeyInput, KeyInput}}}, expectedError: "duplicate key"},
+		{name: "unknown action", rules: []Rule{{Name: "a", Rate: 1, Action: "block"}}, expectedError: "invalid action"},
+		{name: "sample without rate", rules: []Rule{{Name: "a", Rate: 1, Action: ActionSample}}, expectedError: "sample rate of at least 2"},
+		{name: "sample rate with drop", rules: []Rule{{Name: "a", Rate: 1, SampleRate: 5}}, expectedError: "only valid with the sample action"},
+		{
+			name:          "summary interval with drop",
+			rules:         []Rule{{Name: "a", Rate: 1, SummaryInterval: parsing.Duration(time.Second)}},
+			expectedError: "only valid with the summarize action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(nil, tt.rules)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func repeat(msg *protocol.Message, count int) (messages []*protocol.Message) {
+	for range count {
+		messages = append(messages, msg)
+	}
+	return
+}
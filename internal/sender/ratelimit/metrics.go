@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"sdsyslog/internal/metrics"
+	"time"
+)
+
+func (limiter *Limiter) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	if limiter == nil {
+		return
+	}
+
+	// Record read time
+	recordTime := time.Now()
+
+	for _, rule := range limiter.rules {
+		// Read and clear
+		suppressed := rule.metrics.Suppressed.Swap(0)
+		sampled := rule.metrics.Sampled.Swap(0)
+		summaries := rule.metrics.Summaries.Swap(0)
+
+		rule.mu.Lock()
+		keys := uint64(len(rule.buckets))
+		rule.mu.Unlock()
+
+		collection = append(collection,
+			metrics.Metric{
+				Name:        MTSuppressed,
+				Description: "Total messages suppressed by rate limit",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      suppressed,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTSampled,
+				Description: "Total over-limit messages kept by sampling",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      sampled,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTSummaries,
+				Description: "Total suppression summary records emitted",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      summaries,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTKeys,
+				Description: "Current number of tracked rate limit buckets",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      keys,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Gauge,
+				Timestamp: recordTime,
+			},
+		)
+	}
+	return
+}
@@ -0,0 +1,132 @@
+// Token bucket rate limiting of messages before assembly with drop, sample and summarize actions
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sdsyslog/internal/parsing"
+	"slices"
+	"time"
+)
+
+// Validates rate limit rules and applies defaults. No rules returns a nil limiter.
+func New(namespace []string, rules []Rule) (limiter *Limiter, err error) {
+	if len(rules) == 0 {
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve hostname for summary records: %w", err)
+		return
+	}
+
+	limiter = &Limiter{
+		rules:    make([]*rule, 0, len(rules)),
+		hostname: hostname,
+		now:      time.Now,
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	for index, userRule := range rules {
+		if userRule.Name == "" {
+			err = fmt.Errorf("rate limit at index %d has no name", index)
+			return
+		}
+		if _, duplicate := names[userRule.Name]; duplicate {
+			err = fmt.Errorf("duplicate rate limit name %q", userRule.Name)
+			return
+		}
+		names[userRule.Name] = struct{}{}
+
+		err = userRule.validate()
+		if err != nil {
+			err = fmt.Errorf("rate limit %q: %w", userRule.Name, err)
+			return
+		}
+		userRule.setDefaults()
+
+		limiter.rules = append(limiter.rules, &rule{
+			Rule:      userRule,
+			namespace: append(slices.Clone(namespace), userRule.Name),
+			buckets:   make(map[string]*bucket),
+		})
+	}
+	return
+}
+
+// Checks rule values for invalid combinations
+func (userRule Rule) validate() (err error) {
+	if userRule.Match != nil {
+		err = userRule.Match.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid match filter: %w", err)
+			return
+		}
+	}
+	for index, key := range userRule.KeyBy {
+		if !slices.Contains([]string{KeyInput, KeyAppName, KeySeverity}, key) {
+			err = fmt.Errorf("invalid key %q: must be one of %q", key, []string{KeyInput, KeyAppName, KeySeverity})
+			return
+		}
+		if slices.Contains(userRule.KeyBy[:index], key) {
+			err = fmt.Errorf("duplicate key %q", key)
+			return
+		}
+	}
+	if userRule.Rate <= 0 || math.IsInf(userRule.Rate, 0) || math.IsNaN(userRule.Rate) {
+		err = fmt.Errorf("messages per second must be a positive number")
+		return
+	}
+	if userRule.Burst < 0 {
+		err = fmt.Errorf("burst cannot be negative")
+		return
+	}
+	if userRule.MaxKeys < 0 {
+		err = fmt.Errorf("maximum keys cannot be negative")
+		return
+	}
+
+	switch userRule.Action {
+	case "", ActionDrop:
+	case ActionSample:
+		if userRule.SampleRate < 2 {
+			err = fmt.Errorf("sample action requires a sample rate of at least 2")
+			return
+		}
+	case ActionSummarize:
+		if userRule.SummaryInterval < 0 {
+			err = fmt.Errorf("summary interval cannot be negative")
+			return
+		}
+	default:
+		err = fmt.Errorf("invalid action %q: must be one of %q", userRule.Action, []string{ActionDrop, ActionSample, ActionSummarize})
+		return
+	}
+	if userRule.SampleRate != 0 && userRule.Action != ActionSample {
+		err = fmt.Errorf("sample rate is only valid with the sample action")
+		return
+	}
+	if userRule.SummaryInterval != 0 && userRule.Action != ActionSummarize {
+		err = fmt.Errorf("summary interval is only valid with the summarize action")
+		return
+	}
+	return
+}
+
+// Fills in defaults for unset values
+func (userRule *Rule) setDefaults() {
+	if userRule.Action == "" {
+		userRule.Action = ActionDrop
+	}
+	if userRule.Burst == 0 {
+		userRule.Burst = int(math.Ceil(userRule.Rate))
+	}
+	if userRule.MaxKeys == 0 {
+		userRule.MaxKeys = DefaultMaxKeys
+	}
+	if userRule.Action == ActionSummarize && userRule.SummaryInterval == 0 {
+		userRule.SummaryInterval = parsing.Duration(DefaultSummaryInterval)
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules"
+	"sdsyslog/internal/logctx"
+	"sdsyslog/internal/queue/mpmc"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"time"
+)
+
+// Periodically pushes summary records of suppressed messages to the assembler queue until context is cancelled.
+// Suppressed counts not yet reported at cancellation are pushed before returning.
+func (limiter *Limiter) Run(ctx context.Context, outbox *mpmc.Queue[*protocol.Message]) {
+	if limiter == nil {
+		return
+	}
+
+	ticker := time.NewTicker(summaryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			limiter.push(ctx, outbox, limiter.summaries(limiter.now(), true))
+			return
+		case <-ticker.C:
+		}
+
+		limiter.push(ctx, outbox, limiter.summaries(limiter.now(), false))
+	}
+}
+
+// Sends summary records to the assembler queue
+func (limiter *Limiter) push(ctx context.Context, outbox *mpmc.Queue[*protocol.Message], records []*protocol.Message) {
+	for _, summary := range records {
+		err := outbox.Push(summary, uint64(summary.Size()))
+		if err != nil {
+			logctx.LogStdWarn(ctx, "failed to push rate limit summary to assembler queue: %w\n", err)
+		}
+	}
+}
+
+// Creates summary records for summarize rules whose interval has elapsed (or all rules) and prunes idle buckets
+func (limiter *Limiter) summaries(now time.Time, all bool) (records []*protocol.Message) {
+	for _, rule := range limiter.rules {
+		if rule.Action != ActionSummarize {
+			continue
+		}
+
+		rule.mu.Lock()
+		if rule.lastSummary.IsZero() {
+			rule.lastSummary = now
+		}
+		if !all && now.Sub(rule.lastSummary) < time.Duration(rule.SummaryInterval) {
+			rule.mu.Unlock()
+			continue
+		}
+		rule.lastSummary = now
+
+		var emitted uint64
+		for _, bucket := range rule.buckets {
+			if bucket.suppressed == 0 {
+				continue
+			}
+			records = append(records, limiter.summaryRecord(rule, bucket, now))
+			bucket.suppressed = 0
+			emitted++
+		}
+		rule.pruneIdle(now)
+		rule.mu.Unlock()
+
+		rule.metrics.Summaries.Add(emitted)
+	}
+	return
+}
+
+// Builds the "N messages suppressed" record for one bucket of a rule
+func (limiter *Limiter) summaryRecord(rule *rule, bucket *bucket, now time.Time) (record *protocol.Message) {
+	record = &protocol.Message{
+		Timestamp: now,
+		Hostname:  limiter.hostname,
+		Fields: map[string]any{
+			iomodules.CtxKey:      logctx.NSRateLimit,
+			iomodules.CFappname:   global.ProgBaseName,
+			iomodules.CFprocessid: os.Getpid(),
+			iomodules.CFfacility:  iomodules.DefaultFacility,
+			iomodules.CFseverity:  summarySeverity,
+			CFrateLimit:           rule.Name,
+			CFsuppressedCount:     int64(bucket.suppressed), // Protocol context values are signed
+		},
+	}
+
+	var keyText []string
+	for index, value := range bucket.keyValues {
+		keyName := rule.KeyBy[index]
+		keyText = append(keyText, keyName+"="+value)
+		if value == "" {
+			continue
+		}
+		switch keyName {
+		case KeyInput:
+			record.Fields[CFsuppressedInput] = value
+		case KeyAppName:
+			record.Fields[iomodules.CFappname] = value
+		case KeySeverity:
+			record.Fields[iomodules.CFseverity] = value
+		}
+	}
+
+	text := fmt.Sprintf("%d messages suppressed by rate limit %q", bucket.suppressed, rule.Name)
+	if len(keyText) > 0 {
+		text += " (" + strings.Join(keyText, ", ") + ")"
+	} else if len(rule.KeyBy) > 0 {
+		text += " (keys beyond maximum tracked)"
+	}
+	record.Data = []byte(text)
+	return
+}
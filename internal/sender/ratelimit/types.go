@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"sdsyslog/internal/parsing"
+	"sdsyslog/pkg/protocol"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// User supplied token bucket rate limit. The first matching rule applies to a message.
+type Rule struct {
+	Name            string                  `json:"name"`
+	Match           *protocol.MessageFilter `json:"match,omitempty"`           // Only limit matching messages (all messages when unset)
+	KeyBy           []string                `json:"keyBy,omitempty"`           // Separate bucket per value of input, applicationName and/or severity
+	Rate            float64                 `json:"messagesPerSecond"`         // Token refill rate
+	Burst           int                     `json:"burst,omitempty"`           // Bucket size (default rate rounded up)
+	Action          string                  `json:"action,omitempty"`          // Over-limit action: drop (default), sample or summarize
+	SampleRate      int                     `json:"sampleRate,omitempty"`      // Sample action keeps 1 in N over-limit messages
+	SummaryInterval parsing.Duration        `json:"summaryInterval,omitempty"` // Summarize action record interval (default 1m)
+	MaxKeys         int                     `json:"maxKeys,omitempty"`         // Maximum tracked buckets, new keys beyond share one bucket
+}
+
+// Ordered set of rate limit rules shared by all assembler instances
+type Limiter struct {
+	rules    []*rule
+	hostname string
+	now      func() time.Time
+}
+
+// Rate limit rule with its buckets
+type rule struct {
+	Rule
+	namespace []string
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	lastSummary time.Time
+
+	metrics ruleMetrics
+}
+
+// Token bucket for one key of a rule
+type bucket struct {
+	keyValues  []string // Values of the rules' key fields
+	tokens     float64
+	updated    time.Time
+	overLimit  uint64 // Over-limit messages seen (for sampling)
+	suppressed uint64 // Suppressed messages since the last summary record
+}
+
+type ruleMetrics struct {
+	Suppressed atomic.Uint64 // Messages dropped by the rule (cleared by metric collection)
+	Sampled    atomic.Uint64 // Over-limit messages kept by sampling (cleared by metric collection)
+	Summaries  atomic.Uint64 // Summary records emitted (cleared by metric collection)
+}
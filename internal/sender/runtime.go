@@ -2,6 +2,7 @@
 package sender
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -42,6 +43,13 @@ func (daemon *Daemon) Start() (err error) {
 		SourceAddress:    daemon.cfg.sourceSocket,
 		DestAddress:      daemon.cfg.destSocket,
 	}
+	if daemon.opts.DiskSpill.Enabled {
+		outMgrConf.SpillDirectory = daemon.opts.DiskSpill.Directory
+		outMgrConf.SpillMaxBytes = daemon.opts.DiskSpill.MaxSizeBytes
+		outMgrConf.SpillSegmentBytes = daemon.opts.DiskSpill.SegmentSizeBytes
+		outMgrConf.SpillHighWatermark = daemon.opts.DiskSpill.HighWatermark
+		outMgrConf.SpillRetryInterval = time.Duration(daemon.opts.DiskSpill.RetryInterval)
+	}
 	outMgrConf.MinInstanceCount.Store(uint32(daemon.opts.AutoScaling.MinOutputs))
 	outMgrConf.MaxInstanceCount.Store(uint32(daemon.opts.AutoScaling.MaxOutputs))
 	daemon.Mgrs.Out, err = outMgrConf.NewManager(daemon.ctx)
@@ -57,6 +65,12 @@ func (daemon *Daemon) Start() (err error) {
 	logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
 		"%d output instance(s) started successfully\n", daemon.opts.AutoScaling.MinOutputs)
 
+	if daemon.opts.DiskSpill.Enabled {
+		daemon.Mgrs.Out.StartSpiller()
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"Output disk spill instance started successfully\n")
+	}
+
 	// Stage 2 - Assembler Manager
 	pkgMgrConf := &assembler.ManagerConfig{
 		MinQueueCapacity:          daemon.opts.AutoScaling.MinAssemblerQueueSize,
@@ -68,6 +82,10 @@ func (daemon *Daemon) Start() (err error) {
 		ThrottlingEnabled:         daemon.opts.Throttling.Enabled,
 		OutputThrottlingThreshold: daemon.opts.Throttling.MinFragmentThreshold,
 		OutputThrottlingTime:      time.Duration(daemon.opts.Throttling.PerFragmentDelay),
+		ParityPercent:             daemon.opts.ErrorCorrection.ParityPercent,
+		Dedup:                     daemon.cfg.dedup,
+		RateLimits:                daemon.cfg.rateLimits,
+		Transforms:                daemon.cfg.transforms,
 	}
 	pkgMgrConf.MinInstanceCount.Store(uint32(daemon.opts.AutoScaling.MinAssemblers))
 	pkgMgrConf.MaxInstanceCount.Store(uint32(daemon.opts.AutoScaling.MaxAssemblers))
@@ -84,6 +102,26 @@ func (daemon *Daemon) Start() (err error) {
 	logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
 		"%d assembler instance(s) started successfully\n", daemon.opts.AutoScaling.MinAssemblers)
 
+	// Stage 2 - Collapsed repeat records
+	var summaryCtx context.Context
+	summaryCtx, daemon.summaryCancel = context.WithCancel(daemon.ctx)
+	if daemon.cfg.dedup != nil {
+		daemon.summaryWG.Go(func() {
+			daemon.cfg.dedup.Run(summaryCtx, daemon.Mgrs.Assem.InQueue)
+		})
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"Deduplication instance started successfully\n")
+	}
+
+	// Stage 2 - Rate limit summary records
+	if daemon.cfg.rateLimits != nil {
+		daemon.summaryWG.Go(func() {
+			daemon.cfg.rateLimits.Run(summaryCtx, daemon.Mgrs.Assem.InQueue)
+		})
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"Rate limit summary instance started successfully\n")
+	}
+
 	// Swap internal logger to assembler if requested
 	if daemon.opts.Inputs.SendInternalLogs {
 		daemon.Mgrs.LogInjector, err = internallogger.NewSenderInjector(daemon.ctx, daemon.Mgrs.Assem.InQueue)
@@ -98,15 +136,21 @@ func (daemon *Daemon) Start() (err error) {
 	// Stage 1 - Listeners(Readers)
 	inMgrConf := ingest.ManagerConfig{
 		SourceDropFilters: daemon.opts.Inputs.DropFilters,
+		FileParsers:       daemon.cfg.fileParsers,
 	}
 	daemon.Mgrs.In, err = inMgrConf.NewManager(daemon.ctx, daemon.Mgrs.Assem.InQueue)
 	if err != nil {
 		err = fmt.Errorf("error creating new ingest instance manager: %w", err)
 		return
 	}
-	if len(daemon.opts.Inputs.FilePaths) > 0 {
-		for _, filePath := range daemon.opts.Inputs.FilePaths {
-			err = daemon.Mgrs.In.AddFileInstance(filePath, daemon.opts.State.BaseFile)
+	fileInputs := daemon.opts.Inputs.fileInputs()
+	if len(fileInputs) > 0 {
+		for _, fileInput := range fileInputs {
+			if fileInput.IsDynamic() {
+				err = daemon.Mgrs.In.AddFileWatch(fileInput, daemon.opts.State.BaseFile)
+			} else {
+				err = daemon.Mgrs.In.AddFileInstance(fileInput, daemon.opts.State.BaseFile)
+			}
 			if err != nil {
 				err = fmt.Errorf("failed adding new file ingest instance: %w", err)
 				daemon.Shutdown()
@@ -115,7 +159,7 @@ func (daemon *Daemon) Start() (err error) {
 		}
 
 		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
-			"%d file ingest instance started successfully\n", len(daemon.opts.Inputs.FilePaths))
+			"%d file ingest instance started successfully\n", len(fileInputs))
 	}
 
 	if daemon.opts.Inputs.JournalEnabled {
@@ -128,6 +172,56 @@ func (daemon *Daemon) Start() (err error) {
 		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
 			"1 journal ingest instance started successfully\n")
 	}
+	if daemon.opts.Inputs.KernelEnabled {
+		err = daemon.Mgrs.In.AddKmsgInstance(daemon.opts.State.BaseFile)
+		if err != nil {
+			err = fmt.Errorf("failed creating kernel ring buffer ingest instance: %w", err)
+			daemon.Shutdown()
+			return
+		}
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"1 kernel ring buffer ingest instance started successfully\n")
+	}
+	if daemon.opts.Inputs.AuditEnabled {
+		err = daemon.Mgrs.In.AddAuditInstance()
+		if err != nil {
+			err = fmt.Errorf("failed creating audit ingest instance: %w", err)
+			daemon.Shutdown()
+			return
+		}
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"1 audit ingest instance started successfully\n")
+	}
+	if daemon.opts.Inputs.SyslogUDPAddress != "" || daemon.opts.Inputs.SyslogTCPAddress != "" {
+		err = daemon.Mgrs.In.AddSyslogInstance(daemon.opts.Inputs.SyslogUDPAddress, daemon.opts.Inputs.SyslogTCPAddress)
+		if err != nil {
+			err = fmt.Errorf("failed creating syslog ingest instance: %w", err)
+			daemon.Shutdown()
+			return
+		}
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"1 syslog ingest instance started successfully\n")
+	}
+	if daemon.opts.Inputs.UnixSocketPath != "" || daemon.opts.Inputs.UnixStreamPath != "" {
+		err = daemon.Mgrs.In.AddDevLogInstance(daemon.opts.Inputs.UnixSocketPath, daemon.opts.Inputs.UnixStreamPath)
+		if err != nil {
+			err = fmt.Errorf("failed creating local socket ingest instance: %w", err)
+			daemon.Shutdown()
+			return
+		}
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"1 local socket ingest instance started successfully\n")
+	}
+	if daemon.opts.Inputs.Beats.Address != "" {
+		err = daemon.Mgrs.In.AddBeatsInstance(daemon.opts.Inputs.Beats)
+		if err != nil {
+			err = fmt.Errorf("failed creating beats ingest instance: %w", err)
+			daemon.Shutdown()
+			return
+		}
+		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+			"1 beats ingest instance started successfully\n")
+	}
 	if daemon.RawInput != nil {
 		err = daemon.Mgrs.In.AddRawInstance(daemon.RawInput)
 		if err != nil {
@@ -260,8 +354,10 @@ func (daemon *Daemon) Shutdown() {
 
 	// Stop ingest instances
 	if daemon.Mgrs.In != nil {
-		for filename := range daemon.Mgrs.In.FileSources {
-			err := daemon.Mgrs.In.RemoveFileInstance(filename)
+		// Stop starting new file instances before stopping existing ones
+		daemon.Mgrs.In.RemoveFileWatches()
+		for filePath := range daemon.Mgrs.In.FileSources {
+			err := daemon.Mgrs.In.RemoveFileInstance(filePath)
 			if err != nil {
 				logctx.LogStdWarn(daemon.ctx, "ingest file worker shutdown failed: %w\n", err)
 			} else {
@@ -278,6 +374,51 @@ func (daemon *Daemon) Shutdown() {
 					"Successfully stopped ingest journald instance\n")
 			}
 		}
+		if daemon.Mgrs.In.KmsgSource != nil {
+			err := daemon.Mgrs.In.RemoveKmsgInstance()
+			if err != nil {
+				logctx.LogStdWarn(daemon.ctx, "ingest kernel ring buffer worker shutdown failed: %w\n", err)
+			} else {
+				logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+					"Successfully stopped ingest kernel ring buffer instance\n")
+			}
+		}
+		if daemon.Mgrs.In.AuditSource != nil {
+			err := daemon.Mgrs.In.RemoveAuditInstance()
+			if err != nil {
+				logctx.LogStdWarn(daemon.ctx, "ingest audit worker shutdown failed: %w\n", err)
+			} else {
+				logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+					"Successfully stopped ingest audit instance\n")
+			}
+		}
+		if daemon.Mgrs.In.SyslogSource != nil {
+			err := daemon.Mgrs.In.RemoveSyslogInstance()
+			if err != nil {
+				logctx.LogStdWarn(daemon.ctx, "ingest syslog worker shutdown failed: %w\n", err)
+			} else {
+				logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+					"Successfully stopped ingest syslog instance\n")
+			}
+		}
+		if daemon.Mgrs.In.DevLogSource != nil {
+			err := daemon.Mgrs.In.RemoveDevLogInstance()
+			if err != nil {
+				logctx.LogStdWarn(daemon.ctx, "ingest local socket worker shutdown failed: %w\n", err)
+			} else {
+				logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+					"Successfully stopped ingest local socket instance\n")
+			}
+		}
+		if daemon.Mgrs.In.BeatsSource != nil {
+			err := daemon.Mgrs.In.RemoveBeatsInstance()
+			if err != nil {
+				logctx.LogStdWarn(daemon.ctx, "ingest beats worker shutdown failed: %w\n", err)
+			} else {
+				logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
+					"Successfully stopped ingest beats instance\n")
+			}
+		}
 		if daemon.Mgrs.In.RawSource != nil {
 			err := daemon.Mgrs.In.RemoveRawInstance()
 			if err != nil {
@@ -289,6 +430,12 @@ func (daemon *Daemon) Shutdown() {
 		}
 	}
 
+	// Stop summary workers, pending records are sent with the rest of the assembler queue
+	if daemon.summaryCancel != nil {
+		daemon.summaryCancel()
+		daemon.summaryWG.Wait()
+	}
+
 	// Stop assemblers
 	if daemon.Mgrs.Assem != nil {
 		logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
@@ -327,7 +474,9 @@ func (daemon *Daemon) Shutdown() {
 		queue := daemon.Mgrs.Out.InQueue.ActiveWrite.Load()
 		queue.ResyncDepthMetric()
 		success, last := atomics.WaitUntilZero(&queue.Metrics.Depth, 10*time.Second)
-		if !success {
+		if !success && daemon.Mgrs.Out.Spill != nil {
+			logctx.LogStdWarn(daemon.ctx, "output inbox queue did not empty in time: persisting %d messages to disk\n", last)
+		} else if !success {
 			logctx.LogStdWarn(daemon.ctx, "output inbox queue did not empty in time: dropped %d messages\n", last)
 		}
 
@@ -340,6 +489,12 @@ func (daemon *Daemon) Shutdown() {
 			logctx.LogEvent(daemon.ctx, logctx.VerbosityProgress, logctx.InfoLog,
 				"Successfully stopped output instance %d\n", removedID)
 		}
+
+		// Persist anything left over for the next process
+		err := daemon.Mgrs.Out.StopSpiller()
+		if err != nil {
+			logctx.LogStdWarn(daemon.ctx, "output disk spill shutdown failed: %w\n", err)
+		}
 	}
 
 	// Stop the run loop after instances are drained and stopped
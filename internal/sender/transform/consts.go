@@ -0,0 +1,14 @@
+package transform
+
+const (
+	DefaultReplacement string = "[REDACTED]"
+
+	// Serialized byte slice values carry a length prefix inside the context value
+	sliceLengthPrefix int = 4
+)
+
+// Metric Names
+const (
+	MTMatches string = "transform_matches"
+	MTChanges string = "transform_changes"
+)
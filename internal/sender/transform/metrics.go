@@ -0,0 +1,49 @@
+package transform
+
+import (
+	"sdsyslog/internal/metrics"
+	"time"
+)
+
+func (pipeline *Pipeline) CollectMetrics(interval time.Duration) (collection []metrics.Metric) {
+	if pipeline == nil {
+		return
+	}
+
+	// Record read time
+	recordTime := time.Now()
+
+	for _, rule := range pipeline.rules {
+		// Read and clear
+		matches := rule.metrics.Matches.Swap(0)
+		changes := rule.metrics.Changes.Swap(0)
+
+		collection = append(collection,
+			metrics.Metric{
+				Name:        MTMatches,
+				Description: "Total messages selected by transform rule",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      matches,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+			metrics.Metric{
+				Name:        MTChanges,
+				Description: "Total messages modified by transform rule",
+				Namespace:   rule.namespace,
+				Value: metrics.MetricValue{
+					Raw:      changes,
+					Unit:     "count",
+					Interval: interval,
+				},
+				Type:      metrics.Counter,
+				Timestamp: recordTime,
+			},
+		)
+	}
+	return
+}
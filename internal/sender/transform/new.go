@@ -0,0 +1,111 @@
+// Config-driven message transforms (redaction, field add/drop/rename, truncation) applied before assembly
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"sdsyslog/pkg/protocol"
+	"slices"
+)
+
+// Validates and compiles transform rules. No rules returns a nil pipeline.
+func New(namespace []string, rules []Rule) (pipeline *Pipeline, err error) {
+	if len(rules) == 0 {
+		return
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	compiled := make([]*rule, 0, len(rules))
+	for index, userRule := range rules {
+		if userRule.Name == "" {
+			err = fmt.Errorf("transform at index %d has no name", index)
+			return
+		}
+		if _, duplicate := names[userRule.Name]; duplicate {
+			err = fmt.Errorf("duplicate transform name %q", userRule.Name)
+			return
+		}
+		names[userRule.Name] = struct{}{}
+
+		var newRule *rule
+		newRule, err = compileRule(userRule)
+		if err != nil {
+			err = fmt.Errorf("transform %q: %w", userRule.Name, err)
+			return
+		}
+		newRule.namespace = append(slices.Clone(namespace), userRule.Name)
+		compiled = append(compiled, newRule)
+	}
+
+	pipeline = &Pipeline{rules: compiled}
+	return
+}
+
+// Checks rule actions and compiles its patterns
+func compileRule(userRule Rule) (compiled *rule, err error) {
+	if len(userRule.RenameFields) == 0 && len(userRule.DropFields) == 0 && len(userRule.AddFields) == 0 &&
+		len(userRule.Redact) == 0 && !userRule.TruncateFields {
+		err = fmt.Errorf("no actions configured")
+		return
+	}
+
+	if userRule.Match != nil {
+		err = userRule.Match.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid match filter: %w", err)
+			return
+		}
+	}
+	for oldName, newName := range userRule.RenameFields {
+		err = validateFieldName(newName)
+		if err != nil {
+			err = fmt.Errorf("invalid new name for field %q: %w", oldName, err)
+			return
+		}
+	}
+	for key := range userRule.AddFields {
+		err = validateFieldName(key)
+		if err != nil {
+			err = fmt.Errorf("invalid added field: %w", err)
+			return
+		}
+	}
+
+	compiled = &rule{Rule: userRule}
+	for index, redaction := range userRule.Redact {
+		if redaction.Pattern == "" {
+			err = fmt.Errorf("redaction at index %d has no pattern", index)
+			return
+		}
+
+		var regex *regexp.Regexp
+		regex, err = regexp.Compile(redaction.Pattern)
+		if err != nil {
+			err = fmt.Errorf("invalid redaction pattern at index %d: %w", index, err)
+			return
+		}
+
+		replacement := redaction.Replacement
+		if replacement == "" {
+			replacement = DefaultReplacement
+		}
+		compiled.redactions = append(compiled.redactions, compiledRedaction{
+			regex:       regex,
+			replacement: []byte(replacement),
+		})
+	}
+	return
+}
+
+// Checks field name fits within protocol context key limits
+func validateFieldName(name string) (err error) {
+	if name == "" {
+		err = fmt.Errorf("empty field name")
+		return
+	}
+	if len(name) > protocol.MaxCtxKeyLen {
+		err = fmt.Errorf("field name %q is longer than %d bytes", name, protocol.MaxCtxKeyLen)
+		return
+	}
+	return
+}
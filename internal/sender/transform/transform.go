@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"sdsyslog/pkg/protocol"
+	"unicode/utf8"
+)
+
+// Runs every matching rule against the message in order, modifying it in place
+func (pipeline *Pipeline) Apply(msg *protocol.Message) {
+	if pipeline == nil {
+		return
+	}
+	if msg.Fields == nil {
+		msg.Fields = make(map[string]any)
+	}
+
+	for _, rule := range pipeline.rules {
+		if rule.Match != nil && !rule.Match.Match(msg) {
+			continue
+		}
+		rule.metrics.Matches.Add(1)
+
+		if rule.apply(msg) {
+			rule.metrics.Changes.Add(1)
+		}
+	}
+}
+
+// Runs rule actions against message, reporting whether anything was modified
+func (rule *rule) apply(msg *protocol.Message) (changed bool) {
+	// Read all renamed values first so renames do not chain into each other
+	renamed := make(map[string]any, len(rule.RenameFields))
+	for oldName, newName := range rule.RenameFields {
+		value, exists := msg.Fields[oldName]
+		if !exists || oldName == newName {
+			continue
+		}
+		renamed[newName] = value
+		delete(msg.Fields, oldName)
+	}
+	for newName, value := range renamed {
+		msg.Fields[newName] = value
+		changed = true
+	}
+
+	for _, name := range rule.DropFields {
+		if _, exists := msg.Fields[name]; !exists {
+			continue
+		}
+		delete(msg.Fields, name)
+		changed = true
+	}
+
+	for key, value := range rule.AddFields {
+		if existing, exists := msg.Fields[key]; exists && existing == value {
+			continue
+		}
+		msg.Fields[key] = value
+		changed = true
+	}
+
+	for _, redaction := range rule.redactions {
+		if !redaction.regex.Match(msg.Data) {
+			continue
+		}
+		msg.Data = redaction.regex.ReplaceAll(msg.Data, redaction.replacement)
+		changed = true
+	}
+
+	if rule.TruncateFields {
+		for key, value := range msg.Fields {
+			shortened, truncated := truncateValue(value)
+			if !truncated {
+				continue
+			}
+			msg.Fields[key] = shortened
+			changed = true
+		}
+	}
+	return
+}
+
+// Shortens text and byte values that would exceed the protocol context value limit once serialized
+func truncateValue(value any) (shortened any, truncated bool) {
+	switch typed := value.(type) {
+	case string:
+		if len(typed) <= protocol.MaxCtxValLen {
+			return
+		}
+		// Cut on a character boundary so the value remains valid UTF-8
+		end := protocol.MaxCtxValLen
+		for end > 0 && !utf8.RuneStart(typed[end]) {
+			end--
+		}
+		shortened = typed[:end]
+		truncated = true
+	case []byte:
+		maxLen := protocol.MaxCtxValLen - sliceLengthPrefix
+		if len(typed) <= maxLen {
+			return
+		}
+		shortened = typed[:maxLen:maxLen]
+		truncated = true
+	}
+	return
+}
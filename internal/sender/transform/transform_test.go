@@ -0,0 +1,218 @@
+package transform
+
+import (
+	"reflect"
+	"sdsyslog/internal/filtering"
+	"sdsyslog/pkg/protocol"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	longValue := strings.Repeat("a", protocol.MaxCtxValLen-1) + "é"
+
+	tests := []struct {
+		name            string
+		rules           []Rule
+		msg             *protocol.Message
+		expectedData    string
+		expectedFields  map[string]any
+		expectedMatches []uint64
+		expectedChanges []uint64
+	}{
+		{
+			name: "redact with default and group replacement",
+			rules: []Rule{{
+				Name: "secrets",
+				Redact: []Redaction{
+					{Pattern: `(password|token)=\S+`, Replacement: "$1=***"},
+					{Pattern: `\b\d(?:[ -]?\d){12,15}\b`},
+				},
+			}},
+			msg: &protocol.Message{
+				Data:   []byte("login password=hunter2 card 4111 1111 1111 1111 token=abc"),
+				Fields: map[string]any{},
+			},
+			expectedData:    "login password=*** card [REDACTED] token=***",
+			expectedFields:  map[string]any{},
+			expectedMatches: []uint64{1},
+			expectedChanges: []uint64{1},
+		},
+		{
+			name:            "no redaction match is not a change",
+			rules:           []Rule{{Name: "secrets", Redact: []Redaction{{Pattern: `password=\S+`}}}},
+			msg:             &protocol.Message{Data: []byte("nothing here")},
+			expectedData:    "nothing here",
+			expectedFields:  map[string]any{},
+			expectedMatches: []uint64{1},
+			expectedChanges: []uint64{0},
+		},
+		{
+			name: "rename drop and add",
+			rules: []Rule{{
+				Name:         "fields",
+				RenameFields: map[string]string{"SourceIP": "RemoteAddress", "RemoteAddress": "Old"},
+				DropFields:   []string{"UserID", "Missing"},
+				AddFields:    map[string]string{"Site": "plant-3", "Classification": "internal"},
+			}},
+			msg: &protocol.Message{
+				Data:   []byte("text"),
+				Fields: map[string]any{"SourceIP": "10.0.0.1", "RemoteAddress": "x", "UserID": 1000, "Site": "other"},
+			},
+			expectedData: "text",
+			expectedFields: map[string]any{
+				"RemoteAddress":  "10.0.0.1",
+				"Old":            "x",
+				"Site":           "plant-3",
+				"Classification": "internal",
+			},
+			expectedMatches: []uint64{1},
+			expectedChanges: []uint64{1},
+		},
+		{
+			name:  "truncate on character boundary",
+			rules: []Rule{{Name: "limit", TruncateFields: true}},
+			msg: &protocol.Message{
+				Fields: map[string]any{
+					"Long":  longValue,
+					"Bytes": make([]byte, 300),
+					"Short": "ok",
+					"Num":   12,
+				},
+			},
+			expectedFields: map[string]any{
+				"Long":  longValue[:protocol.MaxCtxValLen-1],
+				"Bytes": make([]byte, protocol.MaxCtxValLen-sliceLengthPrefix),
+				"Short": "ok",
+				"Num":   12,
+			},
+			expectedMatches: []uint64{1},
+			expectedChanges: []uint64{1},
+		},
+		{
+			name: "match filter selects messages",
+			rules: []Rule{
+				{
+					Name:      "sshd-only",
+					Match:     &protocol.MessageFilter{Fields: map[string]*filtering.Filter{"ApplicationName": {Exact: "sshd"}}},
+					AddFields: map[string]string{"Team": "security"},
+				},
+				{Name: "everything", AddFields: map[string]string{"Site": "plant-3"}},
+			},
+			msg: &protocol.Message{
+				Data:   []byte("text"),
+				Fields: map[string]any{"ApplicationName": "cron"},
+			},
+			expectedData:    "text",
+			expectedFields:  map[string]any{"ApplicationName": "cron", "Site": "plant-3"},
+			expectedMatches: []uint64{0, 1},
+			expectedChanges: []uint64{0, 1},
+		},
+		{
+			name: "rules run in order",
+			rules: []Rule{
+				{Name: "add", AddFields: map[string]string{"Site": "plant-3"}},
+				{Name: "rename", RenameFields: map[string]string{"Site": "Location"}},
+			},
+			msg:             &protocol.Message{Data: []byte("text")},
+			expectedData:    "text",
+			expectedFields:  map[string]any{"Location": "plant-3"},
+			expectedMatches: []uint64{1, 1},
+			expectedChanges: []uint64{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := New([]string{"Test"}, tt.rules)
+			if err != nil {
+				t.Fatalf("unexpected error creating pipeline: %v", err)
+			}
+
+			pipeline.Apply(tt.msg)
+
+			if string(tt.msg.Data) != tt.expectedData {
+				t.Errorf("expected data %q, got %q", tt.expectedData, tt.msg.Data)
+			}
+			if !reflect.DeepEqual(tt.msg.Fields, tt.expectedFields) {
+				t.Errorf("expected fields %v, got %v", tt.expectedFields, tt.msg.Fields)
+			}
+			for index, rule := range pipeline.rules {
+				matches := rule.metrics.Matches.Load()
+				changes := rule.metrics.Changes.Load()
+				if matches != tt.expectedMatches[index] || changes != tt.expectedChanges[index] {
+					t.Errorf("rule %q: expected %d matches and %d changes, got %d and %d",
+						rule.Name, tt.expectedMatches[index], tt.expectedChanges[index], matches, changes)
+				}
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         []Rule
+		expectedError string
+	}{
+		{
+			name:  "valid rules",
+			rules: []Rule{{Name: "a", TruncateFields: true}, {Name: "b", DropFields: []string{"x"}}},
+		},
+		{
+			name:          "missing name",
+			rules:         []Rule{{TruncateFields: true}},
+			expectedError: "has no name",
+		},
+		{
+			name:          "duplicate name",
+			rules:         []Rule{{Name: "a", TruncateFields: true}, {Name: "a", TruncateFields: true}},
+			expectedError: "duplicate transform name",
+		},
+		{
+			name:          "no actions",
+			rules:         []Rule{{Name: "a"}},
+			expectedError: "no actions configured",
+		},
+		{
+			name:          "invalid pattern",
+			rules:         []Rule{{Name: "a", Redact: []Redaction{{Pattern: "("}}}},
+			expectedError: "invalid redaction pattern",
+		},
+		{
+			name:          "empty pattern",
+			rules:         []Rule{{Name: "a", Redact: []Redaction{{}}}},
+			expectedError: "has no pattern",
+		},
+		{
+			name:          "added field name too long",
+			rules:         []Rule{{Name: "a", AddFields: map[string]string{strings.Repeat("k", protocol.MaxCtxKeyLen+1): "v"}}},
+			expectedError: "invalid added field",
+		},
+		{
+			name:          "empty rename target",
+			rules:         []Rule{{Name: "a", RenameFields: map[string]string{"old": ""}}},
+			expectedError: "invalid new name",
+		},
+		{
+			name:          "invalid match filter",
+			rules:         []Rule{{Name: "a", TruncateFields: true, Match: &protocol.MessageFilter{Data: &filtering.Filter{Regex: "("}}}},
+			expectedError: "invalid match filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(nil, tt.rules)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package transform
+
+import (
+	"regexp"
+	"sdsyslog/pkg/protocol"
+	"sync/atomic"
+)
+
+// User supplied transform rule. Actions run in order: rename, drop, add, redact, truncate.
+type Rule struct {
+	Name           string                  `json:"name"`
+	Match          *protocol.MessageFilter `json:"match,omitempty"`          // Only transform matching messages (all messages when unset)
+	RenameFields   map[string]string       `json:"renameFields,omitempty"`   // Old field name to new field name
+	DropFields     []string                `json:"dropFields,omitempty"`     // Field names to remove
+	AddFields      map[string]string       `json:"addFields,omitempty"`      // Static fields, replacing existing values
+	Redact         []Redaction             `json:"redact,omitempty"`         // Applied to message text in order
+	TruncateFields bool                    `json:"truncateFields,omitempty"` // Shorten values over the protocol context value limit
+}
+
+// Regular expression replacement in message text
+type Redaction struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement,omitempty"` // Supports $1 style group references (default [REDACTED])
+}
+
+// Ordered set of compiled transform rules shared by all assembler instances
+type Pipeline struct {
+	rules []*rule
+}
+
+// Transform rule prepared for matching
+type rule struct {
+	Rule
+	redactions []compiledRedaction
+	namespace  []string
+	metrics    ruleMetrics
+}
+
+type compiledRedaction struct {
+	regex       *regexp.Regexp
+	replacement []byte
+}
+
+type ruleMetrics struct {
+	Matches atomic.Uint64 // Messages selected by the rule (cleared by metric collection)
+	Changes atomic.Uint64 // Messages modified by the rule (cleared by metric collection)
+}
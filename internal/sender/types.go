@@ -6,10 +6,15 @@ import (
 	"net"
 	"net/http"
 	"sdsyslog/internal/global"
+	"sdsyslog/internal/iomodules/beats"
+	"sdsyslog/internal/iomodules/file"
 	metricGlb "sdsyslog/internal/metrics"
 	"sdsyslog/internal/parsing"
+	"sdsyslog/internal/sender/dedup"
 	"sdsyslog/internal/sender/metrics"
+	"sdsyslog/internal/sender/ratelimit"
 	"sdsyslog/internal/sender/shared"
+	"sdsyslog/internal/sender/transform"
 	"sdsyslog/pkg/protocol"
 	"sync"
 	"time"
@@ -33,8 +38,9 @@ type JSONOptions struct {
 		Port                   int    `json:"port"`
 		OverrideMaxPayloadSize int    `json:"maxPayloadSize,omitempty"`
 	} `json:"network"`
-	Inputs  JSONInputs `json:"inputs"`
-	Metrics struct {
+	Inputs     JSONInputs       `json:"inputs"`
+	Transforms []transform.Rule `json:"transforms,omitempty"`
+	Metrics    struct {
 		Interval          parsing.Duration `json:"collectionInterval"`
 		MaxAge            parsing.Duration `json:"maximumRetention,omitempty"`
 		EnableQueryServer bool             `json:"enableHTTPQueryServer"`
@@ -52,6 +58,22 @@ type JSONOptions struct {
 		MinAssemblerQueueSize global.MinValue  `json:"minAssemblerQueueSize,omitempty"`
 		MaxAssemblerQueueSize global.MaxValue  `json:"maxAssemblerQueueSize,omitempty"`
 	} `json:"autoscaling"`
+	DiskSpill struct {
+		Enabled          bool             `json:"enabled"`
+		Directory        string           `json:"directory,omitempty"`
+		HighWatermark    int              `json:"highWatermarkPercent,omitempty"`
+		MaxSizeBytes     uint64           `json:"maxSizeBytes,omitempty"`
+		SegmentSizeBytes uint64           `json:"segmentSizeBytes,omitempty"`
+		RetryInterval    parsing.Duration `json:"retryInterval,omitempty"`
+	} `json:"diskSpill,omitempty"`
+	Deduplication struct {
+		Enabled    bool             `json:"enabled"`
+		Window     parsing.Duration `json:"window,omitempty"`
+		MaxEntries int              `json:"maxEntries,omitempty"`
+	} `json:"deduplication,omitempty"`
+	ErrorCorrection struct {
+		ParityPercent int `json:"parityPercent"`
+	} `json:"errorCorrection,omitempty"`
 	Throttling struct {
 		Enabled              bool             `json:"enabled"`
 		MinFragmentThreshold int              `json:"minimumFragmentThreshold"`
@@ -62,8 +84,18 @@ type JSONOptions struct {
 type JSONInputs struct {
 	Include          string                              `json:"include,omitempty"`
 	DropFilters      map[string][]protocol.MessageFilter `json:"dropFilters,omitempty"`
+	RateLimits       []ratelimit.Rule                    `json:"rateLimits,omitempty"`
 	FilePaths        []string                            `json:"filePaths,omitempty"`
+	Files            []file.InputConfig                  `json:"files,omitempty"`   // File inputs with per-file settings
+	Parsers          []file.ParserConfig                 `json:"parsers,omitempty"` // Named parser profiles for file inputs
 	JournalEnabled   bool                                `json:"journalEnabled,omitempty"`
+	KernelEnabled    bool                                `json:"kernelEnabled,omitempty"` // Kernel ring buffer (/dev/kmsg)
+	AuditEnabled     bool                                `json:"auditEnabled,omitempty"`  // Kernel audit records (netlink multicast)
+	SyslogUDPAddress string                              `json:"syslogUDPAddress,omitempty"`
+	SyslogTCPAddress string                              `json:"syslogTCPAddress,omitempty"`
+	UnixSocketPath   string                              `json:"unixSocketPath,omitempty"`
+	UnixStreamPath   string                              `json:"unixStreamSocketPath,omitempty"`
+	Beats            beats.InputConfig                   `json:"beats,omitempty"` // Lumberjack server for Filebeat/Winlogbeat agents
 	SendInternalLogs bool                                `json:"sendInternalLogs,omitempty"`
 }
 
@@ -71,6 +103,14 @@ type Config struct {
 	// Crypto
 	signingPrivateKey []byte
 
+	// Compiled message deduplication, rate limits and transforms
+	dedup      *dedup.Deduplicator
+	rateLimits *ratelimit.Limiter
+	transforms *transform.Pipeline
+
+	// Compiled file input parser profiles by name
+	fileParsers map[string]*file.Parser
+
 	// Parsed network
 	sourceSocket *net.UDPAddr
 	destSocket   *net.UDPAddr
@@ -95,6 +135,10 @@ type Daemon struct {
 
 	wg sync.WaitGroup
 
+	// Collapsed repeat and rate limit summary workers (stopped before the assembler queue is drained)
+	summaryCancel context.CancelFunc
+	summaryWG     sync.WaitGroup
+
 	// Pipeline component trackers (reverse order)
 	Mgrs               shared.Managers
 	metricsCollector   *metrics.Gatherer
@@ -0,0 +1,9 @@
+package setup
+
+import "sdsyslog/internal/iomodules/file"
+
+// Verifies hash chain and checkpoint signatures of audit file output archives (oldest first)
+func VerifyArchive(paths []string, publicKey []byte) (report file.ArchiveReport, err error) {
+	report, err = file.VerifyArchive(paths, publicKey)
+	return
+}
@@ -13,9 +13,11 @@ import (
 	"sdsyslog/internal/iomodules/journald"
 	"sdsyslog/internal/metrics/server"
 	"sdsyslog/internal/parsing"
+	"sdsyslog/internal/queue/spill"
 	"sdsyslog/internal/receiver"
 	"sdsyslog/internal/sender"
 	"sdsyslog/internal/sender/ingest"
+	"sdsyslog/internal/sender/output"
 	"sdsyslog/pkg/crypto/registry"
 	"sdsyslog/pkg/protocol"
 	"syscall"
@@ -314,6 +316,11 @@ func CreateSendTemplateConfig(filepath string) (err error) {
 	newCfg.Crypto.SignatureSuite = registry.NoSigName
 	newCfg.Crypto.TransportSuite = registry.DefaultCryptoName
 
+	newCfg.DiskSpill.Enabled = true
+	newCfg.DiskSpill.Directory = sender.DefaultSpillDirectory
+	newCfg.DiskSpill.HighWatermark = output.DefaultSpillHighWatermark
+	newCfg.DiskSpill.MaxSizeBytes = spill.DefaultMaxBytes
+
 	newCfg.Throttling.Enabled = true
 	newCfg.Throttling.MinFragmentThreshold = sender.DefaultOutputThrottlingThreshold
 	newCfg.Throttling.PerFragmentDelay = parsing.Duration(sender.DefaultOutputThrottlingTime)
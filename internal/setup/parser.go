@@ -0,0 +1,94 @@
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sdsyslog/internal/iomodules/file"
+	"sdsyslog/internal/sender"
+	"sdsyslog/pkg/protocol"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Parses sample lines with a parser profile from the sender config and prints the resulting messages
+func TestParserProfile(configPath string, profileName string, samplePaths []string, output io.Writer) (err error) {
+	// Built-in detection does not need a config
+	var parser *file.Parser
+	if configPath != "" || profileName != file.ParserAuto {
+		if configPath == "" {
+			err = fmt.Errorf("testing parser profile %q requires the sender config (-c)", profileName)
+			return
+		}
+		parser, err = sender.LoadParserProfile(configPath, profileName)
+		if err != nil {
+			return
+		}
+	}
+
+	localHostname, err := os.Hostname()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve local hostname: %w", err)
+		return
+	}
+
+	var samples []io.Reader
+	for _, samplePath := range samplePaths {
+		var sampleFile *os.File
+		sampleFile, err = os.Open(samplePath)
+		if err != nil {
+			err = fmt.Errorf("failed to open sample file: %w", err)
+			return
+		}
+		defer func() {
+			_ = sampleFile.Close()
+		}()
+		samples = append(samples, sampleFile)
+	}
+	if len(samples) == 0 {
+		samples = append(samples, os.Stdin)
+	}
+
+	scanner := bufio.NewScanner(io.MultiReader(samples...))
+	scanner.Buffer(make([]byte, 0, 65536), 1<<20)
+	var lineNumber int
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		message := parser.Parse(line, localHostname)
+		fmt.Fprintf(output, "Line %d: %s\n", lineNumber, line)
+		fmt.Fprint(output, formatParsedMessage(message))
+	}
+	err = scanner.Err()
+	if err != nil {
+		err = fmt.Errorf("failed to read sample lines: %w", err)
+		return
+	}
+	return
+}
+
+// Human readable listing of message metadata and fields (sorted by name)
+func formatParsedMessage(message *protocol.Message) (text string) {
+	keys := make([]string, 0, len(message.Fields))
+	for key := range message.Fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "  %-16s %s\n", "Timestamp:", message.Timestamp.Format(time.RFC3339Nano))
+	fmt.Fprintf(&builder, "  %-16s %s\n", "Hostname:", message.Hostname)
+	fmt.Fprintf(&builder, "  %-16s %s\n", "Message:", message.Data)
+	for _, key := range keys {
+		fmt.Fprintf(&builder, "  %-16s %s\n", key+":", protocol.FormatValue(message.Fields[key]))
+	}
+	builder.WriteString("\n")
+	text = builder.String()
+	return
+}
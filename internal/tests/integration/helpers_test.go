@@ -325,7 +325,7 @@ func checkPipelineCounts(expectedCount int, startTime time.Time, senderDaemon *s
 
 	// Receive - Output
 	var totalRecvOutCtn int
-	recvOutMetrics := recvDaemon.MetricDataSearcher(output.MTRawWritesSuc, []string{logctx.NSRecv, logctx.NSmOutput}, startTime, endTime)
+	recvOutMetrics := recvDaemon.MetricDataSearcher(output.MTOutputWritesSuc, []string{logctx.NSRecv, logctx.NSmOutput}, startTime, endTime)
 	for _, metric := range recvOutMetrics {
 		cnt, ok := metric.Value.Raw.(uint64)
 		if !ok {
@@ -148,7 +148,7 @@ func mockPackets(numMessages int, rawMessage []byte, maxPayloadSize int, publicK
 
 	for range numMessages {
 		var fragments [][]byte
-		fragments, err = protocol.Create(newMsg, mainHostID, maxPayloadSize, 1, 0)
+		fragments, err = protocol.Create(newMsg, mainHostID, maxPayloadSize, 1, 0, 0)
 		if err != nil {
 			err = fmt.Errorf("failed serialize test data for mock packets: %w", err)
 			return
@@ -3,6 +3,9 @@ package registry
 const (
 	SuiteIDLen int = 1 // Byte length for ID (crypto and signature) in blobs
 
-	NoSigName string = "NoSignature"
+	NoSigName         string = "NoSignature"
+	ContentSigName    string = "ed25519-content"
 	DefaultCryptoName string = "x25519-hkdf-chacha20poly1305"
+	AESCryptoName     string = "x25519-hkdfsha384-aes256gcm"
+	HybridCryptoName  string = "x25519mlkem768-hkdf-chacha20poly1305"
 )
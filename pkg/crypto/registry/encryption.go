@@ -7,6 +7,7 @@ import (
 	"sdsyslog/internal/crypto/ecdh"
 	"sdsyslog/internal/crypto/hash"
 	"sdsyslog/internal/crypto/hkdf"
+	"sdsyslog/internal/crypto/kem"
 	"slices"
 
 	"golang.org/x/crypto/chacha20poly1305"
@@ -147,6 +148,93 @@ func init() {
 			return
 		},
 	}
+	cryptoSuites[2] = &SuiteInfo{
+		Name:            AESCryptoName,
+		KeySize:         curve25519.ScalarSize, // Outer payload (ephemeral key size)
+		NonceSize:       aead.GCMNonceSize,
+		CipherOverhead:  aead.GCMTagOverhead,
+		ValidateKey:     cryptoSuites[1].ValidateKey, // Same persistent x25519 keys as suite 1
+		NewKey:          cryptoSuites[1].NewKey,
+		DerivePublicKey: cryptoSuites[1].DerivePublicKey,
+		Encrypt: func(publicKey, payload []byte) (ciphertext []byte, ephemeralPub []byte, nonce []byte, err error) {
+			sharedSecret, ephemeralPub, err := ecdh.CreateSharedSecret(publicKey)
+			if err != nil {
+				err = fmt.Errorf("failed to create secret: %w", err)
+				return
+			}
+
+			nonce = make([]byte, aead.GCMNonceSize)
+			_, err = rand.Read(nonce)
+			if err != nil {
+				err = fmt.Errorf("failed to create random nonce: %w", err)
+				return
+			}
+
+			salt, err := hash.MultipleSlices(ephemeralPub, nonce)
+			if err != nil {
+				err = fmt.Errorf("failed to create salt: %w", err)
+				return
+			}
+
+			actualKey, err := hkdf.DeriveKeySHA384(sharedSecret,
+				salt,
+				AESCryptoName,
+				aead.AES256KeySize)
+			if err != nil {
+				err = fmt.Errorf("failed to derive key: %w", err)
+				return
+			}
+
+			aad := append([]byte{2}, ephemeralPub...)
+			ciphertext, err = aead.EncryptAES256GCM(payload, actualKey, nonce, aad)
+			if err != nil {
+				err = fmt.Errorf("failed encryption: %w", err)
+				return
+			}
+			return
+		},
+		Decrypt: func(privateKey, ciphertext, ephemeralPub, nonce []byte) (payload []byte, err error) {
+			sharedSecret, err := ecdh.ReCreateSharedSecret(privateKey, ephemeralPub)
+			if err != nil {
+				err = fmt.Errorf("failed recreating secret: %w", err)
+				return
+			}
+
+			salt, err := hash.MultipleSlices(ephemeralPub, nonce)
+			if err != nil {
+				err = fmt.Errorf("failed creating salt: %w", err)
+				return
+			}
+
+			actualKey, err := hkdf.DeriveKeySHA384(sharedSecret,
+				salt,
+				AESCryptoName,
+				aead.AES256KeySize)
+			if err != nil {
+				err = fmt.Errorf("failed deriving key: %w", err)
+				return
+			}
+
+			aad := append([]byte{2}, ephemeralPub...)
+			payload, err = aead.DecryptAES256GCM(ciphertext, actualKey, nonce, aad)
+			if err != nil {
+				return
+			}
+			return
+		},
+	}
+	cryptoSuites[3] = &SuiteInfo{
+		Name:                HybridCryptoName,
+		KeySize:             curve25519.PointSize, // Outer payload (ephemeral key size)
+		EncapsulatedKeySize: kem.MLKEM768CiphertextSize,
+		NonceSize:           chacha20poly1305.NonceSize,
+		CipherOverhead:      chacha20poly1305.Overhead,
+		ValidateKey:         validateHybridKey,
+		NewKey:              newHybridKey,
+		DerivePublicKey:     deriveHybridPublicKey,
+		Encrypt:             encryptHybrid,
+		Decrypt:             decryptHybrid,
+	}
 }
 
 // Query crypto suite (concurrent safe)
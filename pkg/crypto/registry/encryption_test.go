@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"encoding/hex"
 	"slices"
 	"testing"
 )
@@ -25,6 +26,18 @@ func TestCryptoSuites(t *testing.T) {
 			expectValid:   true,
 			runTamperTest: true,
 		},
+		{
+			id:            2,
+			name:          "x25519-hkdfsha384-aes256gcm",
+			expectValid:   true,
+			runTamperTest: true,
+		},
+		{
+			id:            3,
+			name:          "x25519mlkem768-hkdf-chacha20poly1305",
+			expectValid:   true,
+			runTamperTest: true,
+		},
 		{
 			id:          200, // unregistered suite
 			name:        "",
@@ -102,3 +115,103 @@ func TestCryptoSuites(t *testing.T) {
 		})
 	}
 }
+
+// Known answer tests - ciphertexts generated independently with the standard library
+// (crypto/ecdh X25519, crypto/hkdf SHA-384, crypto/aes GCM)
+func TestCryptoSuiteVectors(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            uint8
+		privateKey    string
+		publicKey     string
+		ephemeralPub  string
+		nonce         string
+		ciphertext    string
+		expectPayload string
+		expectErr     bool
+	}{
+		{
+			name:          "aes256gcm empty payload",
+			id:            2,
+			privateKey:    "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+			publicKey:     "07a37cbc142093c8b755dc1b10e86cb426374ad16aa853ed0bdfc0b2b86d1c7c",
+			ephemeralPub:  "605a725d2a4adfeeb1a29e17edd621c1b7593ee8cdbc44ac6c4ab6e2f805d23c",
+			nonce:         "101112131415161718191a1b",
+			ciphertext:    "170276ea6d246fd7b2214167303f1ae8",
+			expectPayload: "",
+		},
+		{
+			name:          "aes256gcm message",
+			id:            2,
+			privateKey:    "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+			publicKey:     "07a37cbc142093c8b755dc1b10e86cb426374ad16aa853ed0bdfc0b2b86d1c7c",
+			ephemeralPub:  "605a725d2a4adfeeb1a29e17edd621c1b7593ee8cdbc44ac6c4ab6e2f805d23c",
+			nonce:         "101112131415161718191a1b",
+			ciphertext:    "ab9d8ad6d44fd1b1c80441213e7dbacaae2459ddd600e4c619401acbdd0109184bb0f9d9a1ea03e35c768a545d24a6",
+			expectPayload: "test vector message for suite 2",
+		},
+		{
+			name:         "aes256gcm tampered tag",
+			id:           2,
+			privateKey:   "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+			publicKey:    "07a37cbc142093c8b755dc1b10e86cb426374ad16aa853ed0bdfc0b2b86d1c7c",
+			ephemeralPub: "605a725d2a4adfeeb1a29e17edd621c1b7593ee8cdbc44ac6c4ab6e2f805d23c",
+			nonce:        "101112131415161718191a1b",
+			ciphertext:   "ab9d8ad6d44fd1b1c80441213e7dbacaae2459ddd600e4c619401acbdd0109184bb0f9d9a1ea03e35c768a545d24a7",
+			expectErr:    true,
+		},
+		{
+			name:         "aes256gcm vector rejected by chacha20poly1305 suite",
+			id:           1,
+			privateKey:   "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+			publicKey:    "07a37cbc142093c8b755dc1b10e86cb426374ad16aa853ed0bdfc0b2b86d1c7c",
+			ephemeralPub: "605a725d2a4adfeeb1a29e17edd621c1b7593ee8cdbc44ac6c4ab6e2f805d23c",
+			nonce:        "101112131415161718191a1b",
+			ciphertext:   "ab9d8ad6d44fd1b1c80441213e7dbacaae2459ddd600e4c619401acbdd0109184bb0f9d9a1ea03e35c768a545d24a6",
+			expectErr:    true,
+		},
+	}
+
+	mustDecode := func(t *testing.T, input string) (output []byte) {
+		output, err := hex.DecodeString(input)
+		if err != nil {
+			t.Fatalf("invalid hex in test vector: %v", err)
+		}
+		return
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, valid := GetSuiteInfo(tt.id)
+			if !valid {
+				t.Fatalf("suite ID %d is not registered", tt.id)
+			}
+
+			privateKey := mustDecode(t, tt.privateKey)
+			publicKey, err := info.DerivePublicKey(privateKey)
+			if err != nil {
+				t.Fatalf("DerivePublicKey failed: %v", err)
+			}
+			if hex.EncodeToString(publicKey) != tt.publicKey {
+				t.Fatalf("expected public key %s got %x", tt.publicKey, publicKey)
+			}
+
+			payload, err := info.Decrypt(privateKey,
+				mustDecode(t, tt.ciphertext),
+				mustDecode(t, tt.ephemeralPub),
+				mustDecode(t, tt.nonce))
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected decryption error, got payload %q", string(payload))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected decryption error: %v", err)
+			}
+			if string(payload) != tt.expectPayload {
+				t.Fatalf("expected payload %q got %q", tt.expectPayload, string(payload))
+			}
+		})
+	}
+}
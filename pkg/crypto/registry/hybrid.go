@@ -0,0 +1,164 @@
+package registry
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sdsyslog/internal/crypto"
+	"sdsyslog/internal/crypto/aead"
+	"sdsyslog/internal/crypto/ecdh"
+	"sdsyslog/internal/crypto/hash"
+	"sdsyslog/internal/crypto/hkdf"
+	"sdsyslog/internal/crypto/kem"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Hybrid X25519 + ML-KEM-768 key layouts (classical part first)
+// Private key: x25519 scalar || ML-KEM-768 seed
+// Public key: x25519 public key || ML-KEM-768 encapsulation key
+// Header key field: x25519 ephemeral public key || ML-KEM-768 ciphertext
+const (
+	hybridPrivateKeySize int = curve25519.ScalarSize + kem.MLKEM768SeedSize
+	hybridPublicKeySize  int = curve25519.PointSize + kem.MLKEM768PublicKeySize
+)
+
+func validateHybridKey(key []byte) (err error) {
+	if len(key) != hybridPrivateKeySize {
+		err = fmt.Errorf("persistent private key must be %d bytes (got %d bytes)", hybridPrivateKeySize, len(key))
+		return
+	}
+	return
+}
+
+func newHybridKey() (privateKey []byte, publicKey []byte, err error) {
+	classicalPriv, classicalPub, err := ecdh.CreatePersistentKey()
+	if err != nil {
+		return
+	}
+	kemPriv, kemPub, err := kem.CreatePersistentKey()
+	if err != nil {
+		return
+	}
+
+	privateKey = append(classicalPriv, kemPriv...)
+	publicKey = append(classicalPub, kemPub...)
+	crypto.Memzero(kemPriv)
+	return
+}
+
+func deriveHybridPublicKey(privateKey []byte) (publicKey []byte, err error) {
+	err = validateHybridKey(privateKey)
+	if err != nil {
+		return
+	}
+	classicalPub, err := ecdh.DerivePersistentPublicKey(privateKey[:curve25519.ScalarSize])
+	if err != nil {
+		return
+	}
+	kemPub, err := kem.DerivePersistentPublicKey(privateKey[curve25519.ScalarSize:])
+	if err != nil {
+		return
+	}
+	publicKey = append(classicalPub, kemPub...)
+	return
+}
+
+func encryptHybrid(publicKey, payload []byte) (ciphertext []byte, headerKey []byte, nonce []byte, err error) {
+	if len(publicKey) != hybridPublicKeySize {
+		err = fmt.Errorf("persistent public key must be %d bytes (got %d bytes)", hybridPublicKeySize, len(publicKey))
+		return
+	}
+
+	classicalSecret, ephemeralPub, err := ecdh.CreateSharedSecret(publicKey[:curve25519.PointSize])
+	if err != nil {
+		err = fmt.Errorf("failed to create secret: %w", err)
+		return
+	}
+	kemSecret, encapsulatedKey, err := kem.Encapsulate(publicKey[curve25519.PointSize:])
+	if err != nil {
+		err = fmt.Errorf("failed to encapsulate secret: %w", err)
+		return
+	}
+	headerKey = append(ephemeralPub, encapsulatedKey...)
+
+	nonce = make([]byte, chacha20poly1305.NonceSize)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		err = fmt.Errorf("failed to create random nonce: %w", err)
+		return
+	}
+
+	actualKey, err := deriveHybridKey(classicalSecret, kemSecret, headerKey, nonce)
+	if err != nil {
+		err = fmt.Errorf("failed to derive key: %w", err)
+		return
+	}
+
+	aad := append([]byte{3}, headerKey...)
+	ciphertext, err = aead.Encrypt(payload, actualKey, nonce, aad)
+	if err != nil {
+		err = fmt.Errorf("failed encryption: %w", err)
+		return
+	}
+	return
+}
+
+func decryptHybrid(privateKey, ciphertext, headerKey, nonce []byte) (payload []byte, err error) {
+	err = validateHybridKey(privateKey)
+	if err != nil {
+		return
+	}
+	if len(headerKey) != curve25519.PointSize+kem.MLKEM768CiphertextSize {
+		err = fmt.Errorf("header key field must be %d bytes (got %d bytes)",
+			curve25519.PointSize+kem.MLKEM768CiphertextSize, len(headerKey))
+		return
+	}
+
+	classicalSecret, err := ecdh.ReCreateSharedSecret(privateKey[:curve25519.ScalarSize], headerKey[:curve25519.PointSize])
+	if err != nil {
+		err = fmt.Errorf("failed recreating secret: %w", err)
+		return
+	}
+	kemSecret, err := kem.Decapsulate(privateKey[curve25519.ScalarSize:], headerKey[curve25519.PointSize:])
+	if err != nil {
+		err = fmt.Errorf("failed recreating secret: %w", err)
+		return
+	}
+
+	actualKey, err := deriveHybridKey(classicalSecret, kemSecret, headerKey, nonce)
+	if err != nil {
+		err = fmt.Errorf("failed deriving key: %w", err)
+		return
+	}
+
+	aad := append([]byte{3}, headerKey...)
+	payload, err = aead.Decrypt(ciphertext, actualKey, nonce, aad)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Combines both shared secrets so the derived key stays secure while either algorithm is unbroken.
+// Salt binds the key to both the ephemeral public key and the KEM ciphertext.
+func deriveHybridKey(classicalSecret, kemSecret, headerKey, nonce []byte) (actualKey []byte, err error) {
+	combinedSecret := make([]byte, 0, len(classicalSecret)+len(kemSecret))
+	combinedSecret = append(combinedSecret, classicalSecret...)
+	combinedSecret = append(combinedSecret, kemSecret...)
+	crypto.Memzero(classicalSecret)
+	crypto.Memzero(kemSecret)
+
+	salt, err := hash.MultipleSlices(headerKey, nonce)
+	if err != nil {
+		crypto.Memzero(combinedSecret)
+		err = fmt.Errorf("failed to create salt: %w", err)
+		return
+	}
+
+	actualKey, err = hkdf.DeriveKey(combinedSecret,
+		salt,
+		HybridCryptoName,
+		chacha20poly1305.KeySize)
+	return
+}
@@ -32,46 +32,64 @@ func init() {
 		Name:               "ed25519",
 		MinSignatureLength: ed25519.SignatureSize, // Fixed length sig
 		MaxSignatureLength: ed25519.SignatureSize, // Fixed length sig
-		ValidateKey: func(key []byte) (err error) {
-			if len(key) != ed25519.PrivateKeySize {
-				err = fmt.Errorf("ed25519 private key must be %d bytes (got %d bytes)", ed25519.PrivateKeySize, len(key))
-				return
-			}
-			return
-		},
-		Sign: func(priv []byte, msg []byte) (signature []byte, err error) {
-			if len(priv) != ed25519.PrivateKeySize {
-				err = fmt.Errorf("invalid ed25519 private key size: must be %d (provided key is %d bytes)", ed25519.PrivateKeySize, len(priv))
-				return
-			}
-			signature = ed25519.Sign(ed25519.PrivateKey(priv), msg)
-			return
-		},
-		Verify: func(pub []byte, msg []byte, sig []byte) (valid bool) {
-			if len(pub) != ed25519.PublicKeySize ||
-				len(sig) != ed25519.SignatureSize {
-				return
-			}
-			valid = ed25519.Verify(ed25519.PublicKey(pub), msg, sig)
-			return
-		},
-		NewKey: func() (privateKey []byte, publicKey []byte, err error) {
-			var newSeed []byte
-			err = random.PopulateEmptySlice(&newSeed, ed25519.SeedSize)
-			if err != nil {
-				err = fmt.Errorf("failed to create key seed: %w", err)
-			}
-			priv := ed25519.NewKeyFromSeed(newSeed)
-			pub, ok := priv.Public().(ed25519.PublicKey)
-			if !ok {
-				err = fmt.Errorf("failed to type assert public key generic to ed25519.PublicKey []byte: value=%+v type=%T", priv.Public(), priv.Public())
-				return
-			}
-			privateKey = priv
-			publicKey = pub
-			return
-		},
+		ValidateKey:        validateEd25519Key,
+		Sign:               signEd25519,
+		Verify:             verifyEd25519,
+		NewKey:             newEd25519Key,
+	}
+	signatureSuites[2] = &SigInfo{
+		Name:                   ContentSigName,
+		MinSignatureLength:     ed25519.SignatureSize, // Fixed length sig
+		MaxSignatureLength:     ed25519.SignatureSize, // Fixed length sig
+		ContentSignatureLength: ed25519.SignatureSize,
+		ValidateKey:            validateEd25519Key,
+		Sign:                   signEd25519,
+		Verify:                 verifyEd25519,
+		NewKey:                 newEd25519Key,
+	}
+}
+
+func validateEd25519Key(key []byte) (err error) {
+	if len(key) != ed25519.PrivateKeySize {
+		err = fmt.Errorf("ed25519 private key must be %d bytes (got %d bytes)", ed25519.PrivateKeySize, len(key))
+		return
+	}
+	return
+}
+
+func signEd25519(priv []byte, msg []byte) (signature []byte, err error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		err = fmt.Errorf("invalid ed25519 private key size: must be %d (provided key is %d bytes)", ed25519.PrivateKeySize, len(priv))
+		return
+	}
+	signature = ed25519.Sign(ed25519.PrivateKey(priv), msg)
+	return
+}
+
+func verifyEd25519(pub []byte, msg []byte, sig []byte) (valid bool) {
+	if len(pub) != ed25519.PublicKeySize ||
+		len(sig) != ed25519.SignatureSize {
+		return
 	}
+	valid = ed25519.Verify(ed25519.PublicKey(pub), msg, sig)
+	return
+}
+
+func newEd25519Key() (privateKey []byte, publicKey []byte, err error) {
+	var newSeed []byte
+	err = random.PopulateEmptySlice(&newSeed, ed25519.SeedSize)
+	if err != nil {
+		err = fmt.Errorf("failed to create key seed: %w", err)
+	}
+	priv := ed25519.NewKeyFromSeed(newSeed)
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		err = fmt.Errorf("failed to type assert public key generic to ed25519.PublicKey []byte: value=%+v type=%T", priv.Public(), priv.Public())
+		return
+	}
+	privateKey = priv
+	publicKey = pub
+	return
 }
 
 // Query signature suite (concurrent safe)
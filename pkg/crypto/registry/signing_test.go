@@ -2,6 +2,8 @@ package registry
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"fmt"
 	"testing"
 )
 
@@ -24,6 +26,11 @@ func TestSignatureSuites(t *testing.T) {
 			name:        "ed25519",
 			expectValid: true,
 		},
+		{
+			id:          2,
+			name:        "ed25519-content",
+			expectValid: true,
+		},
 		{
 			id:          200, // unregistered suite
 			name:        "",
@@ -102,3 +109,27 @@ func TestSignatureSuites(t *testing.T) {
 		})
 	}
 }
+
+func TestSignatureFieldLength(t *testing.T) {
+	tests := []struct {
+		id             uint8
+		expectedLength int
+	}{
+		{id: 0, expectedLength: 0},
+		{id: 1, expectedLength: ed25519.SignatureSize},
+		{id: 2, expectedLength: 2 * ed25519.SignatureSize}, // Identity sig + content sig
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("suite-%d", tt.id), func(t *testing.T) {
+			info, ok := GetSignatureInfo(tt.id)
+			if !ok {
+				t.Fatalf("expected suite %d to be registered", tt.id)
+			}
+			if info.MinFieldLength() != tt.expectedLength || info.MaxFieldLength() != tt.expectedLength {
+				t.Errorf("expected field length %d, got min=%d max=%d",
+					tt.expectedLength, info.MinFieldLength(), info.MaxFieldLength())
+			}
+		})
+	}
+}
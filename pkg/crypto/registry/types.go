@@ -8,27 +8,48 @@ type DerivcePublicKeyFunc func(privateKey []byte) (publicKey []byte, err error)
 type EncryptFunc func(publicKey, payload []byte) (ciphertext, ephemeralPub, nonce []byte, err error)
 type DecryptFunc func(privateKey, ciphertext, ephemeralPub, nonce []byte) (payload []byte, err error)
 
+// Encrypt returns (and Decrypt takes) the ephemeral public key followed by the encapsulated key (if the suite has one)
 type SuiteInfo struct {
-	Name            string
-	KeySize         int
-	NonceSize       int
-	CipherOverhead  int
-	ValidateKey     ValidateKeyFunc
-	NewKey          NewKeyFunc
-	DerivePublicKey DerivcePublicKeyFunc
-	Encrypt         EncryptFunc
-	Decrypt         DecryptFunc
+	Name                string
+	KeySize             int // Ephemeral public key size
+	EncapsulatedKeySize int // KEM ciphertext size following the ephemeral public key (0 = no KEM)
+	NonceSize           int
+	CipherOverhead      int
+	ValidateKey         ValidateKeyFunc
+	NewKey              NewKeyFunc
+	DerivePublicKey     DerivcePublicKeyFunc
+	Encrypt             EncryptFunc
+	Decrypt             DecryptFunc
+}
+
+// Total length of the key field in the outer payload header
+func (suite SuiteInfo) HeaderKeySize() (size int) {
+	size = suite.KeySize + suite.EncapsulatedKeySize
+	return
 }
 
 type SignFunc func(privateKey []byte, message []byte) (sig []byte, err error)
 type VerifyFunc func(publicKey []byte, message []byte, sig []byte) (valid bool)
 
 type SigInfo struct {
-	Name               string
-	MinSignatureLength int
-	MaxSignatureLength int
-	ValidateKey        ValidateKeyFunc
-	NewKey             NewKeyFunc
-	Sign               SignFunc
-	Verify             VerifyFunc
+	Name                   string
+	MinSignatureLength     int
+	MaxSignatureLength     int
+	ContentSignatureLength int // Content signature following the identity signature in the signature field (0 = identity only)
+	ValidateKey            ValidateKeyFunc
+	NewKey                 NewKeyFunc
+	Sign                   SignFunc
+	Verify                 VerifyFunc
+}
+
+// Shortest valid signature field in the inner payload (identity and content signatures combined)
+func (suite SigInfo) MinFieldLength() (length int) {
+	length = suite.MinSignatureLength + suite.ContentSignatureLength
+	return
+}
+
+// Longest valid signature field in the inner payload (identity and content signatures combined)
+func (suite SigInfo) MaxFieldLength() (length int) {
+	length = suite.MaxSignatureLength + suite.ContentSignatureLength
+	return
 }
@@ -10,7 +10,11 @@ const (
 	MissingFragmentPlaceholder string = "[missing fragment]"
 	HostPrefixUnkSig           string = "[UNKNOWN]"
 	HostPrefixUnverified       string = "[UNVERIFIED]"
+	HostPrefixPartial          string = "[PARTIAL]" // Content signed message with missing fragments (content unverifiable)
 	IdentitySignatureContext   string = "D3P-ID-SIG"
+	ContentSignatureContext    string = "D3P-CONTENT-SIG"
+	ParityFieldKey             string = "_parity" // Reserved context field describing erasure coded fragments
+	MaxParityPercent           int    = 100
 
 	terminatorByte          byte   = 0x00
 	customFieldsEmptyMarker uint16 = 0x0001
@@ -32,13 +36,14 @@ const (
 	maxSignatureLen  int = 255
 	maxCtxSectionLen int = (1 << (8 * lenContextSectionNxtLen)) - 1
 	minCtxKeyLen     int = 1
-	maxCtxKeyLen     int = 32
+	MaxCtxKeyLen     int = 32
 	minCtxValLen     int = 1
 	MaxCtxValLen     int = 255
 	minDataLen       int = 1
 	maxDataLen       int = (1 << (8 * lenDataNxtLen)) - 1
 	minPaddingLen    int = 10
 	maxPaddingLen    int = 60
+	maxErasureShards int = 256 // Data plus parity fragments for GF(2^8) Reed-Solomon
 	// Protocol wire field lengths (fixed fields)
 	lenHostID    int = 4
 	lenMsgID     int = 4
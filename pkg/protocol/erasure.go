@@ -0,0 +1,260 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// Galois field GF(2^8) lookup tables (primitive polynomial x^8+x^4+x^3+x^2+1, generator 2)
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := 1
+	for i := range 255 {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	// Duplicate table to avoid modulo in multiplication
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) (product byte) {
+	if a == 0 || b == 0 {
+		return
+	}
+	product = gfExp[int(gfLog[a])+int(gfLog[b])]
+	return
+}
+
+func gfDiv(a, b byte) (quotient byte) {
+	if a == 0 {
+		return
+	}
+	quotient = gfExp[int(gfLog[a])+255-int(gfLog[b])]
+	return
+}
+
+func gfPow(base byte, exponent int) (result byte) {
+	if exponent == 0 {
+		result = 1
+		return
+	}
+	if base == 0 {
+		return
+	}
+	result = gfExp[(int(gfLog[base])*exponent)%255]
+	return
+}
+
+// Systematic Reed-Solomon erasure code over GF(2^8)
+type reedSolomon struct {
+	dataShards   int
+	parityShards int
+	matrix       [][]byte // (data+parity) x data encoding matrix, top rows are the identity
+}
+
+// Creates erasure coder for the given shard counts (total may not exceed 256)
+func newReedSolomon(dataShards, parityShards int) (codec *reedSolomon, err error) {
+	if dataShards <= 0 || parityShards <= 0 {
+		err = fmt.Errorf("data and parity shard counts must be greater than 0")
+		return
+	}
+	if dataShards+parityShards > maxErasureShards {
+		err = fmt.Errorf("total shard count %d exceeds maximum of %d", dataShards+parityShards, maxErasureShards)
+		return
+	}
+
+	totalShards := dataShards + parityShards
+
+	// Vandermonde matrix - any data sized subset of rows is invertible
+	vandermonde := make([][]byte, totalShards)
+	for row := range totalShards {
+		vandermonde[row] = make([]byte, dataShards)
+		for col := range dataShards {
+			vandermonde[row][col] = gfPow(byte(row), col)
+		}
+	}
+
+	// Multiply by inverse of top square so data shards are passed through unchanged
+	topInverse, err := invertMatrix(vandermonde[:dataShards])
+	if err != nil {
+		return
+	}
+
+	codec = &reedSolomon{
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		matrix:       multiplyMatrix(vandermonde, topInverse),
+	}
+	return
+}
+
+// Computes parity shards from equal length data shards
+func (codec *reedSolomon) encode(dataShards [][]byte) (parityShards [][]byte, err error) {
+	if len(dataShards) != codec.dataShards {
+		err = fmt.Errorf("expected %d data shards, got %d", codec.dataShards, len(dataShards))
+		return
+	}
+	shardLen := len(dataShards[0])
+	for _, shard := range dataShards {
+		if len(shard) != shardLen {
+			err = fmt.Errorf("data shards must all be the same length")
+			return
+		}
+	}
+
+	parityShards = make([][]byte, codec.parityShards)
+	for index := range parityShards {
+		parityShards[index] = codec.combineRow(codec.matrix[codec.dataShards+index], dataShards, shardLen)
+	}
+	return
+}
+
+// Rebuilds missing (nil) data shards in place from any data-count of present shards.
+// Shards must be ordered data then parity, all present shards the same length.
+func (codec *reedSolomon) reconstruct(shards [][]byte) (err error) {
+	if len(shards) != codec.dataShards+codec.parityShards {
+		err = fmt.Errorf("expected %d shards, got %d", codec.dataShards+codec.parityShards, len(shards))
+		return
+	}
+
+	var missingData bool
+	for index := range codec.dataShards {
+		if shards[index] == nil {
+			missingData = true
+			break
+		}
+	}
+	if !missingData {
+		return
+	}
+
+	// Pick first data-count present shards and the matching encoding rows
+	shardLen := -1
+	subMatrix := make([][]byte, 0, codec.dataShards)
+	subShards := make([][]byte, 0, codec.dataShards)
+	for index, shard := range shards {
+		if shard == nil {
+			continue
+		}
+		if shardLen == -1 {
+			shardLen = len(shard)
+		} else if len(shard) != shardLen {
+			err = fmt.Errorf("present shards must all be the same length")
+			return
+		}
+		subMatrix = append(subMatrix, codec.matrix[index])
+		subShards = append(subShards, shard)
+		if len(subShards) == codec.dataShards {
+			break
+		}
+	}
+	if len(subShards) < codec.dataShards {
+		err = fmt.Errorf("too few shards present to reconstruct: have %d, need %d", len(subShards), codec.dataShards)
+		return
+	}
+
+	decodeMatrix, err := invertMatrix(subMatrix)
+	if err != nil {
+		return
+	}
+
+	for index := range codec.dataShards {
+		if shards[index] != nil {
+			continue
+		}
+		shards[index] = codec.combineRow(decodeMatrix[index], subShards, shardLen)
+	}
+	return
+}
+
+// Linear combination of shards using coefficients from a matrix row
+func (codec *reedSolomon) combineRow(coefficients []byte, shards [][]byte, shardLen int) (output []byte) {
+	output = make([]byte, shardLen)
+	for shardIndex, coefficient := range coefficients {
+		if coefficient == 0 {
+			continue
+		}
+		for pos, value := range shards[shardIndex] {
+			output[pos] ^= gfMul(coefficient, value)
+		}
+	}
+	return
+}
+
+func multiplyMatrix(left, right [][]byte) (product [][]byte) {
+	product = make([][]byte, len(left))
+	for row := range left {
+		product[row] = make([]byte, len(right[0]))
+		for col := range right[0] {
+			var value byte
+			for inner := range right {
+				value ^= gfMul(left[row][inner], right[inner][col])
+			}
+			product[row][col] = value
+		}
+	}
+	return
+}
+
+// Gauss-Jordan elimination over GF(2^8)
+func invertMatrix(matrix [][]byte) (inverse [][]byte, err error) {
+	size := len(matrix)
+
+	// Augment with identity
+	work := make([][]byte, size)
+	for row := range size {
+		work[row] = make([]byte, 2*size)
+		copy(work[row], matrix[row])
+		work[row][size+row] = 1
+	}
+
+	for col := range size {
+		// Find pivot
+		pivot := -1
+		for row := col; row < size; row++ {
+			if work[row][col] != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot == -1 {
+			err = fmt.Errorf("matrix is singular")
+			return
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		// Scale pivot row to 1
+		scale := work[col][col]
+		if scale != 1 {
+			for pos := range work[col] {
+				work[col][pos] = gfDiv(work[col][pos], scale)
+			}
+		}
+
+		// Eliminate column from all other rows
+		for row := range size {
+			factor := work[row][col]
+			if row == col || factor == 0 {
+				continue
+			}
+			for pos := range work[row] {
+				work[row][pos] ^= gfMul(factor, work[col][pos])
+			}
+		}
+	}
+
+	inverse = make([][]byte, size)
+	for row := range size {
+		inverse[row] = work[row][size:]
+	}
+	return
+}
@@ -35,6 +35,11 @@ func (mf MessageFilter) Match(msg *Message) (msgMatch bool) {
 		matches = append(matches, valMatched)
 	}
 
+	for key, filter := range mf.Fields {
+		value, exists := msg.Fields[key]
+		matches = append(matches, exists && filter.Match([]byte(FormatValue(value))))
+	}
+
 	// No filters active, nothing to match
 	if len(matches) == 0 {
 		msgMatch = false
@@ -82,7 +87,18 @@ func (mf MessageFilter) Validate() (err error) {
 	if mf.FieldsValue != nil {
 		err = mf.FieldsValue.Validate()
 		if err != nil {
-			err = fmt.Errorf("invalid fields name filter: %w", err)
+			err = fmt.Errorf("invalid fields value filter: %w", err)
+			return
+		}
+	}
+	for key, filter := range mf.Fields {
+		if filter == nil {
+			err = fmt.Errorf("field %q has no filter", key)
+			return
+		}
+		err = filter.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid filter for field %q: %w", key, err)
 			return
 		}
 	}
@@ -7,6 +7,8 @@ import (
 )
 
 func TestMessageFilter(t *testing.T) {
+	latencyLimit := 250.0
+
 	tests := []struct {
 		name          string
 		filter        MessageFilter
@@ -273,6 +275,86 @@ func TestMessageFilter(t *testing.T) {
 			},
 			expectedMatch: false,
 		},
+		{
+			name: "field scoped value match",
+			filter: MessageFilter{
+				Fields: map[string]*filtering.Filter{
+					"ApplicationName": {Exact: "sshd"},
+				},
+			},
+			input: Message{
+				Fields: map[string]any{
+					"ApplicationName": "sshd",
+				},
+				Data: []byte("msg"),
+			},
+			expectedMatch: true,
+		},
+		{
+			name: "field scoped value on other field",
+			filter: MessageFilter{
+				Fields: map[string]*filtering.Filter{
+					"ApplicationName": {Exact: "sshd"},
+				},
+			},
+			input: Message{
+				Fields: map[string]any{
+					"ApplicationName": "cron",
+					"parent":          "sshd",
+				},
+				Data: []byte("msg"),
+			},
+			expectedMatch: false,
+		},
+		{
+			name: "field scoped missing field",
+			filter: MessageFilter{
+				Fields: map[string]*filtering.Filter{
+					"count": {Not: &filtering.Filter{Exact: "1"}},
+				},
+			},
+			input: Message{
+				Fields: map[string]any{},
+				Data:   []byte("msg"),
+			},
+			expectedMatch: false,
+		},
+		{
+			name: "field scoped numeric and severity",
+			filter: MessageFilter{
+				Fields: map[string]*filtering.Filter{
+					"Severity": {SeverityAtLeast: "warning"},
+					"latency":  {GreaterThan: &latencyLimit},
+				},
+				UseAnd: true,
+			},
+			input: Message{
+				Fields: map[string]any{
+					"Severity": "err",
+					"latency":  float32(312.5),
+				},
+				Data: []byte("msg"),
+			},
+			expectedMatch: true,
+		},
+		{
+			name: "field scoped numeric below",
+			filter: MessageFilter{
+				Fields: map[string]*filtering.Filter{
+					"Severity": {SeverityAtLeast: "warning"},
+					"latency":  {GreaterThan: &latencyLimit},
+				},
+				UseAnd: true,
+			},
+			input: Message{
+				Fields: map[string]any{
+					"Severity": "err",
+					"latency":  int16(12),
+				},
+				Data: []byte("msg"),
+			},
+			expectedMatch: false,
+		},
 	}
 
 	for _, tt := range tests {
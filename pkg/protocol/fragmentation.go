@@ -9,7 +9,8 @@ import (
 
 // Creates payload objects based on main payload and the transports maximum payload size
 // Sets random padding length per fragment
-func Fragment(primaryPayload *Payload, maxPayloadSize int, fixedProtocolSize int) (payloads []*Payload, err error) {
+// Non-zero parity percent appends Reed-Solomon parity fragments (percent of data fragment count, rounded up)
+func Fragment(primaryPayload *Payload, maxPayloadSize int, fixedProtocolSize int, parityPercent int) (payloads []*Payload, err error) {
 	if maxPayloadSize <= 0 {
 		err = fmt.Errorf("%w: maxPayloadSize must be greater than 0", ErrFragmentation)
 		return
@@ -18,6 +19,27 @@ func Fragment(primaryPayload *Payload, maxPayloadSize int, fixedProtocolSize int
 		err = fmt.Errorf("%w: fixedProtocolSize must be greater than 0", ErrFragmentation)
 		return
 	}
+	if parityPercent < 0 || parityPercent > MaxParityPercent {
+		err = fmt.Errorf("%w: parity percent must be between 0 and %d", ErrFragmentation, MaxParityPercent)
+		return
+	}
+
+	if parityPercent > 0 {
+		// Erasure coding requires equal sized fragments, so size all of them for the largest padding
+		maxShardLen := min(maxPayloadSize-fixedProtocolSize-maxPaddingLen, maxDataLen)
+		if maxShardLen <= 0 {
+			err = fmt.Errorf("max_payload_size=%d bytes < protocol_overhead=%d bytes", maxPayloadSize, fixedProtocolSize+maxPaddingLen)
+			err = fmt.Errorf("no room left for message in packet: %w", err)
+			err = fmt.Errorf("%w: protocol overhead (including custom fields) exceeded max payload size: %w", ErrFragmentation, err)
+			return
+		}
+		if len(primaryPayload.Data) == 0 {
+			err = fmt.Errorf("%w: cannot fragment empty data", ErrFragmentation)
+			return
+		}
+		payloads, err = fragmentWithParity(primaryPayload, maxShardLen, parityPercent)
+		return
+	}
 
 	remaining := []byte(primaryPayload.Data)
 	seq := 0
@@ -28,9 +50,8 @@ func Fragment(primaryPayload *Payload, maxPayloadSize int, fixedProtocolSize int
 		payloadFragment.MessageSeq = seq
 
 		// Get a random padding length for this section of the data
-		payloadFragment.PaddingLen, err = random.NumberInRange(minPaddingLen, maxPaddingLen)
+		payloadFragment.PaddingLen, err = randomPaddingLen()
 		if err != nil {
-			err = fmt.Errorf("%w: failed to generate padding length: %w", ErrFragmentation, err)
 			return
 		}
 
@@ -64,7 +85,18 @@ func Fragment(primaryPayload *Payload, maxPayloadSize int, fixedProtocolSize int
 	return
 }
 
+// Random padding length for a single fragment
+func randomPaddingLen() (paddingLen int, err error) {
+	paddingLen, err = random.NumberInRange(minPaddingLen, maxPaddingLen)
+	if err != nil {
+		err = fmt.Errorf("%w: failed to generate padding length: %w", ErrFragmentation, err)
+		return
+	}
+	return
+}
+
 // Recombines payload objects into singular object
+// Messages sent with parity must be passed through RecoverFragments first
 // Expects validated (individual) payloads - only run post payload parsing
 func Defragment(payloads []*Payload) (primaryPayload *Payload, err error) {
 	if len(payloads) == 0 {
@@ -128,6 +160,8 @@ func Defragment(payloads []*Payload) (primaryPayload *Payload, err error) {
 		Timestamp:    payloads[0].Timestamp,
 		CustomFields: payloads[0].CustomFields,
 		Hostname:     payloads[0].Hostname,
+		SignatureID:  payloads[0].SignatureID,
+		Signature:    payloads[0].Signature,
 	}
 
 	// Include the, now whole, log message
@@ -165,7 +199,8 @@ func (payload *Payload) EqualTo(comparePayload *Payload) (equal bool) {
 
 	if comparePayload.RemoteIP != payload.RemoteIP || comparePayload.HostID != payload.HostID ||
 		comparePayload.MsgID != payload.MsgID || !comparePayload.Timestamp.Equal(payload.Timestamp) ||
-		comparePayload.Hostname != payload.Hostname {
+		comparePayload.Hostname != payload.Hostname || comparePayload.SignatureID != payload.SignatureID ||
+		!bytes.Equal(comparePayload.Signature, payload.Signature) {
 
 		equal = false
 		return
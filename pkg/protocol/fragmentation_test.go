@@ -93,7 +93,7 @@ func TestFragmentAndDefragment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			frags, err := Fragment(tt.input, tt.maxPayloadSize, tt.fixedProtocolSize)
+			frags, err := Fragment(tt.input, tt.maxPayloadSize, tt.fixedProtocolSize, 0)
 
 			if tt.expectError {
 				if err == nil {
@@ -264,9 +264,9 @@ func DeconstructInnerPayload(payload []byte) (fields *innerWireFormat, err error
 	}
 	// Empty sig lengths skip signature field
 	if sigLen > 0 {
-		if sigLen > uint8(suite.MaxSignatureLength) || sigLen < uint8(suite.MinSignatureLength) {
+		if sigLen > uint8(suite.MaxFieldLength()) || sigLen < uint8(suite.MinFieldLength()) {
 			err = fmt.Errorf("%w: signature length %d for id %d must be between %d and %d bytes",
-				ErrProtocolViolation, sigLen, sigID, suite.MinSignatureLength, suite.MaxSignatureLength)
+				ErrProtocolViolation, sigLen, sigID, suite.MinFieldLength(), suite.MaxFieldLength())
 			return
 		}
 		fields.Signature = make([]byte, sigLen)
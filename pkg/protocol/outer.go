@@ -30,10 +30,10 @@ func ConstructOuterPayload(innerPayload []byte, suiteID uint8) (outerPayload []b
 		return
 	}
 
-	// Validate ephemeral against chosen suite
-	if len(ephemeralPub) != suite.KeySize {
+	// Validate ephemeral (and encapsulated key, if any) against chosen suite
+	if len(ephemeralPub) != suite.HeaderKeySize() {
 		err = fmt.Errorf("%w: invalid key length: suite ID %d requires length %d, but received key length %d",
-			ErrProtocolViolation, suiteID, suite.KeySize, len(ephemeralPub))
+			ErrProtocolViolation, suiteID, suite.HeaderKeySize(), len(ephemeralPub))
 		return
 	}
 
@@ -105,7 +105,7 @@ func DeconstructOuterPayload(blob []byte) (innerPayload []byte, err error) {
 	}
 
 	// Calculate entire header length to further validate blob
-	minLength := registry.SuiteIDLen + suite.KeySize + suite.NonceSize
+	minLength := registry.SuiteIDLen + suite.HeaderKeySize() + suite.NonceSize
 
 	// Reject packets below header length
 	if len(blob) < minLength {
@@ -115,8 +115,9 @@ func DeconstructOuterPayload(blob []byte) (innerPayload []byte, err error) {
 	}
 
 	// Extract the rest of the fields
-	pubKey := blob[currentIndex : currentIndex+suite.KeySize]
-	currentIndex += suite.KeySize
+	// Key field is the ephemeral public key followed by the encapsulated key (KEM suites only)
+	pubKey := blob[currentIndex : currentIndex+suite.HeaderKeySize()]
+	currentIndex += suite.HeaderKeySize()
 
 	nonce := blob[currentIndex : currentIndex+suite.NonceSize]
 	currentIndex += suite.NonceSize
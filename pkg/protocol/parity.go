@@ -0,0 +1,238 @@
+package protocol
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Outcome of forward error correction handling for a single message
+type ParityResult uint8
+
+const (
+	ParityNotUsed       ParityResult = iota // Message was sent without parity fragments
+	ParityIntact                            // All data fragments arrived, parity was not needed
+	ParityRecovered                         // Missing data fragments were rebuilt from parity
+	ParityUnrecoverable                     // Too many fragments lost, placeholders will be inserted
+)
+
+// Packs erasure coding parameters into the reserved context field value.
+// Fixed width so the field can be sized before fragment counts are known.
+func encodeParityInfo(dataShards, parityShards, dataLength int) (value int64) {
+	value = int64(dataShards)<<48 | int64(parityShards)<<32 | int64(uint32(dataLength))
+	return
+}
+
+// Unpacks erasure coding parameters from the reserved context field value
+func decodeParityInfo(value any) (dataShards, parityShards, dataLength int, err error) {
+	packed, ok := value.(int64)
+	if !ok {
+		err = fmt.Errorf("%w: parity field has unexpected type %T", ErrFragmentation, value)
+		return
+	}
+	dataShards = int(packed >> 48 & 0xFFFF)
+	parityShards = int(packed >> 32 & 0xFFFF)
+	dataLength = int(uint32(packed))
+
+	if dataShards == 0 || parityShards == 0 || dataShards+parityShards > maxErasureShards {
+		err = fmt.Errorf("%w: parity field has invalid shard counts data=%d parity=%d",
+			ErrFragmentation, dataShards, parityShards)
+		return
+	}
+	if dataLength == 0 {
+		err = fmt.Errorf("%w: parity field has empty data length", ErrFragmentation)
+		return
+	}
+	return
+}
+
+// Length of every data fragment except the (possibly shorter) final one
+func shardLength(dataLength, dataShards int) (shardLen int) {
+	shardLen = (dataLength + dataShards - 1) / dataShards
+	return
+}
+
+// Number of parity fragments added for the given data fragment count
+func parityCount(dataShards int, parityPercent int) (parityShards int) {
+	parityShards = (dataShards*parityPercent + 99) / 100
+	if parityShards > maxErasureShards-dataShards {
+		parityShards = maxErasureShards - dataShards
+	}
+	return
+}
+
+// Splits payload into equal sized data fragments plus Reed-Solomon parity fragments.
+// Fragments are no larger than needed to spread the message over the fewest fragments of at most maxShardLen.
+// Messages with too many data fragments for erasure coding are sent without parity.
+func fragmentWithParity(primaryPayload *Payload, maxShardLen int, parityPercent int) (payloads []*Payload, err error) {
+	data := primaryPayload.Data
+	dataShards := (len(data) + maxShardLen - 1) / maxShardLen
+	shardLen := shardLength(len(data), dataShards)
+	parityShards := parityCount(dataShards, parityPercent)
+
+	// Reserved field must not leak into the message or be shared with the caller
+	customFields := make(map[string]any, len(primaryPayload.CustomFields))
+	maps.Copy(customFields, primaryPayload.CustomFields)
+	delete(customFields, ParityFieldKey)
+
+	if parityShards > 0 {
+		customFields[ParityFieldKey] = encodeParityInfo(dataShards, parityShards, len(data))
+	}
+
+	// Data shards are slices of the message, last one zero-filled for encoding only
+	shards := make([][]byte, dataShards)
+	for index := range dataShards {
+		start := index * shardLen
+		end := min(start+shardLen, len(data))
+		shards[index] = data[start:end]
+	}
+
+	if parityShards > 0 {
+		var codec *reedSolomon
+		codec, err = newReedSolomon(dataShards, parityShards)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrFragmentation, err)
+			return
+		}
+
+		encodeShards := make([][]byte, dataShards)
+		copy(encodeShards, shards)
+		encodeShards[dataShards-1] = make([]byte, shardLen)
+		copy(encodeShards[dataShards-1], shards[dataShards-1])
+
+		var parity [][]byte
+		parity, err = codec.encode(encodeShards)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrFragmentation, err)
+			return
+		}
+		shards = append(shards, parity...)
+	}
+
+	for seq, shard := range shards {
+		payloadFragment := *primaryPayload
+		payloadFragment.CustomFields = customFields
+		payloadFragment.MessageSeq = seq
+		payloadFragment.MessageSeqMax = len(shards) - 1
+		payloadFragment.Data = shard
+
+		payloadFragment.PaddingLen, err = randomPaddingLen()
+		if err != nil {
+			return
+		}
+
+		payloads = append(payloads, &payloadFragment)
+	}
+	return
+}
+
+// Rebuilds missing data fragments from parity fragments (if message was sent with parity).
+// Returned payloads contain only data fragments with the reserved parity field removed.
+// Unrecoverable messages are returned with the data fragments that did arrive.
+// Expects validated (individual) payloads - only run post payload parsing
+func RecoverFragments(payloads []*Payload) (restored []*Payload, result ParityResult, err error) {
+	restored = payloads
+	result = ParityNotUsed
+
+	if len(payloads) == 0 {
+		return
+	}
+	parityField, hasParity := payloads[0].CustomFields[ParityFieldKey]
+	if !hasParity {
+		return
+	}
+	if !allFieldsEqual(payloads) {
+		err = fmt.Errorf("%w: some received payloads have shared fields that are not identical - could indicate client misbehavior", ErrFragmentation)
+		return
+	}
+
+	dataShards, parityShards, dataLength, err := decodeParityInfo(parityField)
+	if err != nil {
+		return
+	}
+	totalShards := dataShards + parityShards
+	if payloads[0].MessageSeqMax != totalShards-1 {
+		err = fmt.Errorf("%w: parity field declares %d fragments but maximum sequence is %d",
+			ErrFragmentation, totalShards, payloads[0].MessageSeqMax)
+		return
+	}
+
+	// Fragment length is derived from the declared data length, not from whichever fragments arrived
+	shardLen := shardLength(dataLength, dataShards)
+	lastShardLen := dataLength - (dataShards-1)*shardLen
+	shards := make([][]byte, totalShards)
+	for _, payload := range payloads {
+		if payload.MessageSeq >= totalShards {
+			continue
+		}
+		shards[payload.MessageSeq] = payload.Data
+	}
+
+	// Inconsistent lengths cannot be decoded, fall back to placeholders for the data fragments that arrived
+	lengthsValid := lastShardLen >= 1
+	var present, missingData int
+	for seq, shard := range shards {
+		if shard == nil {
+			if seq < dataShards {
+				missingData++
+			}
+			continue
+		}
+		present++
+
+		expectedLen := shardLen
+		if seq == dataShards-1 {
+			expectedLen = lastShardLen
+		}
+		if len(shard) != expectedLen {
+			lengthsValid = false
+		}
+	}
+
+	// Shared fields for all returned fragments
+	customFields := make(map[string]any, len(payloads[0].CustomFields))
+	maps.Copy(customFields, payloads[0].CustomFields)
+	delete(customFields, ParityFieldKey)
+
+	switch {
+	case missingData == 0:
+		result = ParityIntact
+	case present < dataShards || !lengthsValid:
+		result = ParityUnrecoverable
+	default:
+		result = ParityRecovered
+
+		// Final data shard was zero-filled for encoding
+		if shards[dataShards-1] != nil {
+			padded := make([]byte, shardLen)
+			copy(padded, shards[dataShards-1])
+			shards[dataShards-1] = padded
+		}
+
+		var codec *reedSolomon
+		codec, err = newReedSolomon(dataShards, parityShards)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrFragmentation, err)
+			return
+		}
+		err = codec.reconstruct(shards)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrFragmentation, err)
+			return
+		}
+		shards[dataShards-1] = shards[dataShards-1][:lastShardLen]
+	}
+
+	restored = make([]*Payload, 0, dataShards)
+	for seq, shard := range shards[:dataShards] {
+		if shard == nil {
+			continue
+		}
+		dataFragment := *payloads[0]
+		dataFragment.CustomFields = customFields
+		dataFragment.MessageSeq = seq
+		dataFragment.MessageSeqMax = dataShards - 1
+		dataFragment.Data = shard
+		restored = append(restored, &dataFragment)
+	}
+	return
+}
@@ -0,0 +1,205 @@
+package protocol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFragmentWithParity(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		data           []byte
+		maxPayloadSize int
+		parityPercent  int
+		dropSeqs       []int
+		tamper         func(frags []*Payload)
+		expectedResult ParityResult
+		expectedData   []byte
+	}{
+		{
+			name:           "no loss",
+			data:           []byte(strings.Repeat("abcdefghij", 50)),
+			maxPayloadSize: 200,
+			parityPercent:  25,
+			expectedResult: ParityIntact,
+		},
+		{
+			name:           "single message fragment duplicated by parity",
+			data:           []byte("short message"),
+			maxPayloadSize: 200,
+			parityPercent:  10,
+			dropSeqs:       []int{0},
+			expectedResult: ParityRecovered,
+		},
+		{
+			name:           "lost middle and last data fragments",
+			data:           []byte(strings.Repeat("abcdefghij", 50)),
+			maxPayloadSize: 200,
+			parityPercent:  50,
+			dropSeqs:       []int{2, 4},
+			expectedResult: ParityRecovered,
+		},
+		{
+			name:           "lost data and parity fragments within limit",
+			data:           []byte(strings.Repeat("0123456789", 97)),
+			maxPayloadSize: 150,
+			parityPercent:  30,
+			dropSeqs:       []int{0, 7, 11},
+			expectedResult: ParityRecovered,
+		},
+		{
+			name:           "too many lost fragments",
+			data:           []byte(strings.Repeat("abcdefghij", 50)),
+			maxPayloadSize: 200,
+			parityPercent:  20,
+			dropSeqs:       []int{0, 1},
+			expectedResult: ParityUnrecoverable,
+			expectedData:   []byte(MissingFragmentPlaceholder + MissingFragmentPlaceholder + strings.Repeat("abcdefghij", 50)[200:]),
+		},
+		{
+			name:           "only short final data fragment arrives",
+			data:           []byte(strings.Repeat("abcdefghij", 48) + "x"),
+			maxPayloadSize: 200,
+			parityPercent:  20,
+			dropSeqs:       []int{0, 1, 2, 3, 5},
+			expectedResult: ParityUnrecoverable,
+			expectedData:   []byte(strings.Repeat(MissingFragmentPlaceholder, 4) + (strings.Repeat("abcdefghij", 48) + "x")[388:]),
+		},
+		{
+			name:           "fragment length inconsistent with parity field",
+			data:           []byte(strings.Repeat("abcdefghij", 50)),
+			maxPayloadSize: 200,
+			parityPercent:  50,
+			dropSeqs:       []int{1},
+			tamper: func(frags []*Payload) {
+				frags[0].Data = frags[0].Data[:50]
+			},
+			expectedResult: ParityUnrecoverable,
+			expectedData: []byte(strings.Repeat("abcdefghij", 50)[:50] + MissingFragmentPlaceholder +
+				strings.Repeat("abcdefghij", 50)[200:]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &Payload{
+				HostID:       1,
+				MsgID:        42,
+				Timestamp:    now,
+				Hostname:     "server1",
+				CustomFields: map[string]any{"severity": "info"},
+				Data:         tt.data,
+			}
+
+			// Fixed overhead chosen so each fragment carries 100 bytes for max payload 200
+			frags, err := Fragment(input, tt.maxPayloadSize, 40, tt.parityPercent)
+			if err != nil {
+				t.Fatalf("unexpected fragmentation error: %v", err)
+			}
+			if _, present := input.CustomFields[ParityFieldKey]; present {
+				t.Fatalf("fragmentation modified caller custom fields")
+			}
+			for _, frag := range frags {
+				if len(frag.Data) > len(tt.data) {
+					t.Fatalf("fragment %d has length %d, larger than the %d byte message",
+						frag.MessageSeq, len(frag.Data), len(tt.data))
+				}
+			}
+			if tt.tamper != nil {
+				tt.tamper(frags)
+			}
+
+			var received []*Payload
+			for _, frag := range frags {
+				dropped := false
+				for _, seq := range tt.dropSeqs {
+					if frag.MessageSeq == seq {
+						dropped = true
+					}
+				}
+				if !dropped {
+					received = append(received, frag)
+				}
+			}
+
+			restored, result, err := RecoverFragments(received)
+			if err != nil {
+				t.Fatalf("unexpected recovery error: %v", err)
+			}
+			if result != tt.expectedResult {
+				t.Fatalf("expected parity result %d, got %d", tt.expectedResult, result)
+			}
+			for _, frag := range restored {
+				if _, present := frag.CustomFields[ParityFieldKey]; present {
+					t.Fatalf("recovered fragment still contains parity field")
+				}
+			}
+
+			msg, err := Defragment(restored)
+			if err != nil {
+				t.Fatalf("unexpected defragmentation error: %v", err)
+			}
+
+			expectedData := tt.expectedData
+			if expectedData == nil {
+				expectedData = tt.data
+			}
+			if !bytes.Equal(msg.Data, expectedData) {
+				t.Errorf("reassembled data mismatch.\nGot:  %q\nWant: %q", msg.Data, expectedData)
+			}
+			if len(msg.CustomFields) != 1 || msg.CustomFields["severity"] != "info" {
+				t.Errorf("unexpected custom fields after reassembly: %v", msg.CustomFields)
+			}
+		})
+	}
+}
+
+func TestReedSolomonAllErasurePatterns(t *testing.T) {
+	const dataShards, parityShards = 4, 3
+
+	codec, err := newReedSolomon(dataShards, parityShards)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := make([][]byte, dataShards)
+	for index := range data {
+		data[index] = bytes.Repeat([]byte{byte(index*37 + 1)}, 16)
+		data[index][index] = 0xff
+	}
+	parity, err := codec.encode(data)
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	all := append(append([][]byte{}, data...), parity...)
+
+	// Every combination of up to parityShards erasures must be recoverable
+	for mask := 0; mask < 1<<len(all); mask++ {
+		var erased int
+		shards := make([][]byte, len(all))
+		for index := range all {
+			if mask&(1<<index) != 0 {
+				erased++
+				continue
+			}
+			shards[index] = all[index]
+		}
+		if erased > parityShards {
+			continue
+		}
+
+		err = codec.reconstruct(shards)
+		if err != nil {
+			t.Fatalf("erasure mask %07b: unexpected error: %v", mask, err)
+		}
+		for index := range dataShards {
+			if !bytes.Equal(shards[index], data[index]) {
+				t.Fatalf("erasure mask %07b: shard %d mismatch", mask, index)
+			}
+		}
+	}
+}
@@ -45,6 +45,7 @@ func ConstructPayload(request *Payload, sigID uint8) (proto *innerWireFormat, er
 	// Strip trust markers if in the hostname
 	request.Hostname = strings.ReplaceAll(request.Hostname, HostPrefixUnkSig, "")
 	request.Hostname = strings.ReplaceAll(request.Hostname, HostPrefixUnverified, "")
+	request.Hostname = strings.ReplaceAll(request.Hostname, HostPrefixPartial, "")
 	proto.Hostname = cleanStringToBytes(request.Hostname, maxHostnameLen)
 
 	if sigID > 0 {
@@ -67,9 +68,9 @@ func ConstructPayload(request *Payload, sigID uint8) (proto *innerWireFormat, er
 				ErrInvalidPayload, request.SignatureID, sigID)
 			return
 		}
-		if len(request.Signature) > suite.MaxSignatureLength || len(request.Signature) < suite.MinSignatureLength {
+		if len(request.Signature) > suite.MaxFieldLength() || len(request.Signature) < suite.MinFieldLength() {
 			err = fmt.Errorf("%w: signature length %d for id %d must be between %d and %d bytes",
-				ErrInvalidPayload, len(request.Signature), sigID, suite.MinSignatureLength, suite.MaxSignatureLength)
+				ErrInvalidPayload, len(request.Signature), sigID, suite.MinFieldLength(), suite.MaxFieldLength())
 			return
 		}
 
@@ -85,72 +86,10 @@ func ConstructPayload(request *Payload, sigID uint8) (proto *innerWireFormat, er
 
 	// Context: Serialize
 	if len(request.CustomFields) > 0 {
-		proto.ContextFields = make([]contextWireFormat, 0, len(request.CustomFields))
-
-		// Ensure predictable order of wire format context fields
-		keys := make([]string, 0, len(request.CustomFields))
-		for k := range request.CustomFields {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-
 		var totalCtxLen int
-		for _, key := range keys {
-			value := request.CustomFields[key]
-
-			cleanKey := cleanStringToBytes(key, maxCtxKeyLen)
-			if len(cleanKey) < minCtxKeyLen {
-				err = fmt.Errorf("%w: %w: key cannot be less than %d byte(s)",
-					ErrInvalidPayload, ErrInvalidContextField, minCtxKeyLen)
-				return
-			}
-			if len(cleanKey) > maxCtxKeyLen {
-				err = fmt.Errorf("%w: %w: key %q: cannot be more than %d byte(s)",
-					ErrInvalidPayload, ErrInvalidContextField, cleanKey, minCtxKeyLen)
-				return
-			}
-
-			var cleanType uint8
-			var cleanValue []byte
-			if value == nil {
-				// no value, use placeholder
-				cleanType = ContextString
-				cleanValue = []byte(EmptyFieldChar)
-			} else {
-				cleanType, cleanValue, err = serializeAnyValue(value)
-				if err != nil {
-					err = fmt.Errorf("%w: key %q: %w",
-						ErrSerialization, cleanKey, err)
-					return
-				}
-				if len(cleanValue) < minCtxValLen {
-					cleanValue = []byte(EmptyFieldChar)
-				}
-				if len(cleanValue) > MaxCtxValLen {
-					err = fmt.Errorf("%w: %w: field %q: value size of %d too long, must be less than %d bytes",
-						ErrInvalidPayload, ErrInvalidContextField, key, len(cleanValue), MaxCtxValLen)
-					return
-				}
-				// Only strings enforce utf8
-				if cleanType == ContextString {
-					if !utf8.Valid(cleanValue) {
-						err = fmt.Errorf("%w: %w: key %q: non-UTF8 values are unsupported",
-							ErrInvalidPayload, ErrInvalidContextField, string(cleanKey))
-						return
-					}
-				}
-			}
-
-			newCtxEntry := contextWireFormat{
-				Key:     cleanKey,
-				valType: cleanType,
-				Value:   cleanValue,
-			}
-			proto.ContextFields = append(proto.ContextFields, newCtxEntry)
-			// Peeking true length of eventual serialized bytes
-			totalCtxLen += lenCtxKeyNxtLen + len(newCtxEntry.Key) + lenCtxKeyTerminator +
-				lenCtxTypeVal +
-				lenCtxValNxtLen + len(newCtxEntry.Value) + lenCtxValTerminator
+		proto.ContextFields, totalCtxLen, err = serializeContext(request.CustomFields)
+		if err != nil {
+			return
 		}
 
 		if totalCtxLen < minCtxSecLenWithData {
@@ -180,6 +119,78 @@ func ConstructPayload(request *Payload, sigID uint8) (proto *innerWireFormat, er
 	return
 }
 
+// Normalizes and serializes custom fields into wire format entries (sorted by key).
+// Returns the total serialized length of all entries (excluding section length and terminator).
+func serializeContext(customFields map[string]any) (ctxFields []contextWireFormat, totalCtxLen int, err error) {
+	ctxFields = make([]contextWireFormat, 0, len(customFields))
+
+	// Ensure predictable order of wire format context fields
+	keys := make([]string, 0, len(customFields))
+	for k := range customFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := customFields[key]
+
+		cleanKey := cleanStringToBytes(key, MaxCtxKeyLen)
+		if len(cleanKey) < minCtxKeyLen {
+			err = fmt.Errorf("%w: %w: key cannot be less than %d byte(s)",
+				ErrInvalidPayload, ErrInvalidContextField, minCtxKeyLen)
+			return
+		}
+		if len(cleanKey) > MaxCtxKeyLen {
+			err = fmt.Errorf("%w: %w: key %q: cannot be more than %d byte(s)",
+				ErrInvalidPayload, ErrInvalidContextField, cleanKey, minCtxKeyLen)
+			return
+		}
+
+		var cleanType uint8
+		var cleanValue []byte
+		if value == nil {
+			// no value, use placeholder
+			cleanType = ContextString
+			cleanValue = []byte(EmptyFieldChar)
+		} else {
+			cleanType, cleanValue, err = serializeAnyValue(value)
+			if err != nil {
+				err = fmt.Errorf("%w: key %q: %w",
+					ErrSerialization, cleanKey, err)
+				return
+			}
+			if len(cleanValue) < minCtxValLen {
+				cleanValue = []byte(EmptyFieldChar)
+			}
+			if len(cleanValue) > MaxCtxValLen {
+				err = fmt.Errorf("%w: %w: field %q: value size of %d too long, must be less than %d bytes",
+					ErrInvalidPayload, ErrInvalidContextField, key, len(cleanValue), MaxCtxValLen)
+				return
+			}
+			// Only strings enforce utf8
+			if cleanType == ContextString {
+				if !utf8.Valid(cleanValue) {
+					err = fmt.Errorf("%w: %w: key %q: non-UTF8 values are unsupported",
+						ErrInvalidPayload, ErrInvalidContextField, string(cleanKey))
+					return
+				}
+			}
+		}
+
+		newCtxEntry := contextWireFormat{
+			Key:     cleanKey,
+			valType: cleanType,
+			Value:   cleanValue,
+		}
+		ctxFields = append(ctxFields, newCtxEntry)
+		// Peeking true length of eventual serialized bytes
+		totalCtxLen += lenCtxKeyNxtLen + len(newCtxEntry.Key) + lenCtxKeyTerminator +
+			lenCtxTypeVal +
+			lenCtxValNxtLen + len(newCtxEntry.Value) + lenCtxValTerminator
+	}
+	return
+}
+
 // Validates and extracts packet inner payload
 func DeconstructPayload(proto *innerWireFormat) (validated *Payload, err error) {
 	validated = &Payload{}
@@ -228,6 +239,7 @@ func DeconstructPayload(proto *innerWireFormat) (validated *Payload, err error)
 	// Strip trust markers if in the hostname
 	proto.Hostname = bytes.ReplaceAll(proto.Hostname, []byte(HostPrefixUnkSig), nil)
 	proto.Hostname = bytes.ReplaceAll(proto.Hostname, []byte(HostPrefixUnverified), nil)
+	proto.Hostname = bytes.ReplaceAll(proto.Hostname, []byte(HostPrefixPartial), nil)
 	validated.Hostname = string(proto.Hostname)
 
 	pubKey, knownHost := wrappers.LookupPinnedSender(validated.Hostname)
@@ -243,10 +255,20 @@ func DeconstructPayload(proto *innerWireFormat) (validated *Payload, err error)
 		// No pinned key and gratuitous signature - no verification attempt, mark as unknown
 		validated.Hostname = HostPrefixUnkSig + validated.Hostname
 	} else if knownHost && proto.SignatureID != 0 {
-		// Pinned key with signature: verify timestamp and hostname
-		bytesToVerify := SerializeSignature(proto.Hostname, proto.HostID, proto.Timestamp)
+		// Pinned key with signature: verify timestamp and hostname (content is verified after reassembly)
+		suite, validID := registry.GetSignatureInfo(proto.SignatureID)
+		if !validID {
+			err = fmt.Errorf("%w: %w: ID %d", ErrInvalidPayload, ErrUnknownSignatureSuite, proto.SignatureID)
+			return
+		}
+		var identitySignature []byte
+		identitySignature, _, err = splitSignature(suite, proto.Signature)
+		if err != nil {
+			return
+		}
+		bytesToVerify := identitySignable(suite, proto.SignatureID, proto.Hostname, proto.HostID, proto.Timestamp)
 		var valid bool
-		valid, err = wrappers.VerifySignature(pubKey, bytesToVerify, proto.Signature, proto.SignatureID)
+		valid, err = wrappers.VerifySignature(pubKey, bytesToVerify, identitySignature, proto.SignatureID)
 		if err != nil {
 			err = fmt.Errorf("%w: %w", ErrCryptoFailure, err)
 			return
@@ -272,7 +294,7 @@ func DeconstructPayload(proto *innerWireFormat) (validated *Payload, err error)
 					ErrInvalidPayload, ErrInvalidContextField, minCtxKeyLen)
 				return
 			}
-			if keyLength > maxCtxKeyLen {
+			if keyLength > MaxCtxKeyLen {
 				err = fmt.Errorf("%w: %w: key %q: length of %d, cannot be more than %d byte(s)",
 					ErrInvalidPayload, ErrInvalidContextField, string(field.Key), keyLength, minCtxKeyLen)
 				return
@@ -357,7 +379,7 @@ func CalculateProtocolOverhead(suiteID uint8, primaryPayload *Payload) (fixedOve
 
 	// Outer length is fixed based on chosen crypto suite
 	outerTotal := registry.SuiteIDLen +
-		cryptoInfo.KeySize +
+		cryptoInfo.HeaderKeySize() +
 		cryptoInfo.NonceSize +
 		cryptoInfo.CipherOverhead
 
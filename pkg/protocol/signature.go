@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"sdsyslog/internal/crypto/wrappers"
+	"sdsyslog/pkg/crypto/registry"
+)
+
+// Creates the wire signature field for a message prior to fragmentation.
+// Content covering suites append a signature over the message data and context fields.
+func createSignature(payload *Payload, sigID uint8) (signature []byte, err error) {
+	suite, validID := registry.GetSignatureInfo(sigID)
+	if !validID {
+		err = fmt.Errorf("%w: ID %d", ErrUnknownSignatureSuite, sigID)
+		return
+	}
+
+	bitTime := uint64(payload.Timestamp.UnixMilli())
+	bytesToSign := identitySignable(suite, sigID, []byte(payload.Hostname), uint32(payload.HostID), bitTime)
+	signature, err = wrappers.CreateSignature(bytesToSign, sigID)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrCryptoFailure, err)
+		return
+	}
+
+	if suite.ContentSignatureLength == 0 {
+		return
+	}
+
+	contentToSign, err := SerializeContentSignature([]byte(payload.Hostname), uint32(payload.HostID), bitTime,
+		payload.CustomFields, payload.Data)
+	if err != nil {
+		return
+	}
+	contentSignature, err := wrappers.CreateSignature(contentToSign, sigID)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrCryptoFailure, err)
+		return
+	}
+	signature = append(signature, contentSignature...)
+	return
+}
+
+// Identity signature input for the given suite.
+// Content covering suites append their ID so identity signatures cannot be replayed under an identity-only suite.
+func identitySignable(suite registry.SigInfo, sigID uint8, hostname []byte, hostID uint32, timestamp uint64) (signable []byte) {
+	signable = SerializeSignature(hostname, hostID, timestamp)
+	if suite.ContentSignatureLength > 0 {
+		signable = append(signable, sigID)
+	}
+	return
+}
+
+// Splits wire signature field into identity and content parts based on suite
+func splitSignature(suite registry.SigInfo, signature []byte) (identity, content []byte, err error) {
+	if len(signature) < suite.ContentSignatureLength {
+		err = fmt.Errorf("%w: signature length %d is shorter than content signature length %d",
+			ErrInvalidPayload, len(signature), suite.ContentSignatureLength)
+		return
+	}
+	split := len(signature) - suite.ContentSignatureLength
+	identity = signature[:split]
+	content = signature[split:]
+	return
+}
+
+// Creates content signature input: context string, timestamp, host ID, hostname,
+// and a SHA-512 digest over the serialized context fields and message data.
+func SerializeContentSignature(hostname []byte, hostID uint32, timestamp uint64, customFields map[string]any, data []byte) (signable []byte, err error) {
+	ctxFields, _, err := serializeContext(customFields)
+	if err != nil {
+		return
+	}
+
+	hasher := sha512.New()
+	for _, field := range ctxFields {
+		hasher.Write([]byte{uint8(len(field.Key))})
+		hasher.Write(field.Key)
+		hasher.Write([]byte{field.valType, uint8(len(field.Value))})
+		hasher.Write(field.Value)
+	}
+	hasher.Write(binary.BigEndian.AppendUint32(nil, uint32(len(data))))
+	hasher.Write(data)
+
+	signable = make([]byte, 0, len(ContentSignatureContext)+lenTimestamp+lenHostID+1+len(hostname)+sha512.Size)
+	signable = append(signable, ContentSignatureContext...)
+	signable = binary.BigEndian.AppendUint64(signable, timestamp)
+	signable = binary.BigEndian.AppendUint32(signable, hostID)
+	signable = append(signable, uint8(len(hostname)))
+	signable = append(signable, hostname...)
+	signable = hasher.Sum(signable)
+	return
+}
+
+// Verifies content signature of a reassembled message (post Defragment).
+// No-op for identity-only signature suites and for senders without a pinned key.
+// Incomplete messages (missing fragments) cannot be verified and are marked with a hostname prefix instead.
+func VerifyContentSignature(message *Payload, complete bool) (err error) {
+	suite, validID := registry.GetSignatureInfo(message.SignatureID)
+	if !validID {
+		err = fmt.Errorf("%w: %w: ID %d", ErrInvalidPayload, ErrUnknownSignatureSuite, message.SignatureID)
+		return
+	}
+	if suite.ContentSignatureLength == 0 {
+		return
+	}
+
+	// Unpinned senders already carry a trust marker prefix and never match
+	pubKey, knownHost := wrappers.LookupPinnedSender(message.Hostname)
+	if !knownHost {
+		return
+	}
+
+	if !complete {
+		message.Hostname = HostPrefixPartial + message.Hostname
+		return
+	}
+
+	_, contentSignature, err := splitSignature(suite, message.Signature)
+	if err != nil {
+		return
+	}
+
+	bytesToVerify, err := SerializeContentSignature([]byte(message.Hostname), uint32(message.HostID),
+		uint64(message.Timestamp.UnixMilli()), message.CustomFields, message.Data)
+	if err != nil {
+		return
+	}
+
+	valid, err := wrappers.VerifySignature(pubKey, bytesToVerify, contentSignature, message.SignatureID)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrCryptoFailure, err)
+		return
+	}
+	if !valid {
+		err = fmt.Errorf("%w: message from %q has invalid content signature",
+			ErrInvalidPayload, message.Hostname)
+		return
+	}
+	return
+}
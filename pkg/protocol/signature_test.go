@@ -0,0 +1,134 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"sdsyslog/internal/crypto/wrappers"
+	"testing"
+	"time"
+)
+
+func TestVerifyContentSignature(t *testing.T) {
+	signingKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte("c"), ed25519.SeedSize))
+	otherKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte("d"), ed25519.SeedSize))
+
+	err := wrappers.SetupCreateSignature(signingKey)
+	if err != nil {
+		t.Fatalf("failed to setup signing function: %v", err)
+	}
+	err = wrappers.SetupVerifySignature(map[string][]byte{
+		"pinned-host":  signingKey.Public().(ed25519.PublicKey),
+		"rotated-host": otherKey.Public().(ed25519.PublicKey),
+	})
+	if err != nil {
+		t.Fatalf("failed to setup verification function: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		sigID            uint8
+		hostname         string
+		complete         bool
+		mutate           func(message *Payload)
+		expectErr        error
+		expectedHostname string
+	}{
+		{
+			name:             "valid content signature",
+			sigID:            2,
+			hostname:         "pinned-host",
+			complete:         true,
+			expectedHostname: "pinned-host",
+		},
+		{
+			name:     "forged data",
+			sigID:    2,
+			hostname: "pinned-host",
+			complete: true,
+			mutate: func(message *Payload) {
+				message.Data = []byte("forged message text")
+			},
+			expectErr: ErrInvalidPayload,
+		},
+		{
+			name:     "forged context field",
+			sigID:    2,
+			hostname: "pinned-host",
+			complete: true,
+			mutate: func(message *Payload) {
+				message.CustomFields["severity"] = "debug"
+			},
+			expectErr: ErrInvalidPayload,
+		},
+		{
+			name:      "signed by a different key",
+			sigID:     2,
+			hostname:  "rotated-host",
+			complete:  true,
+			expectErr: ErrInvalidPayload,
+		},
+		{
+			name:             "incomplete message is marked",
+			sigID:            2,
+			hostname:         "pinned-host",
+			complete:         false,
+			expectedHostname: HostPrefixPartial + "pinned-host",
+		},
+		{
+			name:     "identity only suite is not checked",
+			sigID:    1,
+			hostname: "pinned-host",
+			complete: true,
+			mutate: func(message *Payload) {
+				message.Data = []byte("forged message text")
+			},
+			expectedHostname: "pinned-host",
+		},
+		{
+			name:             "unpinned sender is not checked",
+			sigID:            2,
+			hostname:         "unknown-host",
+			complete:         true,
+			expectedHostname: "unknown-host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := &Payload{
+				HostID:       7,
+				MsgID:        9,
+				Timestamp:    time.Now(),
+				Hostname:     tt.hostname,
+				CustomFields: map[string]any{"severity": "info", "pid": 42},
+				Data:         []byte("original message text"),
+				SignatureID:  tt.sigID,
+			}
+			message.Signature, err = createSignature(message, tt.sigID)
+			if err != nil {
+				t.Fatalf("unexpected signing error: %v", err)
+			}
+
+			// Receiver side sees deserialized values
+			message.CustomFields["pid"] = int64(42)
+			if tt.mutate != nil {
+				tt.mutate(message)
+			}
+
+			err = VerifyContentSignature(message, tt.complete)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if message.Hostname != tt.expectedHostname {
+				t.Errorf("expected hostname %q, got %q", tt.expectedHostname, message.Hostname)
+			}
+		})
+	}
+}
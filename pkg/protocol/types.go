@@ -23,6 +23,9 @@ type MessageFilter struct {
 	FieldsKey   *filtering.Filter `json:"fieldsKey,omitempty"`
 	FieldsValue *filtering.Filter `json:"fieldsValue,omitempty"`
 
+	// Filters for the value of specific fields by name (missing fields never match)
+	Fields map[string]*filtering.Filter `json:"fields,omitempty"`
+
 	// Optional: match only if all fields match (AND) or any field matches (OR)
 	UseAnd bool `json:"use_and,omitempty"` // default true = AND
 }
@@ -3,12 +3,13 @@ package protocol
 
 import (
 	"fmt"
+	"maps"
 	"sdsyslog/internal/crypto/random"
-	"sdsyslog/internal/crypto/wrappers"
 )
 
 // Main Entry Point: Takes in a new message to be sent and creates packets (transport layer payload)
-func Create(sendMsg *Message, hostID int, maxPayloadSize int, cryptoSuite, signatureSuite uint8) (packets [][]byte, err error) {
+// Non-zero parity percent adds erasure coded parity fragments (see Fragment)
+func Create(sendMsg *Message, hostID int, maxPayloadSize int, cryptoSuite, signatureSuite uint8, parityPercent int) (packets [][]byte, err error) {
 	newMessageID, err := random.FourByte()
 	if err != nil {
 		err = fmt.Errorf("failed to generate random message identifier: %w", err)
@@ -27,23 +28,27 @@ func Create(sendMsg *Message, hostID int, maxPayloadSize int, cryptoSuite, signa
 
 	// Create signature pre-fragmentation (signature validation is done in payload construction)
 	if signatureSuite > 0 {
-		bitTime := uint64(newMsg.Timestamp.UnixMilli())
-		bytesToSign := SerializeSignature([]byte(newMsg.Hostname), uint32(newMsg.HostID), bitTime)
-		newMsg.Signature, err = wrappers.CreateSignature(bytesToSign, signatureSuite)
+		newMsg.Signature, err = createSignature(newMsg, signatureSuite)
 		if err != nil {
-			err = fmt.Errorf("%w: %w", ErrCryptoFailure, err)
 			return
 		}
 	}
 	newMsg.SignatureID = signatureSuite
 
+	if parityPercent > 0 {
+		// Reserve room for the fixed width parity field (value is set during fragmentation)
+		newMsg.CustomFields = make(map[string]any, len(sendMsg.Fields)+1)
+		maps.Copy(newMsg.CustomFields, sendMsg.Fields)
+		newMsg.CustomFields[ParityFieldKey] = encodeParityInfo(0, 0, 0)
+	}
+
 	protocolOverhead, err := CalculateProtocolOverhead(cryptoSuite, newMsg)
 	if err != nil {
 		err = fmt.Errorf("failed to calculate protocol overhead: %w", err)
 		return
 	}
 
-	fragments, err := Fragment(newMsg, maxPayloadSize, protocolOverhead)
+	fragments, err := Fragment(newMsg, maxPayloadSize, protocolOverhead, parityPercent)
 	if err != nil {
 		return
 	}
@@ -107,11 +112,23 @@ func Extract(packets [][]byte) (recvMsg *Message, hostID int, err error) {
 		fragments = append(fragments, messageFragment)
 	}
 
+	// Completeness is only known before placeholders are inserted
+	fragments, _, err = RecoverFragments(fragments)
+	if err != nil {
+		return
+	}
+	complete := len(fragments) > 0 && len(fragments) == fragments[0].MessageSeqMax+1
+
 	primaryPayload, err := Defragment(fragments)
 	if err != nil {
 		return
 	}
 
+	err = VerifyContentSignature(primaryPayload, complete)
+	if err != nil {
+		return
+	}
+
 	recvMsg = &Message{
 		Timestamp: primaryPayload.Timestamp,
 		Hostname:  primaryPayload.Hostname,
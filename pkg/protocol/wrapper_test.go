@@ -22,6 +22,7 @@ func TestProtocol(t *testing.T) {
 		sigID            uint8
 		hostID           int
 		maxPayloadSize   int
+		parityPercent    int
 		mutatePackets    func(packets [][]byte) [][]byte
 		pinnedPubKeys    map[string][]byte
 		expectedMsg      *Message
@@ -47,6 +48,77 @@ func TestProtocol(t *testing.T) {
 				Data:      []byte("hello world"),
 			},
 		},
+		{
+			name: "aes-256-gcm suite multiple fragments",
+			msg: &Message{
+				Timestamp: now,
+				Hostname:  "host-aes",
+				Fields:    map[string]any{"env": "prod"},
+				Data:      bytes.Repeat([]byte("G"), 1500),
+			},
+			hostID:         1,
+			maxPayloadSize: 512,
+			cryptoID:       2,
+			expectedMsg: &Message{
+				Timestamp: now,
+				Hostname:  HostPrefixUnverified + "host-aes",
+				Fields:    map[string]any{"env": "prod"},
+				Data:      bytes.Repeat([]byte("G"), 1500),
+			},
+		},
+		{
+			name: "hybrid post-quantum suite fits packets within payload size",
+			msg: &Message{
+				Timestamp: now,
+				Hostname:  "host-pq",
+				Fields:    map[string]any{"env": "prod"},
+				Data:      bytes.Repeat([]byte("Q"), 1500),
+			},
+			hostID:         1,
+			maxPayloadSize: 1472,
+			cryptoID:       3,
+			expectedMsg: &Message{
+				Timestamp: now,
+				Hostname:  HostPrefixUnverified + "host-pq",
+				Fields:    map[string]any{"env": "prod"},
+				Data:      bytes.Repeat([]byte("Q"), 1500),
+			},
+		},
+		{
+			name: "hybrid post-quantum suite payload size too small",
+			msg: &Message{
+				Timestamp: now,
+				Hostname:  "host-pq",
+				Data:      []byte("hello world"),
+			},
+			hostID:          1,
+			maxPayloadSize:  1024,
+			cryptoID:        3,
+			expectErrCreate: "protocol overhead (including custom fields) exceeded max payload size",
+		},
+		{
+			name: "parity fragments rebuild lost packets",
+			msg: &Message{
+				Timestamp: now,
+				Hostname:  "host-a",
+				Fields:    map[string]any{"env": "dev"},
+				Data:      []byte(strings.Repeat("parity protected text ", 200)),
+			},
+			hostID:         1,
+			maxPayloadSize: 512,
+			parityPercent:  50,
+			cryptoID:       1,
+			mutatePackets: func(packets [][]byte) [][]byte {
+				// Lose the first packet and one from the middle
+				return append(packets[1:len(packets)/2], packets[len(packets)/2+1:]...)
+			},
+			expectedMsg: &Message{
+				Timestamp: now,
+				Hostname:  HostPrefixUnverified + "host-a",
+				Fields:    map[string]any{"env": "dev"},
+				Data:      []byte(strings.Repeat("parity protected text ", 200)),
+			},
+		},
 		{
 			name: "valid signature",
 			msg: &Message{
@@ -70,6 +142,29 @@ func TestProtocol(t *testing.T) {
 				Data:      []byte("hello world"),
 			},
 		},
+		{
+			name: "valid content signature across fragments",
+			msg: &Message{
+				Timestamp: now,
+				Hostname:  "host-a-w-content-sig",
+				Fields:    map[string]any{"env": "dev", "empty": ""},
+				Data:      []byte(strings.Repeat("signed content ", 150)),
+			},
+			hostID:         1,
+			maxPayloadSize: 600,
+			signingPrivKey: ed25519.NewKeyFromSeed(bytes.Repeat([]byte("x"), ed25519.SeedSize)),
+			cryptoID:       1,
+			sigID:          2,
+			pinnedPubKeys: map[string][]byte{
+				"host-a-w-content-sig": ed25519.NewKeyFromSeed(bytes.Repeat([]byte("x"), ed25519.SeedSize)).Public().(ed25519.PublicKey),
+			},
+			expectedMsg: &Message{
+				Timestamp: now,
+				Hostname:  "host-a-w-content-sig",
+				Fields:    map[string]any{"env": "dev", "empty": EmptyFieldChar},
+				Data:      []byte(strings.Repeat("signed content ", 150)),
+			},
+		},
 		{
 			name: "hostname forgery (incorrect signature)",
 			msg: &Message{
@@ -316,7 +411,7 @@ func TestProtocol(t *testing.T) {
 				}
 			}
 
-			packets, err := Create(tt.msg, tt.hostID, tt.maxPayloadSize, tt.cryptoID, tt.sigID)
+			packets, err := Create(tt.msg, tt.hostID, tt.maxPayloadSize, tt.cryptoID, tt.sigID, tt.parityPercent)
 			gotExpected, err := utils.MatchErrorString(err, tt.expectErrCreate)
 			if err != nil {
 				t.Fatalf("create: %v", err)